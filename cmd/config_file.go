@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// configFileReloadInterval is the interval at which the config file is checked for changes.
+const configFileReloadInterval = 10 * time.Second
+
+// configFileReloader reloads the settings of config.ReloadableFlags of configHolder when the config file of options changes.
+type configFileReloader struct {
+	options      *Options
+	configHolder *config.Holder
+	settings     map[string]string
+}
+
+func newConfigFileReloader(options *Options, configHolder *config.Holder) (*configFileReloader, error) {
+	settings, err := config.ReadFile(options.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	return &configFileReloader{options: options, configHolder: configHolder, settings: settings}, nil
+}
+
+func (r *configFileReloader) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(configFileReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.reload(); err != nil {
+				glog.Errorf("failed to reload config file %v due to %v", r.options.ConfigFile, err)
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+func (r *configFileReloader) reload() error {
+	settings, err := config.ReadFile(r.options.ConfigFile)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(settings, r.settings) {
+		return nil
+	}
+
+	// the command line is parsed again, so flags set on it still take precedence.
+	reloaded := &Options{}
+	flags := newFlagSet(reloaded)
+	_ = flags.Parse(os.Args)
+	commandLine := sets.NewString()
+	flags.Visit(func(f *pflag.Flag) {
+		commandLine.Insert(f.Name)
+	})
+	if err := config.ApplySettings(flags, settings, commandLine.Has); err != nil {
+		return err
+	}
+	if err := validateReloadableConfig(&reloaded.config); err != nil {
+		return err
+	}
+
+	reloadable := sets.NewString(config.ReloadableFlags...)
+	for _, name := range sets.StringKeySet(settings).Union(sets.StringKeySet(r.settings)).List() {
+		if settings[name] != r.settings[name] && !reloadable.Has(name) && !commandLine.Has(name) {
+			glog.Warningf("setting %v changed in config file %v, the controller must be restarted for it to take effect", name, r.options.ConfigFile)
+		}
+	}
+	// the configuration is swapped rather than modified, since reconciles read it concurrently.
+	r.configHolder.Reload(&reloaded.config)
+	r.settings = settings
+	glog.Infof("reloaded config file %v", r.options.ConfigFile)
+	return nil
+}
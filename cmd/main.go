@@ -18,18 +18,22 @@ package main
 
 import (
 	"encoding/json"
+	"expvar"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"runtime"
 	"syscall"
 	"time"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric/collectors"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/ticketmaster/aws-sdk-go-cache/cache"
@@ -70,10 +74,21 @@ func main() {
 	if err != nil {
 		glog.Fatal(err)
 	}
+	// the manager only supports ConfigMap locks, so Lease locks are acquired before the manager is started.
+	var leaseLock *k8s.LeaseLock
+	if options.LeaderElection && options.LeaderElectionLockType == k8s.LeaderElectionLockLeases {
+		identity, err := os.Hostname()
+		if err != nil {
+			glog.Fatal(err)
+		}
+		if leaseLock, err = k8s.NewLeaseLock(restCfg, options.LeaderElectionNamespace, options.LeaderElectionID, identity); err != nil {
+			glog.Fatal(err)
+		}
+	}
 	mgr, err := manager.New(restCfg, manager.Options{
 		Namespace:               options.WatchNamespace,
 		SyncPeriod:              &options.SyncPeriod,
-		LeaderElection:          options.LeaderElection,
+		LeaderElection:          options.LeaderElection && leaseLock == nil,
 		LeaderElectionID:        options.LeaderElectionID,
 		LeaderElectionNamespace: options.LeaderElectionNamespace,
 	})
@@ -93,21 +108,93 @@ func main() {
 	}
 	mc.Start()
 
-	cloud := aws.New(options.AWSAPIMaxRetries, options.AWSAPIDebug, options.config.ClusterName, mc, cc)
-	if err := controller.Initialize(&options.config, mgr, mc, cloud); err != nil {
+	auditActor, err := os.Hostname()
+	if err != nil {
 		glog.Fatal(err)
 	}
-
+	awsConfig, err := aws.NewConfig(options.AWSRegion, options.AWSEndpoints, options.AWSUseFIPSEndpoints, options.AWSSTSRegionalEndpoints == aws.STSRegionalEndpoints, options.AWSInsecureSkipTLSVerify, options.AWSProxyURL, options.AWSNoProxy)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	var vpcEndpointServices []string
+	if options.AWSVPCEndpointsOnly {
+		// endpoints are resolved before credentials, so roles are assumed through the endpoint of STS.
+		if err := aws.UseVPCEndpoints(awsConfig, options.config.VpcID, options.AWSRoleARN, options.AWSEndpoints); err != nil {
+			glog.Errorf("failed to use VPC endpoints, calling the endpoints of the region: %v", err)
+		}
+		vpcEndpointServices = aws.VPCEndpointServices
+	}
+	if awsConfig.Credentials, err = aws.NewCredentials(awsConfig, options.AWSRoleARN); err != nil {
+		glog.Fatal(err)
+	}
+	if options.config.ClusterName == "" {
+		if options.config.ClusterName, err = aws.DiscoverClusterName(awsConfig); err != nil {
+			glog.Fatal(err)
+		}
+		glog.Infof("discovered cluster name %v from the tags of the node", options.config.ClusterName)
+		setClusterNameDefaults(&options.config)
+	}
+	auditor, err := aws.NewAuditor(options.AuditLogFile, options.AuditLogGroup, auditActor, awsConfig)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	changeEventPublisher, err := aws.NewChangeEventPublisher(options.ChangeEventsQueueURL, options.ChangeEventsEventBridge, options.config.ClusterName, awsConfig)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	mutationLimiter := aws.NewMutationLimiter(options.AWSMaxConcurrentMutations, options.AWSMaxConcurrentMutationsPerALB)
+	cloud := aws.New(options.AWSAPIMaxRetries, options.AWSAPIDebug, options.config.ClusterTagKey, options.config.VpcID, mc, cc, auditor, changeEventPublisher, mutationLimiter, options.AWSDescribeCacheTTL, awsConfig)
 	mux := http.NewServeMux()
+	var webhookMux *http.ServeMux
+	if options.WebhookPort != 0 {
+		webhookMux = http.NewServeMux()
+	}
+	configHolder := config.NewHolder(&options.config)
+	drainer, err := controller.Initialize(configHolder, mgr, mc, cloud, mux, webhookMux)
+	if err != nil {
+		glog.Fatal(err)
+	}
+	if webhookMux != nil {
+		go startWebhookServer(options.WebhookPort, options.WebhookCertFile, options.WebhookKeyFile, webhookMux)
+	}
+
 	if options.ProfilingEnabled {
-		registerProfiler(mux)
+		registerProfiler(mux, options)
 	}
-	registerHealthz(mux, &aws.HealthChecker{Cloud: cloud})
+	awsChecker := &aws.HealthChecker{Cloud: cloud, VPCEndpointServices: vpcEndpointServices}
+	registerHealthz(mux, awsChecker)
 	registerMetrics(mux, reg)
 	registerHandlers(mux)
 	go startHTTPServer(options.HealthzPort, mux)
 
-	glog.Fatal(mgr.Start(signals.SetupSignalHandler()))
+	stopCh := signals.SetupSignalHandler()
+	go awsChecker.Run(options.HealthCheckPeriod, stopCh)
+	if options.ConfigFile != "" {
+		reloader, err := newConfigFileReloader(options, configHolder)
+		if err != nil {
+			glog.Fatal(err)
+		}
+		go reloader.run(stopCh)
+	}
+	// on SIGTERM, new reconciles stop and in-flight ones finish before the manager, and the caches they rely on, is stopped.
+	// A second SIGTERM exits straight away.
+	mgrStopCh := make(chan struct{})
+	go func() {
+		<-stopCh
+		glog.Infof("shutting down, waiting up to %v for in-flight reconciles", options.ShutdownTimeout)
+		if !drainer.Drain(options.ShutdownTimeout) {
+			glog.Warningf("in-flight reconciles didn't finish within %v, they're retried by the next leader", options.ShutdownTimeout)
+		}
+		close(mgrStopCh)
+	}()
+	if leaseLock != nil {
+		err = k8s.RunLeaderElected(leaseLock, mgrStopCh, func() error { return mgr.Start(mgrStopCh) })
+	} else {
+		err = mgr.Start(mgrStopCh)
+	}
+	if err != nil {
+		glog.Fatal(err)
+	}
 }
 
 // buildRestConfig creates a new Kubernetes REST configuration. apiserverHost is
@@ -145,6 +232,8 @@ func registerHandlers(mux *http.ServeMux) {
 
 func registerHealthz(mux *http.ServeMux, awsChecker *aws.HealthChecker) {
 	healthz.InstallHandler(mux, healthz.PingHealthz, awsChecker)
+	// pods are only ready once the AWS APIs are reachable with their credentials.
+	healthz.InstallPathHandler(mux, "/readyz", healthz.PingHealthz, awsChecker.Readiness())
 }
 
 func registerMetrics(mux *http.ServeMux, reg *prometheus.Registry) {
@@ -157,7 +246,10 @@ func registerMetrics(mux *http.ServeMux, reg *prometheus.Registry) {
 	)
 }
 
-func registerProfiler(mux *http.ServeMux) {
+func registerProfiler(mux *http.ServeMux, options *Options) {
+	// block and mutex profiles are empty unless their sampling is enabled.
+	runtime.SetBlockProfileRate(options.ProfilingBlockRate)
+	runtime.SetMutexProfileFraction(options.ProfilingMutexFraction)
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/heap", pprof.Index)
 	mux.HandleFunc("/debug/pprof/mutex", pprof.Index)
@@ -168,6 +260,12 @@ func registerProfiler(mux *http.ServeMux) {
 	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	// expvar serves memstats and cmdline by default, goroutines are published to spot leaks without taking a profile.
+	expvar.Publish("goroutines", expvar.Func(func() interface{} {
+		return runtime.NumGoroutine()
+	}))
+	mux.Handle("/debug/vars", expvar.Handler())
 }
 
 func startHTTPServer(port int, mux *http.ServeMux) {
@@ -181,3 +279,15 @@ func startHTTPServer(port int, mux *http.ServeMux) {
 	}
 	glog.Fatal(server.ListenAndServe())
 }
+
+func startWebhookServer(port int, certFile string, keyFile string, mux *http.ServeMux) {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%v", port),
+		Handler:           mux,
+		ReadTimeout:       10 * time.Second,
+		ReadHeaderTimeout: 10 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+	glog.Fatal(server.ListenAndServeTLS(certFile, keyFile))
+}
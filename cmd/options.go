@@ -23,6 +23,7 @@ import (
 	"hash/crc32"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
@@ -31,22 +32,32 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	ing_net "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/net"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
 )
 
 const (
 	defaultLeaderElection          = true
 	defaultLeaderElectionID        = "ingress-controller-leader-alb"
 	defaultLeaderElectionNamespace = ""
+	defaultLeaderElectionLockType  = k8s.LeaderElectionLockConfigMaps
 	defaultWatchNamespace          = apiv1.NamespaceAll
 	defaultSyncPeriod              = 30 * time.Second
 	defaultHealthCheckPeriod       = 1 * time.Minute
+	defaultShutdownTimeout         = 25 * time.Second
 	defaultHealthzPort             = 10254
 	defaultAWSAPIMaxRetries        = 10
 	defaultAWSAPIDebug             = false
+	defaultAWSDescribeCacheTTL     = 5 * time.Minute
 	defaultProfilingEnabled        = true
+	defaultLogFormat               = log.FormatText
 )
 
 // Options defines the commandline interface of this binary
@@ -60,21 +71,102 @@ type Options struct {
 	LeaderElectionID        string
 	LeaderElectionNamespace string
 
+	// LeaderElectionLockType is the kind of object the leader election lock is stored in, configmaps or leases
+	LeaderElectionLockType string
+
 	WatchNamespace    string
 	SyncPeriod        time.Duration
 	HealthCheckPeriod time.Duration
 	HealthzPort       int
 
+	// ShutdownTimeout is how long in-flight reconciles are waited for on shutdown
+	ShutdownTimeout time.Duration
+
 	AWSAPIMaxRetries int
 	AWSAPIDebug      bool
+
+	// AWSMaxConcurrentMutations and AWSMaxConcurrentMutationsPerALB limit concurrent AWS calls mutating resources, they're not limited if 0
+	AWSMaxConcurrentMutations       int
+	AWSMaxConcurrentMutationsPerALB int
+
+	// AWSDescribeCacheTTL is how long ELBV2 Describe responses are cached, they're not cached if 0
+	AWSDescribeCacheTTL time.Duration
+
+	// AWSRegion is the region of AWS calls, it defaults to AWS_REGION or the region of the instance from its metadata
+	AWSRegion string
+
+	// AWSRoleARN is the role assumed for all AWS calls, the credentials of the pod are used if empty
+	AWSRoleARN string
+
+	// AWSEndpoints override the endpoints of AWS services in service=URL format, AWSInsecureSkipTLSVerify skips verifying their certificates
+	AWSEndpoints             []string
+	AWSInsecureSkipTLSVerify bool
+
+	// AWSUseFIPSEndpoints makes AWS calls to FIPS endpoints where available
+	AWSUseFIPSEndpoints bool
+
+	// AWSVPCEndpointsOnly makes AWS calls through the interface VPC endpoints of the VPC, the controller isn't ready while required ones are missing
+	AWSVPCEndpointsOnly bool
+
+	// AWSProxyURL is the HTTP(S) proxy of AWS calls except to the hosts of AWSNoProxy, the proxy of the environment is used if empty
+	AWSProxyURL string
+	AWSNoProxy  []string
+
+	// AWSSTSRegionalEndpoints is regional to call STS at the endpoint of the region, or legacy to call its global endpoint
+	AWSSTSRegionalEndpoints string
+
 	ProfilingEnabled bool
+	// ProfilingBlockRate and ProfilingMutexFraction sample blocking and mutex contention for profiling, they're not sampled if 0
+	ProfilingBlockRate     int
+	ProfilingMutexFraction int
+
+	LogFormat        string
+	SensitiveTagKeys []string
+	AuditLogFile     string
+	AuditLogGroup    string
+
+	ChangeEventsQueueURL    string
+	ChangeEventsEventBridge bool
+
+	WebhookPort     int
+	WebhookCertFile string
+	WebhookKeyFile  string
+
+	ConfigFile string
 
 	config config.Configuration
 }
 
 func getOptions() (*Options, error) {
 	options := &Options{}
+	flags := newFlagSet(options)
+
+	_ = flag.Set("logtostderr", "true")
+	_ = flags.Parse(os.Args)
+	if options.ConfigFile != "" {
+		settings, err := config.ReadFile(options.ConfigFile)
+		if err != nil {
+			return options, err
+		}
+		// flags set on command line take precedence over the config file.
+		if err := config.ApplySettings(flags, settings, flags.Changed); err != nil {
+			return options, err
+		}
+	}
 
+	err := configOptionsByEnvironmentVariables(options)
+	if err != nil {
+		return options, err
+	}
+	err = validateOptions(options)
+
+	// TODO: I know, bad smell here:D
+	parser.AnnotationsPrefix = options.config.AnnotationPrefix
+	return options, err
+}
+
+// newFlagSet binds the commandline flags to fields in options, it's parsed again to reload the config file.
+func newFlagSet(options *Options) *pflag.FlagSet {
 	flags := pflag.NewFlagSet("", pflag.ExitOnError)
 	flags.BoolVar(&options.ShowVersion, "version", false,
 		`Show release information about the AWS ALB Ingress controller and exit.`)
@@ -85,11 +177,13 @@ func getOptions() (*Options, error) {
 	flags.StringVar(&options.KubeConfigFile, "kubeconfig", "",
 		`Path to a kubeconfig file containing authorization and API server information.`)
 	flags.BoolVar(&options.LeaderElection, "election", defaultLeaderElection,
-		`Whether we do leader election for ingress controller`)
+		`Whether we do leader election for ingress controller, so that only one of multiple replicas reconciles ingresses`)
 	flags.StringVar(&options.LeaderElectionID, "election-id", defaultLeaderElectionID,
-		`Namespace of leader-election configmap for ingress controller`)
+		`Name of leader-election configmap or lease for ingress controller`)
 	flags.StringVar(&options.LeaderElectionNamespace, "election-namespace", defaultLeaderElectionNamespace,
-		`Namespace of leader-election configmap for ingress controller. If unspecified, the namespace of this controller pod will be used`)
+		`Namespace of leader-election configmap or lease for ingress controller. If unspecified, the namespace of this controller pod will be used`)
+	flags.StringVar(&options.LeaderElectionLockType, "election-lock-type", defaultLeaderElectionLockType,
+		`Kind of object the leader-election lock is stored in, either configmaps or leases`)
 	flags.StringVar(&options.WatchNamespace, "watch-namespace", defaultWatchNamespace,
 		`Namespace the controller watches for updates to Kubernetes objects.
 		This includes Ingresses, Services and all configuration resources. All
@@ -97,33 +191,72 @@ func getOptions() (*Options, error) {
 	flags.DurationVar(&options.SyncPeriod, "sync-period", defaultSyncPeriod,
 		`Period at which the controller forces the repopulation of its local object stores.`)
 	flags.DurationVar(&options.HealthCheckPeriod, "health-check-period", defaultHealthCheckPeriod,
-		`Period at which the controller executes AWS health checks for its healthz endpoint.`)
+		`Period at which the controller executes AWS health checks for its healthz and readyz endpoints.`)
 	flags.IntVar(&options.HealthzPort, "healthz-port", defaultHealthzPort,
 		`Port to use for the healthz endpoint.`)
+	flags.DurationVar(&options.ShutdownTimeout, "shutdown-timeout", defaultShutdownTimeout,
+		`Maximum time to wait for in-flight reconciles to finish on SIGTERM before exiting. It should be less than the terminationGracePeriodSeconds of the pod.`)
 	flags.IntVar(&options.AWSAPIMaxRetries, "aws-max-retries", defaultAWSAPIMaxRetries,
 		`Maximum number of times to retry the AWS API.`)
 	flags.BoolVar(&options.AWSAPIDebug, "aws-api-debug", defaultAWSAPIDebug,
 		`Enable debug logging of AWS API`)
+	flags.IntVar(&options.AWSMaxConcurrentMutations, "aws-max-concurrent-mutations", 0,
+		`Maximum number of concurrent AWS calls creating, modifying or deleting resources, across all ingresses. Not limited if 0.`)
+	flags.IntVar(&options.AWSMaxConcurrentMutationsPerALB, "aws-max-concurrent-mutations-per-alb", 0,
+		`Maximum number of concurrent AWS calls creating, modifying or deleting an ALB, its listeners or rules. Not limited if 0.`)
+	flags.DurationVar(&options.AWSDescribeCacheTTL, "aws-describe-cache-ttl", defaultAWSDescribeCacheTTL,
+		`How long responses describing ALBs, listeners, rules, target groups and their tags are cached. They are invalidated when the controller modifies the resources they reference. Not cached if 0.`)
+	flags.StringVar(&options.AWSRegion, "aws-region", "",
+		`AWS region of the cluster, e.g. us-west-2. Defaults to the AWS_REGION environment variable, or the region of the node from its instance metadata.`)
+	flags.StringVar(&options.AWSRoleARN, "role-arn", "",
+		`ARN of an IAM role to assume for all AWS calls, with the credentials of the pod, e.g. of its service account with IAM Roles for Service Accounts. Not assumed if unspecified.`)
+	flags.StringSliceVar(&options.AWSEndpoints, "aws-endpoints", nil,
+		`Endpoints of AWS services in service=URL format, keyed by the endpoint prefix of services, e.g. elasticloadbalancing=http://localhost:4566,ec2=http://localhost:4566. Other services use the endpoints of the region.`)
+	flags.BoolVar(&options.AWSInsecureSkipTLSVerify, "aws-insecure-skip-tls-verify", false,
+		`Skip verifying the TLS certificates of AWS endpoints, e.g. of a local test endpoint with a self-signed certificate. Insecure, not for production use.`)
+	flags.BoolVar(&options.AWSUseFIPSEndpoints, "aws-use-fips-endpoints", false,
+		`Make AWS calls to the FIPS endpoints of services in regions that have them. Endpoints of --aws-endpoints take precedence.`)
+	flags.BoolVar(&options.AWSVPCEndpointsOnly, "aws-vpc-endpoints-only", false,
+		`Make AWS calls through the interface VPC endpoints of the VPC, for clusters without internet egress. The controller isn't ready while the VPC lacks endpoints of elasticloadbalancing, ec2, acm or sts. Endpoints of --aws-endpoints take precedence.`)
+	flags.StringVar(&options.AWSProxyURL, "aws-proxy-url", "",
+		`HTTP(S) proxy of AWS calls, e.g. http://proxy.example.com:3128. Defaults to the HTTPS_PROXY and HTTP_PROXY environment variables.`)
+	flags.StringSliceVar(&options.AWSNoProxy, "aws-no-proxy", nil,
+		`Hosts, domains or CIDRs AWS calls to are made without --aws-proxy-url, e.g. .vpce.amazonaws.com. Instance metadata is never proxied.`)
+	flags.StringVar(&options.AWSSTSRegionalEndpoints, "aws-sts-regional-endpoints", aws.STSRegionalEndpoints,
+		`Endpoint roles are assumed at: regional for the STS endpoint of the region, or legacy for the global STS endpoint.`)
 	flags.BoolVar(&options.ProfilingEnabled, "profiling", defaultProfilingEnabled,
-		`Enable profiling via web interface host:port/debug/pprof/`)
+		`Enable profiling via web interface host:port/debug/pprof/, and runtime variables via host:port/debug/vars`)
+	flags.IntVar(&options.ProfilingBlockRate, "profiling-block-rate", 0,
+		`Sample one blocking event per this many nanoseconds spent blocked, for the block profile. Not sampled if 0.`)
+	flags.IntVar(&options.ProfilingMutexFraction, "profiling-mutex-fraction", 0,
+		`Sample one in this many mutex contention events, for the mutex profile. Not sampled if 0.`)
+	flags.StringVar(&options.LogFormat, "log-format", defaultLogFormat,
+		`Format of log lines, either text or json. In json format, log lines of ingresses carry namespace, ingress, reconcileID and awsRequestID fields.`)
+	flags.StringSliceVar(&options.SensitiveTagKeys, "sensitive-tag-keys", nil,
+		`Keys of tags whose values are redacted in logs and events, e.g. --sensitive-tag-keys=secret-key,token.`)
+	flags.StringVar(&options.AuditLogFile, "audit-log-file", "",
+		`Path of the file audit records of AWS calls creating, modifying or deleting resources are appended to. Audit records are not written to file if unspecified.`)
+	flags.StringVar(&options.AuditLogGroup, "audit-log-group", "",
+		`Existing CloudWatch Logs group audit records of AWS calls creating, modifying or deleting resources are shipped to, in an log stream named after the controller pod. Audit records are not shipped if unspecified.`)
+	flags.StringVar(&options.ChangeEventsQueueURL, "change-events-queue-url", "",
+		`URL of the SQS queue an message is sent to for every AWS resource created, modified or deleted. Disabled if unspecified.`)
+	flags.BoolVar(&options.ChangeEventsEventBridge, "change-events-eventbridge", false,
+		`Put an event to the default EventBridge event bus for every AWS resource created, modified or deleted.`)
+	flags.IntVar(&options.WebhookPort, "webhook-port", 0,
+		`Port to serve the validating admission webhook of ingresses on over HTTPS. Disabled if unspecified.`)
+	flags.StringVar(&options.WebhookCertFile, "webhook-cert-file", "",
+		`Path of the TLS certificate of the validating admission webhook.`)
+	flags.StringVar(&options.WebhookKeyFile, "webhook-key-file", "",
+		`Path of the TLS private key of the validating admission webhook.`)
+	flags.StringVar(&options.ConfigFile, "config-file", "",
+		`Path of an YAML file mapping flag names to values, e.g. cluster-name: prod, as an alternative to flags. Flags set on command line take precedence. Settings read when they're used, e.g. default-tags or require-waf, are reloaded when the file changes.`)
 	options.config.BindFlags(flags)
 
 	_ = flags.MarkDeprecated("aws-sync-period", `No longer used, will be removed in next release`)
 	_ = flags.MarkDeprecated("default-backend-service", `No longer used, will be removed in next release`)
 
-	_ = flag.Set("logtostderr", "true")
 	flags.AddGoFlagSet(flag.CommandLine)
-	_ = flags.Parse(os.Args)
-
-	err := configOptionsByEnvironmentVariables(options)
-	if err != nil {
-		return options, err
-	}
-	err = validateOptions(options)
-
-	// TODO: I know, bad smell here:D
-	parser.AnnotationsPrefix = options.config.AnnotationPrefix
-	return options, err
+	return flags
 }
 
 // configOptionsByEnvironmentVariables deals with the legacy way of configuration by environment variables
@@ -160,25 +293,146 @@ func configOptionsByEnvironmentVariables(options *Options) error {
 	return nil
 }
 
+// setClusterNameDefaults defaults the ALB name prefix and cluster tag key of cfg by its cluster name,
+// once it's specified or discovered.
+func setClusterNameDefaults(cfg *config.Configuration) {
+	if cfg.ALBNamePrefix == "" {
+		cfg.ALBNamePrefix = generateALBNamePrefix(cfg.ClusterName)
+	}
+	if cfg.ClusterTagKey == "" {
+		cfg.ClusterTagKey = aws.TagNameCluster + "/" + cfg.ClusterName
+	}
+}
+
 func validateOptions(options *Options) error {
-	if options.config.DefaultTargetType == "pod" {
-		glog.Warningf("The target type parameter for 'pod' has changed to 'ip' to better match AWS APIs and documentation.")
-		options.config.DefaultTargetType = elbv2.TargetTypeEnumIp
+	if err := validateReloadableConfig(&options.config); err != nil {
+		return err
+	}
+	if len(options.config.WatchNamespaces) != 0 && options.WatchNamespace != defaultWatchNamespace {
+		return fmt.Errorf("watch-namespace and watch-namespaces are mutually exclusive")
 	}
-	if options.config.ClusterName == "" {
-		return fmt.Errorf("clusterName must be specified")
+	if len(options.config.WatchNamespaces) == 1 {
+		// only objects in the namespace are watched, otherwise objects in all namespaces are watched and ingresses are filtered.
+		options.WatchNamespace = options.config.WatchNamespaces[0]
 	}
+	if options.config.IngressLabelSelector != "" {
+		selector, err := labels.Parse(options.config.IngressLabelSelector)
+		if err != nil {
+			return fmt.Errorf("ingress-label-selector is invalid due to %v", err)
+		}
+		options.config.IngressSelector = selector
+	}
+	if options.config.ShardCount > 1 && (options.config.ShardIndex < 0 || options.config.ShardIndex >= options.config.ShardCount) {
+		return fmt.Errorf("shard-index must be from 0 to shard-count - 1")
+	}
+	if err := log.SetFormat(options.LogFormat); err != nil {
+		return err
+	}
+	log.SetSensitiveTagKeys(options.SensitiveTagKeys)
 	if len(options.config.ALBNamePrefix) > 12 {
 		return fmt.Errorf("ALBNamePrefix must be 12 characters or less")
 	}
-	if options.config.ALBNamePrefix == "" {
-		options.config.ALBNamePrefix = generateALBNamePrefix(options.config.ClusterName)
+	if options.config.ClusterName != "" {
+		setClusterNameDefaults(&options.config)
+	}
+	if options.config.ClusterTagValue == "" || options.config.ManagedByTagKey == "" || options.config.ManagedByTagValue == "" {
+		return fmt.Errorf("cluster-tag-value, managed-by-tag-key and managed-by-tag-value must not be empty")
+	}
+	if _, err := tags.ParseTemplates(options.config.TagTemplates); err != nil {
+		return err
+	}
+	if options.config.MaxConcurrentReconciles < 1 {
+		return fmt.Errorf("max-concurrent-reconciles must be at least 1")
+	}
+	if options.config.ReconcileBackoffBase <= 0 || options.config.ReconcileBackoffMax < options.config.ReconcileBackoffBase {
+		return fmt.Errorf("reconcile-backoff-base-delay must be positive and no greater than reconcile-backoff-max-delay")
+	}
+	if options.config.SyncRateBurst < 0 || (options.config.SyncRateBurst > 0 && options.config.SyncRateLimit <= 0) {
+		return fmt.Errorf("sync-rate-burst must not be negative, and sync-rate-limit must be positive with it")
+	}
+	if options.config.AWSResyncPeriod < 0 || options.config.DriftDetectionInterval < 0 {
+		return fmt.Errorf("aws-resync-period and drift-detection-interval must not be negative")
+	}
+	if options.AWSMaxConcurrentMutations < 0 || options.AWSMaxConcurrentMutationsPerALB < 0 {
+		return fmt.Errorf("aws-max-concurrent-mutations and aws-max-concurrent-mutations-per-alb must not be negative")
+	}
+	if options.ProfilingBlockRate < 0 || options.ProfilingMutexFraction < 0 {
+		return fmt.Errorf("profiling-block-rate and profiling-mutex-fraction must not be negative")
+	}
+	if options.AWSDescribeCacheTTL < 0 {
+		return fmt.Errorf("aws-describe-cache-ttl must not be negative")
+	}
+	if _, err := aws.ParseEndpointOverrides(options.AWSEndpoints); err != nil {
+		return err
+	}
+	if options.LeaderElectionLockType != k8s.LeaderElectionLockConfigMaps && options.LeaderElectionLockType != k8s.LeaderElectionLockLeases {
+		return fmt.Errorf("election-lock-type must be either %v or %v", k8s.LeaderElectionLockConfigMaps, k8s.LeaderElectionLockLeases)
+	}
+	if options.AWSSTSRegionalEndpoints != aws.STSRegionalEndpoints && options.AWSSTSRegionalEndpoints != aws.STSLegacyEndpoints {
+		return fmt.Errorf("aws-sts-regional-endpoints must be either %v or %v", aws.STSRegionalEndpoints, aws.STSLegacyEndpoints)
+	}
+	if options.AWSVPCEndpointsOnly && (options.AWSUseFIPSEndpoints || options.AWSSTSRegionalEndpoints == aws.STSLegacyEndpoints) {
+		return fmt.Errorf("aws-vpc-endpoints-only can't be used with aws-use-fips-endpoints or legacy aws-sts-regional-endpoints, which have no VPC endpoints")
+	}
+	if options.AWSRoleARN != "" && !strings.HasPrefix(options.AWSRoleARN, "arn:") {
+		return fmt.Errorf("role-arn must be an ARN, e.g. arn:aws:iam::123456789012:role/alb-ingress-controller")
+	}
+	if options.config.HealthMetricsNamespace != "" && options.config.HealthMetricsInterval < time.Second {
+		return fmt.Errorf("cloudwatch-health-metrics-interval must be at least 1s")
+	}
+	if options.config.FailureNotificationThreshold < 1 {
+		return fmt.Errorf("failure-notification-threshold must be at least 1")
+	}
+	if options.config.AnnotationDefaultsConfigMap != "" {
+		namespace, name, err := cache.SplitMetaNamespaceKey(options.config.AnnotationDefaultsConfigMap)
+		if err != nil || namespace == "" || name == "" {
+			return fmt.Errorf("annotation-defaults-configmap must be in namespace/name format")
+		}
+		if options.WatchNamespace != defaultWatchNamespace && namespace != options.WatchNamespace {
+			return fmt.Errorf("annotation-defaults-configmap must be in the watched namespace %v", options.WatchNamespace)
+		}
+	}
+	if options.config.StateConfigMap != "" {
+		namespace, name, err := cache.SplitMetaNamespaceKey(options.config.StateConfigMap)
+		if err != nil || namespace == "" || name == "" {
+			return fmt.Errorf("state-configmap must be in namespace/name format")
+		}
+		if options.WatchNamespace != defaultWatchNamespace && namespace != options.WatchNamespace {
+			return fmt.Errorf("state-configmap must be in the watched namespace %v", options.WatchNamespace)
+		}
+	}
+
+	if options.WebhookPort != 0 && (options.WebhookCertFile == "" || options.WebhookKeyFile == "") {
+		return fmt.Errorf("webhook-cert-file and webhook-key-file must be specified with webhook-port")
+	}
+	if options.config.AnnotationDefaultsFile != "" && options.WebhookPort == 0 {
+		return fmt.Errorf("webhook-port must be specified with annotation-defaults-file")
 	}
 
 	// check port collisions
 	if !ing_net.IsPortAvailable(options.HealthzPort) {
 		return fmt.Errorf("port %v is alreadt in use. Please check the flag --healthz-port", options.HealthzPort)
 	}
+	if options.WebhookPort != 0 && (options.WebhookPort == options.HealthzPort || !ing_net.IsPortAvailable(options.WebhookPort)) {
+		return fmt.Errorf("port %v is already in use. Please check the flag --webhook-port", options.WebhookPort)
+	}
+	return nil
+}
+
+// validateReloadableConfig validates the settings of config.ReloadableFlags, which are validated again when they're reloaded.
+func validateReloadableConfig(cfg *config.Configuration) error {
+	if cfg.DefaultTargetType == "pod" {
+		glog.Warningf("The target type parameter for 'pod' has changed to 'ip' to better match AWS APIs and documentation.")
+		cfg.DefaultTargetType = elbv2.TargetTypeEnumIp
+	}
+	if _, err := tags.ParseDefaultTags(cfg.DefaultTags); err != nil {
+		return err
+	}
+	for _, action := range cfg.CloudWatchAlarmActions {
+		if !strings.HasPrefix(action, "arn:") {
+			return fmt.Errorf("cloudwatch-alarm-actions must be ARNs, e.g. arn:aws:sns:us-west-2:123456789012:alarms")
+		}
+	}
 	return nil
 }
 
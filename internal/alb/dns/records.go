@@ -0,0 +1,433 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+const (
+	// ownershipRecordPrefix prefixes the names of the TXT records telling the owner of the alias records of hosts, like the registry of external-dns.
+	// The TXT records are named apart from hosts, so they never conflict with other TXT records of hosts.
+	ownershipRecordPrefix = "_alb-ingress-controller."
+	ownershipHeritage     = "heritage=aws-alb-ingress-controller"
+	ownershipRecordTTL    = 300
+
+	// recordSetsTTL is the duration the record sets of the hosted zone are cached for, so reconciles don't list the whole zone against the Route53 rate limit.
+	// Changes made by the controller are applied to the cache as well.
+	recordSetsTTL = 5 * time.Minute
+)
+
+// Controller manages the alias records of the hosts of ingresses pointing at their LoadBalancer, in an hosted zone.
+// Records are owned by ingresses as told by an ownership TXT record of each host, records of hosts owned by others are never changed.
+type Controller interface {
+	// Reconcile ensures the hosts of ingress within the hosted zone have alias records pointing at LoadBalancer instance,
+	// and removes the records of hosts owned by ingress that are no longer in it.
+	Reconcile(ctx context.Context, ingress *extensions.Ingress, instance *elbv2.LoadBalancer) error
+
+	// Delete removes the records pointing at LoadBalancer instance, unless they're owned by others.
+	Delete(ctx context.Context, instance *elbv2.LoadBalancer) error
+}
+
+// NewController constructs an Controller managing records in the hosted zone with zoneID, records aren't managed if zoneID is empty.
+// ownerID identifies the records owned by controller, e.g. the name of cluster.
+func NewController(cloud aws.CloudAPI, zoneID string, ownerID string) Controller {
+	return &defaultController{
+		cloud:   cloud,
+		zoneID:  zoneID,
+		ownerID: ownerID,
+	}
+}
+
+type defaultController struct {
+	cloud   aws.CloudAPI
+	zoneID  string
+	ownerID string
+
+	// zoneName is the domain of the hosted zone without trailing dot, it's retrieved on first use
+	zoneNameMutex sync.Mutex
+	zoneName      string
+
+	// recordSets caches the record sets of the hosted zone until recordSetsExpiresAt, it's also held while records are changed
+	recordSetsMutex     sync.Mutex
+	recordSets          []*route53.ResourceRecordSet
+	recordSetsExpiresAt time.Time
+}
+
+var _ Controller = (*defaultController)(nil)
+
+// recordKey identifies an record set by its normalized name and type
+type recordKey struct {
+	name       string
+	recordType string
+}
+
+func (c *defaultController) Reconcile(ctx context.Context, ingress *extensions.Ingress, instance *elbv2.LoadBalancer) error {
+	if c.zoneID == "" {
+		return nil
+	}
+	zoneName, err := c.getZoneName(ctx)
+	if err != nil {
+		return err
+	}
+	c.recordSetsMutex.Lock()
+	defer c.recordSetsMutex.Unlock()
+	current, err := c.getRecordSets(ctx)
+	if err != nil {
+		return err
+	}
+	owner := ownership{ownerID: c.ownerID, resource: ingressResource(ingress)}
+	desired := buildRecordSets(zoneName, ingressHosts(ingress), instance)
+	changes, refused := recordChanges(current, desired, owner, aws.StringValue(instance.DNSName))
+	for _, name := range refused {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "DNS", "record %v isn't owned by ingress, it's not changed", name)
+	}
+	return c.changeRecordSets(ctx, changes)
+}
+
+func (c *defaultController) Delete(ctx context.Context, instance *elbv2.LoadBalancer) error {
+	if c.zoneID == "" {
+		return nil
+	}
+	c.recordSetsMutex.Lock()
+	defer c.recordSetsMutex.Unlock()
+	current, err := c.getRecordSets(ctx)
+	if err != nil {
+		return err
+	}
+	changes := deleteRecordChanges(current, c.ownerID, aws.StringValue(instance.DNSName))
+	return c.changeRecordSets(ctx, changes)
+}
+
+// getRecordSets returns the record sets of the hosted zone, listing them if the cache expired. recordSetsMutex must be held.
+func (c *defaultController) getRecordSets(ctx context.Context) ([]*route53.ResourceRecordSet, error) {
+	if c.recordSets != nil && time.Now().Before(c.recordSetsExpiresAt) {
+		return c.recordSets, nil
+	}
+	recordSets, err := c.cloud.ListResourceRecordSets(ctx, c.zoneID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list records of hosted zone %v due to %v", c.zoneID, err)
+	}
+	if recordSets == nil {
+		recordSets = []*route53.ResourceRecordSet{}
+	}
+	c.recordSets = recordSets
+	c.recordSetsExpiresAt = time.Now().Add(recordSetsTTL)
+	return c.recordSets, nil
+}
+
+// changeRecordSets makes changes to the hosted zone and applies them to the cached record sets. recordSetsMutex must be held.
+func (c *defaultController) changeRecordSets(ctx context.Context, changes []*route53.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	for _, change := range changes {
+		albctx.GetLogger(ctx).Infof("%v record %v %v in hosted zone %v", strings.ToLower(aws.StringValue(change.Action)),
+			aws.StringValue(change.ResourceRecordSet.Type), aws.StringValue(change.ResourceRecordSet.Name), c.zoneID)
+	}
+	if err := c.cloud.ChangeResourceRecordSets(ctx, c.zoneID, changes); err != nil {
+		// the records may have been changed by others since they're cached.
+		c.recordSets = nil
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "failed to change records of hosted zone %v due to %v", c.zoneID, err)
+		return fmt.Errorf("failed to change records of hosted zone %v due to %v", c.zoneID, err)
+	}
+	c.recordSets = applyChanges(c.recordSets, changes)
+	albctx.GetEventf(ctx)(corev1.EventTypeNormal, "DNS", "%d records of hosted zone %v changed", len(changes), c.zoneID)
+	return nil
+}
+
+func (c *defaultController) getZoneName(ctx context.Context) (string, error) {
+	c.zoneNameMutex.Lock()
+	defer c.zoneNameMutex.Unlock()
+	if c.zoneName != "" {
+		return c.zoneName, nil
+	}
+	zoneName, err := c.cloud.GetHostedZoneName(ctx, c.zoneID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get hosted zone %v due to %v", c.zoneID, err)
+	}
+	c.zoneName = normalizeName(zoneName)
+	return c.zoneName, nil
+}
+
+// applyChanges returns recordSets with changes applied.
+func applyChanges(recordSets []*route53.ResourceRecordSet, changes []*route53.Change) []*route53.ResourceRecordSet {
+	changed := make(map[recordKey]*route53.Change, len(changes))
+	for _, change := range changes {
+		changed[keyOf(change.ResourceRecordSet)] = change
+	}
+	result := make([]*route53.ResourceRecordSet, 0, len(recordSets)+len(changes))
+	for _, recordSet := range recordSets {
+		if _, ok := changed[keyOf(recordSet)]; !ok {
+			result = append(result, recordSet)
+		}
+	}
+	for _, change := range changes {
+		if aws.StringValue(change.Action) != route53.ChangeActionDelete {
+			result = append(result, change.ResourceRecordSet)
+		}
+	}
+	return result
+}
+
+// ownership is the owner of the records of an host, which is told by the TXT record of ownershipName of host.
+type ownership struct {
+	ownerID  string
+	resource string
+}
+
+// value returns the value of ownership TXT record, which is quoted per Route53.
+func (o ownership) value() string {
+	return fmt.Sprintf(`"%s,aws-alb-ingress-controller/owner=%s,aws-alb-ingress-controller/resource=%s"`, ownershipHeritage, o.ownerID, o.resource)
+}
+
+// parseOwnership parses the ownership of TXT record set, ok is false if it isn't an ownership record.
+func parseOwnership(recordSet *route53.ResourceRecordSet) (o ownership, ok bool) {
+	for _, record := range recordSet.ResourceRecords {
+		value := strings.Trim(aws.StringValue(record.Value), `"`)
+		parts := strings.Split(value, ",")
+		if len(parts) == 0 || parts[0] != ownershipHeritage {
+			continue
+		}
+		for _, part := range parts[1:] {
+			if v := strings.TrimPrefix(part, "aws-alb-ingress-controller/owner="); v != part {
+				o.ownerID = v
+			}
+			if v := strings.TrimPrefix(part, "aws-alb-ingress-controller/resource="); v != part {
+				o.resource = v
+			}
+		}
+		return o, true
+	}
+	return o, false
+}
+
+// ownershipName returns the normalized name of the ownership TXT record of host.
+func ownershipName(host string) string {
+	return ownershipRecordPrefix + host
+}
+
+func buildOwnershipRecordSet(host string, owner ownership) *route53.ResourceRecordSet {
+	return &route53.ResourceRecordSet{
+		Name:            aws.String(ownershipName(host) + "."),
+		Type:            aws.String(route53.RRTypeTxt),
+		TTL:             aws.Int64(ownershipRecordTTL),
+		ResourceRecords: []*route53.ResourceRecord{{Value: aws.String(owner.value())}},
+	}
+}
+
+// ingressResource identifies ingress as owner of records.
+func ingressResource(ingress *extensions.Ingress) string {
+	return fmt.Sprintf("ingress/%s/%s", ingress.Namespace, ingress.Name)
+}
+
+// ingressHosts returns the distinct hosts of the rules of ingress.
+func ingressHosts(ingress *extensions.Ingress) []string {
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, rule := range ingress.Spec.Rules {
+		host := normalizeName(rule.Host)
+		if host == "" || seen[host] {
+			continue
+		}
+		seen[host] = true
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// buildRecordSets builds the alias records of hosts within zoneName pointing at LoadBalancer instance, A records and AAAA records for dualstack LoadBalancers.
+func buildRecordSets(zoneName string, hosts []string, instance *elbv2.LoadBalancer) map[recordKey]*route53.ResourceRecordSet {
+	recordTypes := []string{route53.RRTypeA}
+	if aws.StringValue(instance.IpAddressType) == elbv2.IpAddressTypeDualstack {
+		recordTypes = append(recordTypes, route53.RRTypeAaaa)
+	}
+	recordSets := make(map[recordKey]*route53.ResourceRecordSet)
+	for _, host := range hosts {
+		if host != zoneName && !strings.HasSuffix(host, "."+zoneName) {
+			continue
+		}
+		for _, recordType := range recordTypes {
+			recordSets[recordKey{name: host, recordType: recordType}] = &route53.ResourceRecordSet{
+				Name: aws.String(host + "."),
+				Type: aws.String(recordType),
+				AliasTarget: &route53.AliasTarget{
+					DNSName:              instance.DNSName,
+					HostedZoneId:         instance.CanonicalHostedZoneId,
+					EvaluateTargetHealth: aws.Bool(false),
+				},
+			}
+		}
+	}
+	return recordSets
+}
+
+// recordChanges returns the changes making the A and AAAA records of current owned by owner match desired, and the names of desired records that are refused
+// since they're owned by others. Records of hosts without ownership are adopted if they point at LoadBalancer with dnsName or don't exist yet.
+// Records of hosts owned by owner that aren't desired are deleted.
+func recordChanges(current []*route53.ResourceRecordSet, desired map[recordKey]*route53.ResourceRecordSet, owner ownership, dnsName string) ([]*route53.Change, []string) {
+	currentByKey := indexRecordSets(current)
+	owners := indexOwnerships(current)
+
+	keys := make([]recordKey, 0, len(desired))
+	for key := range desired {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].name < keys[j].name || (keys[i].name == keys[j].name && keys[i].recordType < keys[j].recordType)
+	})
+	var changes []*route53.Change
+	var refused []string
+	claimedHosts := make(map[string]bool)
+	for _, key := range keys {
+		recordSet, ok := currentByKey[key]
+		hostOwner, owned := owners[key.name]
+		if (owned && hostOwner != owner) || (!owned && ok && !pointsAt(recordSet, dnsName)) {
+			if name := aws.StringValue(desired[key].Name); len(refused) == 0 || refused[len(refused)-1] != name {
+				refused = append(refused, name)
+			}
+			continue
+		}
+		if !owned && !claimedHosts[key.name] {
+			// the ownership record is created rather than upserted, so changes fail if others claimed the host meanwhile.
+			claimedHosts[key.name] = true
+			changes = append(changes, &route53.Change{
+				Action:            aws.String(route53.ChangeActionCreate),
+				ResourceRecordSet: buildOwnershipRecordSet(key.name, owner),
+			})
+		}
+		if ok && aliasMatches(recordSet, desired[key]) {
+			continue
+		}
+		changes = append(changes, &route53.Change{
+			Action:            aws.String(route53.ChangeActionUpsert),
+			ResourceRecordSet: desired[key],
+		})
+	}
+
+	desiredHosts := make(map[string]bool)
+	for key := range desired {
+		desiredHosts[key.name] = true
+	}
+	for _, host := range sortedHosts(owners) {
+		if owners[host] != owner || desiredHosts[host] {
+			continue
+		}
+		changes = append(changes, deleteHostChanges(currentByKey, host)...)
+	}
+	return changes, refused
+}
+
+// deleteRecordChanges returns the changes deleting the A and AAAA records of current pointing at LoadBalancer with dnsName, along with their ownership records,
+// unless they're owned by others than ownerID.
+func deleteRecordChanges(current []*route53.ResourceRecordSet, ownerID string, dnsName string) []*route53.Change {
+	currentByKey := indexRecordSets(current)
+	owners := indexOwnerships(current)
+	hosts := make(map[string]ownership)
+	for key, recordSet := range currentByKey {
+		if key.recordType == route53.RRTypeTxt || !pointsAt(recordSet, dnsName) {
+			continue
+		}
+		if hostOwner, owned := owners[key.name]; owned && hostOwner.ownerID != ownerID {
+			continue
+		}
+		hosts[key.name] = owners[key.name]
+	}
+	var changes []*route53.Change
+	for _, host := range sortedHosts(hosts) {
+		changes = append(changes, deleteHostChanges(currentByKey, host)...)
+	}
+	return changes
+}
+
+// deleteHostChanges returns the changes deleting the A and AAAA records of host in currentByKey, and its ownership record.
+func deleteHostChanges(currentByKey map[recordKey]*route53.ResourceRecordSet, host string) []*route53.Change {
+	var changes []*route53.Change
+	for _, key := range []recordKey{
+		{name: host, recordType: route53.RRTypeA},
+		{name: host, recordType: route53.RRTypeAaaa},
+		{name: ownershipName(host), recordType: route53.RRTypeTxt},
+	} {
+		if recordSet, ok := currentByKey[key]; ok {
+			changes = append(changes, &route53.Change{
+				Action:            aws.String(route53.ChangeActionDelete),
+				ResourceRecordSet: recordSet,
+			})
+		}
+	}
+	return changes
+}
+
+// indexRecordSets indexes the A, AAAA and TXT record sets of recordSets by key.
+func indexRecordSets(recordSets []*route53.ResourceRecordSet) map[recordKey]*route53.ResourceRecordSet {
+	byKey := make(map[recordKey]*route53.ResourceRecordSet)
+	for _, recordSet := range recordSets {
+		recordType := aws.StringValue(recordSet.Type)
+		if recordType != route53.RRTypeA && recordType != route53.RRTypeAaaa && recordType != route53.RRTypeTxt {
+			continue
+		}
+		byKey[keyOf(recordSet)] = recordSet
+	}
+	return byKey
+}
+
+// indexOwnerships indexes the ownerships of recordSets by the host they're of.
+func indexOwnerships(recordSets []*route53.ResourceRecordSet) map[string]ownership {
+	owners := make(map[string]ownership)
+	for _, recordSet := range recordSets {
+		name := normalizeName(aws.StringValue(recordSet.Name))
+		if aws.StringValue(recordSet.Type) != route53.RRTypeTxt || !strings.HasPrefix(name, ownershipRecordPrefix) {
+			continue
+		}
+		if o, ok := parseOwnership(recordSet); ok {
+			owners[strings.TrimPrefix(name, ownershipRecordPrefix)] = o
+		}
+	}
+	return owners
+}
+
+func sortedHosts(owners map[string]ownership) []string {
+	hosts := make([]string, 0, len(owners))
+	for host := range owners {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func keyOf(recordSet *route53.ResourceRecordSet) recordKey {
+	return recordKey{name: normalizeName(aws.StringValue(recordSet.Name)), recordType: aws.StringValue(recordSet.Type)}
+}
+
+// pointsAt tests whether recordSet is an alias to LoadBalancer with dnsName.
+func pointsAt(recordSet *route53.ResourceRecordSet, dnsName string) bool {
+	return recordSet.AliasTarget != nil && dnsName != "" && normalizeAliasDNSName(aws.StringValue(recordSet.AliasTarget.DNSName)) == normalizeAliasDNSName(dnsName)
+}
+
+// aliasMatches tests whether recordSet is the alias of desired.
+func aliasMatches(recordSet *route53.ResourceRecordSet, desired *route53.ResourceRecordSet) bool {
+	return pointsAt(recordSet, aws.StringValue(desired.AliasTarget.DNSName)) &&
+		aws.StringValue(recordSet.AliasTarget.HostedZoneId) == aws.StringValue(desired.AliasTarget.HostedZoneId) &&
+		aws.BoolValue(recordSet.AliasTarget.EvaluateTargetHealth) == aws.BoolValue(desired.AliasTarget.EvaluateTargetHealth)
+}
+
+// normalizeName normalizes the name of an record as returned by Route53, which ends with a dot and escapes the wildcard as \052.
+func normalizeName(name string) string {
+	name = strings.Replace(name, `\052`, "*", -1)
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// normalizeAliasDNSName normalizes the DNS name of LoadBalancers as alias target, which Route53 may prefix with dualstack.
+func normalizeAliasDNSName(dnsName string) string {
+	return strings.TrimPrefix(normalizeName(dnsName), "dualstack.")
+}
@@ -0,0 +1,160 @@
+package dns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	lbDNSName = "web-123456789.us-west-2.elb.amazonaws.com"
+	lbZoneID  = "Z1H1FL5HABSF5"
+)
+
+func aliasRecord(name string, recordType string, dnsName string) *route53.ResourceRecordSet {
+	return &route53.ResourceRecordSet{
+		Name: aws.String(name),
+		Type: aws.String(recordType),
+		AliasTarget: &route53.AliasTarget{
+			DNSName:              aws.String(dnsName),
+			HostedZoneId:         aws.String(lbZoneID),
+			EvaluateTargetHealth: aws.Bool(false),
+		},
+	}
+}
+
+func TestBuildRecordSets(t *testing.T) {
+	instance := &elbv2.LoadBalancer{
+		DNSName:               aws.String(lbDNSName),
+		CanonicalHostedZoneId: aws.String(lbZoneID),
+		IpAddressType:         aws.String(elbv2.IpAddressTypeDualstack),
+	}
+	recordSets := buildRecordSets("example.com", []string{"example.com", "*.api.example.com", "www.example.org"}, instance)
+	assert.Equal(t, map[recordKey]*route53.ResourceRecordSet{
+		{name: "example.com", recordType: route53.RRTypeA}:          aliasRecord("example.com.", route53.RRTypeA, lbDNSName),
+		{name: "example.com", recordType: route53.RRTypeAaaa}:       aliasRecord("example.com.", route53.RRTypeAaaa, lbDNSName),
+		{name: "*.api.example.com", recordType: route53.RRTypeA}:    aliasRecord("*.api.example.com.", route53.RRTypeA, lbDNSName),
+		{name: "*.api.example.com", recordType: route53.RRTypeAaaa}: aliasRecord("*.api.example.com.", route53.RRTypeAaaa, lbDNSName),
+	}, recordSets)
+}
+
+func ownershipRecord(host string, ownerID string, resource string) *route53.ResourceRecordSet {
+	return buildOwnershipRecordSet(host, ownership{ownerID: ownerID, resource: resource})
+}
+
+func TestParseOwnership(t *testing.T) {
+	owner := ownership{ownerID: "cluster", resource: "ingress/default/web"}
+	o, ok := parseOwnership(buildOwnershipRecordSet("app.example.com", owner))
+	assert.True(t, ok)
+	assert.Equal(t, owner, o)
+
+	_, ok = parseOwnership(&route53.ResourceRecordSet{
+		Type:            aws.String(route53.RRTypeTxt),
+		ResourceRecords: []*route53.ResourceRecord{{Value: aws.String(`"v=spf1 -all"`)}},
+	})
+	assert.False(t, ok)
+}
+
+func TestRecordChanges(t *testing.T) {
+	owner := ownership{ownerID: "cluster", resource: "ingress/default/web"}
+	desired := map[recordKey]*route53.ResourceRecordSet{
+		{name: "app.example.com", recordType: route53.RRTypeA}:   aliasRecord("app.example.com.", route53.RRTypeA, lbDNSName),
+		{name: "*.example.com", recordType: route53.RRTypeA}:     aliasRecord("*.example.com.", route53.RRTypeA, lbDNSName),
+		{name: "mine.example.com", recordType: route53.RRTypeA}:  aliasRecord("mine.example.com.", route53.RRTypeA, lbDNSName),
+		{name: "other.example.com", recordType: route53.RRTypeA}: aliasRecord("other.example.com.", route53.RRTypeA, lbDNSName),
+		{name: "taken.example.com", recordType: route53.RRTypeA}: aliasRecord("taken.example.com.", route53.RRTypeA, lbDNSName),
+	}
+	removed := aliasRecord("removed.example.com.", route53.RRTypeA, "dualstack."+lbDNSName+".")
+	removedOwnership := ownershipRecord("removed.example.com", "cluster", "ingress/default/web")
+	current := []*route53.ResourceRecordSet{
+		{Name: aws.String("example.com."), Type: aws.String(route53.RRTypeNs)},
+		aliasRecord("app.example.com.", route53.RRTypeA, "dualstack."+lbDNSName+"."),
+		aliasRecord(`\052.example.com.`, route53.RRTypeA, "other-987654321.us-west-2.elb.amazonaws.com."),
+		aliasRecord("mine.example.com.", route53.RRTypeA, "old-987654321.us-west-2.elb.amazonaws.com."),
+		ownershipRecord("mine.example.com", "cluster", "ingress/default/web"),
+		aliasRecord("taken.example.com.", route53.RRTypeA, "other-987654321.us-west-2.elb.amazonaws.com."),
+		ownershipRecord("taken.example.com", "cluster", "ingress/default/other"),
+		removed,
+		removedOwnership,
+		aliasRecord("foreign.example.com.", route53.RRTypeA, "dualstack."+lbDNSName+"."),
+		ownershipRecord("foreign.example.com", "other-cluster", "ingress/default/web"),
+		aliasRecord("unrelated.example.com.", route53.RRTypeA, "other-987654321.us-west-2.elb.amazonaws.com."),
+	}
+
+	changes, refused := recordChanges(current, desired, owner, lbDNSName)
+	assert.Equal(t, []*route53.Change{
+		{Action: aws.String(route53.ChangeActionCreate), ResourceRecordSet: ownershipRecord("app.example.com", "cluster", "ingress/default/web")},
+		{Action: aws.String(route53.ChangeActionUpsert), ResourceRecordSet: desired[recordKey{name: "mine.example.com", recordType: route53.RRTypeA}]},
+		{Action: aws.String(route53.ChangeActionCreate), ResourceRecordSet: ownershipRecord("other.example.com", "cluster", "ingress/default/web")},
+		{Action: aws.String(route53.ChangeActionUpsert), ResourceRecordSet: desired[recordKey{name: "other.example.com", recordType: route53.RRTypeA}]},
+		{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: removed},
+		{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: removedOwnership},
+	}, changes)
+	assert.Equal(t, []string{"*.example.com.", "taken.example.com."}, refused)
+}
+
+func TestDeleteRecordChanges(t *testing.T) {
+	owned := aliasRecord("app.example.com.", route53.RRTypeA, "dualstack."+lbDNSName+".")
+	ownedOwnership := ownershipRecord("app.example.com", "cluster", "ingress/default/web")
+	legacy := aliasRecord("legacy.example.com.", route53.RRTypeAaaa, lbDNSName)
+	current := []*route53.ResourceRecordSet{
+		owned,
+		ownedOwnership,
+		legacy,
+		aliasRecord("foreign.example.com.", route53.RRTypeA, lbDNSName),
+		ownershipRecord("foreign.example.com", "other-cluster", "ingress/default/web"),
+		aliasRecord("unrelated.example.com.", route53.RRTypeA, "other-987654321.us-west-2.elb.amazonaws.com."),
+	}
+
+	assert.Equal(t, []*route53.Change{
+		{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: owned},
+		{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: ownedOwnership},
+		{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: legacy},
+	}, deleteRecordChanges(current, "cluster", lbDNSName))
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "*.api.*.example.com", normalizeName(`\052.api.\052.Example.com.`))
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	instance := &elbv2.LoadBalancer{
+		DNSName:               aws.String(lbDNSName),
+		CanonicalHostedZoneId: aws.String(lbZoneID),
+		IpAddressType:         aws.String(elbv2.IpAddressTypeIpv4),
+	}
+	ingress := &extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec: extensions.IngressSpec{
+			Rules: []extensions.IngressRule{{Host: "app.example.com"}, {Host: "App.example.com"}, {}},
+		},
+	}
+	cloud := &mocks.CloudAPI{}
+	cloud.On("GetHostedZoneName", ctx, "Z0123456789").Return("example.com.", nil).Once()
+	cloud.On("ListResourceRecordSets", ctx, "Z0123456789").Return([]*route53.ResourceRecordSet{}, nil).Once()
+	cloud.On("ChangeResourceRecordSets", ctx, "Z0123456789", []*route53.Change{
+		{Action: aws.String(route53.ChangeActionCreate), ResourceRecordSet: ownershipRecord("app.example.com", "cluster", "ingress/default/web")},
+		{Action: aws.String(route53.ChangeActionUpsert), ResourceRecordSet: aliasRecord("app.example.com.", route53.RRTypeA, lbDNSName)},
+	}).Return(nil).Once()
+	cloud.On("ChangeResourceRecordSets", ctx, "Z0123456789", []*route53.Change{
+		{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: aliasRecord("app.example.com.", route53.RRTypeA, lbDNSName)},
+		{Action: aws.String(route53.ChangeActionDelete), ResourceRecordSet: ownershipRecord("app.example.com", "cluster", "ingress/default/web")},
+	}).Return(nil).Once()
+
+	// records are listed once, later reconciles use the cached records updated with the changes.
+	controller := NewController(cloud, "Z0123456789", "cluster")
+	assert.NoError(t, controller.Reconcile(ctx, ingress, instance))
+	assert.NoError(t, controller.Reconcile(ctx, ingress, instance))
+	assert.NoError(t, controller.Delete(ctx, instance))
+	cloud.AssertExpectations(t)
+
+	assert.NoError(t, NewController(&mocks.CloudAPI{}, "", "cluster").Reconcile(ctx, ingress, instance))
+}
@@ -13,7 +13,8 @@ func NewNameTagGenerator(cfg config.Configuration) *NameTagGenerator {
 			ALBNamePrefix: cfg.ALBNamePrefix,
 		},
 		TagGenerator{
-			ClusterName: cfg.ClusterName,
+			ClusterTagKey:   cfg.ClusterTagKey,
+			ClusterTagValue: cfg.ClusterTagValue,
 		},
 	}
 }
@@ -2,15 +2,20 @@ package generator
 
 import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/lb"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/ls"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/rs"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
 )
 
 var _ tg.TagGenerator = (*TagGenerator)(nil)
 var _ lb.TagGenerator = (*TagGenerator)(nil)
+var _ ls.TagGenerator = (*TagGenerator)(nil)
+var _ rs.TagGenerator = (*TagGenerator)(nil)
 
 type TagGenerator struct {
-	ClusterName string
+	ClusterTagKey   string
+	ClusterTagValue string
 }
 
 func (gen *TagGenerator) TagLB(namespace string, ingressName string) map[string]string {
@@ -21,6 +26,14 @@ func (gen *TagGenerator) TagTGGroup(namespace string, ingressName string) map[st
 	return gen.tagIngressResources(namespace, ingressName)
 }
 
+func (gen *TagGenerator) TagListener(namespace string, ingressName string) map[string]string {
+	return gen.tagIngressResources(namespace, ingressName)
+}
+
+func (gen *TagGenerator) TagRule(namespace string, ingressName string) map[string]string {
+	return gen.tagIngressResources(namespace, ingressName)
+}
+
 func (gen *TagGenerator) TagTG(serviceName string, servicePort string) map[string]string {
 	return map[string]string{
 		tags.ServiceName: serviceName,
@@ -30,7 +43,7 @@ func (gen *TagGenerator) TagTG(serviceName string, servicePort string) map[strin
 
 func (gen *TagGenerator) tagIngressResources(namespace string, ingressName string) map[string]string {
 	m := make(map[string]string)
-	m["kubernetes.io/cluster/"+gen.ClusterName] = "owned"
+	m[gen.ClusterTagKey] = gen.ClusterTagValue
 	m[tags.Namespace] = namespace
 	m[tags.IngressName] = ingressName
 	return m
@@ -0,0 +1,335 @@
+package lb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	api "k8s.io/api/core/v1"
+)
+
+// elbAccountIDs are the AWS accounts Elastic Load Balancing uses to deliver access logs, keyed by region.
+// see https://docs.aws.amazon.com/elasticloadbalancing/latest/application/load-balancer-access-logs.html
+var elbAccountIDs = map[string]string{
+	"us-east-1":      "127311923021",
+	"us-east-2":      "033677994240",
+	"us-west-1":      "027434742980",
+	"us-west-2":      "797873946194",
+	"af-south-1":     "098369216593",
+	"ca-central-1":   "985666609251",
+	"eu-central-1":   "054676820928",
+	"eu-west-1":      "156460612806",
+	"eu-west-2":      "652711504416",
+	"eu-south-1":     "635631232127",
+	"eu-west-3":      "009996457667",
+	"eu-north-1":     "897822967062",
+	"ap-east-1":      "754344448648",
+	"ap-northeast-1": "582318560864",
+	"ap-northeast-2": "600734575887",
+	"ap-northeast-3": "383597477331",
+	"ap-southeast-1": "114774131450",
+	"ap-southeast-2": "783225319266",
+	"ap-south-1":     "718504428378",
+	"me-south-1":     "076674570225",
+	"sa-east-1":      "507241528517",
+	"us-gov-west-1":  "048591011584",
+	"us-gov-east-1":  "190560391635",
+	"cn-north-1":     "638102146993",
+	"cn-northwest-1": "037604701340",
+}
+
+// elbLogDeliveryServices are the service principals that can be granted access log delivery instead of the
+// regional ELB account.
+var elbLogDeliveryServices = []string{
+	"logdelivery.elasticloadbalancing.amazonaws.com",
+	"delivery.logs.amazonaws.com",
+}
+
+// validateLogsBucket emits a warning event when the bucket policy of a logs bucket doesn't allow
+// Elastic Load Balancing to deliver logs of kind (access or connection) for the load balancer identified by lbArn.
+func (c *attributesController) validateLogsBucket(ctx context.Context, lbArn string, kind string, bucket string, prefix string) {
+	if bucket == "" {
+		return
+	}
+	raw, err := c.cloud.GetBucketPolicy(ctx, bucket)
+	if err != nil {
+		albctx.GetLogger(ctx).Warnf("unable to verify bucket policy of %v due to %v", bucket, err)
+		albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "unable to verify bucket policy of %v allows %v log delivery: %v", bucket, kind, err)
+		return
+	}
+	if !accessLogsAllowed(aws.StringValue(raw), lbArn, bucket, prefix) {
+		albctx.GetLogger(ctx).Warnf("bucket policy of %v doesn't allow %v log delivery", bucket, kind)
+		albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "bucket policy of %v doesn't allow Elastic Load Balancing to write %v logs to %v",
+			bucket, kind, accessLogsObjectArn(lbArn, bucket, prefix))
+	}
+}
+
+// ensureAccessLogsBucket creates the access logs bucket with a policy allowing log delivery and a lifecycle
+// rule expiring old logs, when provisioning is enabled and the bucket doesn't exist yet. Buckets S3 denies access to exist, they're left alone.
+func (controller *defaultController) ensureAccessLogsBucket(ctx context.Context, lbArn string, attrs []*elbv2.LoadBalancerAttribute) error {
+	cfg := controller.store.GetConfig()
+	if !cfg.AccessLogsBucketProvisioning {
+		return nil
+	}
+	desired, err := NewAttributes(attrs)
+	if err != nil && !IsInvalidAttribute(err) {
+		return fmt.Errorf("failed parsing attributes: %v", err)
+	}
+	if !desired.AccessLogsS3Enabled || desired.AccessLogsS3Bucket == "" {
+		return nil
+	}
+	bucket := desired.AccessLogsS3Bucket
+	exists, err := controller.cloud.BucketExists(ctx, bucket)
+	if aws.IsBucketForbidden(err) {
+		// the bucket exists, it's up to its owner to allow log delivery.
+		albctx.GetLogger(ctx).Warnf("unable to verify access logs bucket %v due to %v, assuming it exists", bucket, err)
+		albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "unable to verify access logs bucket %v, which exists but isn't accessible: %v", bucket, err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check existence of bucket %v due to %v", bucket, err)
+	}
+	if exists {
+		return nil
+	}
+
+	region, _ := arnRegionAndAccount(lbArn)
+	in := &s3.CreateBucketInput{Bucket: aws.String(bucket)}
+	if region != "" && region != "us-east-1" {
+		in.CreateBucketConfiguration = &s3.CreateBucketConfiguration{LocationConstraint: aws.String(region)}
+	}
+	albctx.GetLogger(ctx).Infof("creating access logs bucket %v", bucket)
+	if _, err := controller.cloud.CreateBucketWithContext(ctx, in); err != nil {
+		albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "failed to create access logs bucket %v due to %v", bucket, err)
+		return fmt.Errorf("failed to create bucket %v due to %v", bucket, err)
+	}
+
+	policy, err := accessLogsBucketPolicy(lbArn, bucket, desired.AccessLogsS3Prefix)
+	if err != nil {
+		return err
+	}
+	if _, err := controller.cloud.PutBucketPolicyWithContext(ctx, &s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucket),
+		Policy: aws.String(policy),
+	}); err != nil {
+		albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "failed to put policy on access logs bucket %v due to %v", bucket, err)
+		return fmt.Errorf("failed to put policy on bucket %v due to %v", bucket, err)
+	}
+
+	if cfg.AccessLogsBucketExpirationDays > 0 {
+		if _, err := controller.cloud.PutBucketLifecycleConfigurationWithContext(ctx, &s3.PutBucketLifecycleConfigurationInput{
+			Bucket: aws.String(bucket),
+			LifecycleConfiguration: &s3.BucketLifecycleConfiguration{
+				Rules: []*s3.LifecycleRule{
+					{
+						ID:         aws.String("alb-access-logs-expiration"),
+						Status:     aws.String(s3.ExpirationStatusEnabled),
+						Filter:     &s3.LifecycleRuleFilter{Prefix: aws.String(desired.AccessLogsS3Prefix)},
+						Expiration: &s3.LifecycleExpiration{Days: aws.Int64(cfg.AccessLogsBucketExpirationDays)},
+					},
+				},
+			},
+		}); err != nil {
+			albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "failed to put lifecycle rules on access logs bucket %v due to %v", bucket, err)
+			return fmt.Errorf("failed to put lifecycle rules on bucket %v due to %v", bucket, err)
+		}
+	}
+
+	albctx.GetLogger(ctx).Infof("access logs bucket %v created", bucket)
+	albctx.GetEventf(ctx)(api.EventTypeNormal, "CREATE", "access logs bucket %v created", bucket)
+	return nil
+}
+
+// accessLogsBucketPolicy builds a bucket policy that allows Elastic Load Balancing to deliver access logs for lbArn.
+func accessLogsBucketPolicy(lbArn string, bucket string, prefix string) (string, error) {
+	region, _ := arnRegionAndAccount(lbArn)
+	principal := map[string]string{"Service": elbLogDeliveryServices[0]}
+	partition := arnPartition(lbArn)
+	if accountID, ok := elbAccountIDs[region]; ok {
+		principal = map[string]string{"AWS": fmt.Sprintf("arn:%v:iam::%v:root", partition, accountID)}
+	}
+	object := accessLogsObjectArn(lbArn, bucket, prefix)
+	resource := object[:strings.LastIndex(object, "/elasticloadbalancing/")] + "/*"
+
+	policy := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Effect":    "Allow",
+				"Principal": principal,
+				"Action":    "s3:PutObject",
+				"Resource":  resource,
+			},
+		},
+	}
+	b, err := json.Marshal(policy)
+	if err != nil {
+		return "", fmt.Errorf("failed to build bucket policy due to %v", err)
+	}
+	return string(b), nil
+}
+
+type policyDocument struct {
+	Statement policyStatements
+}
+
+type policyStatement struct {
+	Effect    string
+	Principal policyPrincipal
+	Action    stringOrSlice
+	Resource  stringOrSlice
+}
+
+// policyStatements unmarshals either a single statement or a list of statements.
+type policyStatements []policyStatement
+
+func (s *policyStatements) UnmarshalJSON(b []byte) error {
+	var list []policyStatement
+	if err := json.Unmarshal(b, &list); err == nil {
+		*s = list
+		return nil
+	}
+	var single policyStatement
+	if err := json.Unmarshal(b, &single); err != nil {
+		return err
+	}
+	*s = policyStatements{single}
+	return nil
+}
+
+// policyPrincipal unmarshals either the "*" principal or a map of principal types to principals.
+type policyPrincipal map[string]stringOrSlice
+
+func (p *policyPrincipal) UnmarshalJSON(b []byte) error {
+	var wildcard string
+	if err := json.Unmarshal(b, &wildcard); err == nil {
+		*p = policyPrincipal{"*": stringOrSlice{wildcard}}
+		return nil
+	}
+	m := map[string]stringOrSlice{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	*p = m
+	return nil
+}
+
+// stringOrSlice unmarshals either a single string or a list of strings.
+type stringOrSlice []string
+
+func (s *stringOrSlice) UnmarshalJSON(b []byte) error {
+	var list []string
+	if err := json.Unmarshal(b, &list); err == nil {
+		*s = list
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(b, &single); err != nil {
+		return err
+	}
+	*s = stringOrSlice{single}
+	return nil
+}
+
+// accessLogsAllowed checks whether policy contains a statement that allows Elastic Load Balancing to put
+// access log objects for lbArn into bucket under prefix. Conditions are not evaluated.
+func accessLogsAllowed(policy string, lbArn string, bucket string, prefix string) bool {
+	doc := policyDocument{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		return false
+	}
+	region, _ := arnRegionAndAccount(lbArn)
+	object := accessLogsObjectArn(lbArn, bucket, prefix)
+	for _, statement := range doc.Statement {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		if !statement.Principal.allowsLogDelivery(region) {
+			continue
+		}
+		if !wildcardMatchAny(statement.Action, "s3:PutObject", true) {
+			continue
+		}
+		if !wildcardMatchAny(statement.Resource, object, false) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (p policyPrincipal) allowsLogDelivery(region string) bool {
+	for _, v := range p["*"] {
+		if v == "*" {
+			return true
+		}
+	}
+	for _, v := range p["AWS"] {
+		if v == "*" {
+			return true
+		}
+		accountID, ok := elbAccountIDs[region]
+		if !ok {
+			// we don't know the ELB account of this region, trust the policy author.
+			return true
+		}
+		if v == accountID || strings.HasSuffix(v, ":iam::"+accountID+":root") {
+			return true
+		}
+	}
+	for _, v := range p["Service"] {
+		for _, service := range elbLogDeliveryServices {
+			if v == service {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// accessLogsObjectArn returns the ARN of a sample access log object for lbArn.
+func accessLogsObjectArn(lbArn string, bucket string, prefix string) string {
+	_, accountID := arnRegionAndAccount(lbArn)
+	key := fmt.Sprintf("AWSLogs/%v/elasticloadbalancing/access.log.gz", accountID)
+	if prefix != "" {
+		key = strings.Trim(prefix, "/") + "/" + key
+	}
+	return fmt.Sprintf("arn:%v:s3:::%v/%v", arnPartition(lbArn), bucket, key)
+}
+
+func arnPartition(arn string) string {
+	if parts := strings.SplitN(arn, ":", 3); len(parts) == 3 {
+		return parts[1]
+	}
+	return "aws"
+}
+
+func arnRegionAndAccount(arn string) (string, string) {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 5 {
+		return "", ""
+	}
+	return parts[3], parts[4]
+}
+
+// wildcardMatchAny checks whether any of the IAM style patterns (supporting * and ?) matches s.
+func wildcardMatchAny(patterns []string, s string, ignoreCase bool) bool {
+	for _, pattern := range patterns {
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.Replace(expr, `\*`, ".*", -1)
+		expr = strings.Replace(expr, `\?`, ".", -1)
+		if ignoreCase {
+			expr = "(?i)" + expr
+		}
+		if regexp.MustCompile("^" + expr + "$").MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
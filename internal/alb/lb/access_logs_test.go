@@ -0,0 +1,75 @@
+package lb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_accessLogsAllowed(t *testing.T) {
+	lbArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188"
+	for _, tc := range []struct {
+		name     string
+		policy   string
+		bucket   string
+		prefix   string
+		expected bool
+	}{
+		{
+			name:     "regional ELB account is allowed to put objects",
+			policy:   `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::797873946194:root"},"Action":"s3:PutObject","Resource":"arn:aws:s3:::my-bucket/my-prefix/AWSLogs/123456789012/*"}]}`,
+			bucket:   "my-bucket",
+			prefix:   "my-prefix",
+			expected: true,
+		},
+		{
+			name:     "single statement with log delivery service principal",
+			policy:   `{"Statement":{"Effect":"Allow","Principal":{"Service":"logdelivery.elasticloadbalancing.amazonaws.com"},"Action":["s3:*"],"Resource":["arn:aws:s3:::my-bucket/*"]}}`,
+			bucket:   "my-bucket",
+			expected: true,
+		},
+		{
+			name:     "ELB account of another region",
+			policy:   `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::127311923021:root"},"Action":"s3:PutObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`,
+			bucket:   "my-bucket",
+			expected: false,
+		},
+		{
+			name:     "resource doesn't cover prefix",
+			policy:   `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"797873946194"},"Action":"s3:PutObject","Resource":"arn:aws:s3:::my-bucket/other-prefix/*"}]}`,
+			bucket:   "my-bucket",
+			prefix:   "my-prefix",
+			expected: false,
+		},
+		{
+			name:     "deny statement",
+			policy:   `{"Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:PutObject","Resource":"arn:aws:s3:::my-bucket/*"}]}`,
+			bucket:   "my-bucket",
+			expected: false,
+		},
+		{
+			name:     "invalid policy",
+			policy:   `not json`,
+			bucket:   "my-bucket",
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, accessLogsAllowed(tc.policy, lbArn, tc.bucket, tc.prefix))
+		})
+	}
+}
+
+func Test_accessLogsBucketPolicy(t *testing.T) {
+	for _, lbArn := range []string{
+		"arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188",
+		"arn:aws:elasticloadbalancing:xx-unknown-1:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188",
+	} {
+		t.Run(lbArn, func(t *testing.T) {
+			policy, err := accessLogsBucketPolicy(lbArn, "my-bucket", "my-prefix")
+			assert.NoError(t, err)
+			assert.True(t, accessLogsAllowed(policy, lbArn, "my-bucket", "my-prefix"))
+			assert.False(t, accessLogsAllowed(policy, lbArn, "my-bucket", "other-prefix"))
+		})
+	}
+}
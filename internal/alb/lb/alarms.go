@@ -0,0 +1,188 @@
+package lb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// the baseline alarms are evaluated over alarmEvaluationPeriods periods of alarmPeriodSeconds, and don't alarm without datapoints,
+// e.g. when the LoadBalancer serves no traffic.
+const (
+	alarmPeriodSeconds     = 60
+	alarmEvaluationPeriods = 5
+
+	// target5XXThreshold is the number of 5XX responses of targets per minute the LoadBalancer alarms at
+	target5XXThreshold = 10
+	// targetResponseTimeThreshold is the average response time of targets in seconds the LoadBalancer alarms above
+	targetResponseTimeThreshold = 1
+	// unhealthyHostsThreshold is the number of unhealthy targets a targetGroup alarms at
+	unhealthyHostsThreshold = 1
+)
+
+// hasCloudWatchAlarms tests whether instance is tagged with CloudWatch alarms provisioned.
+func (controller *defaultController) hasCloudWatchAlarms(ctx context.Context, instance *elbv2.LoadBalancer) (bool, error) {
+	lbTags, err := controller.describeLBTags(ctx, instance)
+	if err != nil {
+		return false, err
+	}
+	return lbTags[tags.CloudWatchAlarms] == "true", nil
+}
+
+// reconcileCloudWatchAlarms ensures the baseline alarms of LoadBalancer and its targetGroups exist when they're enabled by lbConfig, alarms of removed targetGroups are deleted.
+// Alarms are named after lbID, they're all deleted when disabled if provisioned, so the alarms API isn't called for LoadBalancers that never had them.
+func (controller *defaultController) reconcileCloudWatchAlarms(ctx context.Context, lbID string, lbArn string, lbConfig *loadBalancerConfig,
+	tgGroup tg.TargetGroupGroup, provisioned bool) error {
+	if !lbConfig.CloudWatchAlarms {
+		if !provisioned {
+			return nil
+		}
+		return controller.deleteCloudWatchAlarms(ctx, lbID)
+	}
+
+	desired := buildCloudWatchAlarms(lbID, lbArn, tgGroup, lbConfig.CloudWatchAlarmActions)
+	current, err := controller.cloud.GetMetricAlarmsByPrefix(ctx, alarmNamePrefix(lbID))
+	if err != nil {
+		return err
+	}
+	currentByName := make(map[string]*cloudwatch.MetricAlarm, len(current))
+	for _, alarm := range current {
+		currentByName[aws.StringValue(alarm.AlarmName)] = alarm
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		alarm, ok := currentByName[name]
+		if ok && alarmMatches(desired[name], alarm) {
+			continue
+		}
+		if ok {
+			albctx.GetLogger(ctx).Infof("modifying CloudWatch alarm %v", name)
+		} else {
+			albctx.GetLogger(ctx).Infof("creating CloudWatch alarm %v", name)
+		}
+		if err := controller.cloud.PutMetricAlarm(ctx, desired[name], lbConfig.Tags); err != nil {
+			return fmt.Errorf("failed to put alarm %v due to %v", name, err)
+		}
+	}
+
+	var stale []string
+	for _, alarm := range current {
+		if _, ok := desired[aws.StringValue(alarm.AlarmName)]; !ok {
+			stale = append(stale, aws.StringValue(alarm.AlarmName))
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	albctx.GetLogger(ctx).Infof("deleting CloudWatch alarms %v", stale)
+	return controller.cloud.DeleteMetricAlarms(ctx, stale)
+}
+
+// deleteCloudWatchAlarms deletes all alarms named after lbID.
+func (controller *defaultController) deleteCloudWatchAlarms(ctx context.Context, lbID string) error {
+	alarms, err := controller.cloud.GetMetricAlarmsByPrefix(ctx, alarmNamePrefix(lbID))
+	if err != nil {
+		return err
+	}
+	if len(alarms) == 0 {
+		return nil
+	}
+	var names []string
+	for _, alarm := range alarms {
+		names = append(names, aws.StringValue(alarm.AlarmName))
+	}
+	albctx.GetLogger(ctx).Infof("deleting CloudWatch alarms %v", names)
+	return controller.cloud.DeleteMetricAlarms(ctx, names)
+}
+
+// alarmNamePrefix is the prefix of the names of alarms of LoadBalancer lbID, the generated name of LoadBalancer ends with an hash so prefixes of ingresses don't overlap.
+func alarmNamePrefix(lbID string) string {
+	return lbID + "-"
+}
+
+// buildCloudWatchAlarms builds the baseline alarms of LoadBalancer lbArn and the targetGroups of tgGroup keyed by name:
+// the 5XX count and response time of targets of the LoadBalancer, and the number of unhealthy hosts of each targetGroup.
+func buildCloudWatchAlarms(lbID string, lbArn string, tgGroup tg.TargetGroupGroup, actions []string) map[string]*cloudwatch.PutMetricAlarmInput {
+	lbDimension := &cloudwatch.Dimension{Name: aws.String("LoadBalancer"), Value: aws.String(aws.LoadBalancerDimension(lbArn))}
+	newAlarm := func(name string, metric string, statistic string, operator string, threshold float64, dimensions ...*cloudwatch.Dimension) *cloudwatch.PutMetricAlarmInput {
+		return &cloudwatch.PutMetricAlarmInput{
+			AlarmName:          aws.String(name),
+			AlarmDescription:   aws.String(fmt.Sprintf("%v of %v, managed by the ALB ingress controller", metric, aws.StringValue(dimensions[0].Value))),
+			Namespace:          aws.String(aws.NamespaceApplicationELB),
+			MetricName:         aws.String(metric),
+			Dimensions:         dimensions,
+			Statistic:          aws.String(statistic),
+			Period:             aws.Int64(alarmPeriodSeconds),
+			EvaluationPeriods:  aws.Int64(alarmEvaluationPeriods),
+			Threshold:          aws.Float64(threshold),
+			ComparisonOperator: aws.String(operator),
+			TreatMissingData:   aws.String("notBreaching"),
+			AlarmActions:       aws.StringSlice(actions),
+		}
+	}
+
+	prefix := alarmNamePrefix(lbID)
+	alarms := map[string]*cloudwatch.PutMetricAlarmInput{
+		prefix + "target-5xx": newAlarm(prefix+"target-5xx", aws.MetricHTTPCodeTarget5XX, cloudwatch.StatisticSum,
+			cloudwatch.ComparisonOperatorGreaterThanOrEqualToThreshold, target5XXThreshold, lbDimension),
+		prefix + "target-response-time": newAlarm(prefix+"target-response-time", aws.MetricTargetResponseTime, cloudwatch.StatisticAverage,
+			cloudwatch.ComparisonOperatorGreaterThanThreshold, targetResponseTimeThreshold, lbDimension),
+	}
+	for _, targetGroup := range tgGroup.TGByBackend {
+		tgDimension := aws.TargetGroupDimension(targetGroup.Arn)
+		// the dimension is targetgroup/<name>/<id>, names of targetGroups are unique in the region.
+		tgName := tgDimension
+		if parts := strings.Split(tgDimension, "/"); len(parts) == 3 {
+			tgName = parts[1]
+		}
+		name := prefix + tgName + "-unhealthy-hosts"
+		alarms[name] = newAlarm(name, aws.MetricUnHealthyHostCount, cloudwatch.StatisticMaximum,
+			cloudwatch.ComparisonOperatorGreaterThanOrEqualToThreshold, unhealthyHostsThreshold,
+			&cloudwatch.Dimension{Name: aws.String("TargetGroup"), Value: aws.String(tgDimension)}, lbDimension)
+	}
+	return alarms
+}
+
+// alarmMatches tests whether the settings of alarm are those of desired.
+func alarmMatches(desired *cloudwatch.PutMetricAlarmInput, alarm *cloudwatch.MetricAlarm) bool {
+	if aws.StringValue(desired.MetricName) != aws.StringValue(alarm.MetricName) ||
+		aws.StringValue(desired.Namespace) != aws.StringValue(alarm.Namespace) ||
+		aws.StringValue(desired.Statistic) != aws.StringValue(alarm.Statistic) ||
+		aws.Int64Value(desired.Period) != aws.Int64Value(alarm.Period) ||
+		aws.Int64Value(desired.EvaluationPeriods) != aws.Int64Value(alarm.EvaluationPeriods) ||
+		aws.Float64Value(desired.Threshold) != aws.Float64Value(alarm.Threshold) ||
+		aws.StringValue(desired.ComparisonOperator) != aws.StringValue(alarm.ComparisonOperator) ||
+		aws.StringValue(desired.TreatMissingData) != aws.StringValue(alarm.TreatMissingData) {
+		return false
+	}
+	if !sets.NewString(aws.StringValueSlice(desired.AlarmActions)...).Equal(sets.NewString(aws.StringValueSlice(alarm.AlarmActions)...)) {
+		return false
+	}
+	if len(desired.Dimensions) != len(alarm.Dimensions) {
+		return false
+	}
+	dimensions := make(map[string]string, len(alarm.Dimensions))
+	for _, dimension := range alarm.Dimensions {
+		dimensions[aws.StringValue(dimension.Name)] = aws.StringValue(dimension.Value)
+	}
+	for _, dimension := range desired.Dimensions {
+		if value, ok := dimensions[aws.StringValue(dimension.Name)]; !ok || value != aws.StringValue(dimension.Value) {
+			return false
+		}
+	}
+	return true
+}
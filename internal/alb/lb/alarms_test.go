@@ -0,0 +1,119 @@
+package lb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestBuildCloudWatchAlarms(t *testing.T) {
+	lbArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/web/50dc6c495c0c9188"
+	tgArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/web-tg/73e2d6bc24d8a067"
+	tgGroup := tg.TargetGroupGroup{TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+		{ServiceName: "service", ServicePort: intstr.FromInt(80)}: {Arn: tgArn},
+	}}
+	alarms := buildCloudWatchAlarms("cluster-ns-ingress-1a2b", lbArn, tgGroup, []string{"arn:aws:sns:us-west-2:123456789012:alarms"})
+
+	var names []string
+	for name := range alarms {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{
+		"cluster-ns-ingress-1a2b-target-5xx",
+		"cluster-ns-ingress-1a2b-target-response-time",
+		"cluster-ns-ingress-1a2b-web-tg-unhealthy-hosts",
+	}, names)
+	assert.Equal(t, []*cloudwatch.Dimension{
+		{Name: aws.String("LoadBalancer"), Value: aws.String("app/web/50dc6c495c0c9188")},
+	}, alarms["cluster-ns-ingress-1a2b-target-5xx"].Dimensions)
+	assert.Equal(t, []*cloudwatch.Dimension{
+		{Name: aws.String("TargetGroup"), Value: aws.String("targetgroup/web-tg/73e2d6bc24d8a067")},
+		{Name: aws.String("LoadBalancer"), Value: aws.String("app/web/50dc6c495c0c9188")},
+	}, alarms["cluster-ns-ingress-1a2b-web-tg-unhealthy-hosts"].Dimensions)
+	assert.Equal(t, aws.StringSlice([]string{"arn:aws:sns:us-west-2:123456789012:alarms"}), alarms["cluster-ns-ingress-1a2b-target-response-time"].AlarmActions)
+}
+
+func TestReconcileCloudWatchAlarms(t *testing.T) {
+	lbID := "cluster-ns-ingress-1a2b"
+	lbArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/web/50dc6c495c0c9188"
+	tgGroup := tg.TargetGroupGroup{TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+		{ServiceName: "service", ServicePort: intstr.FromInt(80)}: {Arn: "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/web-tg/73e2d6bc24d8a067"},
+	}}
+	lbTags := map[string]string{"kubernetes.io/ingress-name": "ingress"}
+	desired := buildCloudWatchAlarms(lbID, lbArn, tgGroup, nil)
+	currentAlarm := func(name string) *cloudwatch.MetricAlarm {
+		input := desired[name]
+		return &cloudwatch.MetricAlarm{
+			AlarmName:          input.AlarmName,
+			Namespace:          input.Namespace,
+			MetricName:         input.MetricName,
+			Dimensions:         input.Dimensions,
+			Statistic:          input.Statistic,
+			Period:             input.Period,
+			EvaluationPeriods:  input.EvaluationPeriods,
+			Threshold:          input.Threshold,
+			ComparisonOperator: input.ComparisonOperator,
+			TreatMissingData:   input.TreatMissingData,
+		}
+	}
+	modifiedAlarm := currentAlarm(lbID + "-target-response-time")
+	modifiedAlarm.Threshold = aws.Float64(5)
+
+	for _, tc := range []struct {
+		Name            string
+		Enabled         bool
+		Provisioned     bool
+		CurrentAlarms   []*cloudwatch.MetricAlarm
+		ExpectedPuts    []string
+		ExpectedDeletes []string
+	}{
+		{
+			Name: "disabled and never provisioned",
+		},
+		{
+			Name:            "disabled after being provisioned",
+			Provisioned:     true,
+			CurrentAlarms:   []*cloudwatch.MetricAlarm{currentAlarm(lbID + "-target-5xx")},
+			ExpectedDeletes: []string{lbID + "-target-5xx"},
+		},
+		{
+			Name:    "enabled",
+			Enabled: true,
+			CurrentAlarms: []*cloudwatch.MetricAlarm{
+				currentAlarm(lbID + "-target-5xx"),
+				modifiedAlarm,
+				{AlarmName: aws.String(lbID + "-removed-tg-unhealthy-hosts")},
+			},
+			ExpectedPuts:    []string{lbID + "-target-response-time", lbID + "-web-tg-unhealthy-hosts"},
+			ExpectedDeletes: []string{lbID + "-removed-tg-unhealthy-hosts"},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			if tc.Enabled || tc.Provisioned {
+				cloud.On("GetMetricAlarmsByPrefix", ctx, lbID+"-").Return(tc.CurrentAlarms, nil)
+			}
+			for _, name := range tc.ExpectedPuts {
+				cloud.On("PutMetricAlarm", ctx, desired[name], lbTags).Return(nil)
+			}
+			if len(tc.ExpectedDeletes) != 0 {
+				cloud.On("DeleteMetricAlarms", ctx, tc.ExpectedDeletes).Return(nil)
+			}
+
+			controller := &defaultController{
+				cloud: cloud,
+			}
+			err := controller.reconcileCloudWatchAlarms(ctx, lbID, lbArn, &loadBalancerConfig{Tags: lbTags, CloudWatchAlarms: tc.Enabled}, tgGroup, tc.Provisioned)
+			assert.NoError(t, err)
+			cloud.AssertExpectations(t)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package lb
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
@@ -19,6 +20,11 @@ const (
 	AccessLogsS3PrefixKey        = "access_logs.s3.prefix"
 	IdleTimeoutTimeoutSecondsKey = "idle_timeout.timeout_seconds"
 	RoutingHTTP2EnabledKey       = "routing.http2.enabled"
+	ConnectionLogsS3EnabledKey   = "connection_logs.s3.enabled"
+	ConnectionLogsS3BucketKey    = "connection_logs.s3.bucket"
+	ConnectionLogsS3PrefixKey    = "connection_logs.s3.prefix"
+	DropInvalidHeaderFieldsKey   = "routing.http.drop_invalid_header_fields.enabled"
+	DesyncMitigationModeKey      = "routing.http.desync_mitigation_mode"
 
 	DeletionProtectionEnabled = false
 	AccessLogsS3Enabled       = false
@@ -26,8 +32,29 @@ const (
 	AccessLogsS3Prefix        = ""
 	IdleTimeoutTimeoutSeconds = 60
 	RoutingHTTP2Enabled       = true
+	ConnectionLogsS3Enabled   = false
+	ConnectionLogsS3Bucket    = ""
+	ConnectionLogsS3Prefix    = ""
+	DropInvalidHeaderFields   = false
+	DesyncMitigationMode      = DesyncMitigationModeDefensive
+
+	DesyncMitigationModeMonitor   = "monitor"
+	DesyncMitigationModeDefensive = "defensive"
+	DesyncMitigationModeStrictest = "strictest"
 )
 
+// additionalAttributeDefaults are the defaults of attributes passed through as is, which are reset to them once they're
+// removed from the annotation. Attributes without a default here are left in place once removed.
+var additionalAttributeDefaults = map[string]string{
+	"waf.fail_open.enabled":                                    "false",
+	"routing.http.preserve_host_header.enabled":                "false",
+	"routing.http.x_amzn_tls_version_and_cipher_suite.enabled": "false",
+	"routing.http.xff_client_port.enabled":                     "false",
+	"routing.http.xff_header_processing.mode":                  "append",
+	"client_keep_alive.seconds":                                "3600",
+	"zonal_shift.config.enabled":                               "false",
+}
+
 // Attributes represents the desired state of attributes for a load balancer.
 type Attributes struct {
 	// DeletionProtectionEnabled: deletion_protection.enabled - Indicates whether deletion protection
@@ -55,6 +82,34 @@ type Attributes struct {
 	// RoutingHTTP2Enabled: routing.http2.enabled - Indicates whether HTTP/2 is enabled. The value
 	// is true or false. The default is true.
 	RoutingHTTP2Enabled bool
+
+	// ConnectionLogsS3Enabled: connection_logs.s3.enabled - Indicates whether connection logs are enabled.
+	// The value is true or false. The default is false.
+	ConnectionLogsS3Enabled bool
+
+	// ConnectionLogsS3Bucket: connection_logs.s3.bucket - The name of the S3 bucket for the connection logs.
+	// This attribute is required if connection logs are enabled. The bucket must
+	// exist in the same region as the load balancer and have a bucket policy
+	// that grants Elastic Load Balancing permissions to write to the bucket.
+	ConnectionLogsS3Bucket string
+
+	// ConnectionLogsS3Prefix: connection_logs.s3.prefix - The prefix for the location in the S3 bucket
+	// for the connection logs.
+	ConnectionLogsS3Prefix string
+
+	// DropInvalidHeaderFields: routing.http.drop_invalid_header_fields.enabled - Indicates whether HTTP headers
+	// with invalid header fields are removed by the load balancer. The value is true or false. The default is false.
+	DropInvalidHeaderFields bool
+
+	// DesyncMitigationMode: routing.http.desync_mitigation_mode - Determines how the load balancer handles
+	// requests that might pose a security risk to an application. The value is monitor, defensive or strictest.
+	// The default is defensive.
+	DesyncMitigationMode string
+
+	// Additional contains attributes that are not known by the controller. They're passed through to
+	// ELBV2 as is, so attributes released after the controller can be used right away.
+	// It contains the attributes of additionalAttributeDefaults unless they're set.
+	Additional map[string]string
 }
 
 func NewAttributes(attrs []*elbv2.LoadBalancerAttribute) (a *Attributes, err error) {
@@ -65,6 +120,15 @@ func NewAttributes(attrs []*elbv2.LoadBalancerAttribute) (a *Attributes, err err
 		AccessLogsS3Prefix:        AccessLogsS3Prefix,
 		IdleTimeoutTimeoutSeconds: IdleTimeoutTimeoutSeconds,
 		RoutingHTTP2Enabled:       RoutingHTTP2Enabled,
+		ConnectionLogsS3Enabled:   ConnectionLogsS3Enabled,
+		ConnectionLogsS3Bucket:    ConnectionLogsS3Bucket,
+		ConnectionLogsS3Prefix:    ConnectionLogsS3Prefix,
+		DropInvalidHeaderFields:   DropInvalidHeaderFields,
+		DesyncMitigationMode:      DesyncMitigationMode,
+		Additional:                make(map[string]string),
+	}
+	for k, v := range additionalAttributeDefaults {
+		a.Additional[k] = v
 	}
 	var e error
 	for _, attr := range attrs {
@@ -97,8 +161,32 @@ func NewAttributes(attrs []*elbv2.LoadBalancerAttribute) (a *Attributes, err err
 			if err != nil {
 				return a, fmt.Errorf("invalid load balancer attribute value %s=%s", attrKey, attrValue)
 			}
+		case ConnectionLogsS3EnabledKey:
+			a.ConnectionLogsS3Enabled, err = strconv.ParseBool(attrValue)
+			if err != nil {
+				return a, fmt.Errorf("invalid load balancer attribute value %s=%s", attrKey, attrValue)
+			}
+		case ConnectionLogsS3BucketKey:
+			a.ConnectionLogsS3Bucket = attrValue
+		case ConnectionLogsS3PrefixKey:
+			a.ConnectionLogsS3Prefix = attrValue
+		case DropInvalidHeaderFieldsKey:
+			a.DropInvalidHeaderFields, err = strconv.ParseBool(attrValue)
+			if err != nil {
+				return a, fmt.Errorf("invalid load balancer attribute value %s=%s", attrKey, attrValue)
+			}
+		case DesyncMitigationModeKey:
+			switch attrValue {
+			case DesyncMitigationModeMonitor, DesyncMitigationModeDefensive, DesyncMitigationModeStrictest:
+				a.DesyncMitigationMode = attrValue
+			default:
+				return a, fmt.Errorf("%s must be one of %s, %s or %s", attrKey, DesyncMitigationModeMonitor, DesyncMitigationModeDefensive, DesyncMitigationModeStrictest)
+			}
 		default:
-			e = NewInvalidAttribute(attrKey)
+			if _, ok := additionalAttributeDefaults[attrKey]; !ok {
+				e = NewInvalidAttribute(attrKey)
+			}
+			a.Additional[attrKey] = attrValue
 		}
 	}
 	return a, e
@@ -123,7 +211,7 @@ type attributesController struct {
 
 func (c *attributesController) Reconcile(ctx context.Context, lbArn string, attrs []*elbv2.LoadBalancerAttribute) error {
 	desired, err := NewAttributes(attrs)
-	if err != nil {
+	if err != nil && !IsInvalidAttribute(err) {
 		return fmt.Errorf("failed parsing attributes; %v", err)
 	}
 	raw, err := c.cloud.DescribeLoadBalancerAttributesWithContext(ctx, &elbv2.DescribeLoadBalancerAttributesInput{
@@ -141,6 +229,12 @@ func (c *attributesController) Reconcile(ctx context.Context, lbArn string, attr
 
 	changeSet := attributesChangeSet(current, desired)
 	if len(changeSet) > 0 {
+		if logsChanged(changeSet, AccessLogsS3EnabledKey, AccessLogsS3BucketKey, AccessLogsS3PrefixKey) && desired.AccessLogsS3Enabled {
+			c.validateLogsBucket(ctx, lbArn, "access", desired.AccessLogsS3Bucket, desired.AccessLogsS3Prefix)
+		}
+		if logsChanged(changeSet, ConnectionLogsS3EnabledKey, ConnectionLogsS3BucketKey, ConnectionLogsS3PrefixKey) && desired.ConnectionLogsS3Enabled {
+			c.validateLogsBucket(ctx, lbArn, "connection", desired.ConnectionLogsS3Bucket, desired.ConnectionLogsS3Prefix)
+		}
 		albctx.GetLogger(ctx).Infof("Modifying ELBV2 attributes to %v.", log.Prettify(changeSet))
 		_, err = c.cloud.ModifyLoadBalancerAttributesWithContext(ctx, &elbv2.ModifyLoadBalancerAttributesInput{
 			LoadBalancerArn: aws.String(lbArn),
@@ -181,9 +275,52 @@ func attributesChangeSet(a, b *Attributes) (changeSet []*elbv2.LoadBalancerAttri
 		changeSet = append(changeSet, lbAttribute(RoutingHTTP2EnabledKey, fmt.Sprintf("%v", b.RoutingHTTP2Enabled)))
 	}
 
+	if a.ConnectionLogsS3Enabled != b.ConnectionLogsS3Enabled {
+		changeSet = append(changeSet, lbAttribute(ConnectionLogsS3EnabledKey, fmt.Sprintf("%v", b.ConnectionLogsS3Enabled)))
+	}
+
+	if a.ConnectionLogsS3Bucket != b.ConnectionLogsS3Bucket {
+		changeSet = append(changeSet, lbAttribute(ConnectionLogsS3BucketKey, b.ConnectionLogsS3Bucket))
+	}
+
+	if a.ConnectionLogsS3Prefix != b.ConnectionLogsS3Prefix {
+		changeSet = append(changeSet, lbAttribute(ConnectionLogsS3PrefixKey, b.ConnectionLogsS3Prefix))
+	}
+
+	if a.DropInvalidHeaderFields != b.DropInvalidHeaderFields {
+		changeSet = append(changeSet, lbAttribute(DropInvalidHeaderFieldsKey, fmt.Sprintf("%v", b.DropInvalidHeaderFields)))
+	}
+
+	if a.DesyncMitigationMode != b.DesyncMitigationMode {
+		changeSet = append(changeSet, lbAttribute(DesyncMitigationModeKey, b.DesyncMitigationMode))
+	}
+
+	var additionalKeys []string
+	for k := range b.Additional {
+		additionalKeys = append(additionalKeys, k)
+	}
+	sort.Strings(additionalKeys)
+	for _, k := range additionalKeys {
+		if v, ok := a.Additional[k]; !ok || v != b.Additional[k] {
+			changeSet = append(changeSet, lbAttribute(k, b.Additional[k]))
+		}
+	}
+
 	return
 }
 
+// logsChanged checks whether changeSet modifies any of the logs attribute keys
+func logsChanged(changeSet []*elbv2.LoadBalancerAttribute, keys ...string) bool {
+	for _, attr := range changeSet {
+		for _, key := range keys {
+			if aws.StringValue(attr.Key) == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func lbAttribute(k, v string) *elbv2.LoadBalancerAttribute {
 	return &elbv2.LoadBalancerAttribute{Key: aws.String(k), Value: aws.String(v)}
 }
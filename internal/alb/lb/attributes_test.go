@@ -63,6 +63,21 @@ func Test_NewAttributes(t *testing.T) {
 			ok:         false,
 			attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(RoutingHTTP2EnabledKey, "falfadssdfdsse")},
 		},
+		{
+			name:       fmt.Sprintf("%v is invalid", ConnectionLogsS3EnabledKey),
+			ok:         false,
+			attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(ConnectionLogsS3EnabledKey, "falfadssdfdsse")},
+		},
+		{
+			name:       fmt.Sprintf("%v is invalid", DropInvalidHeaderFieldsKey),
+			ok:         false,
+			attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(DropInvalidHeaderFieldsKey, "falfadssdfdsse")},
+		},
+		{
+			name:       fmt.Sprintf("%v is invalid", DesyncMitigationModeKey),
+			ok:         false,
+			attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(DesyncMitigationModeKey, "paranoid")},
+		},
 		{
 			name:       fmt.Sprintf("undefined attribute"),
 			ok:         false,
@@ -78,6 +93,11 @@ func Test_NewAttributes(t *testing.T) {
 				lbAttribute(AccessLogsS3PrefixKey, "prefix"),
 				lbAttribute(IdleTimeoutTimeoutSecondsKey, "45"),
 				lbAttribute(RoutingHTTP2EnabledKey, "false"),
+				lbAttribute(ConnectionLogsS3EnabledKey, "true"),
+				lbAttribute(ConnectionLogsS3BucketKey, "connection bucket"),
+				lbAttribute(ConnectionLogsS3PrefixKey, "connection prefix"),
+				lbAttribute(DropInvalidHeaderFieldsKey, "true"),
+				lbAttribute(DesyncMitigationModeKey, DesyncMitigationModeStrictest),
 			},
 			output: &Attributes{
 				DeletionProtectionEnabled: true,
@@ -86,6 +106,12 @@ func Test_NewAttributes(t *testing.T) {
 				AccessLogsS3Prefix:        "prefix",
 				IdleTimeoutTimeoutSeconds: 45,
 				RoutingHTTP2Enabled:       false,
+				ConnectionLogsS3Enabled:   true,
+				ConnectionLogsS3Bucket:    "connection bucket",
+				ConnectionLogsS3Prefix:    "connection prefix",
+				DropInvalidHeaderFields:   true,
+				DesyncMitigationMode:      DesyncMitigationModeStrictest,
+				Additional:                additionalAttributeDefaults,
 			},
 		},
 	} {
@@ -166,6 +192,48 @@ func Test_attributesChangeSet(t *testing.T) {
 			b:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute(RoutingHTTP2EnabledKey, "false")}),
 			changeSet: []*elbv2.LoadBalancerAttribute{lbAttribute(RoutingHTTP2EnabledKey, "false")},
 		},
+		{
+			name:      fmt.Sprintf("a contains default, b contains non-default ConnectionLogsS3BucketKey, make a change"),
+			a:         MustNewAttributes(nil),
+			b:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute(ConnectionLogsS3BucketKey, "some bucket")}),
+			changeSet: []*elbv2.LoadBalancerAttribute{lbAttribute(ConnectionLogsS3BucketKey, "some bucket")},
+		},
+		{
+			name:      fmt.Sprintf("a contains default, b contains non-default DropInvalidHeaderFieldsKey, make a change"),
+			a:         MustNewAttributes(nil),
+			b:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute(DropInvalidHeaderFieldsKey, "true")}),
+			changeSet: []*elbv2.LoadBalancerAttribute{lbAttribute(DropInvalidHeaderFieldsKey, "true")},
+		},
+		{
+			name:      fmt.Sprintf("a contains default, b contains non-default DesyncMitigationModeKey, make a change"),
+			a:         MustNewAttributes(nil),
+			b:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute(DesyncMitigationModeKey, DesyncMitigationModeMonitor)}),
+			changeSet: []*elbv2.LoadBalancerAttribute{lbAttribute(DesyncMitigationModeKey, DesyncMitigationModeMonitor)},
+		},
+		{
+			name:      "a contains an unknown attribute, b contains the same value, expect no change",
+			a:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute("new.attribute", "1")}),
+			b:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute("new.attribute", "1")}),
+			changeSet: nil,
+		},
+		{
+			name:      "a contains an unknown attribute, b contains a different value, make a change",
+			a:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute("new.attribute", "1")}),
+			b:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute("new.attribute", "2"), lbAttribute("other.attribute", "true")}),
+			changeSet: []*elbv2.LoadBalancerAttribute{lbAttribute("new.attribute", "2"), lbAttribute("other.attribute", "true")},
+		},
+		{
+			name:      "a contains an attribute passed through, b doesn't, reset it to its default",
+			a:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute("waf.fail_open.enabled", "true"), lbAttribute("client_keep_alive.seconds", "3600")}),
+			b:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{}),
+			changeSet: []*elbv2.LoadBalancerAttribute{lbAttribute("waf.fail_open.enabled", "false")},
+		},
+		{
+			name:      "a contains an unknown attribute without default, b doesn't, expect no change",
+			a:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{lbAttribute("new.attribute", "1")}),
+			b:         MustNewAttributes([]*elbv2.LoadBalancerAttribute{}),
+			changeSet: nil,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			changeSet := attributesChangeSet(tc.a, tc.b)
@@ -199,6 +267,11 @@ func defaultAttributes() []*elbv2.LoadBalancerAttribute {
 		lbAttribute(AccessLogsS3PrefixKey, ""),
 		lbAttribute(IdleTimeoutTimeoutSecondsKey, "60"),
 		lbAttribute(RoutingHTTP2EnabledKey, "true"),
+		lbAttribute(ConnectionLogsS3EnabledKey, "false"),
+		lbAttribute(ConnectionLogsS3BucketKey, ""),
+		lbAttribute(ConnectionLogsS3PrefixKey, ""),
+		lbAttribute(DropInvalidHeaderFieldsKey, "false"),
+		lbAttribute(DesyncMitigationModeKey, DesyncMitigationModeDefensive),
 	}
 }
 
@@ -251,6 +324,74 @@ func TestReconcile(t *testing.T) {
 			},
 			ExpectedError: nil,
 		},
+		{
+			Name:       "start with default attribute set, disable http2",
+			Attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(RoutingHTTP2EnabledKey, "false")},
+			DescribeLoadBalancerAttributesCall: &DescribeLoadBalancerAttributesCall{
+				LbArn:  aws.String("arn"),
+				Output: &elbv2.DescribeLoadBalancerAttributesOutput{Attributes: defaultAttributes()},
+				Err:    nil,
+			},
+			ModifyLoadBalancerAttributesCall: &ModifyLoadBalancerAttributesCall{
+				Input: &elbv2.ModifyLoadBalancerAttributesInput{
+					LoadBalancerArn: aws.String("arn"),
+					Attributes: []*elbv2.LoadBalancerAttribute{
+						lbAttribute("routing.http2.enabled", "false"),
+					},
+				},
+				Err: nil,
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:       "http2 already disabled, no modification",
+			Attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(RoutingHTTP2EnabledKey, "false")},
+			DescribeLoadBalancerAttributesCall: &DescribeLoadBalancerAttributesCall{
+				LbArn:  aws.String("arn"),
+				Output: &elbv2.DescribeLoadBalancerAttributesOutput{Attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(RoutingHTTP2EnabledKey, "false")}},
+				Err:    nil,
+			},
+			ModifyLoadBalancerAttributesCall: nil,
+			ExpectedError:                    nil,
+		},
+		{
+			Name:       "start with default attribute set, pass through an unknown attribute",
+			Attributes: []*elbv2.LoadBalancerAttribute{lbAttribute("waf.fail_open.enabled", "true")},
+			DescribeLoadBalancerAttributesCall: &DescribeLoadBalancerAttributesCall{
+				LbArn:  aws.String("arn"),
+				Output: &elbv2.DescribeLoadBalancerAttributesOutput{Attributes: append(defaultAttributes(), lbAttribute("waf.fail_open.enabled", "false"))},
+				Err:    nil,
+			},
+			ModifyLoadBalancerAttributesCall: &ModifyLoadBalancerAttributesCall{
+				Input: &elbv2.ModifyLoadBalancerAttributesInput{
+					LoadBalancerArn: aws.String("arn"),
+					Attributes: []*elbv2.LoadBalancerAttribute{
+						lbAttribute("waf.fail_open.enabled", "true"),
+					},
+				},
+				Err: nil,
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:       "start with an attribute passed through, reset it once it's removed",
+			Attributes: []*elbv2.LoadBalancerAttribute{},
+			DescribeLoadBalancerAttributesCall: &DescribeLoadBalancerAttributesCall{
+				LbArn:  aws.String("arn"),
+				Output: &elbv2.DescribeLoadBalancerAttributesOutput{Attributes: append(defaultAttributes(), lbAttribute("waf.fail_open.enabled", "true"))},
+				Err:    nil,
+			},
+			ModifyLoadBalancerAttributesCall: &ModifyLoadBalancerAttributesCall{
+				Input: &elbv2.ModifyLoadBalancerAttributesInput{
+					LoadBalancerArn: aws.String("arn"),
+					Attributes: []*elbv2.LoadBalancerAttribute{
+						lbAttribute("waf.fail_open.enabled", "false"),
+					},
+				},
+				Err: nil,
+			},
+			ExpectedError: nil,
+		},
 		{
 			Name:       "start with default attribute set, API throws an error",
 			Attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(IdleTimeoutTimeoutSecondsKey, "120")},
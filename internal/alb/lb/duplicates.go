@@ -0,0 +1,95 @@
+package lb
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// deleteDuplicateLBInstances deletes the LoadBalancers tagged for ingress other than instance and replacedInstance, so there is
+// exactly one LoadBalancer per ingress(two during scheme change), e.g. when the controller crashed while replacing an LoadBalancer.
+// Duplicates are only left by a previous run, so they're looked up on the first reconcile of each ingress since startup, before
+// new resources are created, rather than on every reconcile. The tags of resources are cached for an hour, but not yet at startup.
+func (controller *defaultController) deleteDuplicateLBInstances(ctx context.Context, ingressKey types.NamespacedName, instance *elbv2.LoadBalancer, replacedInstance *elbv2.LoadBalancer) error {
+	if controller.dedupedIngresses.checked(ingressKey) {
+		return nil
+	}
+	taggedInstances, err := controller.findLBsByIngressTags(ctx, ingressKey)
+	if err != nil {
+		return fmt.Errorf("failed to find existing LoadBalancer due to %v", err)
+	}
+	for _, duplicate := range duplicateLBInstances(taggedInstances, instance, replacedInstance) {
+		albctx.GetLogger(ctx).Infof("deleting LoadBalancer %v duplicating %v", aws.StringValue(duplicate.LoadBalancerArn), aws.StringValue(instance.LoadBalancerArn))
+		albctx.GetEventf(ctx)(corev1.EventTypeNormal, "DELETE", "deleting LoadBalancer %v duplicating %v",
+			aws.StringValue(duplicate.LoadBalancerName), aws.StringValue(instance.LoadBalancerName))
+		if err := controller.deleteReplacedLBInstance(ctx, duplicate); err != nil {
+			return fmt.Errorf("failed to delete duplicate LoadBalancer %v due to %v", aws.StringValue(duplicate.LoadBalancerName), err)
+		}
+	}
+	controller.dedupedIngresses.record(ingressKey)
+	return nil
+}
+
+// duplicateLBInstances returns the instances other than those in kept, nil instances in kept are ignored.
+func duplicateLBInstances(instances []*elbv2.LoadBalancer, kept ...*elbv2.LoadBalancer) []*elbv2.LoadBalancer {
+	keptArns := sets.NewString()
+	for _, instance := range kept {
+		if instance != nil {
+			keptArns.Insert(aws.StringValue(instance.LoadBalancerArn))
+		}
+	}
+	var duplicates []*elbv2.LoadBalancer
+	for _, instance := range instances {
+		if !keptArns.Has(aws.StringValue(instance.LoadBalancerArn)) {
+			duplicates = append(duplicates, instance)
+		}
+	}
+	return duplicates
+}
+
+// dedupedIngresses records the ingresses whose duplicate LoadBalancers were deleted since the controller started.
+type dedupedIngresses struct {
+	// mutex protects keys
+	mutex sync.Mutex
+	keys  map[types.NamespacedName]bool
+}
+
+func newDedupedIngresses() *dedupedIngresses {
+	return &dedupedIngresses{keys: make(map[types.NamespacedName]bool)}
+}
+
+// checked tests whether the duplicates of ingress were deleted, which they weren't for ingresses not recorded since the controller started.
+func (d *dedupedIngresses) checked(ingressKey types.NamespacedName) bool {
+	if d == nil {
+		return false
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.keys[ingressKey]
+}
+
+func (d *dedupedIngresses) record(ingressKey types.NamespacedName) {
+	if d == nil {
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.keys[ingressKey] = true
+}
+
+// forget forgets ingress once it's deleted, so an ingress created again with its name is checked again.
+func (d *dedupedIngresses) forget(ingressKey types.NamespacedName) {
+	if d == nil {
+		return
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delete(d.keys, ingressKey)
+}
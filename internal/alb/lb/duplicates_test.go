@@ -0,0 +1,71 @@
+package lb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDuplicateLBInstances(t *testing.T) {
+	lb1 := &elbv2.LoadBalancer{LoadBalancerArn: aws.String("arn:lb1")}
+	lb2 := &elbv2.LoadBalancer{LoadBalancerArn: aws.String("arn:lb2")}
+	lb3 := &elbv2.LoadBalancer{LoadBalancerArn: aws.String("arn:lb3")}
+	for _, tc := range []struct {
+		name       string
+		instances  []*elbv2.LoadBalancer
+		kept       []*elbv2.LoadBalancer
+		duplicates []*elbv2.LoadBalancer
+	}{
+		{
+			name:      "no duplicates",
+			instances: []*elbv2.LoadBalancer{lb1},
+			kept:      []*elbv2.LoadBalancer{lb1, nil},
+		},
+		{
+			name:       "duplicates",
+			instances:  []*elbv2.LoadBalancer{lb1, lb2, lb3},
+			kept:       []*elbv2.LoadBalancer{lb2, nil},
+			duplicates: []*elbv2.LoadBalancer{lb1, lb3},
+		},
+		{
+			name:       "scheme change",
+			instances:  []*elbv2.LoadBalancer{lb1, lb2, lb3},
+			kept:       []*elbv2.LoadBalancer{lb2, lb1},
+			duplicates: []*elbv2.LoadBalancer{lb3},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.duplicates, duplicateLBInstances(tc.instances, tc.kept...))
+		})
+	}
+}
+
+func Test_deleteDuplicateLBInstances(t *testing.T) {
+	ctx := context.Background()
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	lbArn := "arn:lb1"
+	instance := &elbv2.LoadBalancer{LoadBalancerArn: aws.String(lbArn)}
+
+	nameTagGen := &MockNameTagGenerator{}
+	nameTagGen.On("TagLB", "namespace", "ingress").Return(map[string]string{"kubernetes.io/ingress-name": "ingress"})
+	cloud := &mocks.CloudAPI{}
+	cloud.On("GetResourcesByFilters", ctx, map[string][]string{"kubernetes.io/ingress-name": {"ingress"}}, aws.ResourceTypeEnumELBLoadBalancer).Return([]string{lbArn}, nil).Once()
+	cloud.On("GetLoadBalancerByArn", ctx, lbArn).Return(instance, nil).Once()
+
+	controller := &defaultController{
+		cloud:            cloud,
+		nameTagGen:       nameTagGen,
+		dedupedIngresses: newDedupedIngresses(),
+	}
+	// duplicates are only looked up on the first reconcile since startup, and again once the ingress is deleted.
+	assert.NoError(t, controller.deleteDuplicateLBInstances(ctx, ingressKey, instance, nil))
+	assert.NoError(t, controller.deleteDuplicateLBInstances(ctx, ingressKey, instance, nil))
+	cloud.AssertExpectations(t)
+	controller.dedupedIngresses.forget(ingressKey)
+	assert.False(t, controller.dedupedIngresses.checked(ingressKey))
+}
@@ -0,0 +1,198 @@
+package lb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/sg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/lock"
+	api "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// existingLBLocks serializes reconciling the listeners and rules of pre-provisioned LoadBalancers, which can be used by multiple ingresses reconciled concurrently.
+var existingLBLocks = lock.NewKeyedMutex()
+
+// existingLBs records the pre-provisioned LoadBalancers ingresses are reconciled on, so only deleting those ingresses looks up the resources to clean up on them,
+// even after their annotations are gone with the ingress. Ingresses are all reconciled when the controller starts, which records them again.
+type existingLBs struct {
+	// mutex protects lbArns, the ARN of the pre-provisioned LoadBalancer by ingress
+	mutex  sync.Mutex
+	lbArns map[types.NamespacedName]string
+}
+
+func newExistingLBs() *existingLBs {
+	return &existingLBs{lbArns: make(map[types.NamespacedName]string)}
+}
+
+func (r *existingLBs) record(ingressKey types.NamespacedName, lbArn string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.lbArns[ingressKey] = lbArn
+}
+
+func (r *existingLBs) get(ingressKey types.NamespacedName) (string, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	lbArn, ok := r.lbArns[ingressKey]
+	return lbArn, ok
+}
+
+func (r *existingLBs) forget(ingressKey types.NamespacedName) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.lbArns, ingressKey)
+}
+
+// reconcileExistingLB reconciles ingress on an pre-provisioned LoadBalancer specified by the existing-load-balancer annotation.
+// Only listeners, rules and targetGroups are managed, the LoadBalancer itself(attributes, WAF, securityGroups, etc.) is left untouched.
+func (controller *defaultController) reconcileExistingLB(ctx context.Context, ingress *extensions.Ingress, ingressAnnos *annotations.Ingress) (*LoadBalancer, error) {
+	lbArnOrName := aws.StringValue(ingressAnnos.LoadBalancer.ExistingLoadBalancer)
+	instance, err := controller.findExistingLB(ctx, lbArnOrName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing LoadBalancer %v due to %v", lbArnOrName, err)
+	}
+	if instance == nil {
+		albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "existing LoadBalancer %v doesn't exist", lbArnOrName)
+		return nil, fmt.Errorf("existing LoadBalancer %v doesn't exist", lbArnOrName)
+	}
+	lbArn := aws.StringValue(instance.LoadBalancerArn)
+	controller.existingLBs.record(types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}, lbArn)
+	unlock := existingLBLocks.Lock(lbArn)
+	defer unlock()
+
+	tgGroup, err := controller.reconcileListenersAndTGs(ctx, lbArn, ingress)
+	if err != nil {
+		return nil, err
+	}
+	// the securityGroups of existing LoadBalancer are treated as user provided securityGroups, which are never modified.
+	lbID := controller.nameTagGen.NameLB(ingress.Namespace, ingress.Name)
+	securityGroups := aws.StringValueSlice(instance.SecurityGroups)
+	if err := controller.reconcileSGAssociation(ctx, lbID, lbArn, ingress, ingressAnnos, securityGroups, tgGroup); err != nil {
+		return nil, err
+	}
+	return buildLoadBalancer(lbArn, instance), nil
+}
+
+// findExistingLB finds LoadBalancer by ARN or name.
+func (controller *defaultController) findExistingLB(ctx context.Context, lbArnOrName string) (*elbv2.LoadBalancer, error) {
+	if strings.HasPrefix(lbArnOrName, "arn:") {
+		return controller.cloud.GetLoadBalancerByArn(ctx, lbArnOrName)
+	}
+	return controller.cloud.GetLoadBalancerByName(ctx, lbArnOrName)
+}
+
+// findExistingLBOfIngress returns the pre-provisioned LoadBalancer ingress is reconciled on, adopted is false if ingress doesn't use one.
+// lbArn is empty if the LoadBalancer doesn't exist anymore.
+func (controller *defaultController) findExistingLBOfIngress(ctx context.Context, ingressKey types.NamespacedName) (lbArn string, adopted bool, err error) {
+	if lbArn, ok := controller.existingLBs.get(ingressKey); ok {
+		return lbArn, true, nil
+	}
+	// ingresses being finalized aren't reconciled after controller restarts, their annotations are still there.
+	ingressAnnos, err := controller.store.GetIngressAnnotations(ingressKey.String())
+	if err != nil || ingressAnnos.LoadBalancer == nil || ingressAnnos.LoadBalancer.ExistingLoadBalancer == nil {
+		return "", false, nil
+	}
+	instance, err := controller.findExistingLB(ctx, aws.StringValue(ingressAnnos.LoadBalancer.ExistingLoadBalancer))
+	if err != nil {
+		return "", true, fmt.Errorf("failed to find existing LoadBalancer %v due to %v", aws.StringValue(ingressAnnos.LoadBalancer.ExistingLoadBalancer), err)
+	}
+	if instance == nil {
+		return "", true, nil
+	}
+	return aws.StringValue(instance.LoadBalancerArn), true, nil
+}
+
+// cleanupExistingLBs removes the listeners, rules and targetGroups created for ingress on pre-provisioned LoadBalancer lbArn, which is never deleted.
+// Only the targetGroups are removed if lbArn is empty since the LoadBalancer is gone.
+func (controller *defaultController) cleanupExistingLBs(ctx context.Context, ingressKey types.NamespacedName, lbArn string) error {
+	if lbArn != "" {
+		lbID := controller.nameTagGen.NameLB(ingressKey.Namespace, ingressKey.Name)
+		if err := controller.cleanupExistingLB(ctx, lbID, lbArn, ingressKey); err != nil {
+			return err
+		}
+	}
+	if err := controller.tgGroupController.Delete(ctx, ingressKey); err != nil {
+		return fmt.Errorf("failed to GC targetGroups due to %v", err)
+	}
+	controller.existingLBs.forget(ingressKey)
+	return nil
+}
+
+// cleanupExistingLB removes the securityGroup rules, listeners and rules of ingress from pre-provisioned LoadBalancer.
+func (controller *defaultController) cleanupExistingLB(ctx context.Context, lbID string, lbArn string, ingressKey types.NamespacedName) error {
+	unlock := existingLBLocks.Lock(lbArn)
+	defer unlock()
+	if err := controller.sgAssociationController.Delete(ctx, &sg.Association{
+		LbID:       lbID,
+		LbArn:      lbArn,
+		IngressKey: ingressKey.String(),
+	}); err != nil {
+		return fmt.Errorf("failed to clean up securityGroups due to %v", err)
+	}
+	if err := controller.deleteOwnedListenersAndRules(ctx, lbArn, ingressKey); err != nil {
+		return fmt.Errorf("failed to delete listeners due to %v", err)
+	}
+	return nil
+}
+
+// deleteOwnedListenersAndRules deletes the listeners and rules on LoadBalancer that are tagged for ingress,
+// along with the rules of listeners deleted. Listeners and rules managed by others are left untouched.
+func (controller *defaultController) deleteOwnedListenersAndRules(ctx context.Context, lbArn string, ingressKey types.NamespacedName) error {
+	listeners, err := controller.cloud.ListListenersByLoadBalancer(ctx, lbArn)
+	if err != nil {
+		return err
+	}
+	var lsArns []string
+	for _, listener := range listeners {
+		lsArns = append(lsArns, aws.StringValue(listener.ListenerArn))
+	}
+	lsTags, err := tags.DescribeELBV2TagsOfArns(ctx, controller.cloud, lsArns)
+	if err != nil {
+		return err
+	}
+	lsOwnerTags := controller.nameTagGen.TagListener(ingressKey.Namespace, ingressKey.Name)
+	ruleOwnerTags := controller.nameTagGen.TagRule(ingressKey.Namespace, ingressKey.Name)
+	for _, listener := range listeners {
+		lsArn := aws.StringValue(listener.ListenerArn)
+		if t, ok := lsTags[lsArn]; ok && t.Contains(lsOwnerTags) {
+			albctx.GetLogger(ctx).Infof("deleting listener %v on existing LoadBalancer %v", lsArn, lbArn)
+			if err := controller.cloud.DeleteListenersByArn(ctx, lsArn); err != nil {
+				return err
+			}
+			continue
+		}
+		rules, err := controller.cloud.GetRules(ctx, lsArn)
+		if err != nil {
+			return err
+		}
+		var ruleArns []string
+		for _, rule := range rules {
+			if !aws.BoolValue(rule.IsDefault) {
+				ruleArns = append(ruleArns, aws.StringValue(rule.RuleArn))
+			}
+		}
+		ruleTags, err := tags.DescribeELBV2TagsOfArns(ctx, controller.cloud, ruleArns)
+		if err != nil {
+			return err
+		}
+		for _, ruleArn := range ruleArns {
+			if t, ok := ruleTags[ruleArn]; !ok || !t.Contains(ruleOwnerTags) {
+				continue
+			}
+			albctx.GetLogger(ctx).Infof("deleting rule %v on existing LoadBalancer %v", ruleArn, lbArn)
+			if _, err := controller.cloud.DeleteRuleWithContext(ctx, &elbv2.DeleteRuleInput{RuleArn: aws.String(ruleArn)}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
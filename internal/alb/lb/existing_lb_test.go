@@ -0,0 +1,280 @@
+package lb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/ls"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/sg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const existingLBArn = "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/shared/50dc6c495c0c9188"
+
+var ingressOwnerTags = map[string]string{"kubernetes.io/namespace": "namespace", "kubernetes.io/ingress-name": "ingress"}
+
+func ownerELBV2Tags() []*elbv2.Tag {
+	return []*elbv2.Tag{
+		{Key: aws.String("kubernetes.io/ingress-name"), Value: aws.String("ingress")},
+		{Key: aws.String("kubernetes.io/namespace"), Value: aws.String("namespace")},
+	}
+}
+
+func TestReconcileExistingLB(t *testing.T) {
+	ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	ingressAnnos := &annotations.Ingress{
+		LoadBalancer: &loadbalancer.Config{
+			ExistingLoadBalancer: aws.String("shared"),
+			Ports:                []loadbalancer.PortData{{Port: 80, Scheme: elbv2.ProtocolEnumHttp}},
+			InboundCidrs:         []string{"0.0.0.0/0"},
+		},
+	}
+	for _, tc := range []struct {
+		Name                 string
+		Instance             *elbv2.LoadBalancer
+		ExpectedLoadBalancer *LoadBalancer
+		ExpectedError        error
+	}{
+		{
+			Name: "listeners and securityGroup rules are reconciled on existing LoadBalancer",
+			Instance: &elbv2.LoadBalancer{
+				LoadBalancerArn:       aws.String(existingLBArn),
+				DNSName:               aws.String("shared-1234567890.us-west-2.elb.amazonaws.com"),
+				CanonicalHostedZoneId: aws.String("Z1H1FL5HABSF5"),
+				SecurityGroups:        aws.StringSlice([]string{"sg-1"}),
+			},
+			ExpectedLoadBalancer: &LoadBalancer{
+				Arn:                   existingLBArn,
+				DNSName:               "shared-1234567890.us-west-2.elb.amazonaws.com",
+				DualstackDNSName:      "dualstack.shared-1234567890.us-west-2.elb.amazonaws.com",
+				CanonicalHostedZoneID: "Z1H1FL5HABSF5",
+			},
+		},
+		{
+			Name:          "existing LoadBalancer doesn't exist",
+			ExpectedError: errors.New("existing LoadBalancer shared doesn't exist"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			cloud.On("GetLoadBalancerByName", ctx, "shared").Return(tc.Instance, nil)
+			mockStore := &store.MockStorer{}
+			mockNameTagGen := &MockNameTagGenerator{}
+			mockTGGroupController := &tg.MockGroupController{}
+			mockLSGroupController := &ls.MockGroupController{}
+			mockSGAssociationController := &sg.MockAssociationController{}
+			tgGroup := tg.TargetGroupGroup{}
+			if tc.Instance != nil {
+				mockStore.On("GetConfig").Return(&config.Configuration{})
+				mockNameTagGen.On("NameLB", "namespace", "ingress").Return("lbID")
+				mockTGGroupController.On("Reconcile", ctx, ingress).Return(tgGroup, nil)
+				mockTGGroupController.On("GC", ctx, tgGroup).Return(nil)
+				mockLSGroupController.On("Reconcile", ctx, existingLBArn, ingress, tgGroup).Return(nil)
+				mockSGAssociationController.On("Reconcile", ctx, &sg.Association{
+					LbID:           "lbID",
+					LbArn:          existingLBArn,
+					LbPorts:        []int64{80},
+					LbInboundCIDRs: []string{"0.0.0.0/0"},
+					ExternalSGIDs:  []string{"sg-1"},
+					IngressKey:     "namespace/ingress",
+					Tags:           map[string]string{},
+					TGGroup:        tgGroup,
+				}).Return(nil)
+			}
+
+			controller := &defaultController{
+				cloud:                   cloud,
+				store:                   mockStore,
+				nameTagGen:              mockNameTagGen,
+				tgGroupController:       mockTGGroupController,
+				lsGroupController:       mockLSGroupController,
+				sgAssociationController: mockSGAssociationController,
+				existingLBs:             newExistingLBs(),
+			}
+			lb, err := controller.reconcileExistingLB(ctx, ingress, ingressAnnos)
+			assert.Equal(t, tc.ExpectedLoadBalancer, lb)
+			assert.Equal(t, tc.ExpectedError, err)
+			_, adopted := controller.existingLBs.get(types.NamespacedName{Namespace: "namespace", Name: "ingress"})
+			assert.Equal(t, tc.Instance != nil, adopted)
+			cloud.AssertExpectations(t)
+			mockTGGroupController.AssertExpectations(t)
+			mockLSGroupController.AssertExpectations(t)
+			mockSGAssociationController.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFindExistingLBOfIngress(t *testing.T) {
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	for _, tc := range []struct {
+		Name            string
+		RecordedLBArn   string
+		IngressAnnos    *annotations.Ingress
+		IngressAnnosErr error
+		Instance        *elbv2.LoadBalancer
+		ExpectedLBArn   string
+		ExpectedAdopted bool
+	}{
+		{
+			Name:            "recorded LoadBalancer is returned without lookups",
+			RecordedLBArn:   existingLBArn,
+			ExpectedLBArn:   existingLBArn,
+			ExpectedAdopted: true,
+		},
+		{
+			Name: "LoadBalancer is found by the annotation of ingress being finalized",
+			IngressAnnos: &annotations.Ingress{
+				LoadBalancer: &loadbalancer.Config{ExistingLoadBalancer: aws.String("shared")},
+			},
+			Instance:        &elbv2.LoadBalancer{LoadBalancerArn: aws.String(existingLBArn)},
+			ExpectedLBArn:   existingLBArn,
+			ExpectedAdopted: true,
+		},
+		{
+			Name: "LoadBalancer of the annotation is gone",
+			IngressAnnos: &annotations.Ingress{
+				LoadBalancer: &loadbalancer.Config{ExistingLoadBalancer: aws.String("shared")},
+			},
+			ExpectedAdopted: true,
+		},
+		{
+			Name:         "ingress without the annotation",
+			IngressAnnos: &annotations.Ingress{LoadBalancer: &loadbalancer.Config{}},
+		},
+		{
+			Name:            "deleted ingress not recorded",
+			IngressAnnosErr: errors.New("ingress annotations not found"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			mockStore := &store.MockStorer{}
+			controller := &defaultController{
+				cloud:       cloud,
+				store:       mockStore,
+				existingLBs: newExistingLBs(),
+			}
+			if tc.RecordedLBArn != "" {
+				controller.existingLBs.record(ingressKey, tc.RecordedLBArn)
+			} else {
+				mockStore.On("GetIngressAnnotations", "namespace/ingress").Return(tc.IngressAnnos, tc.IngressAnnosErr)
+			}
+			if tc.IngressAnnos != nil && tc.IngressAnnos.LoadBalancer.ExistingLoadBalancer != nil {
+				cloud.On("GetLoadBalancerByName", ctx, "shared").Return(tc.Instance, nil)
+			}
+
+			lbArn, adopted, err := controller.findExistingLBOfIngress(ctx, ingressKey)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ExpectedLBArn, lbArn)
+			assert.Equal(t, tc.ExpectedAdopted, adopted)
+			cloud.AssertExpectations(t)
+			mockStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCleanupExistingLBs(t *testing.T) {
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	for _, tc := range []struct {
+		Name  string
+		LBArn string
+	}{
+		{
+			Name:  "listeners, rules and targetGroups of ingress are deleted",
+			LBArn: existingLBArn,
+		},
+		{
+			Name: "only targetGroups are deleted when LoadBalancer is gone",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			mockNameTagGen := &MockNameTagGenerator{}
+			mockTGGroupController := &tg.MockGroupController{}
+			mockSGAssociationController := &sg.MockAssociationController{}
+			mockTGGroupController.On("Delete", ctx, ingressKey).Return(nil)
+			if tc.LBArn != "" {
+				mockNameTagGen.On("NameLB", "namespace", "ingress").Return("lbID")
+				mockNameTagGen.On("TagListener", "namespace", "ingress").Return(ingressOwnerTags)
+				mockNameTagGen.On("TagRule", "namespace", "ingress").Return(ingressOwnerTags)
+				mockSGAssociationController.On("Delete", ctx, &sg.Association{
+					LbID:       "lbID",
+					LbArn:      tc.LBArn,
+					IngressKey: "namespace/ingress",
+				}).Return(nil)
+				cloud.On("ListListenersByLoadBalancer", ctx, tc.LBArn).Return([]*elbv2.Listener{}, nil)
+			}
+
+			controller := &defaultController{
+				cloud:                   cloud,
+				nameTagGen:              mockNameTagGen,
+				tgGroupController:       mockTGGroupController,
+				sgAssociationController: mockSGAssociationController,
+				existingLBs:             newExistingLBs(),
+			}
+			controller.existingLBs.record(ingressKey, tc.LBArn)
+			err := controller.cleanupExistingLBs(ctx, ingressKey, tc.LBArn)
+			assert.NoError(t, err)
+			_, adopted := controller.existingLBs.get(ingressKey)
+			assert.False(t, adopted)
+			cloud.AssertExpectations(t)
+			mockNameTagGen.AssertExpectations(t)
+			mockTGGroupController.AssertExpectations(t)
+			mockSGAssociationController.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteOwnedListenersAndRules(t *testing.T) {
+	ctx := context.Background()
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	cloud := &mocks.CloudAPI{}
+	cloud.On("ListListenersByLoadBalancer", ctx, existingLBArn).Return([]*elbv2.Listener{
+		{ListenerArn: aws.String("lsArn1"), Port: aws.Int64(8080)},
+		{ListenerArn: aws.String("lsArn2"), Port: aws.Int64(443)},
+	}, nil)
+	cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{"lsArn1", "lsArn2"})}).Return(&elbv2.DescribeTagsOutput{
+		TagDescriptions: []*elbv2.TagDescription{
+			{ResourceArn: aws.String("lsArn1"), Tags: ownerELBV2Tags()},
+			{ResourceArn: aws.String("lsArn2"), Tags: []*elbv2.Tag{{Key: aws.String("team"), Value: aws.String("network")}}},
+		},
+	}, nil)
+	cloud.On("DeleteListenersByArn", ctx, "lsArn1").Return(nil)
+	cloud.On("GetRules", ctx, "lsArn2").Return([]*elbv2.Rule{
+		{RuleArn: aws.String("defaultRuleArn"), IsDefault: aws.Bool(true)},
+		{RuleArn: aws.String("ruleArn1"), IsDefault: aws.Bool(false)},
+		{RuleArn: aws.String("ruleArn2"), IsDefault: aws.Bool(false)},
+	}, nil)
+	cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{"ruleArn1", "ruleArn2"})}).Return(&elbv2.DescribeTagsOutput{
+		TagDescriptions: []*elbv2.TagDescription{
+			{ResourceArn: aws.String("ruleArn1"), Tags: ownerELBV2Tags()},
+		},
+	}, nil)
+	cloud.On("DeleteRuleWithContext", ctx, &elbv2.DeleteRuleInput{RuleArn: aws.String("ruleArn1")}).Return(&elbv2.DeleteRuleOutput{}, nil)
+	mockNameTagGen := &MockNameTagGenerator{}
+	mockNameTagGen.On("TagListener", "namespace", "ingress").Return(ingressOwnerTags)
+	mockNameTagGen.On("TagRule", "namespace", "ingress").Return(ingressOwnerTags)
+
+	controller := &defaultController{
+		cloud:      cloud,
+		nameTagGen: mockNameTagGen,
+	}
+	err := controller.deleteOwnedListenersAndRules(ctx, existingLBArn, ingressKey)
+	assert.NoError(t, err)
+	cloud.AssertExpectations(t)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/dns"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/ls"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/sg"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
@@ -15,7 +16,9 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
 	util "github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/types"
@@ -32,6 +35,9 @@ type Controller interface {
 
 	// Deletes will ensure no LoadBalancer exists for specified ingressKey.
 	Delete(ctx context.Context, ingressKey types.NamespacedName) error
+
+	// ListPartitions finds the keys of partitions that have LoadBalancer created, for ingress split by host.
+	ListPartitions(ctx context.Context, ingressKey types.NamespacedName) ([]types.NamespacedName, error)
 }
 
 func NewController(
@@ -40,7 +46,10 @@ func NewController(
 	nameTagGen NameTagGenerator,
 	tgGroupController tg.GroupController,
 	lsGroupController ls.GroupController,
-	sgAssociationController sg.AssociationController) Controller {
+	sgAssociationController sg.AssociationController,
+	tagsController tags.Controller,
+	dnsController dns.Controller,
+	metricCollector metric.Collector) Controller {
 	attrsController := NewAttributesController(cloud)
 
 	return &defaultController{
@@ -50,7 +59,13 @@ func NewController(
 		tgGroupController:       tgGroupController,
 		lsGroupController:       lsGroupController,
 		sgAssociationController: sgAssociationController,
+		tagsController:          tagsController,
 		attrsController:         attrsController,
+		dnsController:           dnsController,
+		metricCollector:         metricCollector,
+		accountLimits:           &accountLimits{},
+		existingLBs:             newExistingLBs(),
+		dedupedIngresses:        newDedupedIngresses(),
 	}
 }
 
@@ -62,6 +77,18 @@ type loadBalancerConfig struct {
 	Scheme        *string
 	IpAddressType *string
 	Subnets       []string
+
+	CustomerOwnedIPv4Pool *string
+
+	// AllowSchemeChange allows to replace the LoadBalancer when scheme changes
+	AllowSchemeChange bool
+
+	// WebACLID is the webACL associated with the LoadBalancer, which is the default webACL of controller for internet-facing LoadBalancers without one
+	WebACLID *string
+
+	// CloudWatchAlarms provisions baseline CloudWatch alarms for the LoadBalancer and its targetGroups, notifying CloudWatchAlarmActions
+	CloudWatchAlarms       bool
+	CloudWatchAlarmActions []string
 }
 
 type defaultController struct {
@@ -72,16 +99,28 @@ type defaultController struct {
 	tgGroupController       tg.GroupController
 	lsGroupController       ls.GroupController
 	sgAssociationController sg.AssociationController
+	tagsController          tags.Controller
 	attrsController         AttributesController
+	dnsController           dns.Controller
+
+	metricCollector metric.Collector
+	accountLimits   *accountLimits
+	existingLBs     *existingLBs
+
+	// dedupedIngresses records ingresses whose duplicate LoadBalancers were deleted
+	dedupedIngresses *dedupedIngresses
 }
 
 var _ Controller = (*defaultController)(nil)
 
 func (controller *defaultController) Reconcile(ctx context.Context, ingress *extensions.Ingress) (*LoadBalancer, error) {
-	ingressAnnos, err := controller.store.GetIngressAnnotations(k8s.MetaNamespaceKey(ingress))
+	ingressAnnos, err := controller.store.GetIngressAnnotations(k8s.IngressAnnotationsKey(ingress))
 	if err != nil {
 		return nil, err
 	}
+	if ingressAnnos.LoadBalancer.ExistingLoadBalancer != nil {
+		return controller.reconcileExistingLB(ctx, ingress, ingressAnnos)
+	}
 	lbConfig, err := controller.buildLBConfig(ctx, ingress, ingressAnnos)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build LoadBalancer configuration due to %v", err)
@@ -89,74 +128,178 @@ func (controller *defaultController) Reconcile(ctx context.Context, ingress *ext
 	if err := controller.validateLBConfig(ctx, ingress, lbConfig); err != nil {
 		return nil, err
 	}
+	if err := controller.checkQuotas(ctx, ingress, ingressAnnos); err != nil {
+		return nil, err
+	}
 
-	instance, err := controller.ensureLBInstance(ctx, lbConfig)
+	instance, err := controller.ensureLBInstance(ctx, ingress, lbConfig)
 	if err != nil {
 		return nil, err
 	}
 	lbArn := aws.StringValue(instance.LoadBalancerArn)
+	// the tag of provisioned alarms is reconciled with the other tags, so whether alarms are left to clean up is checked beforehand.
+	alarmsProvisioned, err := controller.hasCloudWatchAlarms(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	if err := controller.reconcileLBTags(ctx, lbArn, lbConfig, ingressAnnos.LoadBalancer.RetainOnDelete); err != nil {
+		return nil, fmt.Errorf("failed to reconcile LoadBalancer tags due to %v", err)
+	}
+	if err := controller.ensureAccessLogsBucket(ctx, lbArn, ingressAnnos.LoadBalancer.Attributes); err != nil {
+		return nil, fmt.Errorf("failed to provision access logs bucket due to %v", err)
+	}
 	if err := controller.attrsController.Reconcile(ctx, lbArn, ingressAnnos.LoadBalancer.Attributes); err != nil {
 		return nil, fmt.Errorf("failed to reconcile attributes of %v due to %v", lbArn, err)
 	}
-	if err := controller.reconcileWAF(ctx, lbArn, ingressAnnos.LoadBalancer.WebACLId); err != nil {
+	if err := controller.reconcileWAF(ctx, lbArn, lbConfig.WebACLID); err != nil {
 		return nil, err
 	}
 
-	tgGroup, err := controller.tgGroupController.Reconcile(ctx, ingress)
+	tgGroup, err := controller.reconcileListenersAndTGs(ctx, lbArn, ingress)
 	if err != nil {
-		return nil, fmt.Errorf("failed to reconcile targetGroups due to %v", err)
-	}
-	if err := controller.lsGroupController.Reconcile(ctx, lbArn, ingress, tgGroup); err != nil {
-		return nil, fmt.Errorf("failed to reconcile listeners due to %v", err)
+		return nil, err
 	}
-	if err := controller.tgGroupController.GC(ctx, tgGroup); err != nil {
-		return nil, fmt.Errorf("failed to GC targetGroups due to %v", err)
+	// securityGroups and alarms are always named after the generated LoadBalancer name, so they can be found when ingress is deleted.
+	lbID := controller.nameTagGen.NameLB(ingress.Namespace, ingress.Name)
+	if err := controller.reconcileCloudWatchAlarms(ctx, lbID, lbArn, lbConfig, tgGroup, alarmsProvisioned); err != nil {
+		return nil, fmt.Errorf("failed to reconcile CloudWatch alarms due to %v", err)
 	}
 
 	securityGroups, err := controller.resolveSecurityGroupNames(ctx, ingressAnnos.LoadBalancer.SecurityGroups)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve security group names due to %v", err)
 	}
+	if err := controller.reconcileSGAssociation(ctx, lbID, lbArn, ingress, ingressAnnos, securityGroups, tgGroup); err != nil {
+		return nil, err
+	}
+	// records point at the LoadBalancer once it's ready to serve the hosts of ingress.
+	if err := controller.dnsController.Reconcile(ctx, ingress, instance); err != nil {
+		return nil, fmt.Errorf("failed to reconcile DNS records due to %v", err)
+	}
+	return buildLoadBalancer(lbArn, instance), nil
+}
 
+func (controller *defaultController) reconcileListenersAndTGs(ctx context.Context, lbArn string, ingress *extensions.Ingress) (tg.TargetGroupGroup, error) {
+	tgGroup, err := controller.tgGroupController.Reconcile(ctx, ingress)
+	if err != nil {
+		return tgGroup, fmt.Errorf("failed to reconcile targetGroups due to %v", err)
+	}
+	if err := controller.lsGroupController.Reconcile(ctx, lbArn, ingress, tgGroup); err != nil {
+		return tgGroup, fmt.Errorf("failed to reconcile listeners due to %v", err)
+	}
+	if err := controller.tgGroupController.GC(ctx, tgGroup); err != nil {
+		return tgGroup, fmt.Errorf("failed to GC targetGroups due to %v", err)
+	}
+	return tgGroup, nil
+}
+
+func (controller *defaultController) reconcileSGAssociation(ctx context.Context, lbID string, lbArn string, ingress *extensions.Ingress,
+	ingressAnnos *annotations.Ingress, securityGroups []string, tgGroup tg.TargetGroupGroup) error {
 	lbPorts := []int64{}
 	for _, port := range ingressAnnos.LoadBalancer.Ports {
 		lbPorts = append(lbPorts, port.Port)
 	}
+	sgTags, err := tags.BuildControllerTags(controller.store, ingress)
+	if err != nil {
+		return err
+	}
 	if err := controller.sgAssociationController.Reconcile(ctx, &sg.Association{
-		LbID:           lbConfig.Name,
-		LbArn:          lbArn,
-		LbPorts:        lbPorts,
-		LbInboundCIDRs: ingressAnnos.LoadBalancer.InboundCidrs,
-		ExternalSGIDs:  securityGroups,
-		TGGroup:        tgGroup,
+		LbID:                 lbID,
+		LbArn:                lbArn,
+		LbPorts:              lbPorts,
+		LbInboundCIDRs:       ingressAnnos.LoadBalancer.InboundCidrs,
+		ExternalSGIDs:        securityGroups,
+		ManageBackendSGRules: ingressAnnos.LoadBalancer.ManageBackendSecurityGroupRules,
+		IngressKey:           k8s.MetaNamespaceKey(ingress),
+		Tags:                 sgTags,
+		TGGroup:              tgGroup,
 	}); err != nil {
-		return nil, fmt.Errorf("failed to reconcile securityGroup associations due to %v", err)
+		return fmt.Errorf("failed to reconcile securityGroup associations due to %v", err)
 	}
-	return &LoadBalancer{
-		Arn:     lbArn,
-		DNSName: aws.StringValue(instance.DNSName),
-	}, nil
+	return nil
 }
 
 func (controller *defaultController) Delete(ctx context.Context, ingressKey types.NamespacedName) error {
+	controller.dedupedIngresses.forget(ingressKey)
+	existingLBArn, adopted, err := controller.findExistingLBOfIngress(ctx, ingressKey)
+	if err != nil {
+		return err
+	}
+	if adopted {
+		return controller.cleanupExistingLBs(ctx, ingressKey, existingLBArn)
+	}
 	lbName := controller.nameTagGen.NameLB(ingressKey.Namespace, ingressKey.Name)
 	instance, err := controller.cloud.GetLoadBalancerByName(ctx, lbName)
 	if err != nil {
 		return fmt.Errorf("failed to find existing LoadBalancer due to %v", err)
 	}
+	// LoadBalancers with custom name can only be found by tags, and there are two LoadBalancers during scheme change.
+	taggedInstances, err := controller.findLBsByIngressTags(ctx, ingressKey)
+	if err != nil {
+		return fmt.Errorf("failed to find existing LoadBalancer due to %v", err)
+	}
+	if instance == nil && len(taggedInstances) != 0 {
+		instance = taggedInstances[0]
+	}
+	retain, err := controller.isRetainedOnDelete(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if retain {
+		return controller.orphanResources(ctx, ingressKey, instance)
+	}
+	if instance != nil && !controller.store.GetConfig().DisableDeletionProtectionOnDelete {
+		protected, err := controller.isDeletionProtected(ctx, aws.StringValue(instance.LoadBalancerArn))
+		if err != nil {
+			return err
+		}
+		// the resources are left in place so the ingress can be removed, rather than failing its deletion until protection is disabled.
+		if protected {
+			albctx.GetLogger(ctx).Infof("LoadBalancer %v has deletion protection enabled, retaining resources of deleted ingress %v", aws.StringValue(instance.LoadBalancerArn), ingressKey)
+			albctx.GetEventf(ctx)(corev1.EventTypeWarning, "DELETE", "LoadBalancer %v has deletion protection enabled, it's left in place with its listeners, targetGroups and securityGroups",
+				aws.StringValue(instance.LoadBalancerName))
+			return controller.orphanResources(ctx, ingressKey, instance)
+		}
+	}
+	for _, taggedInstance := range taggedInstances {
+		if aws.StringValue(taggedInstance.LoadBalancerArn) == aws.StringValue(instance.LoadBalancerArn) {
+			continue
+		}
+		if err := controller.deleteReplacedLBInstance(ctx, taggedInstance); err != nil {
+			return err
+		}
+	}
 	if instance != nil {
-		if err = controller.sgAssociationController.Delete(ctx, &sg.Association{
-			LbID:  lbName,
-			LbArn: aws.StringValue(instance.LoadBalancerArn),
-		}); err != nil {
-			return fmt.Errorf("failed to clean up securityGroups due to %v", err)
+		if err = controller.ensureDeletionProtectionDisabled(ctx, aws.StringValue(instance.LoadBalancerArn)); err != nil {
+			return err
+		}
+		// records are deleted first, so hosts stop resolving to the LoadBalancer before it stops serving them.
+		if err = controller.dnsController.Delete(ctx, instance); err != nil {
+			return fmt.Errorf("failed to delete DNS records due to %v", err)
 		}
+		// resources are deleted in order of dependency: listeners with their rules, targetGroups, securityGroups, then the LoadBalancer.
 		if err = controller.lsGroupController.Delete(ctx, aws.StringValue(instance.LoadBalancerArn)); err != nil {
 			return fmt.Errorf("failed to delete listeners due to %v", err)
 		}
 		if err = controller.tgGroupController.Delete(ctx, ingressKey); err != nil {
 			return fmt.Errorf("failed to GC targetGroups due to %v", err)
 		}
+		if err = controller.sgAssociationController.Delete(ctx, &sg.Association{
+			LbID:       lbName,
+			LbArn:      aws.StringValue(instance.LoadBalancerArn),
+			IngressKey: ingressKey.String(),
+		}); err != nil {
+			return fmt.Errorf("failed to clean up securityGroups due to %v", err)
+		}
+		alarmsProvisioned, err := controller.hasCloudWatchAlarms(ctx, instance)
+		if err != nil {
+			return err
+		}
+		if alarmsProvisioned {
+			if err = controller.deleteCloudWatchAlarms(ctx, lbName); err != nil {
+				return fmt.Errorf("failed to delete CloudWatch alarms due to %v", err)
+			}
+		}
 
 		if err = controller.cloud.DeleteLoadBalancerByArn(ctx, aws.StringValue(instance.LoadBalancerArn)); err != nil {
 			return err
@@ -166,24 +309,100 @@ func (controller *defaultController) Delete(ctx context.Context, ingressKey type
 	return nil
 }
 
-func (controller *defaultController) ensureLBInstance(ctx context.Context, lbConfig *loadBalancerConfig) (*elbv2.LoadBalancer, error) {
-	instance, err := controller.cloud.GetLoadBalancerByName(ctx, lbConfig.Name)
+// ensureDeletionProtectionDisabled makes sure the LoadBalancer can be deleted. When deletion protection is enabled,
+// it's either disabled if the controller is configured to do so, or an error is returned before any resource is removed.
+func (controller *defaultController) ensureDeletionProtectionDisabled(ctx context.Context, lbArn string) error {
+	protected, err := controller.isDeletionProtected(ctx, lbArn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find existing LoadBalancer due to %v", err)
+		return err
+	}
+	if !protected {
+		return nil
+	}
+
+	if !controller.store.GetConfig().DisableDeletionProtectionOnDelete {
+		albctx.GetLogger(ctx).Errorf("LoadBalancer %v has deletion protection enabled, it will not be deleted", lbArn)
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "LoadBalancer %v has deletion protection enabled, disable it to delete the LoadBalancer", lbArn)
+		return fmt.Errorf("LoadBalancer %v has deletion protection enabled", lbArn)
+	}
+
+	albctx.GetLogger(ctx).Infof("disabling deletion protection of LoadBalancer %v", lbArn)
+	if _, err := controller.cloud.ModifyLoadBalancerAttributesWithContext(ctx, &elbv2.ModifyLoadBalancerAttributesInput{
+		LoadBalancerArn: aws.String(lbArn),
+		Attributes:      []*elbv2.LoadBalancerAttribute{lbAttribute(DeletionProtectionEnabledKey, "false")},
+	}); err != nil {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "failed to disable deletion protection of %v due to %v", lbArn, err)
+		return fmt.Errorf("failed to disable deletion protection of %v due to %v", lbArn, err)
+	}
+	albctx.GetEventf(ctx)(corev1.EventTypeNormal, "MODIFY", "deletion protection of %v disabled for deletion", lbArn)
+	return nil
+}
+
+// isDeletionProtected tests whether the LoadBalancer has deletion protection enabled.
+func (controller *defaultController) isDeletionProtected(ctx context.Context, lbArn string) (bool, error) {
+	raw, err := controller.cloud.DescribeLoadBalancerAttributesWithContext(ctx, &elbv2.DescribeLoadBalancerAttributesInput{
+		LoadBalancerArn: aws.String(lbArn),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve attributes of %v due to %v", lbArn, err)
+	}
+	attrs, err := NewAttributes(raw.Attributes)
+	if err != nil && !IsInvalidAttribute(err) {
+		return false, fmt.Errorf("failed parsing attributes of %v due to %v", lbArn, err)
+	}
+	return attrs.DeletionProtectionEnabled, nil
+}
+
+func (controller *defaultController) ensureLBInstance(ctx context.Context, ingress *extensions.Ingress, lbConfig *loadBalancerConfig) (*elbv2.LoadBalancer, error) {
+	ingressKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+	instance, err := controller.findLBInstanceByName(ctx, ingress, lbConfig.Name)
+	if err != nil {
+		return nil, err
 	}
-	if instance == nil {
+	// the LoadBalancer replacing an existing one due to scheme change is named with alternate name.
+	alternateInstance, err := controller.findLBInstanceByName(ctx, ingress, alternateLBName(lbConfig.Name))
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil && alternateInstance == nil {
+		// ingress may own an LoadBalancer with another name if it's renamed, which have to be replaced since names are immutable.
+		staleInstance, err := controller.findLBByIngressTags(ctx, ingressKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find existing LoadBalancer due to %v", err)
+		}
+		if staleInstance != nil {
+			instance, err = controller.recreateLBInstance(ctx, staleInstance, lbConfig)
+			if err != nil {
+				return nil, fmt.Errorf("failed to recreate LoadBalancer due to %v", err)
+			}
+			return instance, nil
+		}
 		instance, err = controller.newLBInstance(ctx, lbConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create LoadBalancer due to %v", err)
 		}
 		return instance, nil
 	}
+
+	replacedInstance := alternateInstance
+	if instance == nil || (alternateInstance != nil && !controller.isLBInstanceNeedRecreation(ctx, alternateInstance, lbConfig)) {
+		instance, replacedInstance = alternateInstance, instance
+	}
 	if controller.isLBInstanceNeedRecreation(ctx, instance, lbConfig) {
-		instance, err = controller.recreateLBInstance(ctx, instance, lbConfig)
-		if err != nil {
-			return nil, fmt.Errorf("failed to recreate LoadBalancer due to %v", err)
+		if replacedInstance != nil {
+			return nil, fmt.Errorf("neither LoadBalancer %v nor %v has scheme %v",
+				aws.StringValue(instance.LoadBalancerName), aws.StringValue(replacedInstance.LoadBalancerName), aws.StringValue(lbConfig.Scheme))
+		}
+		return nil, controller.startSchemeChange(ctx, instance, lbConfig)
+	}
+	// duplicates are deleted before listeners are reconciled, since targetGroups can only be used by one LoadBalancer.
+	if err := controller.deleteDuplicateLBInstances(ctx, ingressKey, instance, replacedInstance); err != nil {
+		return nil, err
+	}
+	if replacedInstance != nil {
+		if err := controller.finishSchemeChange(ctx, ingress, instance, replacedInstance); err != nil {
+			return nil, err
 		}
-		return instance, nil
 	}
 	if err := controller.reconcileLBInstance(ctx, instance, lbConfig); err != nil {
 		return nil, err
@@ -191,16 +410,149 @@ func (controller *defaultController) ensureLBInstance(ctx context.Context, lbCon
 	return instance, nil
 }
 
+// findLBInstanceByName finds LoadBalancer by name, and makes sure it's owned by ingress unless it's the generated name.
+func (controller *defaultController) findLBInstanceByName(ctx context.Context, ingress *extensions.Ingress, lbName string) (*elbv2.LoadBalancer, error) {
+	instance, err := controller.cloud.GetLoadBalancerByName(ctx, lbName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing LoadBalancer due to %v", err)
+	}
+	if instance != nil && lbName != controller.nameTagGen.NameLB(ingress.Namespace, ingress.Name) {
+		ingressKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+		if err := controller.ensureLBOwnedByIngress(ctx, instance, ingressKey); err != nil {
+			return nil, err
+		}
+	}
+	return instance, nil
+}
+
+// findLBByIngressTags finds the LoadBalancer created for ingress by tags, it returns nil if there is no such LoadBalancer.
+func (controller *defaultController) findLBByIngressTags(ctx context.Context, ingressKey types.NamespacedName) (*elbv2.LoadBalancer, error) {
+	instances, err := controller.findLBsByIngressTags(ctx, ingressKey)
+	if err != nil || len(instances) == 0 {
+		return nil, err
+	}
+	return instances[0], nil
+}
+
+// findLBsByIngressTags finds all LoadBalancers created for ingress by tags.
+func (controller *defaultController) findLBsByIngressTags(ctx context.Context, ingressKey types.NamespacedName) ([]*elbv2.LoadBalancer, error) {
+	tagFilters := make(map[string][]string)
+	for k, v := range controller.nameTagGen.TagLB(ingressKey.Namespace, ingressKey.Name) {
+		tagFilters[k] = []string{v}
+	}
+	lbArns, err := controller.cloud.GetResourcesByFilters(ctx, tagFilters, aws.ResourceTypeEnumELBLoadBalancer)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(lbArns)
+	var instances []*elbv2.LoadBalancer
+	for _, lbArn := range lbArns {
+		instance, err := controller.cloud.GetLoadBalancerByArn(ctx, lbArn)
+		if err != nil {
+			return nil, err
+		}
+		if instance != nil {
+			instances = append(instances, instance)
+		}
+	}
+	return instances, nil
+}
+
+// maxDescribeTagsResources is the maximum number of resources can be described in a single ELBV2 DescribeTags call
+const maxDescribeTagsResources = 20
+
+func (controller *defaultController) ListPartitions(ctx context.Context, ingressKey types.NamespacedName) ([]types.NamespacedName, error) {
+	tagFilters := map[string][]string{tags.PartitionOf: {ingressKey.Name}}
+	for k, v := range controller.nameTagGen.TagLB(ingressKey.Namespace, ingressKey.Name) {
+		if k == tags.IngressName {
+			continue
+		}
+		tagFilters[k] = []string{v}
+	}
+	lbArns, err := controller.cloud.GetResourcesByFilters(ctx, tagFilters, aws.ResourceTypeEnumELBLoadBalancer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find LoadBalancers of partitions due to %v", err)
+	}
+	sort.Strings(lbArns)
+	var partitionKeys []types.NamespacedName
+	for len(lbArns) > 0 {
+		chunkSize := len(lbArns)
+		if chunkSize > maxDescribeTagsResources {
+			chunkSize = maxDescribeTagsResources
+		}
+		resp, err := controller.cloud.DescribeELBV2TagsWithContext(ctx, &elbv2.DescribeTagsInput{
+			ResourceArns: aws.StringSlice(lbArns[:chunkSize]),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe tags of LoadBalancers of partitions due to %v", err)
+		}
+		for _, tagDescription := range resp.TagDescriptions {
+			for _, tag := range tagDescription.Tags {
+				if aws.StringValue(tag.Key) == tags.IngressName {
+					partitionKeys = append(partitionKeys, types.NamespacedName{Namespace: ingressKey.Namespace, Name: aws.StringValue(tag.Value)})
+				}
+			}
+		}
+		lbArns = lbArns[chunkSize:]
+	}
+	return partitionKeys, nil
+}
+
+// ensureLBOwnedByIngress makes sure an LoadBalancer found by custom name is created for ingress,
+// so that LoadBalancers of other ingresses or created outside of the controller are never modified or deleted.
+func (controller *defaultController) ensureLBOwnedByIngress(ctx context.Context, instance *elbv2.LoadBalancer, ingressKey types.NamespacedName) error {
+	lbName := aws.StringValue(instance.LoadBalancerName)
+	lbTags, err := controller.describeLBTags(ctx, instance)
+	if err != nil {
+		return err
+	}
+	for k, v := range controller.nameTagGen.TagLB(ingressKey.Namespace, ingressKey.Name) {
+		if lbTags[k] != v {
+			albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "LoadBalancer name %v is already in use by another LoadBalancer", lbName)
+			return fmt.Errorf("LoadBalancer name %v is already in use by another LoadBalancer", lbName)
+		}
+	}
+	return nil
+}
+
+// reconcileLBTags ensures the tags of LoadBalancer matches lbConfig, so tags removed from the ingress or added out-of-band are removed.
+// The retain-on-delete annotation is recorded as tag, since annotations are gone when ingress is deleted, and the orphaned tag is removed,
+// so LoadBalancers retained for an deleted ingress are owned again once the ingress is recreated.
+func (controller *defaultController) reconcileLBTags(ctx context.Context, lbArn string, lbConfig *loadBalancerConfig, retainOnDelete bool) error {
+	desired := tags.NewTags(lbConfig.Tags)
+	desired.Arn = lbArn
+	if retainOnDelete {
+		desired.Tags[tags.RetainOnDelete] = "true"
+	}
+	return controller.tagsController.Reconcile(ctx, desired)
+}
+
+// describeLBTags returns the tags of LoadBalancer instance.
+func (controller *defaultController) describeLBTags(ctx context.Context, instance *elbv2.LoadBalancer) (map[string]string, error) {
+	lbTags, err := tags.DescribeELBV2Tags(ctx, controller.cloud, aws.StringValue(instance.LoadBalancerArn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe tags of LoadBalancer %v due to %v", aws.StringValue(instance.LoadBalancerName), err)
+	}
+	return lbTags.Tags, nil
+}
+
 func (controller *defaultController) newLBInstance(ctx context.Context, lbConfig *loadBalancerConfig) (*elbv2.LoadBalancer, error) {
 	albctx.GetLogger(ctx).Infof("creating LoadBalancer %v", lbConfig.Name)
-	resp, err := controller.cloud.CreateLoadBalancerWithContext(ctx, &elbv2.CreateLoadBalancerInput{
+	input := &elbv2.CreateLoadBalancerInput{
 		Name:          aws.String(lbConfig.Name),
 		Type:          lbConfig.Type,
 		Scheme:        lbConfig.Scheme,
 		IpAddressType: lbConfig.IpAddressType,
 		Subnets:       aws.StringSlice(lbConfig.Subnets),
 		Tags:          tags.ConvertToELBV2(lbConfig.Tags),
-	})
+	}
+	var resp *elbv2.CreateLoadBalancerOutput
+	var err error
+	if lbConfig.CustomerOwnedIPv4Pool != nil {
+		resp, err = controller.cloud.CreateLoadBalancerWithCustomerOwnedIpv4PoolWithContext(ctx, input, aws.StringValue(lbConfig.CustomerOwnedIPv4Pool))
+	} else {
+		resp, err = controller.cloud.CreateLoadBalancerWithContext(ctx, input)
+	}
 	if err != nil {
 		albctx.GetLogger(ctx).Errorf("failed to create LoadBalancer %v due to %v", lbConfig.Name, err)
 		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "failed to create LoadBalancer %v due to %v", lbConfig.Name, err)
@@ -213,13 +565,22 @@ func (controller *defaultController) newLBInstance(ctx context.Context, lbConfig
 	return instance, nil
 }
 
+// recreateLBInstance creates an LoadBalancer by lbConfig to replace existingInstance, which is deleted once the replacement is created.
+// The deletion protection of existingInstance is checked first, so no replacement is created for an LoadBalancer that can't be deleted.
 func (controller *defaultController) recreateLBInstance(ctx context.Context, existingInstance *elbv2.LoadBalancer, lbConfig *loadBalancerConfig) (*elbv2.LoadBalancer, error) {
 	existingLBArn := aws.StringValue(existingInstance.LoadBalancerArn)
-	albctx.GetLogger(ctx).Infof("deleting LoadBalancer %v for recreation", existingLBArn)
-	if err := controller.cloud.DeleteLoadBalancerByArn(ctx, existingLBArn); err != nil {
+	if err := controller.ensureDeletionProtectionDisabled(ctx, existingLBArn); err != nil {
+		return nil, err
+	}
+	instance, err := controller.newLBInstance(ctx, lbConfig)
+	if err != nil {
 		return nil, err
 	}
-	return controller.newLBInstance(ctx, lbConfig)
+	albctx.GetLogger(ctx).Infof("deleting LoadBalancer %v replaced by %v", existingLBArn, aws.StringValue(instance.LoadBalancerArn))
+	if err := controller.deleteReplacedLBInstance(ctx, existingInstance); err != nil {
+		return nil, err
+	}
+	return instance, nil
 }
 
 func (controller *defaultController) reconcileLBInstance(ctx context.Context, instance *elbv2.LoadBalancer, lbConfig *loadBalancerConfig) error {
@@ -293,50 +654,115 @@ func (controller *defaultController) reconcileWAF(ctx context.Context, lbArn str
 func (controller *defaultController) isLBInstanceNeedRecreation(ctx context.Context, instance *elbv2.LoadBalancer, lbConfig *loadBalancerConfig) bool {
 	if !util.DeepEqual(instance.Scheme, lbConfig.Scheme) {
 		albctx.GetLogger(ctx).Infof("LoadBalancer %s need recreation due to scheme changed(%s => %s)",
-			lbConfig.Name, aws.StringValue(instance.Scheme), aws.StringValue(lbConfig.Scheme))
+			aws.StringValue(instance.LoadBalancerName), aws.StringValue(instance.Scheme), aws.StringValue(lbConfig.Scheme))
 		return true
 	}
 	return false
 }
 
 func (controller *defaultController) buildLBConfig(ctx context.Context, ingress *extensions.Ingress, ingressAnnos *annotations.Ingress) (*loadBalancerConfig, error) {
+	controllerTags, err := tags.BuildControllerTags(controller.store, ingress)
+	if err != nil {
+		return nil, err
+	}
+	mergedTags, conflicts := tags.MergeIngressTags(controllerTags, ingressAnnos.Tags.LoadBalancer, controller.store.GetConfig().DefaultTagsPrecedence)
+	if len(conflicts) != 0 {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "TAGS", "tags %v of the tags annotation conflict with the tags configured on controller, %s",
+			conflicts, conflictResolution(controller.store.GetConfig().DefaultTagsPrecedence))
+	}
 	lbTags := controller.nameTagGen.TagLB(ingress.Namespace, ingress.Name)
-	for k, v := range ingressAnnos.Tags.LoadBalancer {
+	for k, v := range mergedTags {
 		lbTags[k] = v
 	}
+	if parentName := k8s.IngressParentName(ingress); parentName != ingress.Name {
+		lbTags[tags.PartitionOf] = parentName
+	}
 	subnets, err := controller.resolveSubnets(ctx, aws.StringValue(ingressAnnos.LoadBalancer.Scheme), ingressAnnos.LoadBalancer.Subnets)
 	if err != nil {
 		return nil, err
 	}
+	lbName := controller.nameTagGen.NameLB(ingress.Namespace, ingress.Name)
+	if ingressAnnos.LoadBalancer.Name != nil {
+		lbName = aws.StringValue(ingressAnnos.LoadBalancer.Name)
+	}
+	cloudWatchAlarms := controller.store.GetConfig().CloudWatchAlarms
+	if ingressAnnos.LoadBalancer.CloudWatchAlarms != nil {
+		cloudWatchAlarms = *ingressAnnos.LoadBalancer.CloudWatchAlarms
+	}
+	if cloudWatchAlarms {
+		lbTags[tags.CloudWatchAlarms] = "true"
+	}
 	return &loadBalancerConfig{
-		Name: controller.nameTagGen.NameLB(ingress.Namespace, ingress.Name),
+		Name: lbName,
 		Tags: lbTags,
 
 		Type:          aws.String(elbv2.LoadBalancerTypeEnumApplication),
 		Scheme:        ingressAnnos.LoadBalancer.Scheme,
 		IpAddressType: ingressAnnos.LoadBalancer.IPAddressType,
 		Subnets:       subnets,
+
+		CustomerOwnedIPv4Pool: ingressAnnos.LoadBalancer.CustomerOwnedIPv4Pool,
+		AllowSchemeChange:     ingressAnnos.LoadBalancer.AllowSchemeChange,
+		WebACLID:              resolveWebACLID(controller.store.GetConfig(), ingressAnnos.LoadBalancer.Scheme, ingressAnnos.LoadBalancer.WebACLId),
+
+		CloudWatchAlarms:       cloudWatchAlarms,
+		CloudWatchAlarmActions: controller.store.GetConfig().CloudWatchAlarmActions,
 	}, nil
 }
 
+// resolveWebACLID returns the webACL of an LoadBalancer with scheme, which is the default webACL of controller for internet-facing LoadBalancers without webACLID.
+func resolveWebACLID(controllerCfg *config.Configuration, scheme *string, webACLID *string) *string {
+	if webACLID == nil && controllerCfg.DefaultWebACLID != "" && aws.StringValue(scheme) == elbv2.LoadBalancerSchemeEnumInternetFacing {
+		return aws.String(controllerCfg.DefaultWebACLID)
+	}
+	return webACLID
+}
+
 func (controller *defaultController) validateLBConfig(ctx context.Context, ingress *extensions.Ingress, lbConfig *loadBalancerConfig) error {
 	controllerCfg := controller.store.GetConfig()
 	if controllerCfg.RestrictScheme && aws.StringValue(lbConfig.Scheme) == elbv2.LoadBalancerSchemeEnumInternetFacing {
 		whitelisted := false
 		for _, name := range controllerCfg.InternetFacingIngresses[ingress.Namespace] {
-			if name == ingress.Name {
+			if name == k8s.IngressParentName(ingress) {
 				whitelisted = true
 				break
 			}
 		}
 		if !whitelisted {
-			return fmt.Errorf("ingress %v/%v is not in internetFacing whitelist", ingress.Namespace, ingress.Name)
+			return fmt.Errorf("ingress %v/%v is not in internetFacing whitelist", ingress.Namespace, k8s.IngressParentName(ingress))
 		}
 	}
+	if controllerCfg.RequireWAF && lbConfig.WebACLID == nil && aws.StringValue(lbConfig.Scheme) == elbv2.LoadBalancerSchemeEnumInternetFacing {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "internet-facing LoadBalancers must have an webACL, specify it with the web-acl-id annotation")
+		return fmt.Errorf("ingress %v/%v is internet-facing without webACL", ingress.Namespace, k8s.IngressParentName(ingress))
+	}
+	if missing := missingRequiredTags(lbConfig.Tags, controllerCfg.RequiredTags); len(missing) != 0 {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "required tags %v are missing, specify them with the tags annotation", missing)
+		return fmt.Errorf("ingress %v/%v is missing required tags %v", ingress.Namespace, k8s.IngressParentName(ingress), missing)
+	}
 
 	return nil
 }
 
+// conflictResolution describes which value of conflicting tags is applied.
+func conflictResolution(controllerPrecedence bool) string {
+	if controllerPrecedence {
+		return "the values configured on controller are applied"
+	}
+	return "the values of the tags annotation are applied"
+}
+
+// missingRequiredTags returns the keys in required that are absent or empty in lbTags.
+func missingRequiredTags(lbTags map[string]string, required []string) []string {
+	var missing []string
+	for _, key := range required {
+		if lbTags[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
 func (controller *defaultController) resolveSecurityGroupNames(ctx context.Context, sgIDOrNames []string) ([]string, error) {
 	var names []string
 	var output []string
@@ -368,6 +794,14 @@ func (controller *defaultController) resolveSecurityGroupNames(ctx context.Conte
 	return output, nil
 }
 
+const (
+	// subnetTagFilterPrefix prefixes entries of subnets annotation that select subnets by tag, e.g. tag:Tier=public
+	subnetTagFilterPrefix = "tag:"
+)
+
+// resolveSubnets resolves the subnets annotation into subnet IDs. Entries can be subnet IDs, Name tags, or tag filters
+// in tag:Key=Value format. Explicit subnets must be in distinct availability zones, and one subnet is chosen for each
+// availability zone among subnets matched by tag filters.
 func (controller *defaultController) resolveSubnets(ctx context.Context, scheme string, in []string) ([]string, error) {
 	if len(in) == 0 {
 		subnets, err := controller.clusterSubnets(ctx, scheme)
@@ -375,36 +809,92 @@ func (controller *defaultController) resolveSubnets(ctx context.Context, scheme
 
 	}
 
-	var names []string
-	var subnets []string
-
+	var nameOrIDs []string
+	var tagFilterEntries []string
 	for _, subnet := range in {
-		if strings.HasPrefix(subnet, "subnet-") {
-			subnets = append(subnets, subnet)
+		if strings.HasPrefix(subnet, subnetTagFilterPrefix) {
+			tagFilterEntries = append(tagFilterEntries, strings.TrimPrefix(subnet, subnetTagFilterPrefix))
 			continue
 		}
-		names = append(names, subnet)
+		nameOrIDs = append(nameOrIDs, subnet)
 	}
 
-	if len(names) > 0 {
-		o, err := controller.cloud.GetSubnetsByNameOrID(ctx, names)
+	var chosen []*ec2.Subnet
+	if len(nameOrIDs) > 0 {
+		o, err := controller.cloud.GetSubnetsByNameOrID(ctx, nameOrIDs)
 		if err != nil {
-			return subnets, err
+			return nil, err
+		}
+		if len(o) != len(nameOrIDs) {
+			var resolved []string
+			for _, subnet := range o {
+				resolved = append(resolved, aws.StringValue(subnet.SubnetId))
+			}
+			sort.Strings(resolved)
+			return nil, fmt.Errorf("not all subnets were resolvable, (%v != %v)", strings.Join(nameOrIDs, ","), strings.Join(resolved, ","))
 		}
-
 		for _, subnet := range o {
-			subnets = append(subnets, aws.StringValue(subnet.SubnetId))
+			if !subnetIsUsable(subnet, chosen) {
+				return nil, fmt.Errorf("subnets must be in distinct availability zones, multiple subnets specified in %v", aws.StringValue(subnet.AvailabilityZone))
+			}
+			chosen = append(chosen, subnet)
 		}
 	}
 
-	sort.Strings(subnets)
-	if len(subnets) != len(in) {
-		return subnets, fmt.Errorf("not all subnets were resolvable, (%v != %v)", strings.Join(in, ","), strings.Join(subnets, ","))
+	if len(tagFilterEntries) > 0 {
+		tagFilters, err := parseSubnetTagFilters(tagFilterEntries)
+		if err != nil {
+			return nil, err
+		}
+		o, err := controller.cloud.GetSubnetsByTagFilters(ctx, tagFilters)
+		if err != nil {
+			return nil, err
+		}
+		if len(o) == 0 {
+			return nil, fmt.Errorf("no subnets matched tag filters %v", strings.Join(tagFilterEntries, ","))
+		}
+		chosen = chooseSubnetPerAZ(o, chosen)
+	}
+	if err := validateSubnetZones(chosen); err != nil {
+		return nil, err
 	}
 
+	var subnets []string
+	for _, subnet := range chosen {
+		subnets = append(subnets, aws.StringValue(subnet.SubnetId))
+	}
+	sort.Strings(subnets)
 	return subnets, nil
 }
 
+// parseSubnetTagFilters parses entries in Key=Value format into tag filters, values of the same key are merged.
+func parseSubnetTagFilters(entries []string) (map[string][]string, error) {
+	tagFilters := make(map[string][]string)
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("unable to parse subnet tag filter `%s` into Key=Value pair", entry)
+		}
+		key := strings.TrimSpace(parts[0])
+		tagFilters[key] = append(tagFilters[key], strings.TrimSpace(parts[1]))
+	}
+	return tagFilters, nil
+}
+
+// chooseSubnetPerAZ adds to existing the subnet with smallest ID for each availability zone of candidates not already covered by existing.
+func chooseSubnetPerAZ(candidates []*ec2.Subnet, existing []*ec2.Subnet) []*ec2.Subnet {
+	sorted := append([]*ec2.Subnet{}, candidates...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return aws.StringValue(sorted[i].SubnetId) < aws.StringValue(sorted[j].SubnetId)
+	})
+	for _, subnet := range sorted {
+		if subnetIsUsable(subnet, existing) {
+			existing = append(existing, subnet)
+		}
+	}
+	return existing
+}
+
 func (controller *defaultController) clusterSubnets(ctx context.Context, scheme string) ([]string, error) {
 	var subnetIds []string
 	var useableSubnets []*ec2.Subnet
@@ -419,19 +909,21 @@ func (controller *defaultController) clusterSubnets(ctx context.Context, scheme
 		return nil, fmt.Errorf("invalid scheme [%s]", scheme)
 	}
 
+	discoveryTagFilters, err := parseSubnetTagFilters(controller.store.GetConfig().SubnetDiscoveryTags)
+	if err != nil {
+		return nil, err
+	}
+
 	clusterSubnets, err := controller.cloud.GetClusterSubnets()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get AWS tags. Error: %s", err.Error())
 	}
 
-	for arn, subnetTags := range clusterSubnets {
-		for _, tag := range subnetTags {
-			if aws.StringValue(tag.Key) == key {
-				p := strings.Split(arn, "/")
-				subnetID := p[len(p)-1]
-				subnetIds = append(subnetIds, subnetID)
-			}
+	for subnetID, subnetTags := range clusterSubnets {
+		if !subnetTagsMatches(subnetTags, map[string][]string{key: nil}) || !subnetTagsMatches(subnetTags, discoveryTagFilters) {
+			continue
 		}
+		subnetIds = append(subnetIds, subnetID)
 	}
 
 	o, err := controller.cloud.GetSubnetsByNameOrID(ctx, subnetIds)
@@ -439,11 +931,10 @@ func (controller *defaultController) clusterSubnets(ctx context.Context, scheme
 		return nil, fmt.Errorf("unable to fetch subnets due to %v", err)
 	}
 
-	for _, subnet := range o {
-		if subnetIsUsable(subnet, useableSubnets) {
-			useableSubnets = append(useableSubnets, subnet)
-			out = append(out, aws.StringValue(subnet.SubnetId))
-		}
+	// subnets in Local Zones or Wavelength Zones are never auto-discovered, they can only be used by the subnets annotation.
+	useableSubnets = chooseSubnetPerAZ(filterSubnetsByZoneType(o, zoneTypeAvailabilityZone), nil)
+	for _, subnet := range useableSubnets {
+		out = append(out, aws.StringValue(subnet.SubnetId))
 	}
 
 	if len(out) < 2 {
@@ -460,6 +951,26 @@ func (controller *defaultController) clusterSubnets(ctx context.Context, scheme
 	return out, nil
 }
 
+// subnetTagsMatches tests whether subnetTags matches all tagFilters, a tag filter without values matches any value.
+func subnetTagsMatches(subnetTags util.EC2Tags, tagFilters map[string][]string) bool {
+	for key, values := range tagFilters {
+		matched := false
+		for _, tag := range subnetTags {
+			if aws.StringValue(tag.Key) != key {
+				continue
+			}
+			if len(values) == 0 || sets.NewString(values...).Has(aws.StringValue(tag.Value)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
 // subnetIsUsable determines if the subnet shares the same availablity zone as a subnet in the
 // existing list. If it does, false is returned as you cannot have albs provisioned to 2 subnets in
 // the same availability zone.
@@ -471,3 +982,14 @@ func subnetIsUsable(new *ec2.Subnet, existing []*ec2.Subnet) bool {
 	}
 	return true
 }
+
+// buildLoadBalancer builds the information of LoadBalancer instance with lbArn reported in the status of ingresses.
+func buildLoadBalancer(lbArn string, instance *elbv2.LoadBalancer) *LoadBalancer {
+	dnsName := aws.StringValue(instance.DNSName)
+	return &LoadBalancer{
+		Arn:                   lbArn,
+		DNSName:               dnsName,
+		DualstackDNSName:      "dualstack." + dnsName,
+		CanonicalHostedZoneID: aws.StringValue(instance.CanonicalHostedZoneId),
+	}
+}
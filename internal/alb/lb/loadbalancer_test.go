@@ -4,12 +4,22 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/dns"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/ls"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestCloud_ResolveSecurityGroupNames(t *testing.T) {
@@ -92,3 +102,276 @@ func TestCloud_ResolveSecurityGroupNames(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSubnetTagFilters(t *testing.T) {
+	for _, tc := range []struct {
+		Name               string
+		Entries            []string
+		ExpectedTagFilters map[string][]string
+		ExpectErr          bool
+	}{
+		{
+			Name:               "values of the same key are merged",
+			Entries:            []string{"Tier=public", "Tier=dmz", "Team=a"},
+			ExpectedTagFilters: map[string][]string{"Tier": {"public", "dmz"}, "Team": {"a"}},
+		},
+		{
+			Name:      "entry without value",
+			Entries:   []string{"Tier"},
+			ExpectErr: true,
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			tagFilters, err := parseSubnetTagFilters(tc.Entries)
+			if tc.ExpectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ExpectedTagFilters, tagFilters)
+		})
+	}
+}
+
+func TestMissingRequiredTags(t *testing.T) {
+	for _, tc := range []struct {
+		Name            string
+		Tags            map[string]string
+		Required        []string
+		ExpectedMissing []string
+	}{
+		{
+			Name:     "no required tags",
+			Tags:     map[string]string{"team": "a"},
+			Required: nil,
+		},
+		{
+			Name:     "all required tags present",
+			Tags:     map[string]string{"team": "a", "cost-center": "1234"},
+			Required: []string{"team", "cost-center"},
+		},
+		{
+			Name:            "absent and empty tags are missing",
+			Tags:            map[string]string{"team": ""},
+			Required:        []string{"team", "cost-center"},
+			ExpectedMissing: []string{"team", "cost-center"},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedMissing, missingRequiredTags(tc.Tags, tc.Required))
+		})
+	}
+}
+
+func TestResolveSubnets(t *testing.T) {
+	subnet := func(id string, az string) *ec2.Subnet {
+		return &ec2.Subnet{SubnetId: aws.String(id), AvailabilityZone: aws.String(az)}
+	}
+	for _, tc := range []struct {
+		Name                  string
+		In                    []string
+		NameOrIDSubnets       []*ec2.Subnet
+		TagFilters            map[string][]string
+		TagFilterSubnets      []*ec2.Subnet
+		ExpectedSubnets       []string
+		ExpectedErr           error
+		ExpectNameOrIDLookups bool
+	}{
+		{
+			Name:                  "subnets by ID and name",
+			In:                    []string{"subnet-1", "my-subnet"},
+			NameOrIDSubnets:       []*ec2.Subnet{subnet("subnet-1", "us-west-2a"), subnet("subnet-2", "us-west-2b")},
+			ExpectedSubnets:       []string{"subnet-1", "subnet-2"},
+			ExpectNameOrIDLookups: true,
+		},
+		{
+			Name:                  "subnets in same availability zone",
+			In:                    []string{"subnet-1", "subnet-2"},
+			NameOrIDSubnets:       []*ec2.Subnet{subnet("subnet-1", "us-west-2a"), subnet("subnet-2", "us-west-2a")},
+			ExpectedErr:           errors.New("subnets must be in distinct availability zones, multiple subnets specified in us-west-2a"),
+			ExpectNameOrIDLookups: true,
+		},
+		{
+			Name:       "one subnet per availability zone is chosen for tag filters",
+			In:         []string{"tag:Tier=public"},
+			TagFilters: map[string][]string{"Tier": {"public"}},
+			TagFilterSubnets: []*ec2.Subnet{
+				subnet("subnet-3", "us-west-2a"),
+				subnet("subnet-1", "us-west-2a"),
+				subnet("subnet-2", "us-west-2b"),
+			},
+			ExpectedSubnets: []string{"subnet-1", "subnet-2"},
+		},
+		{
+			Name:                  "explicit subnets take precedence over tag filters within availability zone",
+			In:                    []string{"subnet-3", "tag:Tier=public"},
+			NameOrIDSubnets:       []*ec2.Subnet{subnet("subnet-3", "us-west-2a")},
+			TagFilters:            map[string][]string{"Tier": {"public"}},
+			TagFilterSubnets:      []*ec2.Subnet{subnet("subnet-1", "us-west-2a"), subnet("subnet-2", "us-west-2b")},
+			ExpectedSubnets:       []string{"subnet-2", "subnet-3"},
+			ExpectNameOrIDLookups: true,
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			if tc.ExpectNameOrIDLookups {
+				var nameOrIDs []string
+				for _, entry := range tc.In {
+					if !strings.HasPrefix(entry, "tag:") {
+						nameOrIDs = append(nameOrIDs, entry)
+					}
+				}
+				cloud.On("GetSubnetsByNameOrID", ctx, nameOrIDs).Return(tc.NameOrIDSubnets, nil)
+			}
+			if tc.TagFilters != nil {
+				cloud.On("GetSubnetsByTagFilters", ctx, tc.TagFilters).Return(tc.TagFilterSubnets, nil)
+			}
+
+			controller := &defaultController{
+				cloud: cloud,
+			}
+			subnets, err := controller.resolveSubnets(ctx, "internal", tc.In)
+			assert.Equal(t, tc.ExpectedErr, err)
+			if tc.ExpectedErr == nil {
+				assert.Equal(t, tc.ExpectedSubnets, subnets)
+			}
+			cloud.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolveWebACLID(t *testing.T) {
+	for _, tc := range []struct {
+		Name             string
+		DefaultWebACLID  string
+		Scheme           string
+		WebACLID         *string
+		ExpectedWebACLID *string
+	}{
+		{
+			Name:             "annotated webACL takes precedence",
+			DefaultWebACLID:  "default",
+			Scheme:           "internet-facing",
+			WebACLID:         aws.String("annotated"),
+			ExpectedWebACLID: aws.String("annotated"),
+		},
+		{
+			Name:             "default webACL of internet-facing LoadBalancers",
+			DefaultWebACLID:  "default",
+			Scheme:           "internet-facing",
+			ExpectedWebACLID: aws.String("default"),
+		},
+		{
+			Name:            "no default webACL of internal LoadBalancers",
+			DefaultWebACLID: "default",
+			Scheme:          "internal",
+		},
+		{
+			Name:   "no default webACL",
+			Scheme: "internet-facing",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			controllerCfg := &config.Configuration{DefaultWebACLID: tc.DefaultWebACLID}
+			assert.Equal(t, tc.ExpectedWebACLID, resolveWebACLID(controllerCfg, aws.String(tc.Scheme), tc.WebACLID))
+		})
+	}
+}
+
+func TestRecreateLBInstance(t *testing.T) {
+	existingInstance := &elbv2.LoadBalancer{
+		LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/old/1"),
+		LoadBalancerName: aws.String("old"),
+	}
+	instance := &elbv2.LoadBalancer{
+		LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/new/2"),
+		LoadBalancerName: aws.String("new"),
+	}
+	for _, tc := range []struct {
+		Name               string
+		DeletionProtection string
+		ExpectedCalls      []string
+		ExpectedError      error
+	}{
+		{
+			Name:               "replacement is created before the existing LoadBalancer is deleted",
+			DeletionProtection: "false",
+			ExpectedCalls:      []string{"CreateLoadBalancerWithContext", "DeleteLoadBalancerByArn"},
+		},
+		{
+			Name:               "no replacement is created while deletion protection is enabled",
+			DeletionProtection: "true",
+			ExpectedError:      errors.New("LoadBalancer arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/old/1 has deletion protection enabled"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			var calls []string
+			cloud := &mocks.CloudAPI{}
+			cloud.On("DescribeLoadBalancerAttributesWithContext", ctx, &elbv2.DescribeLoadBalancerAttributesInput{LoadBalancerArn: existingInstance.LoadBalancerArn}).Return(
+				&elbv2.DescribeLoadBalancerAttributesOutput{Attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(DeletionProtectionEnabledKey, tc.DeletionProtection)}}, nil)
+			cloud.On("CreateLoadBalancerWithContext", ctx, mock.Anything).Run(func(mock.Arguments) {
+				calls = append(calls, "CreateLoadBalancerWithContext")
+			}).Return(&elbv2.CreateLoadBalancerOutput{LoadBalancers: []*elbv2.LoadBalancer{instance}}, nil)
+			cloud.On("DeleteLoadBalancerByArn", ctx, aws.StringValue(existingInstance.LoadBalancerArn)).Run(func(mock.Arguments) {
+				calls = append(calls, "DeleteLoadBalancerByArn")
+			}).Return(nil)
+			mockStore := &store.MockStorer{}
+			mockStore.On("GetConfig").Return(&config.Configuration{})
+			lsGroupController := &ls.MockGroupController{}
+			lsGroupController.On("Delete", ctx, aws.StringValue(existingInstance.LoadBalancerArn)).Return(nil)
+
+			controller := &defaultController{
+				cloud:             cloud,
+				store:             mockStore,
+				lsGroupController: lsGroupController,
+				dnsController:     dns.NewController(cloud, "", ""),
+			}
+			out, err := controller.recreateLBInstance(ctx, existingInstance, &loadBalancerConfig{Name: "new"})
+			assert.Equal(t, tc.ExpectedError, err)
+			assert.Equal(t, tc.ExpectedCalls, calls)
+			if tc.ExpectedError == nil {
+				assert.Equal(t, instance, out)
+			}
+		})
+	}
+}
+
+func TestDelete_DeletionProtected(t *testing.T) {
+	ctx := context.Background()
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	lbArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/lb/1"
+	tgArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/tg/1"
+	instance := &elbv2.LoadBalancer{LoadBalancerArn: aws.String(lbArn), LoadBalancerName: aws.String("lb")}
+
+	nameTagGen := &MockNameTagGenerator{}
+	nameTagGen.On("NameLB", "namespace", "ingress").Return("lb")
+	nameTagGen.On("TagLB", "namespace", "ingress").Return(map[string]string{"kubernetes.io/ingress-name": "ingress"})
+	nameTagGen.On("TagTGGroup", "namespace", "ingress").Return(map[string]string{"kubernetes.io/ingress-name": "ingress"})
+	mockStore := &store.MockStorer{}
+	mockStore.On("GetIngressAnnotations", ingressKey.String()).Return(nil, errors.New("not found"))
+	mockStore.On("GetConfig").Return(&config.Configuration{})
+	cloud := &mocks.CloudAPI{}
+	cloud.On("GetLoadBalancerByName", ctx, "lb").Return(instance, nil)
+	cloud.On("GetResourcesByFilters", ctx, map[string][]string{"kubernetes.io/ingress-name": {"ingress"}}, aws.ResourceTypeEnumELBLoadBalancer).Return([]string{lbArn}, nil)
+	cloud.On("GetLoadBalancerByArn", ctx, lbArn).Return(instance, nil)
+	cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: []*string{aws.String(lbArn)}}).Return(&elbv2.DescribeTagsOutput{}, nil)
+	cloud.On("DescribeLoadBalancerAttributesWithContext", ctx, &elbv2.DescribeLoadBalancerAttributesInput{LoadBalancerArn: aws.String(lbArn)}).Return(
+		&elbv2.DescribeLoadBalancerAttributesOutput{Attributes: []*elbv2.LoadBalancerAttribute{lbAttribute(DeletionProtectionEnabledKey, "true")}}, nil)
+	cloud.On("GetResourcesByFilters", ctx, map[string][]string{"kubernetes.io/ingress-name": {"ingress"}}, aws.ResourceTypeEnumELBTargetGroup).Return([]string{tgArn}, nil)
+	cloud.On("TagResourcesWithContext", ctx, &resourcegroupstaggingapi.TagResourcesInput{
+		ResourceARNList: aws.StringSlice([]string{lbArn, tgArn}),
+		Tags:            map[string]*string{tags.Orphaned: aws.String("true")},
+	}).Return(&resourcegroupstaggingapi.TagResourcesOutput{}, nil)
+
+	controller := &defaultController{
+		cloud:       cloud,
+		store:       mockStore,
+		nameTagGen:  nameTagGen,
+		existingLBs: newExistingLBs(),
+	}
+	assert.NoError(t, controller.Delete(ctx, ingressKey))
+	cloud.AssertExpectations(t)
+	cloud.AssertNotCalled(t, "DeleteLoadBalancerByArn", mock.Anything, mock.Anything)
+}
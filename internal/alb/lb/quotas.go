@@ -0,0 +1,122 @@
+package lb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// quotaWarningRatio is the ratio of quota usage that warnings are emitted at
+	quotaWarningRatio = 0.8
+
+	// accountLimitsTTL is the duration account limits are cached for, since they are rarely changed
+	accountLimitsTTL = 1 * time.Hour
+
+	limitListenersPerALB    = "listeners-per-application-load-balancer"
+	limitRulesPerALB        = "rules-per-application-load-balancer"
+	limitTargetGroupsPerALB = "target-groups-per-application-load-balancer"
+)
+
+// accountLimits caches the ELBV2 and securityGroup limits of current account.
+type accountLimits struct {
+	mutex     sync.Mutex
+	limits    map[string]int64
+	expiresAt time.Time
+}
+
+// getAccountLimits returns the ELBV2 and securityGroup limits of current account, or the stale ones if they can't be refreshed.
+func (controller *defaultController) getAccountLimits(ctx context.Context) map[string]int64 {
+	cache := controller.accountLimits
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if cache.limits != nil && time.Now().Before(cache.expiresAt) {
+		return cache.limits
+	}
+	limits, err := controller.cloud.GetAccountLimits(ctx)
+	if err != nil {
+		albctx.GetLogger(ctx).Warnf("failed to get account limits due to %v, quotas are not checked", err)
+		return cache.limits
+	}
+	cache.limits = limits
+	cache.expiresAt = time.Now().Add(accountLimitsTTL)
+	return cache.limits
+}
+
+// checkQuotas reports the usage of per LoadBalancer quotas by ingress, and emits warnings when ingress approaches them.
+// An error is returned if ingress would exceed any quota, so the reconcile fails before any resource is changed
+// instead of failing halfway when creating listeners or rules.
+func (controller *defaultController) checkQuotas(ctx context.Context, ingress *extensions.Ingress, ingressAnnos *annotations.Ingress) error {
+	limits := controller.getAccountLimits(ctx)
+	if len(limits) == 0 {
+		return nil
+	}
+	usages := buildQuotaUsages(ingress, ingressAnnos)
+	var quotas []string
+	for quota := range usages {
+		quotas = append(quotas, quota)
+	}
+	sort.Strings(quotas)
+
+	ingressKey := k8s.MetaNamespaceKey(ingress)
+	for _, quota := range quotas {
+		limit, ok := limits[quota]
+		if !ok || limit <= 0 {
+			continue
+		}
+		usage := usages[quota]
+		ratio := float64(usage) / float64(limit)
+		controller.metricCollector.SetQuotaUsage(ingressKey, quota, ratio)
+		if usage > limit {
+			albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "ingress needs %d %s, which exceeds the quota of %d", usage, quota, limit)
+			return fmt.Errorf("ingress needs %d %s, which exceeds the quota of %d", usage, quota, limit)
+		}
+		if ratio >= quotaWarningRatio {
+			albctx.GetEventf(ctx)(corev1.EventTypeWarning, "QUOTA", "ingress uses %d of %d %s", usage, limit, quota)
+		}
+	}
+	return nil
+}
+
+// buildQuotaUsages counts the resources needed by ingress for each per LoadBalancer quota.
+func buildQuotaUsages(ingress *extensions.Ingress, ingressAnnos *annotations.Ingress) map[string]int64 {
+	paths := 0
+	backends := sets.NewString()
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			paths++
+			if !action.Use(path.Backend.ServicePort.String()) {
+				backends.Insert(path.Backend.ServiceName + ":" + path.Backend.ServicePort.String())
+			}
+		}
+	}
+	if backend := ingress.Spec.Backend; backend != nil && !action.Use(backend.ServicePort.String()) {
+		backends.Insert(backend.ServiceName + ":" + backend.ServicePort.String())
+	}
+	listeners := len(ingressAnnos.LoadBalancer.Ports)
+	// without the security-groups annotation, rules spilling into additional managed securityGroups are capped by the sg package instead.
+	securityGroups := len(ingressAnnos.LoadBalancer.SecurityGroups)
+	if securityGroups == 0 {
+		securityGroups = 1
+	}
+	return map[string]int64{
+		limitListenersPerALB:          int64(listeners),
+		limitRulesPerALB:              int64(paths * listeners),
+		limitTargetGroupsPerALB:       int64(backends.Len()),
+		aws.LimitSecurityGroupsPerALB: int64(securityGroups),
+	}
+}
@@ -0,0 +1,106 @@
+package lb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestCheckQuotas(t *testing.T) {
+	ingress := &extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"},
+		Spec: extensions.IngressSpec{
+			Backend: &extensions.IngressBackend{ServiceName: "service1", ServicePort: intstr.FromInt(80)},
+			Rules: []extensions.IngressRule{
+				{
+					IngressRuleValue: extensions.IngressRuleValue{
+						HTTP: &extensions.HTTPIngressRuleValue{
+							Paths: []extensions.HTTPIngressPath{
+								{Path: "/a", Backend: extensions.IngressBackend{ServiceName: "service1", ServicePort: intstr.FromInt(80)}},
+								{Path: "/b", Backend: extensions.IngressBackend{ServiceName: "service2", ServicePort: intstr.FromInt(80)}},
+								{Path: "/c", Backend: extensions.IngressBackend{ServiceName: "redirect", ServicePort: intstr.FromString("use-annotation")}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	ingressAnnos := &annotations.Ingress{
+		LoadBalancer: &loadbalancer.Config{
+			Ports: []loadbalancer.PortData{{Port: 80, Scheme: "HTTP"}, {Port: 443, Scheme: "HTTPS"}},
+		},
+	}
+
+	assert.Equal(t, map[string]int64{
+		limitListenersPerALB:          2,
+		limitRulesPerALB:              6,
+		limitTargetGroupsPerALB:       2,
+		aws.LimitSecurityGroupsPerALB: 1,
+	}, buildQuotaUsages(ingress, ingressAnnos))
+
+	externalSGsAnnos := &annotations.Ingress{
+		LoadBalancer: &loadbalancer.Config{
+			Ports:          []loadbalancer.PortData{{Port: 80, Scheme: "HTTP"}},
+			SecurityGroups: []string{"sg-1", "sg-2", "sg-3"},
+		},
+	}
+	assert.Equal(t, int64(3), buildQuotaUsages(ingress, externalSGsAnnos)[aws.LimitSecurityGroupsPerALB])
+
+	for _, tc := range []struct {
+		Name             string
+		IngressAnnos     *annotations.Ingress
+		AccountLimits    map[string]int64
+		AccountLimitsErr error
+		ExpectedError    error
+	}{
+		{
+			Name:          "within quotas",
+			AccountLimits: map[string]int64{limitListenersPerALB: 50, limitRulesPerALB: 100, limitTargetGroupsPerALB: 100},
+		},
+		{
+			Name:          "exceeds rules quota",
+			AccountLimits: map[string]int64{limitListenersPerALB: 50, limitRulesPerALB: 5, limitTargetGroupsPerALB: 100},
+			ExpectedError: errors.New("ingress needs 6 rules-per-application-load-balancer, which exceeds the quota of 5"),
+		},
+		{
+			Name:          "exceeds securityGroups quota",
+			IngressAnnos:  externalSGsAnnos,
+			AccountLimits: map[string]int64{limitListenersPerALB: 50, limitRulesPerALB: 100, limitTargetGroupsPerALB: 100, aws.LimitSecurityGroupsPerALB: 2},
+			ExpectedError: errors.New("ingress needs 3 security-groups-per-application-load-balancer, which exceeds the quota of 2"),
+		},
+		{
+			Name:             "quotas are not checked when account limits are unavailable",
+			AccountLimitsErr: errors.New("AccessDenied"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			cloud.On("GetAccountLimits", ctx).Return(tc.AccountLimits, tc.AccountLimitsErr)
+
+			controller := &defaultController{
+				cloud:           cloud,
+				metricCollector: metric.DummyCollector{},
+				accountLimits:   &accountLimits{},
+			}
+			annos := ingressAnnos
+			if tc.IngressAnnos != nil {
+				annos = tc.IngressAnnos
+			}
+			err := controller.checkQuotas(ctx, ingress, annos)
+			assert.Equal(t, tc.ExpectedError, err)
+			cloud.AssertExpectations(t)
+		})
+	}
+}
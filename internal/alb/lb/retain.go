@@ -0,0 +1,62 @@
+package lb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// maxTagResourcesARNs is the maximum number of resources can be tagged in a single TagResources call
+const maxTagResourcesARNs = 20
+
+// isRetainedOnDelete tests whether the resources of an deleted ingress should be retained,
+// either the controller is configured to retain all resources, or instance is tagged to be retained.
+func (controller *defaultController) isRetainedOnDelete(ctx context.Context, instance *elbv2.LoadBalancer) (bool, error) {
+	if controller.store.GetConfig().RetainResourcesOnDelete {
+		return true, nil
+	}
+	if instance == nil {
+		return false, nil
+	}
+	lbTags, err := controller.describeLBTags(ctx, instance)
+	if err != nil {
+		return false, err
+	}
+	return lbTags[tags.RetainOnDelete] == "true", nil
+}
+
+// orphanResources tags the LoadBalancer and targetGroups of an deleted ingress as orphaned instead of deleting them.
+func (controller *defaultController) orphanResources(ctx context.Context, ingressKey types.NamespacedName, instance *elbv2.LoadBalancer) error {
+	tagFilters := make(map[string][]string)
+	for k, v := range controller.nameTagGen.TagTGGroup(ingressKey.Namespace, ingressKey.Name) {
+		tagFilters[k] = []string{v}
+	}
+	arns, err := controller.cloud.GetResourcesByFilters(ctx, tagFilters, aws.ResourceTypeEnumELBTargetGroup)
+	if err != nil {
+		return fmt.Errorf("failed to get targetGroups due to %v", err)
+	}
+	if instance != nil {
+		arns = append([]string{aws.StringValue(instance.LoadBalancerArn)}, arns...)
+	}
+
+	albctx.GetLogger(ctx).Infof("retaining resources of deleted ingress %v: %v", ingressKey, arns)
+	for start := 0; start < len(arns); start += maxTagResourcesARNs {
+		end := start + maxTagResourcesARNs
+		if end > len(arns) {
+			end = len(arns)
+		}
+		if _, err := controller.cloud.TagResourcesWithContext(ctx, &resourcegroupstaggingapi.TagResourcesInput{
+			ResourceARNList: aws.StringSlice(arns[start:end]),
+			Tags:            map[string]*string{tags.Orphaned: aws.String("true")},
+		}); err != nil {
+			return fmt.Errorf("failed to tag retained resources due to %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package lb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileLBTags_Retain(t *testing.T) {
+	lbArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/web/50dc6c495c0c9188"
+	for _, tc := range []struct {
+		Name               string
+		RetainOnDelete     bool
+		CurrentTags        map[string]string
+		ExpectedTagInput   *resourcegroupstaggingapi.TagResourcesInput
+		ExpectedUntagInput *resourcegroupstaggingapi.UntagResourcesInput
+	}{
+		{
+			Name:           "retain tag is added",
+			RetainOnDelete: true,
+			CurrentTags:    map[string]string{},
+			ExpectedTagInput: &resourcegroupstaggingapi.TagResourcesInput{
+				ResourceARNList: []*string{aws.String(lbArn)},
+				Tags:            map[string]*string{tags.RetainOnDelete: aws.String("true")},
+			},
+		},
+		{
+			Name:           "retain tag is kept",
+			RetainOnDelete: true,
+			CurrentTags:    map[string]string{tags.RetainOnDelete: "true"},
+		},
+		{
+			Name:           "retain and orphaned tags are removed",
+			RetainOnDelete: false,
+			CurrentTags:    map[string]string{tags.RetainOnDelete: "true", tags.Orphaned: "true"},
+			ExpectedUntagInput: &resourcegroupstaggingapi.UntagResourcesInput{
+				ResourceARNList: []*string{aws.String(lbArn)},
+				TagKeys:         aws.StringSlice([]string{tags.Orphaned, tags.RetainOnDelete}),
+			},
+		},
+		{
+			Name:           "other tags with ignored prefix are kept",
+			RetainOnDelete: false,
+			CurrentTags:    map[string]string{"alb.ingress.kubernetes.io/owner": "team"},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			var currentTags []*elbv2.Tag
+			for k, v := range tc.CurrentTags {
+				currentTags = append(currentTags, &elbv2.Tag{Key: aws.String(k), Value: aws.String(v)})
+			}
+			cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: []*string{aws.String(lbArn)}}).Return(
+				&elbv2.DescribeTagsOutput{TagDescriptions: []*elbv2.TagDescription{{ResourceArn: aws.String(lbArn), Tags: currentTags}}}, nil)
+			if tc.ExpectedTagInput != nil {
+				cloud.On("TagResourcesWithContext", ctx, tc.ExpectedTagInput).Return(&resourcegroupstaggingapi.TagResourcesOutput{}, nil)
+			}
+			if tc.ExpectedUntagInput != nil {
+				cloud.On("UntagResourcesWithContext", ctx, tc.ExpectedUntagInput).Return(&resourcegroupstaggingapi.UntagResourcesOutput{}, nil)
+			}
+
+			controller := &defaultController{
+				cloud: cloud,
+				// the tags marking resources for the controller are reconciled even if their prefix is ignored
+				tagsController: tags.NewController(cloud, []string{"alb.ingress.kubernetes.io/"}),
+			}
+			err := controller.reconcileLBTags(ctx, lbArn, &loadBalancerConfig{}, tc.RetainOnDelete)
+			assert.NoError(t, err)
+			cloud.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package lb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+// maxLBNameLength is the maximum length of LoadBalancer name allowed by ELBV2
+const maxLBNameLength = 32
+
+// replacedLBGracePeriod is how long an LoadBalancer replaced due to scheme change is kept after the status and DNS records of its ingress
+// point at the replacement, so clients that resolved its DNS name before can still reach it.
+const replacedLBGracePeriod = 5 * time.Minute
+
+// alternateLBName generates the name for the LoadBalancer replacing an LoadBalancer named lbName due to scheme change,
+// since LoadBalancer names are unique within region while both LoadBalancers exist during the change.
+func alternateLBName(lbName string) string {
+	if len(lbName) > maxLBNameLength-2 {
+		lbName = lbName[:maxLBNameLength-2]
+	}
+	return lbName + "-1"
+}
+
+// startSchemeChange creates an LoadBalancer with new scheme to replace instance, if scheme change is allowed for ingress.
+// Traffic keeps flowing to instance until the replacement becomes active, so an error is always returned to retry later.
+func (controller *defaultController) startSchemeChange(ctx context.Context, instance *elbv2.LoadBalancer, lbConfig *loadBalancerConfig) error {
+	lbName := aws.StringValue(instance.LoadBalancerName)
+	if !lbConfig.AllowSchemeChange {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "scheme of LoadBalancer %v can't be changed from %v to %v without recreating it, set the allow-scheme-change annotation to recreate it",
+			lbName, aws.StringValue(instance.Scheme), aws.StringValue(lbConfig.Scheme))
+		return fmt.Errorf("scheme of LoadBalancer %v can't be changed from %v to %v without recreation",
+			lbName, aws.StringValue(instance.Scheme), aws.StringValue(lbConfig.Scheme))
+	}
+
+	replacementConfig := *lbConfig
+	if lbName == lbConfig.Name {
+		replacementConfig.Name = alternateLBName(lbConfig.Name)
+	}
+	albctx.GetLogger(ctx).Infof("creating LoadBalancer %v to replace %v due to scheme change(%v => %v)",
+		replacementConfig.Name, lbName, aws.StringValue(instance.Scheme), aws.StringValue(lbConfig.Scheme))
+	if _, err := controller.newLBInstance(ctx, &replacementConfig); err != nil {
+		return fmt.Errorf("failed to create LoadBalancer due to %v", err)
+	}
+	albctx.GetEventf(ctx)(corev1.EventTypeNormal, "MODIFY", "LoadBalancer %v created to replace %v due to scheme change, listeners will be moved to it once it's active",
+		replacementConfig.Name, lbName)
+	return fmt.Errorf("waiting for LoadBalancer %v to become active", replacementConfig.Name)
+}
+
+// finishSchemeChange moves listeners from replacedInstance to instance once instance is active. replacedInstance is deleted
+// replacedLBGracePeriod after the status of ingress points to instance.
+func (controller *defaultController) finishSchemeChange(ctx context.Context, ingress *extensions.Ingress, instance *elbv2.LoadBalancer, replacedInstance *elbv2.LoadBalancer) error {
+	lbName := aws.StringValue(instance.LoadBalancerName)
+	replacedLBName := aws.StringValue(replacedInstance.LoadBalancerName)
+	if instance.State == nil || aws.StringValue(instance.State.Code) != elbv2.LoadBalancerStateEnumActive {
+		albctx.GetLogger(ctx).Infof("waiting for LoadBalancer %v to become active before moving traffic from %v", lbName, replacedLBName)
+		return fmt.Errorf("waiting for LoadBalancer %v to become active", lbName)
+	}
+
+	// targetGroups can only be used by one LoadBalancer, so listeners are removed from replacedInstance before they are created on instance.
+	listeners, err := controller.cloud.ListListenersByLoadBalancer(ctx, aws.StringValue(replacedInstance.LoadBalancerArn))
+	if err != nil {
+		return fmt.Errorf("failed to list listeners of %v due to %v", replacedLBName, err)
+	}
+	if len(listeners) != 0 {
+		if err := controller.lsGroupController.Delete(ctx, aws.StringValue(replacedInstance.LoadBalancerArn)); err != nil {
+			return fmt.Errorf("failed to delete listeners of %v due to %v", replacedLBName, err)
+		}
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "MODIFY", "listeners of LoadBalancer %v removed to move them to %v, requests to %v fail until clients resolve the DNS records of %v",
+			replacedLBName, lbName, replacedLBName, lbName)
+	}
+
+	// the status of ingress is only updated after DNS records are reconciled, so records of its hosts point at instance as well.
+	if !ingressStatusPointsTo(ingress, aws.StringValue(instance.DNSName)) {
+		albctx.GetLogger(ctx).Infof("LoadBalancer %v will be deleted once ingress status is updated to %v", replacedLBName, lbName)
+		return nil
+	}
+	replacedAt, err := controller.ensureReplacedAt(ctx, replacedInstance, lbName)
+	if err != nil {
+		return err
+	}
+	if deleteAt := replacedAt.Add(replacedLBGracePeriod); time.Now().Before(deleteAt) {
+		albctx.GetLogger(ctx).Infof("LoadBalancer %v will be deleted after %v", replacedLBName, deleteAt.Format(time.RFC3339))
+		return nil
+	}
+	return controller.deleteReplacedLBInstance(ctx, replacedInstance)
+}
+
+// ensureReplacedAt returns the time replacedInstance is tagged to be replaced by LoadBalancer lbName at, it's tagged with the current time if it isn't yet.
+func (controller *defaultController) ensureReplacedAt(ctx context.Context, replacedInstance *elbv2.LoadBalancer, lbName string) (time.Time, error) {
+	lbArn := aws.StringValue(replacedInstance.LoadBalancerArn)
+	lbTags, err := controller.describeLBTags(ctx, replacedInstance)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if replacedAt, err := time.Parse(time.RFC3339, lbTags[tags.ReplacedAt]); err == nil {
+		return replacedAt, nil
+	}
+
+	replacedAt := time.Now()
+	tags.ForgetELBV2Tags(ctx, lbArn)
+	if _, err := controller.cloud.TagResourcesWithContext(ctx, &resourcegroupstaggingapi.TagResourcesInput{
+		ResourceARNList: []*string{replacedInstance.LoadBalancerArn},
+		Tags:            map[string]*string{tags.ReplacedAt: aws.String(replacedAt.Format(time.RFC3339))},
+	}); err != nil {
+		return time.Time{}, fmt.Errorf("failed to tag LoadBalancer %v due to %v", lbArn, err)
+	}
+	albctx.GetEventf(ctx)(corev1.EventTypeNormal, "MODIFY", "ingress status and DNS records point at LoadBalancer %v, replaced LoadBalancer %v will be deleted in %v",
+		lbName, aws.StringValue(replacedInstance.LoadBalancerName), replacedLBGracePeriod)
+	return replacedAt, nil
+}
+
+// deleteReplacedLBInstance deletes an LoadBalancer replaced by another LoadBalancer of ingress, along with its listeners.
+func (controller *defaultController) deleteReplacedLBInstance(ctx context.Context, replacedInstance *elbv2.LoadBalancer) error {
+	lbArn := aws.StringValue(replacedInstance.LoadBalancerArn)
+	if err := controller.ensureDeletionProtectionDisabled(ctx, lbArn); err != nil {
+		return err
+	}
+	// records of hosts are pointed at the LoadBalancer replacing it already, others such as AAAA records of an dualstack LoadBalancer are left.
+	if err := controller.dnsController.Delete(ctx, replacedInstance); err != nil {
+		return fmt.Errorf("failed to delete DNS records of %v due to %v", lbArn, err)
+	}
+	if err := controller.lsGroupController.Delete(ctx, lbArn); err != nil {
+		return fmt.Errorf("failed to delete listeners of %v due to %v", lbArn, err)
+	}
+	albctx.GetLogger(ctx).Infof("deleting replaced LoadBalancer %v", lbArn)
+	if err := controller.cloud.DeleteLoadBalancerByArn(ctx, lbArn); err != nil {
+		return err
+	}
+	albctx.GetEventf(ctx)(corev1.EventTypeNormal, "DELETE", "replaced LoadBalancer %v deleted", aws.StringValue(replacedInstance.LoadBalancerName))
+	return nil
+}
+
+// ingressStatusPointsTo tests whether the status of ingress is updated to dnsName.
+func ingressStatusPointsTo(ingress *extensions.Ingress, dnsName string) bool {
+	for _, lbIngress := range ingress.Status.LoadBalancer.Ingress {
+		if lbIngress.Hostname == dnsName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package lb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/dns"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/ls"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+func TestAlternateLBName(t *testing.T) {
+	for _, tc := range []struct {
+		LBName       string
+		ExpectedName string
+	}{
+		{LBName: "web", ExpectedName: "web-1"},
+		{LBName: "a123456789012345678901234567890", ExpectedName: "a12345678901234567890123456789-1"},
+	} {
+		t.Run(tc.LBName, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedName, alternateLBName(tc.LBName))
+		})
+	}
+}
+
+func TestStartSchemeChange_NotAllowed(t *testing.T) {
+	controller := &defaultController{}
+	instance := &elbv2.LoadBalancer{
+		LoadBalancerName: aws.String("web"),
+		Scheme:           aws.String(elbv2.LoadBalancerSchemeEnumInternal),
+	}
+	lbConfig := &loadBalancerConfig{
+		Name:   "web",
+		Scheme: aws.String(elbv2.LoadBalancerSchemeEnumInternetFacing),
+	}
+	err := controller.startSchemeChange(context.Background(), instance, lbConfig)
+	assert.Equal(t, errors.New("scheme of LoadBalancer web can't be changed from internal to internet-facing without recreation"), err)
+}
+
+func TestFinishSchemeChange_NotActive(t *testing.T) {
+	controller := &defaultController{}
+	instance := &elbv2.LoadBalancer{
+		LoadBalancerName: aws.String("web-1"),
+		State:            &elbv2.LoadBalancerState{Code: aws.String(elbv2.LoadBalancerStateEnumProvisioning)},
+	}
+	replacedInstance := &elbv2.LoadBalancer{
+		LoadBalancerName: aws.String("web"),
+	}
+	err := controller.finishSchemeChange(context.Background(), &extensions.Ingress{}, instance, replacedInstance)
+	assert.Equal(t, errors.New("waiting for LoadBalancer web-1 to become active"), err)
+}
+
+func TestFinishSchemeChange(t *testing.T) {
+	instance := &elbv2.LoadBalancer{
+		LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/web-1/2"),
+		LoadBalancerName: aws.String("web-1"),
+		DNSName:          aws.String("web-1.us-west-2.elb.amazonaws.com"),
+		State:            &elbv2.LoadBalancerState{Code: aws.String(elbv2.LoadBalancerStateEnumActive)},
+	}
+	replacedInstance := &elbv2.LoadBalancer{
+		LoadBalancerArn:  aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/web/1"),
+		LoadBalancerName: aws.String("web"),
+	}
+	replacedArn := aws.StringValue(replacedInstance.LoadBalancerArn)
+	for _, tc := range []struct {
+		Name          string
+		StatusDNSName string
+		ReplacedAt    string
+		ExpectedCalls []string
+	}{
+		{
+			Name:          "replaced LoadBalancer is kept until ingress status points at the replacement",
+			StatusDNSName: "web.us-west-2.elb.amazonaws.com",
+			ExpectedCalls: []string{"DeleteListeners"},
+		},
+		{
+			Name:          "grace period starts once ingress status points at the replacement",
+			StatusDNSName: "web-1.us-west-2.elb.amazonaws.com",
+			ExpectedCalls: []string{"DeleteListeners", "TagResourcesWithContext"},
+		},
+		{
+			Name:          "replaced LoadBalancer is kept within the grace period",
+			StatusDNSName: "web-1.us-west-2.elb.amazonaws.com",
+			ReplacedAt:    time.Now().Add(-replacedLBGracePeriod / 2).Format(time.RFC3339),
+			ExpectedCalls: []string{"DeleteListeners"},
+		},
+		{
+			Name:          "replaced LoadBalancer is deleted after the grace period",
+			StatusDNSName: "web-1.us-west-2.elb.amazonaws.com",
+			ReplacedAt:    time.Now().Add(-replacedLBGracePeriod).Format(time.RFC3339),
+			ExpectedCalls: []string{"DeleteListeners", "DeleteListeners", "DeleteLoadBalancerByArn"},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			var calls []string
+			cloud := &mocks.CloudAPI{}
+			cloud.On("ListListenersByLoadBalancer", ctx, replacedArn).Return([]*elbv2.Listener{{Port: aws.Int64(80)}}, nil)
+			var currentTags []*elbv2.Tag
+			if tc.ReplacedAt != "" {
+				currentTags = append(currentTags, &elbv2.Tag{Key: aws.String(tags.ReplacedAt), Value: aws.String(tc.ReplacedAt)})
+			}
+			cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: []*string{aws.String(replacedArn)}}).Return(
+				&elbv2.DescribeTagsOutput{TagDescriptions: []*elbv2.TagDescription{{ResourceArn: aws.String(replacedArn), Tags: currentTags}}}, nil)
+			cloud.On("TagResourcesWithContext", ctx, mock.Anything).Run(func(mock.Arguments) {
+				calls = append(calls, "TagResourcesWithContext")
+			}).Return(&resourcegroupstaggingapi.TagResourcesOutput{}, nil)
+			cloud.On("DescribeLoadBalancerAttributesWithContext", ctx, &elbv2.DescribeLoadBalancerAttributesInput{LoadBalancerArn: aws.String(replacedArn)}).Return(
+				&elbv2.DescribeLoadBalancerAttributesOutput{}, nil)
+			cloud.On("DeleteLoadBalancerByArn", ctx, replacedArn).Run(func(mock.Arguments) {
+				calls = append(calls, "DeleteLoadBalancerByArn")
+			}).Return(nil)
+			lsGroupController := &ls.MockGroupController{}
+			lsGroupController.On("Delete", ctx, replacedArn).Run(func(mock.Arguments) {
+				calls = append(calls, "DeleteListeners")
+			}).Return(nil)
+
+			controller := &defaultController{
+				cloud:             cloud,
+				lsGroupController: lsGroupController,
+				dnsController:     dns.NewController(cloud, "", ""),
+			}
+			ingress := &extensions.Ingress{
+				Status: extensions.IngressStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{Hostname: tc.StatusDNSName}},
+					},
+				},
+			}
+			err := controller.finishSchemeChange(ctx, ingress, instance, replacedInstance)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ExpectedCalls, calls)
+		})
+	}
+}
+
+func TestIngressStatusPointsTo(t *testing.T) {
+	ingress := &extensions.Ingress{
+		Status: extensions.IngressStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{Hostname: "web-1.us-west-2.elb.amazonaws.com"}},
+			},
+		},
+	}
+	assert.True(t, ingressStatusPointsTo(ingress, "web-1.us-west-2.elb.amazonaws.com"))
+	assert.False(t, ingressStatusPointsTo(ingress, "web.us-west-2.elb.amazonaws.com"))
+}
@@ -1,9 +1,20 @@
 package lb
 
+import (
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/ls"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/rs"
+)
+
 // LoadBalancer contains information of LoadBalancer in AWS
 type LoadBalancer struct {
 	Arn     string
 	DNSName string
+
+	// DualstackDNSName is the DNS name of LoadBalancer prefixed with dualstack., which resolves to its IPv6 addresses as well when it's dualstack.
+	DualstackDNSName string
+
+	// CanonicalHostedZoneID is the ID of the hosted zone of LoadBalancer, which alias records pointing at it are created with.
+	CanonicalHostedZoneID string
 }
 
 // NameGenerator generates name for loadBalancer resources
@@ -14,6 +25,13 @@ type NameGenerator interface {
 // TagGenerator generates tags for loadBalancer resources
 type TagGenerator interface {
 	TagLB(namespace string, ingressName string) map[string]string
+
+	// TagTGGroup generates tags for the group of targetGroups created for a single ingress.
+	TagTGGroup(namespace string, ingressName string) map[string]string
+
+	// listeners and rules created on pre-provisioned LoadBalancers are tagged for their ingress.
+	ls.TagGenerator
+	rs.TagGenerator
 }
 
 // NameTagGenerator combines NameGenerator & TagGenerator
@@ -0,0 +1,69 @@
+package lb
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+)
+
+// zone types of subnets, the SDK version we use doesn't expose ZoneType of availability zones yet,
+// so they are derived from zone names.
+const (
+	zoneTypeAvailabilityZone = "availability-zone"
+	zoneTypeLocalZone        = "local-zone"
+	zoneTypeWavelengthZone   = "wavelength-zone"
+)
+
+// availabilityZonePattern matches names of regular availability zones, e.g. us-west-2a, us-gov-west-1a.
+// Local Zones are named after their parent region with an location suffix, e.g. us-west-2-lax-1a,
+// and Wavelength Zones contains "wlz", e.g. us-east-1-wl1-bos-wlz-1.
+var availabilityZonePattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+[a-z]$`)
+
+// zoneTypeOf determines the zone type of an availability zone by its name.
+func zoneTypeOf(zoneName string) string {
+	switch {
+	case strings.Contains(zoneName, "-wlz-"):
+		return zoneTypeWavelengthZone
+	case availabilityZonePattern.MatchString(zoneName):
+		return zoneTypeAvailabilityZone
+	default:
+		return zoneTypeLocalZone
+	}
+}
+
+// filterSubnetsByZoneType returns the subnets in zones of zoneType.
+func filterSubnetsByZoneType(subnets []*ec2.Subnet, zoneType string) []*ec2.Subnet {
+	var result []*ec2.Subnet
+	for _, subnet := range subnets {
+		if zoneTypeOf(aws.StringValue(subnet.AvailabilityZone)) == zoneType {
+			result = append(result, subnet)
+		}
+	}
+	return result
+}
+
+// validateSubnetZones makes sure ALB can be deployed into subnets: Wavelength Zone subnets are not supported,
+// and Local Zone subnets can't be used together with subnets in regular availability zones.
+func validateSubnetZones(subnets []*ec2.Subnet) error {
+	var localZoneSubnets, azSubnets []string
+	for _, subnet := range subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		zoneName := aws.StringValue(subnet.AvailabilityZone)
+		switch zoneTypeOf(zoneName) {
+		case zoneTypeWavelengthZone:
+			return fmt.Errorf("subnet %v is in Wavelength Zone %v, which is not supported by ALB", subnetID, zoneName)
+		case zoneTypeLocalZone:
+			localZoneSubnets = append(localZoneSubnets, subnetID)
+		default:
+			azSubnets = append(azSubnets, subnetID)
+		}
+	}
+	if len(localZoneSubnets) != 0 && len(azSubnets) != 0 {
+		return fmt.Errorf("subnets in Local Zones (%v) can't be used together with subnets in availability zones (%v)",
+			strings.Join(localZoneSubnets, ","), strings.Join(azSubnets, ","))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package lb
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZoneTypeOf(t *testing.T) {
+	for _, tc := range []struct {
+		ZoneName         string
+		ExpectedZoneType string
+	}{
+		{ZoneName: "us-west-2a", ExpectedZoneType: zoneTypeAvailabilityZone},
+		{ZoneName: "ap-southeast-1c", ExpectedZoneType: zoneTypeAvailabilityZone},
+		{ZoneName: "us-gov-west-1a", ExpectedZoneType: zoneTypeAvailabilityZone},
+		{ZoneName: "us-west-2-lax-1a", ExpectedZoneType: zoneTypeLocalZone},
+		{ZoneName: "us-east-1-wl1-bos-wlz-1", ExpectedZoneType: zoneTypeWavelengthZone},
+	} {
+		t.Run(tc.ZoneName, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedZoneType, zoneTypeOf(tc.ZoneName))
+		})
+	}
+}
+
+func TestValidateSubnetZones(t *testing.T) {
+	subnet := func(id string, az string) *ec2.Subnet {
+		return &ec2.Subnet{SubnetId: aws.String(id), AvailabilityZone: aws.String(az)}
+	}
+	for _, tc := range []struct {
+		Name        string
+		Subnets     []*ec2.Subnet
+		ExpectedErr error
+	}{
+		{
+			Name:    "subnets in availability zones",
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "us-west-2a"), subnet("subnet-2", "us-west-2b")},
+		},
+		{
+			Name:    "subnets in Local Zones",
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "us-west-2-lax-1a"), subnet("subnet-2", "us-west-2-lax-1b")},
+		},
+		{
+			Name:        "subnets in Local Zones and availability zones",
+			Subnets:     []*ec2.Subnet{subnet("subnet-1", "us-west-2-lax-1a"), subnet("subnet-2", "us-west-2b")},
+			ExpectedErr: errors.New("subnets in Local Zones (subnet-1) can't be used together with subnets in availability zones (subnet-2)"),
+		},
+		{
+			Name:        "subnet in Wavelength Zone",
+			Subnets:     []*ec2.Subnet{subnet("subnet-1", "us-east-1-wl1-bos-wlz-1"), subnet("subnet-2", "us-east-1b")},
+			ExpectedErr: errors.New("subnet subnet-1 is in Wavelength Zone us-east-1-wl1-bos-wlz-1, which is not supported by ALB"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedErr, validateSubnetZones(tc.Subnets))
+		})
+	}
+}
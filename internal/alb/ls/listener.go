@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/rs"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
@@ -32,17 +33,19 @@ type Controller interface {
 	Reconcile(ctx context.Context, options ReconcileOptions) error
 }
 
-func NewController(cloud aws.CloudAPI, store store.Storer, rulesController rs.Controller) Controller {
+func NewController(cloud aws.CloudAPI, store store.Storer, rulesController rs.Controller, tagGen TagGenerator) Controller {
 	return &defaultController{
 		cloud:           cloud,
 		store:           store,
+		tagGen:          tagGen,
 		rulesController: rulesController,
 	}
 }
 
 type defaultController struct {
-	cloud aws.CloudAPI
-	store store.Storer
+	cloud  aws.CloudAPI
+	store  store.Storer
+	tagGen TagGenerator
 
 	rulesController rs.Controller
 }
@@ -66,6 +69,15 @@ func (controller *defaultController) Reconcile(ctx context.Context, options Reco
 		if instance, err = controller.newLSInstance(ctx, options.LBArn, config); err != nil {
 			return fmt.Errorf("failed to create listener due to %v", err)
 		}
+		if options.IngressAnnos.LoadBalancer != nil && options.IngressAnnos.LoadBalancer.ExistingLoadBalancer != nil {
+			// listeners on pre-provisioned LoadBalancers are tagged, so they're told apart from the listeners managed by others.
+			if _, err := controller.cloud.TagResourcesWithContext(ctx, &resourcegroupstaggingapi.TagResourcesInput{
+				ResourceARNList: []*string{instance.ListenerArn},
+				Tags:            aws.StringMap(controller.tagGen.TagListener(options.Ingress.Namespace, options.Ingress.Name)),
+			}); err != nil {
+				return fmt.Errorf("failed to tag listener due to %v", err)
+			}
+		}
 	} else {
 		if instance, err = controller.reconcileLSInstance(ctx, instance, config); err != nil {
 			return fmt.Errorf("failed to reconcile listener due to %v", err)
@@ -105,6 +117,10 @@ func (controller *defaultController) reconcileLSInstance(ctx context.Context, in
 		if err != nil {
 			return instance, err
 		}
+		if len(output.Listeners) == 0 {
+			// output is empty in dry-run
+			return instance, nil
+		}
 		return output.Listeners[0], nil
 	}
 	return instance, nil
@@ -2,13 +2,19 @@ package ls
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/rs"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -21,24 +27,32 @@ type GroupController interface {
 	Delete(ctx context.Context, lbArn string) error
 }
 
-func NewGroupController(store store.Storer, cloud aws.CloudAPI, rulesController rs.Controller) GroupController {
-	lsController := NewController(cloud, store, rulesController)
+// TagGenerator generates tags for listeners
+type TagGenerator interface {
+	// TagListener generates tags for the listeners created for ingress on pre-provisioned LoadBalancers, which listeners are owned by ingress is told by them.
+	TagListener(namespace string, ingressName string) map[string]string
+}
+
+func NewGroupController(store store.Storer, cloud aws.CloudAPI, rulesController rs.Controller, tagGen TagGenerator) GroupController {
+	lsController := NewController(cloud, store, rulesController, tagGen)
 	return &defaultGroupController{
 		cloud:        cloud,
 		store:        store,
+		tagGen:       tagGen,
 		lsController: lsController,
 	}
 }
 
 type defaultGroupController struct {
-	cloud aws.CloudAPI
-	store store.Storer
+	cloud  aws.CloudAPI
+	store  store.Storer
+	tagGen TagGenerator
 
 	lsController Controller
 }
 
 func (controller *defaultGroupController) Reconcile(ctx context.Context, lbArn string, ingress *extensions.Ingress, tgGroup tg.TargetGroupGroup) error {
-	ingressAnnos, err := controller.store.GetIngressAnnotations(k8s.MetaNamespaceKey(ingress))
+	ingressAnnos, err := controller.store.GetIngressAnnotations(k8s.IngressAnnotationsKey(ingress))
 	if err != nil {
 		return err
 	}
@@ -46,32 +60,79 @@ func (controller *defaultGroupController) Reconcile(ctx context.Context, lbArn s
 	if err != nil {
 		return err
 	}
+	ownedPorts := sets.Int64KeySet(instancesByPort)
+	if ingressAnnos.LoadBalancer.ExistingLoadBalancer != nil {
+		if ownedPorts, err = controller.findOwnedPorts(ctx, ingress, instancesByPort); err != nil {
+			return err
+		}
+		// listeners on pre-provisioned LoadBalancers may be managed by others, ports already taken by them are refused instead of being overwritten.
+		for _, port := range ingressAnnos.LoadBalancer.Ports {
+			if _, ok := instancesByPort[port.Port]; ok && !ownedPorts.Has(port.Port) {
+				albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "listener on port %v of existing LoadBalancer isn't managed by ingress", port.Port)
+				return fmt.Errorf("listener on port %v of existing LoadBalancer isn't managed by ingress", port.Port)
+			}
+		}
+	}
 
+	// listeners for new ports are created & filled with rules first, and listeners for removed ports are only deleted
+	// after all other listeners are reconciled, so changes to ports never interrupt traffic on the surviving ports.
+	// Rules aren't copied from removed listeners, the rules of every listener are built from ingress, so new listeners
+	// get the same rules as the surviving ones.
+	var newPorts, survivingPorts []loadbalancer.PortData
 	portsInUse := sets.NewInt64()
 	for _, port := range ingressAnnos.LoadBalancer.Ports {
 		portsInUse.Insert(port.Port)
-		instance := instancesByPort[port.Port]
+		if _, ok := instancesByPort[port.Port]; ok {
+			survivingPorts = append(survivingPorts, port)
+		} else {
+			newPorts = append(newPorts, port)
+		}
+	}
+	for _, port := range append(newPorts, survivingPorts...) {
 		if err := controller.lsController.Reconcile(ctx, ReconcileOptions{
 			LBArn:        lbArn,
 			Ingress:      ingress,
 			IngressAnnos: ingressAnnos,
 			Port:         port,
 			TGGroup:      tgGroup,
-			Instance:     instance,
+			Instance:     instancesByPort[port.Port],
 		}); err != nil {
 			return err
 		}
 	}
-	portsUnsed := sets.Int64KeySet(instancesByPort).Difference(portsInUse)
-	for port := range portsUnsed {
+	portsUnsed := ownedPorts.Difference(portsInUse)
+	for _, port := range portsUnsed.List() {
 		instance := instancesByPort[port]
+		albctx.GetLogger(ctx).Infof("deleting listener on port %v since it's removed from ingress", port)
 		if err := controller.cloud.DeleteListenersByArn(ctx, aws.StringValue(instance.ListenerArn)); err != nil {
 			return err
 		}
+		albctx.GetEventf(ctx)(corev1.EventTypeNormal, "DELETE", "listener on port %v deleted", port)
 	}
 	return nil
 }
 
+// findOwnedPorts returns the ports of instancesByPort whose listeners are tagged for ingress.
+func (controller *defaultGroupController) findOwnedPorts(ctx context.Context, ingress *extensions.Ingress, instancesByPort map[int64]*elbv2.Listener) (sets.Int64, error) {
+	var lsArns []string
+	for _, instance := range instancesByPort {
+		lsArns = append(lsArns, aws.StringValue(instance.ListenerArn))
+	}
+	sort.Strings(lsArns)
+	lsTags, err := tags.DescribeELBV2TagsOfArns(ctx, controller.cloud, lsArns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe tags of listeners due to %v", err)
+	}
+	ownerTags := controller.tagGen.TagListener(ingress.Namespace, ingress.Name)
+	ownedPorts := sets.NewInt64()
+	for port, instance := range instancesByPort {
+		if t, ok := lsTags[aws.StringValue(instance.ListenerArn)]; ok && t.Contains(ownerTags) {
+			ownedPorts.Insert(port)
+		}
+	}
+	return ownedPorts, nil
+}
+
 func (controller *defaultGroupController) Delete(ctx context.Context, lbArn string) error {
 	instancesByPort, err := controller.loadListenerInstances(ctx, lbArn)
 	if err != nil {
@@ -35,6 +35,16 @@ type LSControllerReconcileCall struct {
 	Err      error
 }
 
+type DescribeTagsCall struct {
+	LSArns          []string
+	TagDescriptions []*elbv2.TagDescription
+}
+
+var ingressTags = []*elbv2.Tag{
+	{Key: aws.String("kubernetes.io/ingress-name"), Value: aws.String("ingress")},
+	{Key: aws.String("kubernetes.io/namespace"), Value: aws.String("namespace")},
+}
+
 type DeleteListenersByArnCall struct {
 	LSArn string
 	Err   error
@@ -54,6 +64,7 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 		GetIngressAnnotationsCall       *GetIngressAnnotationsCall
 		ListListenersByLoadBalancerCall *ListListenersByLoadBalancerCall
 		LSControllerReconcileCalls      []LSControllerReconcileCall
+		DescribeTagsCall                *DescribeTagsCall
 		DeleteListenersByArnCalls       []DeleteListenersByArnCall
 		ExpectedErr                     error
 	}{
@@ -268,6 +279,49 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 			},
 			ExpectedErr: errors.New("LSControllerReconcileCalls"),
 		},
+		{
+			Name: "Reconcile keeps removed listeners when creating new listener failed",
+			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
+				Key: "namespace/ingress",
+				IngressAnnos: &annotations.Ingress{
+					LoadBalancer: &loadbalancer.Config{
+						Ports: []loadbalancer.PortData{
+							{
+								Port:   80,
+								Scheme: elbv2.ProtocolEnumHttp,
+							},
+							{
+								Port:   8080,
+								Scheme: elbv2.ProtocolEnumHttp,
+							},
+						},
+					},
+				},
+			},
+			ListListenersByLoadBalancerCall: &ListListenersByLoadBalancerCall{
+				Listeners: []*elbv2.Listener{
+					{
+						ListenerArn: aws.String("lsArn1"),
+						Port:        aws.Int64(80),
+					},
+					{
+						ListenerArn: aws.String("lsArn2"),
+						Port:        aws.Int64(443),
+					},
+				},
+			},
+			LSControllerReconcileCalls: []LSControllerReconcileCall{
+				{
+					Port: loadbalancer.PortData{
+						Port:   8080,
+						Scheme: elbv2.ProtocolEnumHttp,
+					},
+					Instance: nil,
+					Err:      errors.New("LSControllerReconcileCalls"),
+				},
+			},
+			ExpectedErr: errors.New("LSControllerReconcileCalls"),
+		},
 		{
 			Name: "Reconcile failed when deleting listener",
 			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
@@ -315,6 +369,93 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 			},
 			ExpectedErr: errors.New("DeleteListenersByArnCall"),
 		},
+		{
+			Name: "Reconcile succeed by keeping listeners not owned on existing LoadBalancer",
+			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
+				Key: "namespace/ingress",
+				IngressAnnos: &annotations.Ingress{
+					LoadBalancer: &loadbalancer.Config{
+						ExistingLoadBalancer: aws.String("existing-lb"),
+						Ports: []loadbalancer.PortData{
+							{
+								Port:   80,
+								Scheme: elbv2.ProtocolEnumHttp,
+							},
+						},
+					},
+				},
+			},
+			ListListenersByLoadBalancerCall: &ListListenersByLoadBalancerCall{
+				Listeners: []*elbv2.Listener{
+					{
+						ListenerArn: aws.String("lsArn1"),
+						Port:        aws.Int64(80),
+					},
+					{
+						ListenerArn: aws.String("lsArn2"),
+						Port:        aws.Int64(8080),
+					},
+					{
+						ListenerArn: aws.String("lsArn3"),
+						Port:        aws.Int64(9090),
+					},
+				},
+			},
+			DescribeTagsCall: &DescribeTagsCall{
+				LSArns: []string{"lsArn1", "lsArn2", "lsArn3"},
+				TagDescriptions: []*elbv2.TagDescription{
+					{ResourceArn: aws.String("lsArn1"), Tags: ingressTags},
+					{ResourceArn: aws.String("lsArn2"), Tags: []*elbv2.Tag{{Key: aws.String("team"), Value: aws.String("network")}}},
+					{ResourceArn: aws.String("lsArn3"), Tags: ingressTags},
+				},
+			},
+			LSControllerReconcileCalls: []LSControllerReconcileCall{
+				{
+					Port: loadbalancer.PortData{
+						Port:   80,
+						Scheme: elbv2.ProtocolEnumHttp,
+					},
+					Instance: &elbv2.Listener{
+						ListenerArn: aws.String("lsArn1"),
+						Port:        aws.Int64(80),
+					},
+				},
+			},
+			DeleteListenersByArnCalls: []DeleteListenersByArnCall{
+				{
+					LSArn: "lsArn3",
+				},
+			},
+		},
+		{
+			Name: "Reconcile failed when port of existing LoadBalancer is taken by listener not owned",
+			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
+				Key: "namespace/ingress",
+				IngressAnnos: &annotations.Ingress{
+					LoadBalancer: &loadbalancer.Config{
+						ExistingLoadBalancer: aws.String("existing-lb"),
+						Ports: []loadbalancer.PortData{
+							{
+								Port:   80,
+								Scheme: elbv2.ProtocolEnumHttp,
+							},
+						},
+					},
+				},
+			},
+			ListListenersByLoadBalancerCall: &ListListenersByLoadBalancerCall{
+				Listeners: []*elbv2.Listener{
+					{
+						ListenerArn: aws.String("lsArn1"),
+						Port:        aws.Int64(80),
+					},
+				},
+			},
+			DescribeTagsCall: &DescribeTagsCall{
+				LSArns: []string{"lsArn1"},
+			},
+			ExpectedErr: errors.New("listener on port 80 of existing LoadBalancer isn't managed by ingress"),
+		},
 	} {
 		t.Run(tc.Name, func(t *testing.T) {
 			ctx := context.Background()
@@ -325,6 +466,11 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 			for _, call := range tc.DeleteListenersByArnCalls {
 				cloud.On("DeleteListenersByArn", ctx, call.LSArn).Return(call.Err)
 			}
+			if tc.DescribeTagsCall != nil {
+				cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice(tc.DescribeTagsCall.LSArns)}).Return(&elbv2.DescribeTagsOutput{TagDescriptions: tc.DescribeTagsCall.TagDescriptions}, nil)
+			}
+			mockTagGen := &MockTagGenerator{}
+			mockTagGen.On("TagListener", "namespace", "ingress").Return(map[string]string{"kubernetes.io/ingress-name": "ingress", "kubernetes.io/namespace": "namespace"})
 
 			mockStore := &store.MockStorer{}
 			if tc.GetIngressAnnotationsCall != nil {
@@ -345,6 +491,7 @@ func TestDefaultGroupController_Reconcile(t *testing.T) {
 			controller := &defaultGroupController{
 				cloud:        cloud,
 				store:        mockStore,
+				tagGen:       mockTagGen,
 				lsController: mockLSController,
 			}
 
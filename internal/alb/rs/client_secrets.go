@@ -0,0 +1,73 @@
+package rs
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+)
+
+// clientSecrets records the client secrets of the authenticate-oidc actions of rules, which AWS doesn't describe,
+// so rules are modified when client secrets are rotated, instead of on every reconcile.
+type clientSecrets struct {
+	// mutex protects secrets, the client secrets of rules by their ARNs
+	mutex   sync.Mutex
+	secrets map[string]string
+}
+
+func newClientSecrets() *clientSecrets {
+	return &clientSecrets{secrets: make(map[string]string)}
+}
+
+// restore returns a copy of rule whose authenticate-oidc actions have the client secret recorded for it,
+// it's left unset for rules not recorded since the controller started, which are modified once.
+func (c *clientSecrets) restore(rule elbv2.Rule) elbv2.Rule {
+	if c == nil {
+		return rule
+	}
+	c.mutex.Lock()
+	secret, ok := c.secrets[aws.StringValue(rule.RuleArn)]
+	c.mutex.Unlock()
+	if !ok {
+		return rule
+	}
+	var actions []*elbv2.Action
+	for _, action := range rule.Actions {
+		if action.AuthenticateOidcConfig != nil {
+			oidcConfig := *action.AuthenticateOidcConfig
+			oidcConfig.ClientSecret = aws.String(secret)
+			restored := *action
+			restored.AuthenticateOidcConfig = &oidcConfig
+			action = &restored
+		}
+		actions = append(actions, action)
+	}
+	rule.Actions = actions
+	return rule
+}
+
+// record records the client secret of the authenticate-oidc actions of rule, once it's created or modified.
+func (c *clientSecrets) record(ruleArn string, rule elbv2.Rule) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, action := range rule.Actions {
+		if action.AuthenticateOidcConfig != nil {
+			c.secrets[ruleArn] = aws.StringValue(action.AuthenticateOidcConfig.ClientSecret)
+			return
+		}
+	}
+	delete(c.secrets, ruleArn)
+}
+
+// forget forgets the client secret of the rule ruleArn once it's deleted.
+func (c *clientSecrets) forget(ruleArn string) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.secrets, ruleArn)
+}
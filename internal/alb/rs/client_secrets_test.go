@@ -0,0 +1,210 @@
+package rs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/auth"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func oidcAuth(clientSecret string) *auth.Config {
+	config := auth.Dummy()
+	config.Type = auth.TypeOIDC
+	config.IDPOIDC = &auth.IDPOIDC{
+		Issuer:                "https://idp",
+		AuthorizationEndpoint: "https://idp/authorize",
+		TokenEndpoint:         "https://idp/token",
+		UserInfoEndpoint:      "https://idp/userinfo",
+		SecretName:            "oidc",
+		ClientID:              "id",
+		ClientSecret:          clientSecret,
+	}
+	return config
+}
+
+func Test_authenticatedActions(t *testing.T) {
+	forward := func() *elbv2.Action {
+		return &elbv2.Action{Type: aws.String(elbv2.ActionTypeEnumForward), TargetGroupArn: aws.String("tgArn")}
+	}
+	authAction := oidcAuth("secret").Action()
+	authAction.Order = aws.Int64(1)
+
+	for _, tc := range []struct {
+		Name         string
+		Protocol     string
+		IngressAnnos *annotations.Ingress
+		Expected     []*elbv2.Action
+	}{
+		{
+			Name:         "users aren't authenticated",
+			Protocol:     elbv2.ProtocolEnumHttps,
+			IngressAnnos: annotations.NewIngressDummy(),
+			Expected:     []*elbv2.Action{forward()},
+		},
+		{
+			Name:         "users are authenticated on HTTPS listeners",
+			Protocol:     elbv2.ProtocolEnumHttps,
+			IngressAnnos: &annotations.Ingress{Auth: oidcAuth("secret")},
+			Expected:     []*elbv2.Action{authAction, {Type: aws.String(elbv2.ActionTypeEnumForward), TargetGroupArn: aws.String("tgArn"), Order: aws.Int64(2)}},
+		},
+		{
+			Name:         "users aren't authenticated on HTTP listeners",
+			Protocol:     elbv2.ProtocolEnumHttp,
+			IngressAnnos: &annotations.Ingress{Auth: oidcAuth("secret")},
+			Expected:     []*elbv2.Action{forward()},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, authenticatedActions(&elbv2.Listener{Protocol: aws.String(tc.Protocol)}, tc.IngressAnnos, forward()))
+		})
+	}
+}
+
+func Test_Reconcile_clientSecrets(t *testing.T) {
+	ctx := context.Background()
+	listener := &elbv2.Listener{ListenerArn: aws.String("lsArn"), Protocol: aws.String(elbv2.ProtocolEnumHttps)}
+	ingress := ingRules(ingRule(extensions.HTTPIngressPath{
+		Path:    "/*",
+		Backend: backend("service1", intstr.FromString("http")),
+	}))
+	tgGroup := tg.TargetGroupGroup{
+		TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+			{ServiceName: "service1", ServicePort: intstr.FromString("http")}: {Arn: "tgArn"},
+		},
+	}
+	// AWS doesn't describe client secrets
+	current := func() []elbv2.Rule {
+		authAction := oidcAuth("").Action()
+		authAction.AuthenticateOidcConfig.ClientSecret = nil
+		authAction.Order = aws.Int64(1)
+		return []elbv2.Rule{
+			{
+				RuleArn:    aws.String("ruleArn"),
+				IsDefault:  aws.Bool(false),
+				Priority:   aws.String("1"),
+				Conditions: conditions(condition("path-pattern", "/*")),
+				Actions: []*elbv2.Action{
+					authAction,
+					{Type: aws.String(elbv2.ActionTypeEnumForward), TargetGroupArn: aws.String("tgArn"), Order: aws.Int64(2)},
+				},
+			},
+		}
+	}
+
+	secrets := newClientSecrets()
+	for _, step := range []struct {
+		Name           string
+		ClientSecret   string
+		ExpectedModify bool
+	}{
+		{
+			Name:           "rules not recorded since the controller started are modified",
+			ClientSecret:   "secret",
+			ExpectedModify: true,
+		},
+		{
+			Name:         "rules whose client secret is applied aren't modified",
+			ClientSecret: "secret",
+		},
+		{
+			Name:           "rules whose client secret is rotated are modified",
+			ClientSecret:   "rotated",
+			ExpectedModify: true,
+		},
+	} {
+		t.Run(step.Name, func(t *testing.T) {
+			cloud := &mocks.CloudAPI{}
+			cloud.On("GetRules", ctx, "lsArn").Return(func() []*elbv2.Rule {
+				var rules []*elbv2.Rule
+				for _, rule := range current() {
+					r := rule
+					rules = append(rules, &r)
+				}
+				return rules
+			}(), nil)
+			if step.ExpectedModify {
+				cloud.On("ModifyRuleWithContext", ctx, mock.AnythingOfType("*elbv2.ModifyRuleInput")).Return(&elbv2.ModifyRuleOutput{}, nil)
+			}
+			controller := NewController(cloud, nil).(*defaultController)
+			controller.clientSecrets = secrets
+			err := controller.Reconcile(ctx, listener, ingress, &annotations.Ingress{Auth: oidcAuth(step.ClientSecret)}, tgGroup)
+			assert.NoError(t, err)
+			cloud.AssertExpectations(t)
+			if !step.ExpectedModify {
+				cloud.AssertNotCalled(t, "ModifyRuleWithContext", ctx, mock.AnythingOfType("*elbv2.ModifyRuleInput"))
+			}
+		})
+	}
+}
+
+func Test_resolveClientSecrets(t *testing.T) {
+	ctx := context.Background()
+	secretArn := "arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc"
+	secretsManagerAuth := func() *auth.Config {
+		config := oidcAuth("")
+		config.IDPOIDC.SecretName = ""
+		config.IDPOIDC.SecretArn = secretArn
+		return config
+	}
+	listener := &elbv2.Listener{Protocol: aws.String(elbv2.ProtocolEnumHttps)}
+	desired := func(config *auth.Config) []elbv2.Rule {
+		return []elbv2.Rule{
+			{Actions: authenticatedActions(listener, &annotations.Ingress{Auth: config}, &elbv2.Action{Type: aws.String(elbv2.ActionTypeEnumForward)})},
+			{Actions: authenticatedActions(listener, &annotations.Ingress{Auth: config}, &elbv2.Action{Type: aws.String(elbv2.ActionTypeEnumForward)})},
+		}
+	}
+
+	for _, tc := range []struct {
+		Name                 string
+		Auth                 *auth.Config
+		GetSecretStringError error
+		ExpectedClientSecret *string
+		ExpectedError        error
+	}{
+		{
+			Name:                 "client secrets of kubernetes secrets are kept",
+			Auth:                 oidcAuth("secret"),
+			ExpectedClientSecret: aws.String("secret"),
+		},
+		{
+			Name:                 "client secrets are read from secrets manager once",
+			Auth:                 secretsManagerAuth(),
+			ExpectedClientSecret: aws.String("fetched"),
+		},
+		{
+			Name:                 "secrets manager fails",
+			Auth:                 secretsManagerAuth(),
+			GetSecretStringError: errors.New("access denied"),
+			ExpectedError:        errors.New("failed to get client secret arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc of auth-idp-oidc due to access denied"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			cloud := &mocks.CloudAPI{}
+			if tc.Auth.ClientSecretArn() != "" {
+				cloud.On("GetSecretString", ctx, secretArn).Return("fetched", tc.GetSecretStringError).Once()
+			}
+			controller := &defaultController{cloud: cloud}
+			rules := desired(tc.Auth)
+			err := controller.resolveClientSecrets(ctx, &annotations.Ingress{Auth: tc.Auth}, rules)
+			cloud.AssertExpectations(t)
+			if tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			for _, rule := range rules {
+				assert.Equal(t, tc.ExpectedClientSecret, rule.Actions[0].AuthenticateOidcConfig.ClientSecret)
+			}
+		})
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
@@ -26,10 +28,18 @@ type Controller interface {
 	Reconcile(ctx context.Context, listener *elbv2.Listener, ingress *extensions.Ingress, ingressAnnos *annotations.Ingress, tgGroup tg.TargetGroupGroup) error
 }
 
+// TagGenerator generates tags for rules
+type TagGenerator interface {
+	// TagRule generates tags for the rules created for ingress on pre-provisioned LoadBalancers, which rules are owned by ingress is told by them.
+	TagRule(namespace string, ingressName string) map[string]string
+}
+
 // NewController constructs a new rules controller
-func NewController(cloud aws.CloudAPI) Controller {
+func NewController(cloud aws.CloudAPI, tagGen TagGenerator) Controller {
 	c := &defaultController{
-		cloud: cloud,
+		cloud:         cloud,
+		tagGen:        tagGen,
+		clientSecrets: newClientSecrets(),
 	}
 	c.getCurrentRulesFunc = c.getCurrentRules
 	c.getDesiredRulesFunc = c.getDesiredRules
@@ -38,6 +48,8 @@ func NewController(cloud aws.CloudAPI) Controller {
 
 type defaultController struct {
 	cloud               aws.CloudAPI
+	tagGen              TagGenerator
+	clientSecrets       *clientSecrets
 	getCurrentRulesFunc func(context.Context, string) ([]elbv2.Rule, error)
 	getDesiredRulesFunc func(*elbv2.Listener, *extensions.Ingress, *annotations.Ingress, tg.TargetGroupGroup) ([]elbv2.Rule, error)
 }
@@ -48,11 +60,25 @@ func (c *defaultController) Reconcile(ctx context.Context, listener *elbv2.Liste
 	if err != nil {
 		return err
 	}
+	if err := c.resolveClientSecrets(ctx, ingressAnnos, desired); err != nil {
+		return err
+	}
 	lsArn := aws.StringValue(listener.ListenerArn)
 	current, err := c.getCurrentRulesFunc(ctx, lsArn)
 	if err != nil {
 		return err
 	}
+	var ownerTags map[string]string
+	if ingressAnnos.LoadBalancer != nil && ingressAnnos.LoadBalancer.ExistingLoadBalancer != nil {
+		// rules on pre-provisioned LoadBalancers may be managed by others, only the ones tagged for ingress are changed.
+		ownerTags = c.tagGen.TagRule(ingress.Namespace, ingress.Name)
+		if current, err = c.filterOwnedRules(ctx, lsArn, current, desired, ownerTags); err != nil {
+			return err
+		}
+	}
+	for i := range current {
+		current[i] = c.clientSecrets.restore(current[i])
+	}
 	additions, modifies, removals := rulesChangeSets(current, desired)
 
 	for _, rule := range additions {
@@ -65,12 +91,24 @@ func (c *defaultController) Reconcile(ctx context.Context, listener *elbv2.Liste
 			Priority:    aws.Int64(priority),
 		}
 
-		if _, err := c.cloud.CreateRuleWithContext(ctx, in); err != nil {
+		resp, err := c.cloud.CreateRuleWithContext(ctx, in)
+		if err != nil {
 			msg := fmt.Sprintf("failed creating rule %v on %v due to %v", aws.StringValue(rule.Priority), lsArn, err)
 			albctx.GetLogger(ctx).Errorf(msg)
 			albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", msg)
 			return fmt.Errorf(msg)
 		}
+		if resp != nil && len(resp.Rules) != 0 {
+			c.clientSecrets.record(aws.StringValue(resp.Rules[0].RuleArn), rule)
+		}
+		if len(ownerTags) != 0 && len(resp.Rules) != 0 {
+			if _, err := c.cloud.TagResourcesWithContext(ctx, &resourcegroupstaggingapi.TagResourcesInput{
+				ResourceARNList: []*string{resp.Rules[0].RuleArn},
+				Tags:            aws.StringMap(ownerTags),
+			}); err != nil {
+				return fmt.Errorf("failed to tag rule %v on %v due to %v", aws.StringValue(rule.Priority), lsArn, err)
+			}
+		}
 
 		msg := fmt.Sprintf("rule %v created with conditions %v", aws.StringValue(rule.Priority), log.Prettify(rule.Conditions))
 		albctx.GetLogger(ctx).Infof(msg)
@@ -91,6 +129,7 @@ func (c *defaultController) Reconcile(ctx context.Context, listener *elbv2.Liste
 			albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", msg)
 			return fmt.Errorf(msg)
 		}
+		c.clientSecrets.record(aws.StringValue(rule.RuleArn), rule)
 
 		msg := fmt.Sprintf("rule %v modified with conditions %v", aws.StringValue(rule.Priority), log.Prettify(rule.Conditions))
 		albctx.GetEventf(ctx)(api.EventTypeNormal, "MODIFY", msg)
@@ -107,6 +146,7 @@ func (c *defaultController) Reconcile(ctx context.Context, listener *elbv2.Liste
 			albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", msg)
 			return fmt.Errorf(msg)
 		}
+		c.clientSecrets.forget(aws.StringValue(rule.RuleArn))
 
 		msg := fmt.Sprintf("rule %v deleted with conditions %v", aws.StringValue(rule.Priority), log.Prettify(rule.Conditions))
 		albctx.GetEventf(ctx)(api.EventTypeNormal, "DELETE", msg)
@@ -115,6 +155,62 @@ func (c *defaultController) Reconcile(ctx context.Context, listener *elbv2.Liste
 	return nil
 }
 
+// resolveClientSecrets sets the client secret of the authenticate-oidc actions of desired to the value of the Secrets Manager secret of ingressAnnos, if it's read from Secrets Manager.
+// The value is cached until the secret is rotated.
+func (c *defaultController) resolveClientSecrets(ctx context.Context, ingressAnnos *annotations.Ingress, desired []elbv2.Rule) error {
+	if ingressAnnos == nil || ingressAnnos.Auth.ClientSecretArn() == "" {
+		return nil
+	}
+	secretArn := ingressAnnos.Auth.ClientSecretArn()
+	var clientSecret *string
+	for _, rule := range desired {
+		for _, action := range rule.Actions {
+			if action.AuthenticateOidcConfig == nil || action.AuthenticateOidcConfig.ClientSecret != nil {
+				continue
+			}
+			if clientSecret == nil {
+				value, err := c.cloud.GetSecretString(ctx, secretArn)
+				if err != nil {
+					albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "failed to get client secret %v of auth-idp-oidc due to %v", secretArn, err)
+					return fmt.Errorf("failed to get client secret %v of auth-idp-oidc due to %v", secretArn, err)
+				}
+				clientSecret = aws.String(value)
+			}
+			action.AuthenticateOidcConfig.ClientSecret = clientSecret
+		}
+	}
+	return nil
+}
+
+// filterOwnedRules returns the rules of current tagged with ownerTags. An error is returned if any desired rule takes the priority of an rule not owned,
+// which would otherwise be overwritten.
+func (c *defaultController) filterOwnedRules(ctx context.Context, lsArn string, current []elbv2.Rule, desired []elbv2.Rule, ownerTags map[string]string) ([]elbv2.Rule, error) {
+	var ruleArns []string
+	for _, rule := range current {
+		ruleArns = append(ruleArns, aws.StringValue(rule.RuleArn))
+	}
+	ruleTags, err := tags.DescribeELBV2TagsOfArns(ctx, c.cloud, ruleArns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe tags of rules on %v due to %v", lsArn, err)
+	}
+	desiredPriorities := sets.NewString()
+	for _, rule := range desired {
+		desiredPriorities.Insert(aws.StringValue(rule.Priority))
+	}
+	var owned []elbv2.Rule
+	for _, rule := range current {
+		if t, ok := ruleTags[aws.StringValue(rule.RuleArn)]; ok && t.Contains(ownerTags) {
+			owned = append(owned, rule)
+			continue
+		}
+		if desiredPriorities.Has(aws.StringValue(rule.Priority)) {
+			albctx.GetEventf(ctx)(api.EventTypeWarning, "ERROR", "rule %v on %v isn't managed by ingress, it can't be replaced", aws.StringValue(rule.Priority), lsArn)
+			return nil, fmt.Errorf("rule %v on %v isn't managed by ingress, it can't be replaced", aws.StringValue(rule.Priority), lsArn)
+		}
+	}
+	return owned, nil
+}
+
 func (c *defaultController) getDesiredRules(listener *elbv2.Listener, ingress *extensions.Ingress, ingressAnnos *annotations.Ingress, tgGroup tg.TargetGroupGroup) ([]elbv2.Rule, error) {
 	var output []elbv2.Rule
 
@@ -144,12 +240,10 @@ func (c *defaultController) getDesiredRules(listener *elbv2.Listener, ingress *e
 					return nil, fmt.Errorf("unable to locate a target group for backend %v:%v",
 						path.Backend.ServiceName, path.Backend.ServicePort.String())
 				}
-				elbRule.Actions = []*elbv2.Action{
-					{
-						Type:           aws.String(elbv2.ActionTypeEnumForward),
-						TargetGroupArn: aws.String(targetGroup.Arn),
-					},
-				}
+				elbRule.Actions = authenticatedActions(listener, ingressAnnos, &elbv2.Action{
+					Type:           aws.String(elbv2.ActionTypeEnumForward),
+					TargetGroupArn: aws.String(targetGroup.Arn),
+				})
 			}
 
 			if ingressRule.Host != "" {
@@ -170,6 +264,21 @@ func (c *defaultController) getDesiredRules(listener *elbv2.Listener, ingress *e
 	return output, nil
 }
 
+// authenticatedActions returns the actions of rules forwarding to backends, forward is preceded by an action authenticating users if ingress authenticates them.
+// ALBs only authenticate users on HTTPS listeners, rules of other listeners only forward.
+func authenticatedActions(listener *elbv2.Listener, ingressAnnos *annotations.Ingress, forward *elbv2.Action) []*elbv2.Action {
+	if ingressAnnos == nil || aws.StringValue(listener.Protocol) != elbv2.ProtocolEnumHttps {
+		return []*elbv2.Action{forward}
+	}
+	authAction := ingressAnnos.Auth.Action()
+	if authAction == nil {
+		return []*elbv2.Action{forward}
+	}
+	authAction.Order = aws.Int64(1)
+	forward.Order = aws.Int64(2)
+	return []*elbv2.Action{authAction, forward}
+}
+
 func (c *defaultController) getCurrentRules(ctx context.Context, listenerArn string) (results []elbv2.Rule, err error) {
 	rules, err := c.cloud.GetRules(ctx, listenerArn)
 	if err != nil {
@@ -10,7 +10,9 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	"github.com/stretchr/testify/assert"
 	extensions "k8s.io/api/extensions/v1beta1"
@@ -418,6 +420,130 @@ func Test_Reconcile(t *testing.T) {
 	}
 }
 
+func Test_Reconcile_existingLoadBalancer(t *testing.T) {
+	listenerArn := aws.String("lsArn")
+	tgArn := aws.String("tgArn")
+	ownerTags := map[string]string{"kubernetes.io/namespace": "namespace", "kubernetes.io/ingress-name": "ingress"}
+	ingressTags := []*elbv2.Tag{
+		{Key: aws.String("kubernetes.io/ingress-name"), Value: aws.String("ingress")},
+		{Key: aws.String("kubernetes.io/namespace"), Value: aws.String("namespace")},
+	}
+	current := []elbv2.Rule{
+		{
+			RuleArn:    aws.String("ruleArn1"),
+			Conditions: conditions(condition("path-pattern", "/a")),
+			Actions:    actions(&elbv2.Action{TargetGroupArn: tgArn}, elbv2.ActionTypeEnumForward),
+			Priority:   aws.String("1"),
+		},
+		{
+			RuleArn:    aws.String("ruleArn2"),
+			Conditions: conditions(condition("path-pattern", "/other-team")),
+			Actions:    actions(&elbv2.Action{TargetGroupArn: aws.String("otherTGArn")}, elbv2.ActionTypeEnumForward),
+			Priority:   aws.String("2"),
+		},
+		{
+			RuleArn:    aws.String("ruleArn3"),
+			Conditions: conditions(condition("path-pattern", "/b")),
+			Actions:    actions(&elbv2.Action{TargetGroupArn: tgArn}, elbv2.ActionTypeEnumForward),
+			Priority:   aws.String("3"),
+		},
+	}
+	for _, tc := range []struct {
+		Name           string
+		Desired        []elbv2.Rule
+		CreateRuleCall *CreateRuleCall
+		DeleteRuleCall *DeleteRuleCall
+		ExpectedError  error
+	}{
+		{
+			Name: "rules not owned are kept",
+			Desired: []elbv2.Rule{
+				{
+					Conditions: conditions(condition("path-pattern", "/a")),
+					Actions:    actions(&elbv2.Action{TargetGroupArn: tgArn}, elbv2.ActionTypeEnumForward),
+					Priority:   aws.String("1"),
+				},
+				{
+					Conditions: conditions(condition("path-pattern", "/c")),
+					Actions:    actions(&elbv2.Action{TargetGroupArn: tgArn}, elbv2.ActionTypeEnumForward),
+					Priority:   aws.String("4"),
+				},
+			},
+			CreateRuleCall: &CreateRuleCall{
+				Input: &elbv2.CreateRuleInput{
+					ListenerArn: listenerArn,
+					Priority:    aws.Int64(4),
+					Conditions:  conditions(condition("path-pattern", "/c")),
+					Actions:     actions(&elbv2.Action{TargetGroupArn: tgArn}, elbv2.ActionTypeEnumForward),
+				},
+			},
+			DeleteRuleCall: &DeleteRuleCall{
+				Input: &elbv2.DeleteRuleInput{RuleArn: aws.String("ruleArn3")},
+			},
+		},
+		{
+			Name: "rules not owned are never replaced",
+			Desired: []elbv2.Rule{
+				{
+					Conditions: conditions(condition("path-pattern", "/a")),
+					Actions:    actions(&elbv2.Action{TargetGroupArn: tgArn}, elbv2.ActionTypeEnumForward),
+					Priority:   aws.String("1"),
+				},
+				{
+					Conditions: conditions(condition("path-pattern", "/c")),
+					Actions:    actions(&elbv2.Action{TargetGroupArn: tgArn}, elbv2.ActionTypeEnumForward),
+					Priority:   aws.String("2"),
+				},
+			},
+			ExpectedError: errors.New("rule 2 on lsArn isn't managed by ingress, it can't be replaced"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			cloud := &mocks.CloudAPI{}
+			cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{
+				ResourceArns: aws.StringSlice([]string{"ruleArn1", "ruleArn2", "ruleArn3"}),
+			}).Return(&elbv2.DescribeTagsOutput{
+				TagDescriptions: []*elbv2.TagDescription{
+					{ResourceArn: aws.String("ruleArn1"), Tags: ingressTags},
+					{ResourceArn: aws.String("ruleArn3"), Tags: ingressTags},
+				},
+			}, nil)
+			if tc.CreateRuleCall != nil {
+				cloud.On("CreateRuleWithContext", ctx, tc.CreateRuleCall.Input).Return(&elbv2.CreateRuleOutput{
+					Rules: []*elbv2.Rule{{RuleArn: aws.String("ruleArn4")}},
+				}, nil)
+				cloud.On("TagResourcesWithContext", ctx, &resourcegroupstaggingapi.TagResourcesInput{
+					ResourceARNList: aws.StringSlice([]string{"ruleArn4"}),
+					Tags:            aws.StringMap(ownerTags),
+				}).Return(nil, nil)
+			}
+			if tc.DeleteRuleCall != nil {
+				cloud.On("DeleteRuleWithContext", ctx, tc.DeleteRuleCall.Input).Return(nil, nil)
+			}
+			mockTagGen := &MockTagGenerator{}
+			mockTagGen.On("TagRule", "namespace", "ingress").Return(ownerTags)
+
+			controller := &defaultController{
+				cloud:               cloud,
+				tagGen:              mockTagGen,
+				getCurrentRulesFunc: func(context.Context, string) ([]elbv2.Rule, error) { return current, nil },
+				getDesiredRulesFunc: func(*elbv2.Listener, *extensions.Ingress, *annotations.Ingress, tg.TargetGroupGroup) ([]elbv2.Rule, error) {
+					return tc.Desired, nil
+				},
+			}
+			ingress := &extensions.Ingress{}
+			ingress.Namespace, ingress.Name = "namespace", "ingress"
+			ingressAnnos := &annotations.Ingress{
+				LoadBalancer: &loadbalancer.Config{ExistingLoadBalancer: aws.String("existing-lb")},
+			}
+			err := controller.Reconcile(ctx, &elbv2.Listener{ListenerArn: listenerArn}, ingress, ingressAnnos, tg.TargetGroupGroup{})
+			assert.Equal(t, tc.ExpectedError, err)
+			cloud.AssertExpectations(t)
+		})
+	}
+}
+
 type GetRulesCall struct {
 	Output []*elbv2.Rule
 	Error  error
@@ -3,12 +3,30 @@ package sg
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// sharedInboundCIDR is the only source of the shared LoadBalancer securityGroup, since its rules apply to all LoadBalancers it's attached to.
+	// LoadBalancers of ingresses with other inbound-cidrs get securityGroups of their own.
+	sharedInboundCIDR = "0.0.0.0/0"
+
+	// nodePortRangeFrom and nodePortRangeTo bound the default NodePort range of Kubernetes services, which the shared Instance securityGroup allows.
+	nodePortRangeFrom = 30000
+	nodePortRangeTo   = 32767
 )
 
 // Association represents the desired state of securityGroups & attachments for an Ingress resource.
@@ -24,6 +42,16 @@ type Association struct {
 	// If customers specified these securityGroups via annotation on ingress, the ingress controller will then stop creating securityGroups for loadbalancer or ec2-instances.
 	ExternalSGIDs []string
 
+	// ManageBackendSGRules makes the ingress controller keep managing the instance securityGroup when ExternalSGIDs are specified,
+	// allowing inbound traffic from ExternalSGIDs to the targets.
+	ManageBackendSGRules bool
+
+	// IngressKey is the namespace/name of ingress, it's recorded in the description of securityGroup rules.
+	IngressKey string
+
+	// Tags are additional tags applied to the securityGroups created for this LoadBalancer only.
+	Tags map[string]string
+
 	TGGroup tg.TargetGroupGroup
 }
 
@@ -46,34 +74,55 @@ func NewAssociationController(store store.Storer, cloud aws.CloudAPI) Associatio
 		cloud: cloud,
 	}
 	sgController := &securityGroupController{
-		cloud: cloud,
+		cloud:             cloud,
+		managedByTagKey:   store.GetConfig().ManagedByTagKey,
+		managedByTagValue: store.GetConfig().ManagedByTagValue,
 	}
 	namer := &namer{}
 	return &associationController{
+		store:                        store,
 		lbAttachmentController:       lbAttachmentController,
 		instanceAttachmentController: instanceAttachmentController,
 		sgController:                 sgController,
 		namer:                        namer,
 		cloud:                        cloud,
+		sharedNodePorts:              newSharedNodePorts(),
 	}
 }
 
 type associationController struct {
+	store                        store.Storer
 	lbAttachmentController       LbAttachmentController
 	instanceAttachmentController InstanceAttachementController
 	sgController                 SecurityGroupController
 	namer                        Namer
 	cloud                        aws.CloudAPI
+
+	// sharedSGsMutex serializes reconciling the shared securityGroups, which every LoadBalancer of cluster contributes rules to, when ingresses are reconciled concurrently
+	sharedSGsMutex sync.Mutex
+	// sharedNodePorts are the NodePorts allowed by the shared Instance securityGroup
+	sharedNodePorts *sharedNodePorts
 }
 
 func (controller *associationController) Reconcile(ctx context.Context, association *Association) error {
 	if len(association.ExternalSGIDs) != 0 {
 		return controller.reconcileWithExternalSGs(ctx, association)
 	}
+	if controller.store.GetConfig().SharedSecurityGroups {
+		if reason := unsharableReason(association); reason != "" {
+			albctx.GetLogger(ctx).Infof("LoadBalancer uses securityGroups of its own instead of the shared ones, since %v", reason)
+			controller.sharedNodePorts.forget(association.LbID)
+			return controller.reconcileWithManagedSGs(ctx, association)
+		}
+		return controller.reconcileWithSharedSGs(ctx, association)
+	}
 	return controller.reconcileWithManagedSGs(ctx, association)
 }
 
 func (controller *associationController) Delete(ctx context.Context, association *Association) error {
+	if controller.sharedNodePorts != nil {
+		controller.sharedNodePorts.forget(association.LbID)
+	}
 	return controller.deletedManagedSGs(ctx, association)
 }
 
@@ -87,6 +136,18 @@ func (controller *associationController) reconcileWithExternalSGs(ctx context.Co
 		return fmt.Errorf("failed to reconcile external LoadBalancer securityGroup due to %v", err)
 	}
 
+	if association.ManageBackendSGRules {
+		err = controller.reconcileManagedInstanceSG(ctx, association, association.ExternalSGIDs)
+		if err != nil {
+			return err
+		}
+		err = controller.deleteManagedLbSG(ctx, association)
+		if err != nil {
+			return fmt.Errorf("failed to delete managed LoadBalancer securityGroup due to %v", err)
+		}
+		return nil
+	}
+
 	err = controller.deletedManagedSGs(ctx, association)
 	if err != nil {
 		return fmt.Errorf("failed to delete managed securityGroups due to %v", err)
@@ -95,70 +156,237 @@ func (controller *associationController) reconcileWithExternalSGs(ctx context.Co
 }
 
 func (controller *associationController) reconcileWithManagedSGs(ctx context.Context, association *Association) error {
-	lbSG, err := controller.reconcileManagedLbSG(ctx, association)
+	lbSGName := controller.namer.NameLbSG(association.LbID)
+	portCIDRs := make(map[int64][]string)
+	for _, port := range association.LbPorts {
+		portCIDRs[port] = association.LbInboundCIDRs
+	}
+	lbSGIDs, err := controller.reconcileLbSGs(ctx, lbSGName, portCIDRs, association.IngressKey, association.LbArn, association.Tags)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile managed LoadBalancer securityGroup due to %v", err)
+	}
+	err = controller.reconcileManagedInstanceSG(ctx, association, lbSGIDs)
 	if err != nil {
 		return err
 	}
-	err = controller.reconcileManagedInstanceSG(ctx, association, lbSG)
+	// additional securityGroups are deleted after instance securityGroup stopped referencing them.
+	err = controller.deleteLbSGs(ctx, association, controller.additionalLbSGNames(lbSGName, len(lbSGIDs)))
+	if err != nil {
+		return fmt.Errorf("failed to delete unused LoadBalancer securityGroups due to %v", err)
+	}
+	return nil
+}
+
+// reconcileWithSharedSGs attaches the securityGroups shared by all loadBalancers of cluster, and deletes the securityGroups
+// managed for this loadBalancer only.
+func (controller *associationController) reconcileWithSharedSGs(ctx context.Context, association *Association) error {
+	controller.sharedSGsMutex.Lock()
+	defer controller.sharedSGsMutex.Unlock()
+	clusterName := controller.store.GetConfig().ClusterName
+	lbSGName := controller.namer.NameSharedLbSG(clusterName)
+	lbSGIDs, err := controller.reconcileLbSGs(ctx, lbSGName, controller.sharedLbPortCIDRs(association), "", association.LbArn, nil)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile shared LoadBalancer securityGroup due to %v", err)
+	}
+
+	instanceSGName := controller.namer.NameSharedInstanceSG(clusterName)
+	permissions, err := controller.buildSharedInstanceInboundPermissions(ctx, instanceSGName, association, lbSGIDs)
 	if err != nil {
 		return err
 	}
+	instanceSG := &SecurityGroup{
+		GroupName:          &instanceSGName,
+		InboundPermissions: permissions,
+	}
+	err = controller.sgController.Reconcile(ctx, instanceSG)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile shared Instance securityGroup due to %v", err)
+	}
+	// the shared instance securityGroup serves targets of every loadBalancer, so it's attached to all ENIs of cluster.
+	err = controller.instanceAttachmentController.Reconcile(ctx, &InstanceAttachment{
+		GroupID: aws.StringValue(instanceSG.GroupID),
+		AllENIs: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile shared Instance securityGroup attachment due to %v", err)
+	}
+
+	err = controller.deletedManagedSGs(ctx, association)
+	if err != nil {
+		return fmt.Errorf("failed to delete managed securityGroups due to %v", err)
+	}
 	return nil
 }
 
-func (controller *associationController) reconcileManagedLbSG(ctx context.Context, association *Association) (*SecurityGroup, error) {
-	lbSGName := controller.namer.NameLbSG(association.LbID)
-	lbSG := &SecurityGroup{
-		GroupName: &lbSGName,
+// buildSharedInstanceInboundPermissions builds the inbound permissions of the shared Instance securityGroup, which allows the NodePorts of
+// all LoadBalancers using the shared securityGroups from lbSGIDs. Contiguous NodePorts are grouped, and the whole NodePort range is allowed
+// if the rules exceed the securityGroup rules limit.
+func (controller *associationController) buildSharedInstanceInboundPermissions(ctx context.Context, instanceSGName string, association *Association, lbSGIDs []string) ([]*ec2.IpPermission, error) {
+	cfg := controller.store.GetConfig()
+	if !controller.sharedNodePorts.isSeeded() {
+		vpcID, err := controller.cloud.GetVPCID()
+		if err != nil {
+			return nil, err
+		}
+		instanceSG, err := controller.cloud.GetSecurityGroupByName(aws.StringValue(vpcID), instanceSGName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get shared Instance securityGroup due to %v", err)
+		}
+		var current []*ec2.IpPermission
+		if instanceSG != nil {
+			current = instanceSG.IpPermissions
+		}
+		controller.sharedNodePorts.seed(current, time.Now())
+	}
+	ranges := controller.sharedNodePorts.record(association.LbID, instanceNodePorts(association.TGGroup), time.Now())
+	description := buildRuleDescription(cfg.ClusterName, "", "targets=nodePorts")
+	permissions := buildPortRangesInboundPermissions(lbSGIDs, ranges, description)
+	if limit := cfg.SecurityGroupRulesLimit; limit > 0 && countIPPermissionRules(permissions) > limit {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "MODIFY", "%d inbound rules for NodePorts exceed securityGroup rules limit %d, allowing NodePorts %d-%d from shared LoadBalancer securityGroup instead",
+			countIPPermissionRules(permissions), limit, nodePortRangeFrom, nodePortRangeTo)
+		permissions = buildPortRangeInboundPermissions(lbSGIDs, nodePortRangeFrom, nodePortRangeTo, description)
+	}
+	return permissions, nil
+}
+
+// unsharableReason returns why the LoadBalancer of association can't use the shared securityGroups, it's empty if it can.
+// The shared LoadBalancer securityGroup only allows sharedInboundCIDR, and the shared Instance securityGroup only allows NodePorts from it.
+func unsharableReason(association *Association) string {
+	if len(association.LbInboundCIDRs) != 1 || association.LbInboundCIDRs[0] != sharedInboundCIDR {
+		return fmt.Sprintf("its inbound-cidrs %v aren't %v", association.LbInboundCIDRs, sharedInboundCIDR)
+	}
+	for _, tgroup := range association.TGGroup.TGByBackend {
+		if tgroup.TargetType != elbv2.TargetTypeEnumInstance {
+			return fmt.Sprintf("targetGroup %v has %v targets", tgNameFromArn(tgroup.Arn), tgroup.TargetType)
+		}
+		if port, err := strconv.ParseInt(tgroup.HealthCheckPort, 10, 64); err == nil && (port < nodePortRangeFrom || port > nodePortRangeTo) {
+			return fmt.Sprintf("health check port %v of targetGroup %v isn't a NodePort", port, tgNameFromArn(tgroup.Arn))
+		}
 	}
+	return ""
+}
+
+// sharedLbPortCIDRs computes the inbound CIDRs per port of the shared LoadBalancer securityGroup, which allows the ports of all ingresses using it.
+// Ingresses with other inbound-cidrs than sharedInboundCIDR don't use it.
+func (controller *associationController) sharedLbPortCIDRs(association *Association) map[int64][]string {
+	portCIDRs := make(map[int64][]string)
 	for _, port := range association.LbPorts {
+		portCIDRs[port] = []string{sharedInboundCIDR}
+	}
+	for _, ingAnnos := range controller.store.ListIngressAnnotations() {
+		if ingAnnos.LoadBalancer == nil || len(ingAnnos.LoadBalancer.SecurityGroups) != 0 {
+			continue
+		}
+		if cidrs := ingAnnos.LoadBalancer.InboundCidrs; len(cidrs) != 1 || cidrs[0] != sharedInboundCIDR {
+			continue
+		}
+		for _, port := range ingAnnos.LoadBalancer.Ports {
+			portCIDRs[port.Port] = []string{sharedInboundCIDR}
+		}
+	}
+	return portCIDRs
+}
+
+// buildLbInboundPermissions builds the inbound permissions of a LoadBalancer securityGroup from the allowed CIDRs per port.
+// ingressKey is empty for the LoadBalancer securityGroup shared by all ingresses.
+func buildLbInboundPermissions(portCIDRs map[int64][]string, clusterName string, ingressKey string) []*ec2.IpPermission {
+	var ports []int64
+	for port := range portCIDRs {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	var permissions []*ec2.IpPermission
+	for _, port := range ports {
 		ipRanges := []*ec2.IpRange{}
-		for _, cidr := range association.LbInboundCIDRs {
+		for _, cidr := range portCIDRs[port] {
 			ipRanges = append(ipRanges, &ec2.IpRange{
 				CidrIp:      aws.String(cidr),
-				Description: aws.String(fmt.Sprintf("Allow ingress on port %v from %v", port, cidr)),
+				Description: aws.String(buildRuleDescription(clusterName, ingressKey, fmt.Sprintf("port=%v source=%v", port, cidr))),
 			})
 		}
-		permission := &ec2.IpPermission{
+		permissions = append(permissions, &ec2.IpPermission{
 			IpProtocol: aws.String("tcp"),
 			FromPort:   aws.Int64(port),
 			ToPort:     aws.Int64(port),
 			IpRanges:   ipRanges,
-		}
-		lbSG.InboundPermissions = append(lbSG.InboundPermissions, permission)
+		})
 	}
+	return permissions
+}
 
-	err := controller.sgController.Reconcile(ctx, lbSG)
-	if err != nil {
-		return lbSG, fmt.Errorf("failed to reconcile managed LoadBalancer securityGroup due to %v", err)
+// reconcileLbSGs ensures the LoadBalancer securityGroups named after lbSGName allows inbound traffic specified by portCIDRs,
+// and they are the only securityGroups attached to LoadBalancer.
+// When rules exceeds the quota of securityGroup, CIDRs are consolidated first, then rules spill to additional securityGroups.
+func (controller *associationController) reconcileLbSGs(ctx context.Context, lbSGName string, portCIDRs map[int64][]string, ingressKey string, lbArn string, tags map[string]string) ([]string, error) {
+	cfg := controller.store.GetConfig()
+	permissions := buildLbInboundPermissions(portCIDRs, cfg.ClusterName, ingressKey)
+	if limit := cfg.SecurityGroupRulesLimit; limit > 0 && countIPPermissionRules(permissions) > limit {
+		albctx.GetLogger(ctx).Infof("%d inbound rules exceed securityGroup rules limit %d, consolidating CIDRs", countIPPermissionRules(permissions), limit)
+		permissions = buildLbInboundPermissions(consolidatePortCIDRs(portCIDRs), cfg.ClusterName, ingressKey)
+	}
+	permissionGroups := splitIPPermissions(permissions, cfg.SecurityGroupRulesLimit)
+	if len(permissionGroups) > maxLbSecurityGroups {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "%d inbound rules exceed the capacity of %d securityGroups with %d rules each, only the first %d rules are applied. Reduce inbound-cidrs or use the security-groups annotation",
+			countIPPermissionRules(permissions), maxLbSecurityGroups, cfg.SecurityGroupRulesLimit, maxLbSecurityGroups*cfg.SecurityGroupRulesLimit)
+		permissionGroups = permissionGroups[:maxLbSecurityGroups]
 	}
+
+	var groupIDs []string
+	for index, groupPermissions := range permissionGroups {
+		sgName := lbSGName
+		if index != 0 {
+			sgName = controller.namer.NameAdditionalLbSG(lbSGName, index)
+		}
+		lbSG := &SecurityGroup{
+			GroupName:          &sgName,
+			InboundPermissions: groupPermissions,
+			Tags:               tags,
+		}
+		err := controller.sgController.Reconcile(ctx, lbSG)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile securityGroup %s due to %v", sgName, err)
+		}
+		groupIDs = append(groupIDs, aws.StringValue(lbSG.GroupID))
+	}
+
 	lbSGAttachment := &LbAttachment{
-		GroupIDs: []string{*lbSG.GroupID},
-		LbArn:    association.LbArn,
+		GroupIDs: groupIDs,
+		LbArn:    lbArn,
 	}
-	err = controller.lbAttachmentController.Reconcile(ctx, lbSGAttachment)
+	err := controller.lbAttachmentController.Reconcile(ctx, lbSGAttachment)
 	if err != nil {
-		return lbSG, fmt.Errorf("failed to reconcile managed LoadBalancer securityGroup attachment due to %v", err)
+		return nil, fmt.Errorf("failed to reconcile securityGroup attachment due to %v", err)
 	}
-	return lbSG, nil
+	return groupIDs, nil
 }
 
-func (controller *associationController) reconcileManagedInstanceSG(ctx context.Context, association *Association, lbSG *SecurityGroup) error {
+// additionalLbSGNames returns names of the additional LoadBalancer securityGroups of lbSGName starting from index from.
+func (controller *associationController) additionalLbSGNames(lbSGName string, from int) []string {
+	var names []string
+	for index := from; index < maxLbSecurityGroups; index++ {
+		if index == 0 {
+			continue
+		}
+		names = append(names, controller.namer.NameAdditionalLbSG(lbSGName, index))
+	}
+	return names
+}
+
+// reconcileManagedInstanceSG ensures the managed instance securityGroup allows inbound traffic from sourceGroupIDs.
+func (controller *associationController) reconcileManagedInstanceSG(ctx context.Context, association *Association, sourceGroupIDs []string) error {
 	instanceSGName := controller.namer.NameInstanceSG(association.LbID)
+	cfg := controller.store.GetConfig()
+	permissions := buildInstanceInboundPermissions(association.TGGroup, sourceGroupIDs, cfg.ClusterName, association.IngressKey)
+	if limit := cfg.SecurityGroupRulesLimit; limit > 0 && countIPPermissionRules(permissions) > limit {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "MODIFY", "%d inbound rules for targets exceed securityGroup rules limit %d, allowing all ports from LoadBalancer securityGroups instead",
+			countIPPermissionRules(permissions), limit)
+		permissions = buildAllPortsInboundPermissions(sourceGroupIDs, buildRuleDescription(cfg.ClusterName, association.IngressKey, "targets=all"))
+	}
 	instanceSG := &SecurityGroup{
-		GroupName: &instanceSGName,
-		InboundPermissions: []*ec2.IpPermission{
-			{
-				IpProtocol: aws.String("tcp"),
-				FromPort:   aws.Int64(0),
-				ToPort:     aws.Int64(65535),
-				UserIdGroupPairs: []*ec2.UserIdGroupPair{
-					{
-						GroupId: lbSG.GroupID,
-					},
-				},
-			},
-		},
+		GroupName:          &instanceSGName,
+		InboundPermissions: permissions,
+		Tags:               association.Tags,
 	}
 	err := controller.sgController.Reconcile(ctx, instanceSG)
 	if err != nil {
@@ -172,9 +400,96 @@ func (controller *associationController) reconcileManagedInstanceSG(ctx context.
 	if err != nil {
 		return fmt.Errorf("failed to reconcile managed Instance securityGroup attachment due to %v", err)
 	}
+	err = controller.reconcilePodSGs(ctx, association, sourceGroupIDs, aws.StringValue(instanceSG.GroupID))
+	if err != nil {
+		return fmt.Errorf("failed to reconcile pod securityGroups due to %v", err)
+	}
 	return nil
 }
 
+// buildInstanceInboundPermissions builds the inbound permissions of an Instance securityGroup, which only allows traffic
+// from sourceGroupIDs to the ports of targets(nodePorts for instance targets, podPorts for ip targets) and the health check ports in tgGroup.
+// Contiguous ports are grouped into a single permission, whose description records the targetGroups it serves.
+func buildInstanceInboundPermissions(tgGroup tg.TargetGroupGroup, sourceGroupIDs []string, clusterName string, ingressKey string) []*ec2.IpPermission {
+	portTGNames := make(map[int64]map[string]bool)
+	addPort := func(port int64, tgArn string) {
+		if _, ok := portTGNames[port]; !ok {
+			portTGNames[port] = make(map[string]bool)
+		}
+		portTGNames[port][tgNameFromArn(tgArn)] = true
+	}
+	for _, tgroup := range tgGroup.TGByBackend {
+		for _, target := range tgroup.Targets {
+			if target.Port != nil {
+				addPort(aws.Int64Value(target.Port), tgroup.Arn)
+			}
+		}
+		if port, err := strconv.ParseInt(tgroup.HealthCheckPort, 10, 64); err == nil {
+			addPort(port, tgroup.Arn)
+		}
+	}
+	var ports []int64
+	for port := range portTGNames {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	var permissions []*ec2.IpPermission
+	for i := 0; i < len(ports); {
+		j := i
+		tgNames := make(map[string]bool)
+		for name := range portTGNames[ports[i]] {
+			tgNames[name] = true
+		}
+		for j+1 < len(ports) && ports[j+1] == ports[j]+1 {
+			j++
+			for name := range portTGNames[ports[j]] {
+				tgNames[name] = true
+			}
+		}
+		description := buildRuleDescription(clusterName, ingressKey, "targetGroups="+strings.Join(sortedKeys(tgNames), ","))
+		var groupPairs []*ec2.UserIdGroupPair
+		for _, groupID := range sourceGroupIDs {
+			groupPairs = append(groupPairs, &ec2.UserIdGroupPair{
+				GroupId:     aws.String(groupID),
+				Description: aws.String(description),
+			})
+		}
+		permissions = append(permissions, &ec2.IpPermission{
+			IpProtocol:       aws.String("tcp"),
+			FromPort:         aws.Int64(ports[i]),
+			ToPort:           aws.Int64(ports[j]),
+			UserIdGroupPairs: groupPairs,
+		})
+		i = j + 1
+	}
+	return permissions
+}
+
+// buildAllPortsInboundPermissions builds the inbound permissions allowing traffic to all ports from sourceGroupIDs.
+func buildAllPortsInboundPermissions(sourceGroupIDs []string, description string) []*ec2.IpPermission {
+	return buildPortRangeInboundPermissions(sourceGroupIDs, 0, 65535, description)
+}
+
+// buildPortRangeInboundPermissions builds the inbound permissions allowing traffic to ports from fromPort to toPort from sourceGroupIDs.
+func buildPortRangeInboundPermissions(sourceGroupIDs []string, fromPort int64, toPort int64, description string) []*ec2.IpPermission {
+	var groupPairs []*ec2.UserIdGroupPair
+	for _, groupID := range sourceGroupIDs {
+		groupPairs = append(groupPairs, &ec2.UserIdGroupPair{
+			GroupId:     aws.String(groupID),
+			Description: aws.String(description),
+		})
+	}
+	return []*ec2.IpPermission{
+		{
+			IpProtocol:       aws.String("tcp"),
+			FromPort:         aws.Int64(fromPort),
+			ToPort:           aws.Int64(toPort),
+			UserIdGroupPairs: groupPairs,
+		},
+	}
+}
+
 func (controller *associationController) deletedManagedSGs(ctx context.Context, association *Association) error {
 	err := controller.deleteManagedInstanceSG(ctx, association)
 	if err != nil {
@@ -189,27 +504,45 @@ func (controller *associationController) deletedManagedSGs(ctx context.Context,
 
 func (controller *associationController) deleteManagedLbSG(ctx context.Context, association *Association) error {
 	lbSGName := controller.namer.NameLbSG(association.LbID)
-	lbSGID, err := controller.findSGIDByName(lbSGName)
-	if err != nil {
-		return err
+	return controller.deleteLbSGs(ctx, association, append([]string{lbSGName}, controller.additionalLbSGNames(lbSGName, 0)...))
+}
+
+// deleteLbSGs ensures the securityGroups with sgNames are detached from LoadBalancer and deleted.
+// Rules referencing them on pod securityGroups are revoked first, otherwise they cannot be deleted.
+func (controller *associationController) deleteLbSGs(ctx context.Context, association *Association, sgNames []string) error {
+	var lbSGIDs []string
+	for _, sgName := range sgNames {
+		lbSGID, err := controller.findSGIDByName(sgName)
+		if err != nil {
+			return err
+		}
+		if lbSGID != nil {
+			lbSGIDs = append(lbSGIDs, *lbSGID)
+		}
 	}
-	if lbSGID == nil {
+	if len(lbSGIDs) == 0 {
 		return nil
 	}
+	err := controller.revokePodSGRules(ctx, association, lbSGIDs, nil)
+	if err != nil {
+		return err
+	}
 	lbSGAttachment := &LbAttachment{
-		GroupIDs: []string{*lbSGID},
+		GroupIDs: lbSGIDs,
 		LbArn:    association.LbArn,
 	}
 	err = controller.lbAttachmentController.Delete(ctx, lbSGAttachment)
 	if err != nil {
 		return err
 	}
-	lbSG := &SecurityGroup{
-		GroupID: lbSGID,
-	}
-	err = controller.sgController.Delete(ctx, lbSG)
-	if err != nil {
-		return err
+	for _, lbSGID := range lbSGIDs {
+		lbSG := &SecurityGroup{
+			GroupID: aws.String(lbSGID),
+		}
+		err = controller.sgController.Delete(ctx, lbSG)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
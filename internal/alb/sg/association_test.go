@@ -0,0 +1,143 @@
+package sg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestBuildInstanceInboundPermissions(t *testing.T) {
+	groupPairs := func(description string) []*ec2.UserIdGroupPair {
+		return []*ec2.UserIdGroupPair{
+			{
+				GroupId:     aws.String("sg-lb"),
+				Description: aws.String(description),
+			},
+		}
+	}
+	for _, tc := range []struct {
+		Name                string
+		TGGroup             tg.TargetGroupGroup
+		ExpectedPermissions []*ec2.IpPermission
+	}{
+		{
+			Name:                "no targets",
+			TGGroup:             tg.TargetGroupGroup{},
+			ExpectedPermissions: nil,
+		},
+		{
+			Name: "contiguous ports are grouped and health check ports are included",
+			TGGroup: tg.TargetGroupGroup{
+				TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+					{ServiceName: "service1", ServicePort: intstr.FromInt(80)}: {
+						Arn: "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/tg1/73e2d6bc24d8a067",
+						Targets: []*elbv2.TargetDescription{
+							{Id: aws.String("i-1"), Port: aws.Int64(30001)},
+							{Id: aws.String("i-2"), Port: aws.Int64(30001)},
+						},
+						HealthCheckPort: "traffic-port",
+					},
+					{ServiceName: "service2", ServicePort: intstr.FromInt(80)}: {
+						Arn: "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/tg2/83e2d6bc24d8a067",
+						Targets: []*elbv2.TargetDescription{
+							{Id: aws.String("i-1"), Port: aws.Int64(30002)},
+						},
+						HealthCheckPort: "8080",
+					},
+				},
+			},
+			ExpectedPermissions: []*ec2.IpPermission{
+				{
+					IpProtocol:       aws.String("tcp"),
+					FromPort:         aws.Int64(8080),
+					ToPort:           aws.Int64(8080),
+					UserIdGroupPairs: groupPairs("alb-ingress-controller cluster=cluster ingress=namespace/ingress targetGroups=tg2"),
+				},
+				{
+					IpProtocol:       aws.String("tcp"),
+					FromPort:         aws.Int64(30001),
+					ToPort:           aws.Int64(30002),
+					UserIdGroupPairs: groupPairs("alb-ingress-controller cluster=cluster ingress=namespace/ingress targetGroups=tg1,tg2"),
+				},
+			},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			permissions := buildInstanceInboundPermissions(tc.TGGroup, []string{"sg-lb"}, "cluster", "namespace/ingress")
+			assert.Equal(t, tc.ExpectedPermissions, permissions)
+		})
+	}
+}
+
+func TestUnsharableReason(t *testing.T) {
+	tgGroup := func(targetType string, healthCheckPort string) tg.TargetGroupGroup {
+		return tg.TargetGroupGroup{
+			TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+				{ServiceName: "service1", ServicePort: intstr.FromInt(80)}: {
+					Arn:             "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/tg1/73e2d6bc24d8a067",
+					TargetType:      targetType,
+					HealthCheckPort: healthCheckPort,
+				},
+			},
+		}
+	}
+	for _, tc := range []struct {
+		Name           string
+		Association    *Association
+		ExpectedReason string
+	}{
+		{
+			Name:        "instance targets from anywhere",
+			Association: &Association{LbInboundCIDRs: []string{"0.0.0.0/0"}, TGGroup: tgGroup(elbv2.TargetTypeEnumInstance, "traffic-port")},
+		},
+		{
+			Name:        "health check NodePort",
+			Association: &Association{LbInboundCIDRs: []string{"0.0.0.0/0"}, TGGroup: tgGroup(elbv2.TargetTypeEnumInstance, "31000")},
+		},
+		{
+			Name:           "other inbound CIDRs",
+			Association:    &Association{LbInboundCIDRs: []string{"10.0.0.0/8"}, TGGroup: tgGroup(elbv2.TargetTypeEnumInstance, "traffic-port")},
+			ExpectedReason: "its inbound-cidrs [10.0.0.0/8] aren't 0.0.0.0/0",
+		},
+		{
+			Name:           "ip targets",
+			Association:    &Association{LbInboundCIDRs: []string{"0.0.0.0/0"}, TGGroup: tgGroup(elbv2.TargetTypeEnumIp, "traffic-port")},
+			ExpectedReason: "targetGroup tg1 has ip targets",
+		},
+		{
+			Name:           "health check port outside NodePorts",
+			Association:    &Association{LbInboundCIDRs: []string{"0.0.0.0/0"}, TGGroup: tgGroup(elbv2.TargetTypeEnumInstance, "8080")},
+			ExpectedReason: "health check port 8080 of targetGroup tg1 isn't a NodePort",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedReason, unsharableReason(tc.Association))
+		})
+	}
+}
+
+func TestSharedLbPortCIDRs(t *testing.T) {
+	mockStore := &store.MockStorer{}
+	mockStore.On("ListIngressAnnotations").Return([]*annotations.Ingress{
+		{LoadBalancer: &loadbalancer.Config{InboundCidrs: []string{"0.0.0.0/0"}, Ports: []loadbalancer.PortData{{Port: 443}}}},
+		{LoadBalancer: &loadbalancer.Config{InboundCidrs: []string{"10.0.0.0/8"}, Ports: []loadbalancer.PortData{{Port: 8443}}}},
+		{LoadBalancer: &loadbalancer.Config{InboundCidrs: []string{"0.0.0.0/0"}, Ports: []loadbalancer.PortData{{Port: 9443}}, SecurityGroups: []string{"sg-custom"}}},
+	})
+	controller := &associationController{store: mockStore}
+
+	// CIDRs of ingresses with their own securityGroups are never allowed to the shared LoadBalancer securityGroup.
+	assert.Equal(t, map[int64][]string{
+		80:  {"0.0.0.0/0"},
+		443: {"0.0.0.0/0"},
+	}, controller.sharedLbPortCIDRs(&Association{LbPorts: []int64{80}, LbInboundCIDRs: []string{"0.0.0.0/0"}}))
+}
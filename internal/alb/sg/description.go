@@ -0,0 +1,146 @@
+package sg
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+)
+
+const (
+	// managedRuleDescriptionPrefix prefixes the description of every securityGroup rule created by ingress controller.
+	managedRuleDescriptionPrefix = "alb-ingress-controller"
+
+	// legacyRuleDescriptionPrefix prefixes the description of securityGroup rules created by older versions of ingress controller.
+	legacyRuleDescriptionPrefix = "Allow ingress on port"
+
+	// maxRuleDescriptionLength is the maximum length of securityGroup rule description allowed by EC2
+	maxRuleDescriptionLength = 255
+)
+
+// buildRuleDescription builds the description of an securityGroup rule, which records the cluster & ingress it serves.
+// ingressKey is empty for rules shared by all ingresses of cluster.
+func buildRuleDescription(clusterName string, ingressKey string, detail string) string {
+	parts := []string{managedRuleDescriptionPrefix, "cluster=" + clusterName}
+	if ingressKey != "" {
+		parts = append(parts, "ingress="+ingressKey)
+	}
+	parts = append(parts, detail)
+	description := strings.Join(parts, " ")
+	if len(description) > maxRuleDescriptionLength {
+		description = description[:maxRuleDescriptionLength]
+	}
+	return description
+}
+
+// buildRuleOwner builds the prefix of descriptions of securityGroup rules created for ingress.
+// The trailing separator ensures ingresses whose name is prefix of another don't own each other's rules.
+func buildRuleOwner(clusterName string, ingressKey string) string {
+	return buildRuleDescription(clusterName, ingressKey, "")
+}
+
+// isManagedRuleDescription tests whether an securityGroup rule with description is managed by ingress controller.
+// rules without description are created by older versions of ingress controller.
+// If owner is specified, only rules with description prefixed by owner are managed.
+func isManagedRuleDescription(description *string, owner string) bool {
+	desc := aws.StringValue(description)
+	if owner != "" {
+		return strings.HasPrefix(desc, owner)
+	}
+	return desc == "" ||
+		strings.HasPrefix(desc, managedRuleDescriptionPrefix) ||
+		strings.HasPrefix(desc, legacyRuleDescriptionPrefix)
+}
+
+// filterManagedIPPermissions returns the parts of permissions that are managed by ingress controller(or owner if specified),
+// so rules added by operators with their own descriptions are left untouched.
+func filterManagedIPPermissions(permissions []*ec2.IpPermission, owner string) []*ec2.IpPermission {
+	var managed []*ec2.IpPermission
+	for _, permission := range permissions {
+		var ipRanges []*ec2.IpRange
+		for _, ipRange := range permission.IpRanges {
+			if isManagedRuleDescription(ipRange.Description, owner) {
+				ipRanges = append(ipRanges, ipRange)
+			}
+		}
+		var groupPairs []*ec2.UserIdGroupPair
+		for _, pair := range permission.UserIdGroupPairs {
+			if isManagedRuleDescription(pair.Description, owner) {
+				groupPairs = append(groupPairs, pair)
+			}
+		}
+		if len(ipRanges) == 0 && len(groupPairs) == 0 {
+			continue
+		}
+		managed = append(managed, &ec2.IpPermission{
+			IpProtocol:       permission.IpProtocol,
+			FromPort:         permission.FromPort,
+			ToPort:           permission.ToPort,
+			IpRanges:         ipRanges,
+			UserIdGroupPairs: groupPairs,
+		})
+	}
+	return managed
+}
+
+// diffIPPermissionDescriptions returns the permissions in source that exists in target but with different descriptions.
+func diffIPPermissionDescriptions(source []*ec2.IpPermission, target []*ec2.IpPermission) (diffs []*ec2.IpPermission) {
+	for _, sPermission := range source {
+		for _, tPermission := range target {
+			if ipPermissionEquals(sPermission, tPermission) && !ipPermissionDescriptionsEquals(sPermission, tPermission) {
+				diffs = append(diffs, sPermission)
+				break
+			}
+		}
+	}
+	return diffs
+}
+
+// ipPermissionDescriptionsEquals test whether the rules of two equal IPPermission have same descriptions
+func ipPermissionDescriptionsEquals(source *ec2.IpPermission, target *ec2.IpPermission) bool {
+	for _, sRange := range source.IpRanges {
+		for _, tRange := range target.IpRanges {
+			if ipRangeEquals(sRange, tRange) && aws.StringValue(sRange.Description) != aws.StringValue(tRange.Description) {
+				return false
+			}
+		}
+	}
+	for _, sPair := range source.UserIdGroupPairs {
+		for _, tPair := range target.UserIdGroupPairs {
+			if userIDGroupPairEquals(sPair, tPair) && aws.StringValue(sPair.Description) != aws.StringValue(tPair.Description) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// tgNameFromArn extracts the name of targetGroup from its ARN, e.g. arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/my-targets/73e2d6bc24d8a067
+func tgNameFromArn(tgArn string) string {
+	parts := strings.Split(tgArn, "/")
+	if len(parts) < 2 {
+		return tgArn
+	}
+	return parts[1]
+}
+
+// sortedKeys returns the keys of set in sorted order
+func sortedKeys(set map[string]bool) []string {
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedTagKeys returns the keys of tags in sorted order
+func sortedTagKeys(tags map[string]string) []string {
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,168 @@
+package sg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+)
+
+func TestBuildRuleDescription(t *testing.T) {
+	for _, tc := range []struct {
+		Name                string
+		IngressKey          string
+		Detail              string
+		ExpectedDescription string
+	}{
+		{
+			Name:                "rule of ingress",
+			IngressKey:          "namespace/ingress",
+			Detail:              "port=80 source=0.0.0.0/0",
+			ExpectedDescription: "alb-ingress-controller cluster=cluster ingress=namespace/ingress port=80 source=0.0.0.0/0",
+		},
+		{
+			Name:                "rule shared by ingresses",
+			IngressKey:          "",
+			Detail:              "targets=all",
+			ExpectedDescription: "alb-ingress-controller cluster=cluster targets=all",
+		},
+		{
+			Name:                "description is truncated",
+			IngressKey:          "namespace/ingress",
+			Detail:              strings.Repeat("a", 300),
+			ExpectedDescription: ("alb-ingress-controller cluster=cluster ingress=namespace/ingress " + strings.Repeat("a", 300))[:maxRuleDescriptionLength],
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedDescription, buildRuleDescription("cluster", tc.IngressKey, tc.Detail))
+		})
+	}
+}
+
+func TestFilterManagedIPPermissions(t *testing.T) {
+	permissions := []*ec2.IpPermission{
+		{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(80),
+			ToPort:     aws.Int64(80),
+			IpRanges: []*ec2.IpRange{
+				{
+					CidrIp:      aws.String("0.0.0.0/0"),
+					Description: aws.String("alb-ingress-controller cluster=cluster ingress=namespace/ingress port=80 source=0.0.0.0/0"),
+				},
+				{
+					CidrIp:      aws.String("10.0.0.0/8"),
+					Description: aws.String("added by operator"),
+				},
+			},
+		},
+		{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(443),
+			ToPort:     aws.Int64(443),
+			IpRanges: []*ec2.IpRange{
+				{
+					CidrIp:      aws.String("0.0.0.0/0"),
+					Description: aws.String("Allow ingress on port 443 from 0.0.0.0/0"),
+				},
+			},
+		},
+		{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(22),
+			ToPort:     aws.Int64(22),
+			UserIdGroupPairs: []*ec2.UserIdGroupPair{
+				{
+					GroupId:     aws.String("sg-bastion"),
+					Description: aws.String("ssh from bastion"),
+				},
+			},
+		},
+		{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(0),
+			ToPort:     aws.Int64(65535),
+			UserIdGroupPairs: []*ec2.UserIdGroupPair{
+				{
+					GroupId: aws.String("sg-lb"),
+				},
+			},
+		},
+	}
+	expected := []*ec2.IpPermission{
+		{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(80),
+			ToPort:     aws.Int64(80),
+			IpRanges: []*ec2.IpRange{
+				{
+					CidrIp:      aws.String("0.0.0.0/0"),
+					Description: aws.String("alb-ingress-controller cluster=cluster ingress=namespace/ingress port=80 source=0.0.0.0/0"),
+				},
+			},
+		},
+		{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(443),
+			ToPort:     aws.Int64(443),
+			IpRanges: []*ec2.IpRange{
+				{
+					CidrIp:      aws.String("0.0.0.0/0"),
+					Description: aws.String("Allow ingress on port 443 from 0.0.0.0/0"),
+				},
+			},
+		},
+		{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(0),
+			ToPort:     aws.Int64(65535),
+			UserIdGroupPairs: []*ec2.UserIdGroupPair{
+				{
+					GroupId: aws.String("sg-lb"),
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, filterManagedIPPermissions(permissions, ""))
+}
+
+func TestFilterManagedIPPermissionsWithOwner(t *testing.T) {
+	owner := buildRuleOwner("cluster", "namespace/ingress")
+	permissions := []*ec2.IpPermission{
+		{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(8080),
+			ToPort:     aws.Int64(8080),
+			UserIdGroupPairs: []*ec2.UserIdGroupPair{
+				{
+					GroupId:     aws.String("sg-lb"),
+					Description: aws.String("alb-ingress-controller cluster=cluster ingress=namespace/ingress targetGroups=tg1"),
+				},
+				{
+					GroupId:     aws.String("sg-lb2"),
+					Description: aws.String("alb-ingress-controller cluster=cluster ingress=namespace/ingress2 targetGroups=tg2"),
+				},
+				{
+					GroupId: aws.String("sg-other"),
+				},
+			},
+		},
+	}
+	expected := []*ec2.IpPermission{
+		{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(8080),
+			ToPort:     aws.Int64(8080),
+			UserIdGroupPairs: []*ec2.UserIdGroupPair{
+				{
+					GroupId:     aws.String("sg-lb"),
+					Description: aws.String("alb-ingress-controller cluster=cluster ingress=namespace/ingress targetGroups=tg1"),
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, filterManagedIPPermissions(permissions, owner))
+}
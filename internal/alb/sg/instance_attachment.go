@@ -17,6 +17,9 @@ import (
 type InstanceAttachment struct {
 	GroupID string
 	TGGroup tg.TargetGroupGroup
+
+	// AllENIs makes the securityGroup attached to all ENIs of k8s cluster instead of only ENIs supporting TGGroup.
+	AllENIs bool
 }
 
 // InstanceAttachementController manages InstanceAttachment
@@ -46,7 +49,7 @@ func (controller *instanceAttachmentController) Reconcile(ctx context.Context, a
 	supportingENIs := controller.findENIsSupportingTargets(instanceENIs, attachment.TGGroup)
 	for _, enis := range instanceENIs {
 		for _, eni := range enis {
-			if _, ok := supportingENIs[aws.StringValue(eni.NetworkInterfaceId)]; ok {
+			if _, ok := supportingENIs[aws.StringValue(eni.NetworkInterfaceId)]; ok || attachment.AllENIs {
 				err := controller.ensureSGAttachedToENI(ctx, attachment.GroupID, eni)
 				if err != nil {
 					return err
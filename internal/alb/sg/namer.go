@@ -10,6 +10,12 @@ type Namer interface {
 	NameLbSG(loadBalancerID string) string
 	// NameInstanceSG generates names for securityGroup we created for ec2-instance
 	NameInstanceSG(loadBalancerID string) string
+	// NameAdditionalLbSG generates names for additional securityGroups we created for loadBalancer when rules don't fit in one securityGroup
+	NameAdditionalLbSG(lbSGName string, index int) string
+	// NameSharedLbSG generates names for securityGroup we created to be shared by all loadBalancers of cluster
+	NameSharedLbSG(clusterName string) string
+	// NameSharedInstanceSG generates names for securityGroup we created for ec2-instance to be shared by all loadBalancers of cluster
+	NameSharedInstanceSG(clusterName string) string
 }
 
 type namer struct{}
@@ -21,3 +27,15 @@ func (namer *namer) NameLbSG(loadBalancerID string) string {
 func (namer *namer) NameInstanceSG(loadBalancerID string) string {
 	return fmt.Sprintf("instance-%s", loadBalancerID)
 }
+
+func (namer *namer) NameAdditionalLbSG(lbSGName string, index int) string {
+	return fmt.Sprintf("%s-%d", lbSGName, index)
+}
+
+func (namer *namer) NameSharedLbSG(clusterName string) string {
+	return fmt.Sprintf("k8s-%s-shared", clusterName)
+}
+
+func (namer *namer) NameSharedInstanceSG(clusterName string) string {
+	return fmt.Sprintf("instance-k8s-%s-shared", clusterName)
+}
@@ -26,3 +26,37 @@ func TestNameSGs(t *testing.T) {
 		}
 	}
 }
+
+func TestNameAdditionalLbSG(t *testing.T) {
+	namer := &namer{}
+	expected := "abcdefgf1sh-1"
+	actual := namer.NameAdditionalLbSG("abcdefgf1sh", 1)
+	if expected != actual {
+		t.Errorf("expected:%v, actual:%v", expected, actual)
+	}
+}
+
+func TestNameSharedSGs(t *testing.T) {
+	namer := &namer{}
+	for _, tc := range []struct {
+		clusterName            string
+		expectedLBSGName       string
+		expectedInstanceSGName string
+	}{
+		{
+			clusterName:            "my-cluster",
+			expectedLBSGName:       "k8s-my-cluster-shared",
+			expectedInstanceSGName: "instance-k8s-my-cluster-shared",
+		},
+	} {
+		actualLBSGName := namer.NameSharedLbSG(tc.clusterName)
+		if tc.expectedLBSGName != actualLBSGName {
+			t.Errorf("expected:%v, actual:%v", tc.expectedLBSGName, actualLBSGName)
+		}
+
+		actualInstanceSGName := namer.NameSharedInstanceSG(tc.clusterName)
+		if tc.expectedInstanceSGName != actualInstanceSGName {
+			t.Errorf("expected:%v, actual:%v", tc.expectedInstanceSGName, actualInstanceSGName)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package sg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+// reconcilePodSGs ensures the securityGroups of pods using branch ENIs(securityGroups for pods) allows inbound traffic
+// from sourceGroupIDs to the ip targets in association, since traffic to these pods isn't controlled by securityGroups of instance ENIs.
+// Rules on securityGroups no longer used by targets are revoked.
+func (controller *associationController) reconcilePodSGs(ctx context.Context, association *Association, sourceGroupIDs []string, instanceSGID string) error {
+	podSGTGGroups, err := controller.buildPodSGTGGroups(ctx, association.TGGroup)
+	if err != nil {
+		return err
+	}
+	clusterName := controller.store.GetConfig().ClusterName
+	ruleOwner := buildRuleOwner(clusterName, association.IngressKey)
+	for podSGID, tgGroup := range podSGTGGroups {
+		podSG := &SecurityGroup{
+			GroupID:            aws.String(podSGID),
+			InboundPermissions: buildInstanceInboundPermissions(tgGroup, sourceGroupIDs, clusterName, association.IngressKey),
+			RuleOwner:          ruleOwner,
+		}
+		if err := controller.sgController.Reconcile(ctx, podSG); err != nil {
+			return fmt.Errorf("failed to reconcile pod securityGroup %s due to %v", podSGID, err)
+		}
+	}
+
+	keepGroupIDs := map[string]bool{instanceSGID: true}
+	for podSGID := range podSGTGGroups {
+		keepGroupIDs[podSGID] = true
+	}
+	return controller.revokePodSGRules(ctx, association, sourceGroupIDs, keepGroupIDs)
+}
+
+// revokePodSGRules revokes the rules we created for association on securityGroups referencing sourceGroupIDs, except the ones in keepGroupIDs.
+func (controller *associationController) revokePodSGRules(ctx context.Context, association *Association, sourceGroupIDs []string, keepGroupIDs map[string]bool) error {
+	if len(sourceGroupIDs) == 0 {
+		return nil
+	}
+	referencingSGs, err := controller.cloud.GetSecurityGroupsReferencingGroups(ctx, sourceGroupIDs)
+	if err != nil {
+		return fmt.Errorf("failed to find securityGroups referencing %v due to %v", sourceGroupIDs, err)
+	}
+	ruleOwner := buildRuleOwner(controller.store.GetConfig().ClusterName, association.IngressKey)
+	for _, referencingSG := range referencingSGs {
+		groupID := aws.StringValue(referencingSG.GroupId)
+		if keepGroupIDs[groupID] {
+			continue
+		}
+		if len(filterManagedIPPermissions(referencingSG.IpPermissions, ruleOwner)) == 0 {
+			continue
+		}
+		albctx.GetLogger(ctx).Infof("revoking inbound permissions from pod securityGroup %s", groupID)
+		podSG := &SecurityGroup{
+			GroupID:   aws.String(groupID),
+			RuleOwner: ruleOwner,
+		}
+		if err := controller.sgController.Reconcile(ctx, podSG); err != nil {
+			return fmt.Errorf("failed to revoke inbound permissions from pod securityGroup %s due to %v", groupID, err)
+		}
+	}
+	return nil
+}
+
+// buildPodSGTGGroups groups the ip targets served by branch ENIs by the securityGroups of these ENIs.
+func (controller *associationController) buildPodSGTGGroups(ctx context.Context, tgGroup tg.TargetGroupGroup) (map[string]tg.TargetGroupGroup, error) {
+	var podIPs []string
+	for _, tgroup := range tgGroup.TGByBackend {
+		if tgroup.TargetType != elbv2.TargetTypeEnumIp {
+			continue
+		}
+		for _, target := range tgroup.Targets {
+			podIPs = append(podIPs, aws.StringValue(target.Id))
+		}
+	}
+	if len(podIPs) == 0 {
+		return nil, nil
+	}
+	branchENIs, err := controller.cloud.GetBranchNetworkInterfacesByPrivateIPs(ctx, podIPs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find branch ENIs of pods due to %v", err)
+	}
+	podIPSGs := make(map[string][]string)
+	for _, eni := range branchENIs {
+		for _, addr := range eni.PrivateIpAddresses {
+			for _, group := range eni.Groups {
+				podIPSGs[aws.StringValue(addr.PrivateIpAddress)] = append(podIPSGs[aws.StringValue(addr.PrivateIpAddress)], aws.StringValue(group.GroupId))
+			}
+		}
+	}
+
+	result := make(map[string]tg.TargetGroupGroup)
+	for backend, tgroup := range tgGroup.TGByBackend {
+		if tgroup.TargetType != elbv2.TargetTypeEnumIp {
+			continue
+		}
+		for _, target := range tgroup.Targets {
+			for _, podSGID := range podIPSGs[aws.StringValue(target.Id)] {
+				if _, ok := result[podSGID]; !ok {
+					result[podSGID] = tg.TargetGroupGroup{TGByBackend: make(map[extensions.IngressBackend]tg.TargetGroup)}
+				}
+				podTG := result[podSGID].TGByBackend[backend]
+				podTG.Arn = tgroup.Arn
+				podTG.TargetType = tgroup.TargetType
+				podTG.HealthCheckPort = tgroup.HealthCheckPort
+				podTG.Targets = append(podTG.Targets, target)
+				result[podSGID].TGByBackend[backend] = podTG
+			}
+		}
+	}
+	return result, nil
+}
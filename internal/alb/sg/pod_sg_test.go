@@ -0,0 +1,74 @@
+package sg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestBuildPodSGTGGroups(t *testing.T) {
+	backend1 := extensions.IngressBackend{ServiceName: "service1", ServicePort: intstr.FromInt(80)}
+	backend2 := extensions.IngressBackend{ServiceName: "service2", ServicePort: intstr.FromInt(80)}
+	tgGroup := tg.TargetGroupGroup{
+		TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+			backend1: {
+				Arn:        "tg1",
+				TargetType: elbv2.TargetTypeEnumIp,
+				Targets: []*elbv2.TargetDescription{
+					{Id: aws.String("192.168.1.1"), Port: aws.Int64(8080)},
+					{Id: aws.String("192.168.1.2"), Port: aws.Int64(8080)},
+				},
+			},
+			backend2: {
+				Arn:        "tg2",
+				TargetType: elbv2.TargetTypeEnumInstance,
+				Targets: []*elbv2.TargetDescription{
+					{Id: aws.String("i-1"), Port: aws.Int64(30001)},
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+	cloud := &mocks.CloudAPI{}
+	cloud.On("GetBranchNetworkInterfacesByPrivateIPs", ctx, []string{"192.168.1.1", "192.168.1.2"}).Return([]*ec2.NetworkInterface{
+		{
+			NetworkInterfaceId: aws.String("eni-branch"),
+			Groups: []*ec2.GroupIdentifier{
+				{GroupId: aws.String("sg-pod")},
+			},
+			PrivateIpAddresses: []*ec2.NetworkInterfacePrivateIpAddress{
+				{PrivateIpAddress: aws.String("192.168.1.2")},
+			},
+		},
+	}, nil)
+	controller := &associationController{
+		cloud: cloud,
+	}
+
+	podSGTGGroups, err := controller.buildPodSGTGGroups(ctx, tgGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]tg.TargetGroupGroup{
+		"sg-pod": {
+			TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+				backend1: {
+					Arn:        "tg1",
+					TargetType: elbv2.TargetTypeEnumIp,
+					Targets: []*elbv2.TargetDescription{
+						{Id: aws.String("192.168.1.2"), Port: aws.Int64(8080)},
+					},
+				},
+			},
+		},
+	}, podSGTGGroups)
+	cloud.AssertExpectations(t)
+}
@@ -0,0 +1,158 @@
+package sg
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// maxLbSecurityGroups is the maximum number of securityGroups can be attached to an LoadBalancer.
+const maxLbSecurityGroups = 5
+
+// countIPPermissionRules counts the number of rules in permissions, each source of an permission is an rule in AWS.
+func countIPPermissionRules(permissions []*ec2.IpPermission) int {
+	count := 0
+	for _, permission := range permissions {
+		count += len(permission.IpRanges) + len(permission.Ipv6Ranges) + len(permission.UserIdGroupPairs) + len(permission.PrefixListIds)
+	}
+	return count
+}
+
+// splitIPPermissions splits permissions into groups that each contains at most limit rules.
+// An permission with more sources than remaining capacity of current group is split across groups.
+// Only IpRanges are split, permissions are kept in single group if limit is not positive.
+func splitIPPermissions(permissions []*ec2.IpPermission, limit int) [][]*ec2.IpPermission {
+	if limit <= 0 {
+		return [][]*ec2.IpPermission{permissions}
+	}
+	var groups [][]*ec2.IpPermission
+	var current []*ec2.IpPermission
+	capacity := limit
+	for _, permission := range permissions {
+		ipRanges := permission.IpRanges
+		for {
+			if len(ipRanges) <= capacity {
+				current = append(current, copyIPPermission(permission, ipRanges))
+				capacity -= len(ipRanges)
+				break
+			}
+			if capacity > 0 {
+				current = append(current, copyIPPermission(permission, ipRanges[:capacity]))
+				ipRanges = ipRanges[capacity:]
+			}
+			groups = append(groups, current)
+			current = nil
+			capacity = limit
+		}
+	}
+	if len(current) != 0 || len(groups) == 0 {
+		groups = append(groups, current)
+	}
+	return groups
+}
+
+func copyIPPermission(permission *ec2.IpPermission, ipRanges []*ec2.IpRange) *ec2.IpPermission {
+	return &ec2.IpPermission{
+		IpProtocol:       permission.IpProtocol,
+		FromPort:         permission.FromPort,
+		ToPort:           permission.ToPort,
+		IpRanges:         ipRanges,
+		UserIdGroupPairs: permission.UserIdGroupPairs,
+	}
+}
+
+// consolidatePortCIDRs consolidates the CIDRs of each port with consolidateCIDRs.
+func consolidatePortCIDRs(portCIDRs map[int64][]string) map[int64][]string {
+	result := make(map[int64][]string, len(portCIDRs))
+	for port, cidrs := range portCIDRs {
+		result[port] = consolidateCIDRs(cidrs)
+	}
+	return result
+}
+
+// consolidateCIDRs reduces the number of CIDRs without changing the addresses they cover,
+// by removing CIDRs contained by other CIDRs, and merging adjacent IPv4 CIDRs into their parent CIDR.
+// Invalid CIDRs are kept as is.
+func consolidateCIDRs(cidrs []string) []string {
+	var invalid []string
+	var nets []*net.IPNet
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			invalid = append(invalid, cidr)
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+
+	for merged := true; merged; {
+		merged = false
+		nets = removeContainedNets(nets)
+		for i := 0; i < len(nets) && !merged; i++ {
+			for j := i + 1; j < len(nets); j++ {
+				if parent := mergeSiblingNets(nets[i], nets[j]); parent != nil {
+					nets[i] = parent
+					nets = append(nets[:j], nets[j+1:]...)
+					merged = true
+					break
+				}
+			}
+		}
+	}
+
+	result := append([]string{}, invalid...)
+	for _, ipNet := range nets {
+		result = append(result, ipNet.String())
+	}
+	sort.Strings(result)
+	return result
+}
+
+// removeContainedNets removes the networks that are contained by another network.
+func removeContainedNets(nets []*net.IPNet) []*net.IPNet {
+	sort.Slice(nets, func(i, j int) bool {
+		iOnes, _ := nets[i].Mask.Size()
+		jOnes, _ := nets[j].Mask.Size()
+		return iOnes < jOnes
+	})
+	var result []*net.IPNet
+	for _, ipNet := range nets {
+		contained := false
+		for _, kept := range result {
+			keptOnes, keptBits := kept.Mask.Size()
+			ones, bits := ipNet.Mask.Size()
+			if keptBits == bits && keptOnes <= ones && kept.Contains(ipNet.IP) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			result = append(result, ipNet)
+		}
+	}
+	return result
+}
+
+// mergeSiblingNets returns the parent network if a and b are the two halves of it, only IPv4 networks are merged.
+func mergeSiblingNets(a *net.IPNet, b *net.IPNet) *net.IPNet {
+	aIP, bIP := a.IP.To4(), b.IP.To4()
+	if aIP == nil || bIP == nil {
+		return nil
+	}
+	aOnes, _ := a.Mask.Size()
+	bOnes, _ := b.Mask.Size()
+	if aOnes != bOnes || aOnes == 0 {
+		return nil
+	}
+	parentMask := net.CIDRMask(aOnes-1, 32)
+	aParent := binary.BigEndian.Uint32(aIP) & binary.BigEndian.Uint32(parentMask)
+	bParent := binary.BigEndian.Uint32(bIP) & binary.BigEndian.Uint32(parentMask)
+	if aParent != bParent || binary.BigEndian.Uint32(aIP) == binary.BigEndian.Uint32(bIP) {
+		return nil
+	}
+	_, parent, _ := net.ParseCIDR(fmt.Sprintf("%s/%d", aIP.Mask(parentMask), aOnes-1))
+	return parent
+}
@@ -0,0 +1,95 @@
+package sg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+)
+
+func TestConsolidateCIDRs(t *testing.T) {
+	for _, tc := range []struct {
+		Name          string
+		CIDRs         []string
+		ExpectedCIDRs []string
+	}{
+		{
+			Name:          "disjoint CIDRs are kept",
+			CIDRs:         []string{"10.0.0.0/24", "192.168.0.0/24"},
+			ExpectedCIDRs: []string{"10.0.0.0/24", "192.168.0.0/24"},
+		},
+		{
+			Name:          "contained CIDRs are removed",
+			CIDRs:         []string{"10.0.1.0/24", "10.0.0.0/16", "10.0.0.0/16"},
+			ExpectedCIDRs: []string{"10.0.0.0/16"},
+		},
+		{
+			Name:          "sibling CIDRs are merged recursively",
+			CIDRs:         []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/23"},
+			ExpectedCIDRs: []string{"10.0.0.0/22"},
+		},
+		{
+			Name:          "adjacent CIDRs that are not siblings are kept",
+			CIDRs:         []string{"10.0.1.0/24", "10.0.2.0/24"},
+			ExpectedCIDRs: []string{"10.0.1.0/24", "10.0.2.0/24"},
+		},
+		{
+			Name:          "invalid CIDRs are kept",
+			CIDRs:         []string{"invalid", "10.0.0.0/24"},
+			ExpectedCIDRs: []string{"10.0.0.0/24", "invalid"},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedCIDRs, consolidateCIDRs(tc.CIDRs))
+		})
+	}
+}
+
+func TestSplitIPPermissions(t *testing.T) {
+	ipRange := func(cidr string) *ec2.IpRange {
+		return &ec2.IpRange{CidrIp: aws.String(cidr)}
+	}
+	permission := func(port int64, ipRanges ...*ec2.IpRange) *ec2.IpPermission {
+		return &ec2.IpPermission{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int64(port),
+			ToPort:     aws.Int64(port),
+			IpRanges:   ipRanges,
+		}
+	}
+	for _, tc := range []struct {
+		Name           string
+		Permissions    []*ec2.IpPermission
+		Limit          int
+		ExpectedGroups [][]*ec2.IpPermission
+	}{
+		{
+			Name:           "permissions within limit",
+			Permissions:    []*ec2.IpPermission{permission(80, ipRange("10.0.0.0/24")), permission(443, ipRange("10.0.0.0/24"))},
+			Limit:          2,
+			ExpectedGroups: [][]*ec2.IpPermission{{permission(80, ipRange("10.0.0.0/24")), permission(443, ipRange("10.0.0.0/24"))}},
+		},
+		{
+			Name:        "permission exceeds limit is split",
+			Permissions: []*ec2.IpPermission{permission(80, ipRange("10.0.0.0/24"), ipRange("10.0.1.0/24")), permission(443, ipRange("10.0.0.0/24"))},
+			Limit:       1,
+			ExpectedGroups: [][]*ec2.IpPermission{
+				{permission(80, ipRange("10.0.0.0/24"))},
+				{permission(80, ipRange("10.0.1.0/24"))},
+				{permission(443, ipRange("10.0.0.0/24"))},
+			},
+		},
+		{
+			Name:           "no permissions",
+			Permissions:    nil,
+			Limit:          1,
+			ExpectedGroups: [][]*ec2.IpPermission{nil},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.ExpectedGroups, splitIPPermissions(tc.Permissions, tc.Limit))
+		})
+	}
+}
@@ -16,6 +16,13 @@ type SecurityGroup struct {
 	GroupName *string
 
 	InboundPermissions []*ec2.IpPermission
+
+	// RuleOwner limits the rules managed by us to rules with description prefixed by RuleOwner, other rules are left untouched.
+	// It's used for securityGroups not created by us, e.g. securityGroups of pods.
+	RuleOwner string
+
+	// Tags are additional tags applied when the securityGroup is created.
+	Tags map[string]string
 }
 
 // SecurityGroupController manages SecurityGroups
@@ -30,6 +37,10 @@ type SecurityGroupController interface {
 
 type securityGroupController struct {
 	cloud aws.CloudAPI
+
+	// managedByTagKey and managedByTagValue is the tag identifying securityGroups created by us
+	managedByTagKey   string
+	managedByTagValue string
 }
 
 func (controller *securityGroupController) Reconcile(ctx context.Context, group *SecurityGroup) error {
@@ -75,26 +86,32 @@ func (controller *securityGroupController) reconcileByNewSGInstance(ctx context.
 	}
 	group.GroupID = createSGOutput.GroupId
 
-	_, err = controller.cloud.AuthorizeSecurityGroupIngressWithContext(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
-		GroupId:       group.GroupID,
-		IpPermissions: group.InboundPermissions,
-	})
-	if err != nil {
-		return err
+	if len(group.InboundPermissions) != 0 {
+		_, err = controller.cloud.AuthorizeSecurityGroupIngressWithContext(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+			GroupId:       group.GroupID,
+			IpPermissions: group.InboundPermissions,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
+	sgTags := []*ec2.Tag{
+		{
+			Key:   aws.String("Name"),
+			Value: group.GroupName,
+		},
+		{
+			Key:   aws.String(controller.managedByTagKey),
+			Value: aws.String(controller.managedByTagValue),
+		},
+	}
+	for _, key := range sortedTagKeys(group.Tags) {
+		sgTags = append(sgTags, &ec2.Tag{Key: aws.String(key), Value: aws.String(group.Tags[key])})
+	}
 	_, err = controller.cloud.CreateTagsWithContext(ctx, &ec2.CreateTagsInput{
 		Resources: []*string{group.GroupID},
-		Tags: []*ec2.Tag{
-			{
-				Key:   aws.String("Name"),
-				Value: group.GroupName,
-			},
-			{
-				Key:   aws.String(aws.ManagedByKey),
-				Value: aws.String(aws.ManagedByValue),
-			},
-		},
+		Tags:      sgTags,
 	})
 	if err != nil {
 		return err
@@ -113,7 +130,8 @@ func (controller *securityGroupController) reconcileByModifySGInstance(ctx conte
 		group.GroupName = instance.GroupName
 	}
 
-	permissionsToRevoke := diffIPPermissions(instance.IpPermissions, group.InboundPermissions)
+	managedPermissions := filterManagedIPPermissions(instance.IpPermissions, group.RuleOwner)
+	permissionsToRevoke := diffIPPermissions(managedPermissions, group.InboundPermissions)
 	if len(permissionsToRevoke) != 0 {
 		albctx.GetLogger(ctx).Infof("revoking inbound permissions from securityGroup %s", aws.StringValue(group.GroupID))
 		_, err := controller.cloud.RevokeSecurityGroupIngressWithContext(ctx, &ec2.RevokeSecurityGroupIngressInput{
@@ -125,7 +143,7 @@ func (controller *securityGroupController) reconcileByModifySGInstance(ctx conte
 		}
 	}
 
-	permissionsToGrant := diffIPPermissions(group.InboundPermissions, instance.IpPermissions)
+	permissionsToGrant := diffIPPermissions(group.InboundPermissions, managedPermissions)
 	if len(permissionsToGrant) != 0 {
 		albctx.GetLogger(ctx).Infof("granting inbound permissions to securityGroup %s", aws.StringValue(group.GroupID))
 		_, err := controller.cloud.AuthorizeSecurityGroupIngressWithContext(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
@@ -136,6 +154,18 @@ func (controller *securityGroupController) reconcileByModifySGInstance(ctx conte
 			return fmt.Errorf("failed to grant inbound permissions due to %v", err)
 		}
 	}
+
+	permissionsToDescribe := diffIPPermissionDescriptions(group.InboundPermissions, managedPermissions)
+	if len(permissionsToDescribe) != 0 {
+		albctx.GetLogger(ctx).Infof("updating inbound permission descriptions of securityGroup %s", aws.StringValue(group.GroupID))
+		_, err := controller.cloud.UpdateSecurityGroupRuleDescriptionsIngressWithContext(ctx, &ec2.UpdateSecurityGroupRuleDescriptionsIngressInput{
+			GroupId:       group.GroupID,
+			IpPermissions: permissionsToDescribe,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update inbound permission descriptions due to %v", err)
+		}
+	}
 	return nil
 }
 
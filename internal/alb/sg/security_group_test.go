@@ -775,7 +775,9 @@ func TestReconcile(t *testing.T) {
 			}
 
 			controller := &securityGroupController{
-				cloud: cloud,
+				cloud:             cloud,
+				managedByTagKey:   aws.ManagedByKey,
+				managedByTagValue: aws.ManagedByValue,
 			}
 			err := controller.Reconcile(context.Background(), &tc.SecurityGroup)
 
@@ -889,7 +891,9 @@ func TestDelete(t *testing.T) {
 			}
 
 			controller := &securityGroupController{
-				cloud: cloud,
+				cloud:             cloud,
+				managedByTagKey:   aws.ManagedByKey,
+				managedByTagValue: aws.ManagedByValue,
 			}
 			err := controller.Delete(context.Background(), &tc.SecurityGroup)
 
@@ -0,0 +1,138 @@
+package sg
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+)
+
+// sharedNodePortsRetention is how long the ports allowed by the shared Instance securityGroup when the controller starts are kept,
+// so the NodePorts of LoadBalancers that aren't reconciled yet since then aren't revoked.
+const sharedNodePortsRetention = 30 * time.Minute
+
+// portRange is an range of ports from from to to, inclusive.
+type portRange struct {
+	from int64
+	to   int64
+}
+
+// sharedNodePorts records the NodePorts used by each LoadBalancer of the shared securityGroups, so the shared Instance securityGroup
+// only allows the NodePorts in use by any of them.
+type sharedNodePorts struct {
+	// mutex protects all fields
+	mutex sync.Mutex
+
+	// portsByLB are the NodePorts of targets and health checks of LoadBalancers by their LbID
+	portsByLB map[string][]int64
+
+	// retained are the port ranges allowed when the controller started, which are kept until retainUntil
+	seeded      bool
+	retained    []portRange
+	retainUntil time.Time
+}
+
+func newSharedNodePorts() *sharedNodePorts {
+	return &sharedNodePorts{portsByLB: make(map[string][]int64)}
+}
+
+// isSeeded tests whether the ports allowed when the controller started are recorded.
+func (p *sharedNodePorts) isSeeded() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.seeded
+}
+
+// seed records the ports allowed by permissions of the shared Instance securityGroup when the controller started, they're kept until sharedNodePortsRetention passed.
+func (p *sharedNodePorts) seed(permissions []*ec2.IpPermission, now time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.seeded {
+		return
+	}
+	for _, permission := range permissions {
+		if aws.StringValue(permission.IpProtocol) != "tcp" || permission.FromPort == nil || permission.ToPort == nil {
+			continue
+		}
+		p.retained = append(p.retained, portRange{from: aws.Int64Value(permission.FromPort), to: aws.Int64Value(permission.ToPort)})
+	}
+	p.seeded = true
+	p.retainUntil = now.Add(sharedNodePortsRetention)
+}
+
+// record records ports as the NodePorts of LoadBalancer lbID, and returns the merged ranges of the NodePorts of all LoadBalancers.
+func (p *sharedNodePorts) record(lbID string, ports []int64, now time.Time) []portRange {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.portsByLB[lbID] = ports
+	var ranges []portRange
+	for _, lbPorts := range p.portsByLB {
+		for _, port := range lbPorts {
+			ranges = append(ranges, portRange{from: port, to: port})
+		}
+	}
+	if now.Before(p.retainUntil) {
+		ranges = append(ranges, p.retained...)
+	}
+	return mergePortRanges(ranges)
+}
+
+// forget forgets the NodePorts of LoadBalancer lbID once it no longer uses the shared securityGroups, they're revoked by the next reconcile of the shared securityGroups.
+func (p *sharedNodePorts) forget(lbID string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.portsByLB, lbID)
+}
+
+// mergePortRanges merges overlapping and adjacent ranges, and returns them sorted.
+func mergePortRanges(ranges []portRange) []portRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].from < ranges[j].from })
+	var merged []portRange
+	for _, r := range ranges {
+		if last := len(merged) - 1; last >= 0 && r.from <= merged[last].to+1 {
+			if r.to > merged[last].to {
+				merged[last].to = r.to
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// instanceNodePorts returns the ports of targets and health checks of tgGroup, which are NodePorts for instance targets.
+func instanceNodePorts(tgGroup tg.TargetGroupGroup) []int64 {
+	seen := make(map[int64]bool)
+	var ports []int64
+	add := func(port int64) {
+		if !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	for _, tgroup := range tgGroup.TGByBackend {
+		for _, target := range tgroup.Targets {
+			if target.Port != nil {
+				add(aws.Int64Value(target.Port))
+			}
+		}
+		if port, err := strconv.ParseInt(tgroup.HealthCheckPort, 10, 64); err == nil {
+			add(port)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
+
+// buildPortRangesInboundPermissions builds the inbound permissions allowing traffic to ranges from sourceGroupIDs.
+func buildPortRangesInboundPermissions(sourceGroupIDs []string, ranges []portRange, description string) []*ec2.IpPermission {
+	var permissions []*ec2.IpPermission
+	for _, r := range ranges {
+		permissions = append(permissions, buildPortRangeInboundPermissions(sourceGroupIDs, r.from, r.to, description)...)
+	}
+	return permissions
+}
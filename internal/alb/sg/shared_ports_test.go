@@ -0,0 +1,84 @@
+package sg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestMergePortRanges(t *testing.T) {
+	for _, tc := range []struct {
+		Name     string
+		Ranges   []portRange
+		Expected []portRange
+	}{
+		{
+			Name:     "no ranges",
+			Ranges:   nil,
+			Expected: nil,
+		},
+		{
+			Name:     "overlapping and adjacent ranges are merged",
+			Ranges:   []portRange{{30005, 30005}, {30001, 30002}, {30003, 30003}, {30002, 30002}, {30010, 30020}, {30015, 30016}},
+			Expected: []portRange{{30001, 30003}, {30005, 30005}, {30010, 30020}},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, mergePortRanges(tc.Ranges))
+		})
+	}
+}
+
+func TestInstanceNodePorts(t *testing.T) {
+	tgGroup := tg.TargetGroupGroup{
+		TGByBackend: map[extensions.IngressBackend]tg.TargetGroup{
+			{ServiceName: "service1", ServicePort: intstr.FromInt(80)}: {
+				Targets: []*elbv2.TargetDescription{
+					{Id: aws.String("i-1"), Port: aws.Int64(30002)},
+					{Id: aws.String("i-2"), Port: aws.Int64(30002)},
+				},
+				HealthCheckPort: "traffic-port",
+			},
+			{ServiceName: "service2", ServicePort: intstr.FromInt(80)}: {
+				Targets: []*elbv2.TargetDescription{
+					{Id: aws.String("i-1"), Port: aws.Int64(30001)},
+				},
+				HealthCheckPort: "30100",
+			},
+		},
+	}
+	assert.Equal(t, []int64{30001, 30002, 30100}, instanceNodePorts(tgGroup))
+}
+
+func TestSharedNodePorts(t *testing.T) {
+	now := time.Now()
+	ports := newSharedNodePorts()
+	assert.False(t, ports.isSeeded())
+
+	ports.seed([]*ec2.IpPermission{
+		{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(30050), ToPort: aws.Int64(30051)},
+		{IpProtocol: aws.String("-1")},
+	}, now)
+	assert.True(t, ports.isSeeded())
+
+	assert.Equal(t, []portRange{{30001, 30002}, {30050, 30051}}, ports.record("lb-1", []int64{30001, 30002}, now))
+	assert.Equal(t, []portRange{{30001, 30003}, {30050, 30051}}, ports.record("lb-2", []int64{30003}, now))
+
+	// ports allowed when the controller started are revoked after the retention
+	later := now.Add(sharedNodePortsRetention)
+	assert.Equal(t, []portRange{{30001, 30003}}, ports.record("lb-2", []int64{30003}, later))
+
+	ports.forget("lb-1")
+	assert.Equal(t, []portRange{{30003, 30003}}, ports.record("lb-2", []int64{30003}, later))
+
+	// seeding again keeps the ports recorded since
+	ports.seed(nil, later)
+	assert.Equal(t, []portRange{{30003, 30004}}, ports.record("lb-1", []int64{30004}, later))
+}
@@ -0,0 +1,130 @@
+package tags
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// maxDescribeTagsResources is the maximum number of resources can be described in a single ELBV2 DescribeTags call
+const maxDescribeTagsResources = 20
+
+// batch describes the tags of ELBV2 resources of an reconcile together, instead of one DescribeTags call for each of them.
+type batch struct {
+	// mutex protects pending, the ARNs whose tags are yet to be described, and described, the tags described by ARN
+	mutex     sync.Mutex
+	pending   sets.String
+	described map[string]*Tags
+}
+
+type batchKey struct{}
+
+// WithBatch returns an context batching the tag lookups of ELBV2 resources made with it, e.g. by reconciling tags.
+// The first lookup not described yet describes the tags of up to 20 resources in a single DescribeTags call:
+// the resource looked up, and resources of arns not looked up yet, e.g. the targetGroups reconciled before.
+func WithBatch(ctx context.Context, arns []string) context.Context {
+	return context.WithValue(ctx, batchKey{}, &batch{
+		pending:   sets.NewString(arns...),
+		described: make(map[string]*Tags),
+	})
+}
+
+// DescribeELBV2Tags returns the tags of ELBV2 resource arn, which are described in batches if ctx is an context of WithBatch.
+func DescribeELBV2Tags(ctx context.Context, cloud aws.CloudAPI, arn string) (*Tags, error) {
+	b, ok := ctx.Value(batchKey{}).(*batch)
+	if !ok {
+		described, err := describeELBV2Tags(ctx, cloud, []string{arn})
+		if err != nil {
+			return nil, err
+		}
+		return described[arn], nil
+	}
+	return b.describe(ctx, cloud, arn)
+}
+
+func (b *batch) describe(ctx context.Context, cloud aws.CloudAPI, arn string) (*Tags, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if t, ok := b.described[arn]; ok {
+		return t.Copy(), nil
+	}
+	b.pending.Delete(arn)
+	arns := []string{arn}
+	for _, pendingArn := range b.pending.List() {
+		if len(arns) == maxDescribeTagsResources {
+			break
+		}
+		arns = append(arns, pendingArn)
+	}
+	described, err := describeELBV2Tags(ctx, cloud, arns)
+	if err != nil && len(arns) > 1 {
+		// an pending resource may have been deleted since, which fails the whole call, so arn is described alone instead.
+		b.pending.Delete(arns[1:]...)
+		described, err = describeELBV2Tags(ctx, cloud, arns[:1])
+	}
+	if err != nil {
+		return nil, err
+	}
+	for describedArn, t := range described {
+		b.pending.Delete(describedArn)
+		b.described[describedArn] = t
+	}
+	return described[arn].Copy(), nil
+}
+
+// forget forgets the tags described for arn, so they're described again once they're modified.
+func (b *batch) forget(arn string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	delete(b.described, arn)
+}
+
+// ForgetELBV2Tags forgets the tags of arn described by the batch of ctx, if any, it must be called once they're modified.
+func ForgetELBV2Tags(ctx context.Context, arn string) {
+	if b, ok := ctx.Value(batchKey{}).(*batch); ok {
+		b.forget(arn)
+	}
+}
+
+// DescribeELBV2TagsOfArns returns the tags of ELBV2 resources arns by ARN, described in as few DescribeTags calls as possible.
+func DescribeELBV2TagsOfArns(ctx context.Context, cloud aws.CloudAPI, arns []string) (map[string]*Tags, error) {
+	described := make(map[string]*Tags, len(arns))
+	for start := 0; start < len(arns); start += maxDescribeTagsResources {
+		end := start + maxDescribeTagsResources
+		if end > len(arns) {
+			end = len(arns)
+		}
+		chunk, err := describeELBV2Tags(ctx, cloud, arns[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for arn, t := range chunk {
+			described[arn] = t
+		}
+	}
+	return described, nil
+}
+
+func describeELBV2Tags(ctx context.Context, cloud aws.CloudAPI, arns []string) (map[string]*Tags, error) {
+	resp, err := cloud.DescribeELBV2TagsWithContext(ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice(arns)})
+	if err != nil {
+		return nil, err
+	}
+	described := make(map[string]*Tags, len(arns))
+	for _, arn := range arns {
+		described[arn] = NewTags()
+	}
+	for _, tagDescription := range resp.TagDescriptions {
+		t, ok := described[aws.StringValue(tagDescription.ResourceArn)]
+		if !ok {
+			continue
+		}
+		for _, tag := range tagDescription.Tags {
+			t.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+	}
+	return described, nil
+}
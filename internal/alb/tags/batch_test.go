@@ -0,0 +1,79 @@
+package tags
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func tagDescription(arn string, key string, value string) *elbv2.TagDescription {
+	return &elbv2.TagDescription{ResourceArn: aws.String(arn), Tags: []*elbv2.Tag{elbv2Tag(key, value)}}
+}
+
+func TestDescribeELBV2Tags(t *testing.T) {
+	t.Run("batched", func(t *testing.T) {
+		cloud := &mocks.CloudAPI{}
+		ctx := WithBatch(context.Background(), []string{"tg1", "tg2"})
+		cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{"lb", "tg1", "tg2"})}).Return(
+			&elbv2.DescribeTagsOutput{TagDescriptions: []*elbv2.TagDescription{
+				tagDescription("lb", "k", "lb"),
+				tagDescription("tg1", "k", "tg1"),
+			}}, nil).Once()
+
+		for _, tc := range []struct {
+			arn      string
+			expected map[string]string
+		}{
+			{arn: "lb", expected: map[string]string{"k": "lb"}},
+			{arn: "tg1", expected: map[string]string{"k": "tg1"}},
+			{arn: "tg2", expected: map[string]string{}},
+		} {
+			actual, err := DescribeELBV2Tags(ctx, cloud, tc.arn)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual.Tags)
+		}
+
+		// tags forgotten once modified are described again, alone.
+		ForgetELBV2Tags(ctx, "tg1")
+		cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{"tg1"})}).Return(
+			&elbv2.DescribeTagsOutput{TagDescriptions: []*elbv2.TagDescription{tagDescription("tg1", "k", "modified")}}, nil).Once()
+		actual, err := DescribeELBV2Tags(ctx, cloud, "tg1")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"k": "modified"}, actual.Tags)
+		cloud.AssertExpectations(t)
+	})
+
+	t.Run("batch fails", func(t *testing.T) {
+		cloud := &mocks.CloudAPI{}
+		ctx := WithBatch(context.Background(), []string{"deleted-tg"})
+		cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{"lb", "deleted-tg"})}).Return(
+			nil, errors.New("TargetGroupNotFound"))
+		cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{"lb"})}).Return(
+			&elbv2.DescribeTagsOutput{TagDescriptions: []*elbv2.TagDescription{tagDescription("lb", "k", "lb")}}, nil)
+
+		actual, err := DescribeELBV2Tags(ctx, cloud, "lb")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"k": "lb"}, actual.Tags)
+		cloud.AssertExpectations(t)
+	})
+
+	t.Run("not batched", func(t *testing.T) {
+		cloud := &mocks.CloudAPI{}
+		ctx := context.Background()
+		cloud.On("DescribeELBV2TagsWithContext", ctx, mock.Anything).Return(
+			&elbv2.DescribeTagsOutput{TagDescriptions: []*elbv2.TagDescription{tagDescription("lb", "k", "lb")}}, nil).Twice()
+
+		for i := 0; i < 2; i++ {
+			actual, err := DescribeELBV2Tags(ctx, cloud, "lb")
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]string{"k": "lb"}, actual.Tags)
+		}
+		cloud.AssertExpectations(t)
+	})
+}
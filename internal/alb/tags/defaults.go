@@ -0,0 +1,61 @@
+package tags
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+// ParseDefaultTags parses default tags in Key=Value format, e.g. owner=platform
+func ParseDefaultTags(entries []string) (map[string]string, error) {
+	defaultTags := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("unable to parse default tag `%s` into Key=Value pair", entry)
+		}
+		defaultTags[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return defaultTags, nil
+}
+
+// BuildControllerTags builds the tags configured on controller for resources created for ingress,
+// which are the default tags overridden by the tag templates.
+func BuildControllerTags(store store.Storer, ingress *extensions.Ingress) (map[string]string, error) {
+	controllerTags, err := ParseDefaultTags(store.GetConfig().DefaultTags)
+	if err != nil {
+		return nil, err
+	}
+	templatedTags, err := BuildTemplatedTags(store, ingress)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range templatedTags {
+		controllerTags[k] = v
+	}
+	return controllerTags, nil
+}
+
+// MergeIngressTags merges the tags configured on controller with the tags annotation of ingress.
+// Tags of ingress take precedence unless controllerPrecedence is set, the keys specified by both with different values are returned as conflicts.
+func MergeIngressTags(controllerTags map[string]string, ingressTags map[string]string, controllerPrecedence bool) (map[string]string, []string) {
+	merged := make(map[string]string, len(controllerTags)+len(ingressTags))
+	for k, v := range controllerTags {
+		merged[k] = v
+	}
+	var conflicts []string
+	for k, v := range ingressTags {
+		if controllerValue, ok := controllerTags[k]; ok && controllerValue != v {
+			conflicts = append(conflicts, k)
+			if controllerPrecedence {
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
@@ -0,0 +1,42 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseDefaultTags(t *testing.T) {
+	defaultTags, err := ParseDefaultTags([]string{"owner=platform", " env = prod ", "empty="})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "platform", "env": "prod", "empty": ""}, defaultTags)
+
+	_, err = ParseDefaultTags([]string{"owner"})
+	assert.EqualError(t, err, "unable to parse default tag `owner` into Key=Value pair")
+}
+
+func Test_MergeIngressTags(t *testing.T) {
+	controllerTags := map[string]string{"owner": "platform", "env": "prod"}
+	ingressTags := map[string]string{"owner": "team-a", "env": "prod", "app": "web"}
+	for _, tc := range []struct {
+		name                 string
+		controllerPrecedence bool
+		expectedTags         map[string]string
+	}{
+		{
+			name:         "ingress tags take precedence",
+			expectedTags: map[string]string{"owner": "team-a", "env": "prod", "app": "web"},
+		},
+		{
+			name:                 "controller tags take precedence",
+			controllerPrecedence: true,
+			expectedTags:         map[string]string{"owner": "platform", "env": "prod", "app": "web"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			merged, conflicts := MergeIngressTags(controllerTags, ingressTags, tc.controllerPrecedence)
+			assert.Equal(t, tc.expectedTags, merged)
+			assert.Equal(t, []string{"owner"}, conflicts)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package tags
 
 import (
 	"context"
+	"sort"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
@@ -19,8 +20,26 @@ const (
 	Namespace   = "kubernetes.io/namespace"
 	ServiceName = "kubernetes.io/service-name"
 	ServicePort = "kubernetes.io/service-port"
+
+	// RetainOnDelete marks LoadBalancers that are retained when their ingress is deleted
+	RetainOnDelete = "alb.ingress.kubernetes.io/retain-on-delete"
+	// Orphaned marks resources retained after their ingress is deleted
+	Orphaned = "alb.ingress.kubernetes.io/orphaned"
+	// PartitionOf marks LoadBalancers created for an partition of ingress split by host, with the name of ingress
+	PartitionOf = "alb.ingress.kubernetes.io/partition-of"
+	// CloudWatchAlarms marks LoadBalancers with CloudWatch alarms provisioned by the controller, so they're deleted with the LoadBalancer
+	CloudWatchAlarms = "alb.ingress.kubernetes.io/cloudwatch-alarms"
+	// ReplacedAt marks LoadBalancers replaced due to scheme change with the time their ingress pointed at the replacement, in RFC3339
+	ReplacedAt = "alb.ingress.kubernetes.io/replaced-at"
 )
 
+// reservedKeyPrefix prefixes the tags reserved by AWS, which can't be modified or removed.
+const reservedKeyPrefix = "aws:"
+
+// managedKeys are the tags marking resources for the controller itself, which are removed even if they're prefixed by ignored key prefixes,
+// e.g. resources retained for an deleted ingress are no longer orphaned once the ingress is recreated.
+var managedKeys = []string{RetainOnDelete, Orphaned, PartitionOf, CloudWatchAlarms}
+
 // Tags stores the tags for an ARN
 type Tags struct {
 	// Arn is the ARN of the resource to be tagged
@@ -46,20 +65,33 @@ func (t *Tags) Copy() *Tags {
 	return NewTags(t.Tags)
 }
 
+// Contains tests whether t has all of tags with the same values, e.g. whether an resource is owned by an ingress.
+func (t *Tags) Contains(tags map[string]string) bool {
+	for k, v := range tags {
+		if value, ok := t.Tags[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
 // Controller manages tags on a resource
 type Controller interface {
 	Reconcile(context.Context, *Tags) error
 }
 
-// NewController constructs a new tags controller
-func NewController(cloud aws.CloudAPI) Controller {
+// NewController constructs a new tags controller, tags prefixed by any of ignoredKeyPrefixes are never removed,
+// so tags added out-of-band by other tools survive reconcile.
+func NewController(cloud aws.CloudAPI, ignoredKeyPrefixes []string) Controller {
 	return &controller{
-		cloud: cloud,
+		cloud:              cloud,
+		ignoredKeyPrefixes: append([]string{reservedKeyPrefix}, ignoredKeyPrefixes...),
 	}
 }
 
 type controller struct {
-	cloud aws.CloudAPI
+	cloud              aws.CloudAPI
+	ignoredKeyPrefixes []string
 }
 
 func (c *controller) Reconcile(ctx context.Context, desired *Tags) error {
@@ -73,6 +105,10 @@ func (c *controller) Reconcile(ctx context.Context, desired *Tags) error {
 	}
 
 	modify, remove := changeSets(current, desired)
+	remove = c.filterIgnoredKeys(remove)
+	if len(modify) > 0 || len(remove) > 0 {
+		ForgetELBV2Tags(ctx, desired.Arn)
+	}
 
 	if len(modify) > 0 {
 		albctx.GetLogger(ctx).Infof("Modifying tags on %v to %v", desired.Arn, log.Prettify(modify))
@@ -103,19 +139,39 @@ func (c *controller) Reconcile(ctx context.Context, desired *Tags) error {
 	return nil
 }
 
-func (c *controller) elbTags(ctx context.Context, arn string) (t *Tags, err error) {
-	var r *elbv2.DescribeTagsOutput
-	t = NewTags()
+// filterIgnoredKeys returns the keys that are not prefixed by any of the ignored key prefixes, and the managedKeys.
+func (c *controller) filterIgnoredKeys(keys []string) []string {
+	var result []string
+	for _, key := range keys {
+		if isManagedKey(key) || !c.isIgnoredKey(key) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
 
-	if r, err = c.cloud.DescribeELBV2TagsWithContext(ctx, &elbv2.DescribeTagsInput{ResourceArns: []*string{aws.String(arn)}}); err == nil {
-		for _, tagDescription := range r.TagDescriptions {
-			for _, tag := range tagDescription.Tags {
-				t.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
-			}
+// isIgnoredKey tests whether key is prefixed by any of the ignored key prefixes.
+func (c *controller) isIgnoredKey(key string) bool {
+	for _, prefix := range c.ignoredKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
 		}
 	}
+	return false
+}
 
-	return
+// isManagedKey tests whether key is one of managedKeys.
+func isManagedKey(key string) bool {
+	for _, managedKey := range managedKeys {
+		if key == managedKey {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *controller) elbTags(ctx context.Context, arn string) (*Tags, error) {
+	return DescribeELBV2Tags(ctx, c.cloud, arn)
 }
 
 // changeSets compares b to a, returning a map of tags to add/change to a and a list of tags to remove from a
@@ -133,6 +189,7 @@ func changeSets(a, b *Tags) (map[string]string, []string) {
 			remove = append(remove, k)
 		}
 	}
+	sort.Strings(remove)
 
 	return modify, remove
 }
@@ -215,6 +215,30 @@ func Test_Reconcile(t *testing.T) {
 			},
 			ExpectedError: fmt.Errorf("nope"),
 		},
+		{
+			name: "tags reserved by AWS or with ignored prefixes are not removed",
+			Tags: emptyTags(),
+			DescribeELBV2TagsCall: &DescribeTagsELBV2Call{
+				Output: &elbv2.DescribeTagsOutput{
+					TagDescriptions: []*elbv2.TagDescription{
+						{
+							ResourceArn: aws.String(arn),
+							Tags: []*elbv2.Tag{
+								elbv2Tag("k", "v"),
+								elbv2Tag("aws:cloudformation:stack-name", "stack"),
+								elbv2Tag("ops/backup", "daily"),
+							},
+						},
+					},
+				},
+			},
+			UntagResourcesCall: &UntagResourcesCall{
+				Input: &resourcegroupstaggingapi.UntagResourcesInput{
+					ResourceARNList: []*string{aws.String(arn)},
+					TagKeys:         []*string{aws.String("k")},
+				},
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			ctx := context.Background()
@@ -232,7 +256,7 @@ func Test_Reconcile(t *testing.T) {
 				cloud.On("UntagResourcesWithContext", ctx, tc.UntagResourcesCall.Input).Return(nil, tc.UntagResourcesCall.Err)
 			}
 
-			controller := NewController(cloud)
+			controller := NewController(cloud, []string{"ops/"})
 			err := controller.Reconcile(context.Background(), tc.Tags)
 
 			if tc.ExpectedError != nil {
@@ -246,3 +270,9 @@ func Test_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func Test_filterIgnoredKeys(t *testing.T) {
+	c := &controller{ignoredKeyPrefixes: []string{reservedKeyPrefix, "alb.ingress.kubernetes.io/"}}
+	keys := []string{"aws:cloudformation:stack-name", "alb.ingress.kubernetes.io/owner", Orphaned, RetainOnDelete, "k"}
+	assert.Equal(t, []string{Orphaned, RetainOnDelete, "k"}, c.filterIgnoredKeys(keys))
+}
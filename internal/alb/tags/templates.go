@@ -0,0 +1,97 @@
+package tags
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+// TemplateData is the data tag templates are rendered with, e.g. {{ .Namespace.Labels.team }}
+type TemplateData struct {
+	Namespace TemplateObject
+	Ingress   TemplateObject
+}
+
+// TemplateObject exposes the metadata of an kubernetes object to tag templates
+type TemplateObject struct {
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// ParseTemplates parses tag templates in Key=Template format, e.g. team={{ .Namespace.Labels.team }}
+func ParseTemplates(entries []string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("unable to parse tag template `%s` into Key=Template pair", entry)
+		}
+		tmpl, err := template.New(parts[0]).Option("missingkey=zero").Parse(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse tag template `%s` due to %v", entry, err)
+		}
+		templates[parts[0]] = tmpl
+	}
+	return templates, nil
+}
+
+// RenderTemplates renders tag templates with data, tags rendered to empty values are omitted.
+func RenderTemplates(entries []string, data TemplateData) (map[string]string, error) {
+	templates, err := ParseTemplates(entries)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for key := range templates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]string)
+	for _, key := range keys {
+		var buf bytes.Buffer
+		if err := templates[key].Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render tag template for %s due to %v", key, err)
+		}
+		if value := strings.TrimSpace(buf.String()); value != "" {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
+
+// BuildTemplatedTags renders the tag templates of controller configuration for resources created for ingress.
+func BuildTemplatedTags(store store.Storer, ingress *extensions.Ingress) (map[string]string, error) {
+	entries := store.GetConfig().TagTemplates
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	namespace, err := store.GetNamespace(ingress.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s due to %v", ingress.Namespace, err)
+	}
+	return RenderTemplates(entries, NewTemplateData(namespace, ingress))
+}
+
+// NewTemplateData builds the data to render tag templates for ingress in namespace.
+func NewTemplateData(namespace *corev1.Namespace, ingress *extensions.Ingress) TemplateData {
+	return TemplateData{
+		Namespace: TemplateObject{
+			Name:        namespace.Name,
+			Labels:      namespace.Labels,
+			Annotations: namespace.Annotations,
+		},
+		Ingress: TemplateObject{
+			Name:        ingress.Name,
+			Labels:      ingress.Labels,
+			Annotations: ingress.Annotations,
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package tags
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ParseTemplates(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		entries     []string
+		expectedErr bool
+	}{
+		{
+			name:    "valid templates",
+			entries: []string{"team={{ .Namespace.Labels.team }}", "ingress={{ .Ingress.Name }}", "static=value"},
+		},
+		{
+			name:        "missing separator",
+			entries:     []string{"team"},
+			expectedErr: true,
+		},
+		{
+			name:        "empty key",
+			entries:     []string{"={{ .Namespace.Name }}"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid template",
+			entries:     []string{"team={{ .Namespace.Labels.team"},
+			expectedErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseTemplates(tc.entries)
+			assert.Equal(t, tc.expectedErr, err != nil)
+		})
+	}
+}
+
+func Test_RenderTemplates(t *testing.T) {
+	data := TemplateData{
+		Namespace: TemplateObject{
+			Name:   "payments",
+			Labels: map[string]string{"team": "billing"},
+		},
+		Ingress: TemplateObject{
+			Name:        "api",
+			Annotations: map[string]string{"cost-center": "1234"},
+		},
+	}
+	for _, tc := range []struct {
+		name         string
+		entries      []string
+		expectedTags map[string]string
+	}{
+		{
+			name: "templates rendered with namespace and ingress metadata",
+			entries: []string{
+				"team={{ .Namespace.Labels.team }}",
+				"cost-center={{ index .Ingress.Annotations \"cost-center\" }}",
+				"owner={{ .Namespace.Name }}/{{ .Ingress.Name }}",
+			},
+			expectedTags: map[string]string{
+				"team":        "billing",
+				"cost-center": "1234",
+				"owner":       "payments/api",
+			},
+		},
+		{
+			name:         "templates rendered to empty values are omitted",
+			entries:      []string{"team={{ .Namespace.Labels.missing }}"},
+			expectedTags: map[string]string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tags, err := RenderTemplates(tc.entries, data)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTags, tags)
+		})
+	}
+}
+
+func Test_BuildTemplatedTags(t *testing.T) {
+	ingress := &extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "api"},
+	}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "billing"}},
+	}
+
+	t.Run("no templates configured", func(t *testing.T) {
+		mockStore := &store.MockStorer{}
+		mockStore.On("GetConfig").Return(&config.Configuration{})
+		tags, err := BuildTemplatedTags(mockStore, ingress)
+		assert.NoError(t, err)
+		assert.Nil(t, tags)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("templates rendered with namespace of ingress", func(t *testing.T) {
+		mockStore := &store.MockStorer{}
+		mockStore.On("GetConfig").Return(&config.Configuration{TagTemplates: []string{"team={{ .Namespace.Labels.team }}"}})
+		mockStore.On("GetNamespace", "payments").Return(namespace, nil)
+		tags, err := BuildTemplatedTags(mockStore, ingress)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"team": "billing"}, tags)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("failed to get namespace", func(t *testing.T) {
+		mockStore := &store.MockStorer{}
+		mockStore.On("GetConfig").Return(&config.Configuration{TagTemplates: []string{"team={{ .Namespace.Labels.team }}"}})
+		mockStore.On("GetNamespace", "payments").Return(nil, errors.New("namespaces are not watched"))
+		_, err := BuildTemplatedTags(mockStore, ingress)
+		assert.EqualError(t, err, "failed to get namespace payments due to namespaces are not watched")
+		mockStore.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,79 @@
+package tg
+
+import (
+	"sync"
+
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TargetsRegistry records the targets of the targetGroups reconciled for ingresses,
+// so that the targets of an service can be reconciled alone when its endpoints change, without reconciling the whole ingress.
+// The methods of an nil TargetsRegistry are no-ops.
+type TargetsRegistry struct {
+	mutex            sync.RWMutex
+	targetsByIngress map[types.NamespacedName][]*Targets
+}
+
+// NewTargetsRegistry constructs an empty TargetsRegistry
+func NewTargetsRegistry() *TargetsRegistry {
+	return &TargetsRegistry{targetsByIngress: make(map[types.NamespacedName][]*Targets)}
+}
+
+// Record records the targetGroups reconciled for ingress by backend, replacing the ones previously recorded for it.
+func (r *TargetsRegistry) Record(ingress *extensions.Ingress, tgByBackend map[extensions.IngressBackend]TargetGroup) {
+	if r == nil {
+		return
+	}
+	var targets []*Targets
+	for backend, tg := range tgByBackend {
+		backend := backend
+		t := NewTargets(tg.TargetType, ingress, &backend)
+		t.TgArn = tg.Arn
+		targets = append(targets, t)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.targetsByIngress[types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}] = targets
+}
+
+// Forget removes the targetGroups recorded for ingress, e.g. when they're deleted.
+func (r *TargetsRegistry) Forget(ingressKey types.NamespacedName) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.targetsByIngress, ingressKey)
+}
+
+// IngressTargets returns the targets of the targetGroups recorded for ingress.
+func (r *TargetsRegistry) IngressTargets(ingressKey types.NamespacedName) []*Targets {
+	if r == nil {
+		return nil
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.targetsByIngress[ingressKey]
+}
+
+// ServiceTargets returns the targets of the targetGroups recorded for backends of service.
+func (r *TargetsRegistry) ServiceTargets(serviceKey types.NamespacedName) []*Targets {
+	if r == nil {
+		return nil
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var result []*Targets
+	for ingressKey, targets := range r.targetsByIngress {
+		if ingressKey.Namespace != serviceKey.Namespace {
+			continue
+		}
+		for _, t := range targets {
+			if t.Backend.ServiceName == serviceKey.Name {
+				result = append(result, t)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+package tg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestTargetsRegistry(t *testing.T) {
+	ingress := &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	backend1 := extensions.IngressBackend{ServiceName: "service1", ServicePort: intstr.FromInt(80)}
+	backend2 := extensions.IngressBackend{ServiceName: "service2", ServicePort: intstr.FromInt(80)}
+	registry := NewTargetsRegistry()
+	registry.Record(ingress, map[extensions.IngressBackend]TargetGroup{
+		backend1: {Arn: "tgArn1", TargetType: "ip"},
+		backend2: {Arn: "tgArn2", TargetType: "instance"},
+	})
+
+	targets := registry.ServiceTargets(types.NamespacedName{Namespace: "namespace", Name: "service1"})
+	assert.Equal(t, []*Targets{{TgArn: "tgArn1", TargetType: "ip", Ingress: ingress, Backend: &backend1}}, targets)
+	assert.Empty(t, registry.ServiceTargets(types.NamespacedName{Namespace: "other", Name: "service1"}))
+	assert.Len(t, registry.IngressTargets(types.NamespacedName{Namespace: "namespace", Name: "ingress"}), 2)
+
+	registry.Forget(types.NamespacedName{Namespace: "namespace", Name: "ingress"})
+	assert.Empty(t, registry.ServiceTargets(types.NamespacedName{Namespace: "namespace", Name: "service1"}))
+	assert.Empty(t, registry.IngressTargets(types.NamespacedName{Namespace: "namespace", Name: "ingress"}))
+
+	var nilRegistry *TargetsRegistry
+	nilRegistry.Record(ingress, map[extensions.IngressBackend]TargetGroup{backend1: {Arn: "tgArn1"}})
+	assert.Empty(t, nilRegistry.ServiceTargets(types.NamespacedName{Namespace: "namespace", Name: "service1"}))
+}
@@ -77,7 +77,10 @@ func (controller *defaultController) Reconcile(ctx context.Context, ingress *ext
 	}
 
 	tgArn := aws.StringValue(tgInstance.TargetGroupArn)
-	tgTags := controller.buildTags(ingress, backend)
+	tgTags, err := controller.buildTags(ingress, backend)
+	if err != nil {
+		return TargetGroup{}, err
+	}
 	if err := controller.tagsController.Reconcile(ctx, &tags.Tags{Arn: tgArn, Tags: tgTags}); err != nil {
 		return TargetGroup{}, fmt.Errorf("failed to reconcile targetGroup tags due to %v", err)
 	}
@@ -90,9 +93,10 @@ func (controller *defaultController) Reconcile(ctx context.Context, ingress *ext
 		return TargetGroup{}, fmt.Errorf("failed to reconcile targetGroup targets due to %v", err)
 	}
 	return TargetGroup{
-		Arn:        tgArn,
-		TargetType: targetType,
-		Targets:    tgTargets.Targets,
+		Arn:             tgArn,
+		TargetType:      targetType,
+		Targets:         tgTargets.Targets,
+		HealthCheckPort: aws.StringValue(serviceAnnos.HealthCheck.Port),
 	}, nil
 }
 
@@ -135,6 +139,10 @@ func (controller *defaultController) reconcileTGInstance(ctx context.Context, in
 		if err != nil {
 			return instance, err
 		}
+		if len(output.TargetGroups) == 0 {
+			// output is empty in dry-run
+			return instance, nil
+		}
 		return output.TargetGroups[0], err
 	}
 	return instance, nil
@@ -169,20 +177,27 @@ func (controller *defaultController) TGInstanceNeedsModification(ctx context.Con
 	return needsChange
 }
 
-func (controller *defaultController) buildTags(ingress *extensions.Ingress, backend extensions.IngressBackend) map[string]string {
+func (controller *defaultController) buildTags(ingress *extensions.Ingress, backend extensions.IngressBackend) (map[string]string, error) {
+	controllerTags, err := tags.BuildControllerTags(controller.store, ingress)
+	if err != nil {
+		return nil, err
+	}
 	tgTags := make(map[string]string)
+	for k, v := range controllerTags {
+		tgTags[k] = v
+	}
 	for k, v := range controller.nameTagGen.TagTGGroup(ingress.Namespace, ingress.Name) {
 		tgTags[k] = v
 	}
 	for k, v := range controller.nameTagGen.TagTG(backend.ServiceName, backend.ServicePort.String()) {
 		tgTags[k] = v
 	}
-	return tgTags
+	return tgTags, nil
 }
 
 func (controller *defaultController) loadServiceAnnotations(ingress *extensions.Ingress, serviceName string) (*annotations.Service, error) {
 	serviceKey := types.NamespacedName{Namespace: ingress.Namespace, Name: serviceName}
-	ingressAnnos, err := controller.store.GetIngressAnnotations(k8s.MetaNamespaceKey(ingress))
+	ingressAnnos, err := controller.store.GetIngressAnnotations(k8s.IngressAnnotationsKey(ingress))
 	if err != nil {
 		return nil, err
 	}
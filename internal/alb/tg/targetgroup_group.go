@@ -32,12 +32,14 @@ func NewGroupController(
 	store store.Storer,
 	nameTagGen NameTagGenerator,
 	tagsController tags.Controller,
-	endpointResolver backend.EndpointResolver) GroupController {
+	endpointResolver backend.EndpointResolver,
+	registry *TargetsRegistry) GroupController {
 	tgController := NewController(cloud, store, nameTagGen, tagsController, endpointResolver)
 	return &defaultGroupController{
 		cloud:        cloud,
 		nameTagGen:   nameTagGen,
 		tgController: tgController,
+		registry:     registry,
 	}
 }
 
@@ -48,6 +50,9 @@ type defaultGroupController struct {
 	nameTagGen NameTagGenerator
 
 	tgController Controller
+
+	// registry records the targetGroups reconciled, it's nil if they're not recorded
+	registry *TargetsRegistry
 }
 
 func (controller *defaultGroupController) Reconcile(ctx context.Context, ingress *extensions.Ingress) (TargetGroupGroup, error) {
@@ -64,6 +69,9 @@ func (controller *defaultGroupController) Reconcile(ctx context.Context, ingress
 			return TargetGroupGroup{}, err
 		}
 	}
+	if !aws.IsDryRun(ctx) {
+		controller.registry.Record(ingress, tgByBackend)
+	}
 	selector := controller.nameTagGen.TagTGGroup(ingress.Namespace, ingress.Name)
 	return TargetGroupGroup{
 		TGByBackend: tgByBackend,
@@ -81,7 +89,7 @@ func (controller *defaultGroupController) GC(ctx context.Context, tgGroup Target
 	for _, tg := range tgGroup.TGByBackend {
 		usedTgArns.Insert(tg.Arn)
 	}
-	arns, err := controller.cloud.GetResourcesByFilters(tagFilters, aws.ResourceTypeEnumELBTargetGroup)
+	arns, err := controller.cloud.GetResourcesByFilters(ctx, tagFilters, aws.ResourceTypeEnumELBTargetGroup)
 	if err != nil {
 		return fmt.Errorf("failed to get targetGroups due to %v", err)
 	}
@@ -100,6 +108,9 @@ func (controller *defaultGroupController) Delete(ctx context.Context, ingressKey
 	tgGroup := TargetGroupGroup{
 		selector: selector,
 	}
+	if !aws.IsDryRun(ctx) {
+		controller.registry.Forget(ingressKey)
+	}
 	return controller.GC(ctx, tgGroup)
 }
 
@@ -526,7 +526,7 @@ func TestDefaultGroupController_GC(t *testing.T) {
 		ctx := context.Background()
 		cloud := &mocks.CloudAPI{}
 		if tc.GetResourcesByFiltersCall != nil {
-			cloud.On("GetResourcesByFilters", tc.GetResourcesByFiltersCall.TagFilters, tc.GetResourcesByFiltersCall.ResourceType).Return(tc.GetResourcesByFiltersCall.Arns, tc.GetResourcesByFiltersCall.Err)
+			cloud.On("GetResourcesByFilters", ctx, tc.GetResourcesByFiltersCall.TagFilters, tc.GetResourcesByFiltersCall.ResourceType).Return(tc.GetResourcesByFiltersCall.Arns, tc.GetResourcesByFiltersCall.Err)
 		}
 		for _, call := range tc.DeleteTargetGroupByArnCalls {
 			cloud.On("DeleteTargetGroupByArn", ctx, call.Arn).Return(call.Err)
@@ -631,7 +631,7 @@ func TestDefaultGroupController_Delete(t *testing.T) {
 		ctx := context.Background()
 		cloud := &mocks.CloudAPI{}
 		if tc.GetResourcesByFiltersCall != nil {
-			cloud.On("GetResourcesByFilters", tc.GetResourcesByFiltersCall.TagFilters, tc.GetResourcesByFiltersCall.ResourceType).Return(tc.GetResourcesByFiltersCall.Arns, tc.GetResourcesByFiltersCall.Err)
+			cloud.On("GetResourcesByFilters", ctx, tc.GetResourcesByFiltersCall.TagFilters, tc.GetResourcesByFiltersCall.ResourceType).Return(tc.GetResourcesByFiltersCall.Arns, tc.GetResourcesByFiltersCall.Err)
 		}
 		for _, call := range tc.DeleteTargetGroupByArnCalls {
 			cloud.On("DeleteTargetGroupByArn", ctx, call.Arn).Return(call.Err)
@@ -258,6 +258,9 @@ func TestDefaultController_Reconcile(t *testing.T) {
 			Name:    "Reconcile succeeds by reconcile non-modified existing instance",
 			Ingress: ingress,
 			Backend: ingressBackend,
+			GetConfigCall: &GetConfigCall{
+				Config: &config.Configuration{},
+			},
 			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
 				Key:          "namespace/ingress",
 				IngressAnnos: &annotations.Ingress{},
@@ -364,6 +367,9 @@ func TestDefaultController_Reconcile(t *testing.T) {
 			Name:    "Reconcile succeeds by reconcile modified existing instance",
 			Ingress: ingress,
 			Backend: ingressBackend,
+			GetConfigCall: &GetConfigCall{
+				Config: &config.Configuration{},
+			},
 			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
 				Key:          "namespace/ingress",
 				IngressAnnos: &annotations.Ingress{},
@@ -692,6 +698,9 @@ func TestDefaultController_Reconcile(t *testing.T) {
 			Name:    "Reconcile failed when reconcile tags",
 			Ingress: ingress,
 			Backend: ingressBackend,
+			GetConfigCall: &GetConfigCall{
+				Config: &config.Configuration{},
+			},
 			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
 				Key:          "namespace/ingress",
 				IngressAnnos: &annotations.Ingress{},
@@ -767,6 +776,9 @@ func TestDefaultController_Reconcile(t *testing.T) {
 			Name:    "Reconcile failed when reconcile attributes",
 			Ingress: ingress,
 			Backend: ingressBackend,
+			GetConfigCall: &GetConfigCall{
+				Config: &config.Configuration{},
+			},
 			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
 				Key:          "namespace/ingress",
 				IngressAnnos: &annotations.Ingress{},
@@ -851,6 +863,9 @@ func TestDefaultController_Reconcile(t *testing.T) {
 			Name:    "Reconcile failed when reconcile targets",
 			Ingress: ingress,
 			Backend: ingressBackend,
+			GetConfigCall: &GetConfigCall{
+				Config: &config.Configuration{},
+			},
 			GetIngressAnnotationsCall: &GetIngressAnnotationsCall{
 				Key:          "namespace/ingress",
 				IngressAnnos: &annotations.Ingress{},
@@ -9,6 +9,7 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/lock"
 	api "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 )
@@ -59,7 +60,12 @@ type targetsController struct {
 	endpointResolver backend.EndpointResolver
 }
 
+// targetGroupTargetsLocks serializes reconciling the targets of an targetGroup, which are reconciled both with its ingress and alone when endpoints change.
+var targetGroupTargetsLocks = lock.NewKeyedMutex()
+
 func (c *targetsController) Reconcile(ctx context.Context, t *Targets) error {
+	unlock := targetGroupTargetsLocks.Lock(t.TgArn)
+	defer unlock()
 	desired, err := c.endpointResolver.Resolve(t.Ingress, t.Backend, t.TargetType)
 	if err != nil {
 		return err
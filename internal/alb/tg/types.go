@@ -10,6 +10,9 @@ type TargetGroup struct {
 	Arn        string
 	TargetType string
 	Targets    []*elbv2.TargetDescription
+
+	// HealthCheckPort is the port used for health checks, either an port number or "traffic-port"
+	HealthCheckPort string
 }
 
 // TargetGroupGroup represents an collection of targetGroups for a single ingress in AWS
@@ -33,6 +33,12 @@ func GetEventf(ctx context.Context) Eventf {
 	return missingEventf
 }
 
+// LookupEventf returns the event function of ctx, ok is false if ctx doesn't have one, e.g. it isn't for an ingress.
+func LookupEventf(ctx context.Context) (f Eventf, ok bool) {
+	f, ok = ctx.Value(contextKeyEventf).(Eventf)
+	return f, ok
+}
+
 func SetLogger(ctx context.Context, logger *log.Logger) context.Context {
 	return context.WithValue(ctx, contextKeyLogger, logger)
 }
@@ -44,3 +50,9 @@ func GetLogger(ctx context.Context) *log.Logger {
 	}
 	return logger
 }
+
+// LookupLogger returns the logger of ctx, ok is false if ctx doesn't have one, e.g. it isn't for an ingress.
+func LookupLogger(ctx context.Context) (logger *log.Logger, ok bool) {
+	logger, ok = ctx.Value(contextKeyLogger).(*log.Logger)
+	return logger, ok
+}
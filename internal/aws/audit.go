@@ -0,0 +1,306 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/private/protocol/json/jsonutil"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/golang/glog"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+)
+
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+
+	auditBufferSize    = 1000
+	auditBatchSize     = 100
+	auditFlushInterval = 5 * time.Second
+
+	// auditMaxBatchSpan is the maximum time span of the events in an PutLogEvents call
+	auditMaxBatchSpan = 24 * time.Hour
+	// auditPutRetries is the number of times an batch is retried with an refreshed sequence token
+	auditPutRetries = 2
+)
+
+// mutatingOperationPrefixes are prefixes of AWS operations that create, modify or delete resources.
+var mutatingOperationPrefixes = []string{
+	"Add", "Associate", "Attach", "Authorize", "Change", "Create", "Delete", "Deregister", "Detach",
+	"Disassociate", "Modify", "Put", "Register", "Remove", "Revoke", "Set", "Tag", "Untag", "Update",
+}
+
+// AuditRecord is the audit record of an AWS call mutating resources.
+type AuditRecord struct {
+	Time        time.Time       `json:"time"`
+	Actor       string          `json:"actor"`
+	Ingress     string          `json:"ingress,omitempty"`
+	ReconcileID string          `json:"reconcileID,omitempty"`
+	Service     string          `json:"service"`
+	Operation   string          `json:"operation"`
+	Input       json.RawMessage `json:"input,omitempty"`
+	RequestID   string          `json:"requestID,omitempty"`
+	Result      string          `json:"result"`
+	Error       string          `json:"error,omitempty"`
+}
+
+// Auditor records the AWS calls mutating resources.
+type Auditor interface {
+	// Audit records an AWS call, Actor of record is set by Auditor.
+	Audit(record AuditRecord)
+}
+
+// NewAuditor creates an Auditor appending records as JSON lines to file, and shipping them to logGroup of CloudWatch Logs
+// in an log stream named after actor with config. Either file or logGroup can be empty, nil is returned if both are empty.
+func NewAuditor(file string, logGroup string, actor string, config *aws.Config) (Auditor, error) {
+	if file == "" && logGroup == "" {
+		return nil, nil
+	}
+	auditor := &defaultAuditor{actor: actor}
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log file %s due to %v", file, err)
+		}
+		auditor.file = f
+	}
+	if logGroup != "" {
+		// a session without our handlers is used, so calls shipping records are not audited themselves.
+		awsSession, err := session.NewSession(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create AWS session for audit log due to %v", err)
+		}
+		auditor.cloudWatchLogs = newCloudWatchLogsShipper(cloudwatchlogs.New(awsSession), logGroup, actor)
+		go auditor.cloudWatchLogs.run()
+	}
+	return auditor, nil
+}
+
+type defaultAuditor struct {
+	actor string
+
+	fileMu sync.Mutex
+	file   *os.File
+
+	cloudWatchLogs *cloudWatchLogsShipper
+}
+
+func (a *defaultAuditor) Audit(record AuditRecord) {
+	record.Actor = a.actor
+	payload, err := json.Marshal(record)
+	if err != nil {
+		glog.Errorf("failed to encode audit record of %s/%s due to %v", record.Service, record.Operation, err)
+		return
+	}
+	if a.file != nil {
+		a.fileMu.Lock()
+		_, err := a.file.Write(append(payload, '\n'))
+		a.fileMu.Unlock()
+		if err != nil {
+			glog.Errorf("failed to write audit record of %s/%s due to %v", record.Service, record.Operation, err)
+		}
+	}
+	if a.cloudWatchLogs != nil {
+		a.cloudWatchLogs.ship(record.Time, string(payload))
+	}
+}
+
+// cloudWatchLogsShipper ships audit records to an log stream of CloudWatch Logs in batches.
+type cloudWatchLogsShipper struct {
+	client    cloudwatchlogsiface.CloudWatchLogsAPI
+	logGroup  string
+	logStream string
+	events    chan *cloudwatchlogs.InputLogEvent
+
+	sequenceToken *string
+	streamCreated bool
+}
+
+func newCloudWatchLogsShipper(client cloudwatchlogsiface.CloudWatchLogsAPI, logGroup string, logStream string) *cloudWatchLogsShipper {
+	return &cloudWatchLogsShipper{
+		client:    client,
+		logGroup:  logGroup,
+		logStream: logStream,
+		events:    make(chan *cloudwatchlogs.InputLogEvent, auditBufferSize),
+	}
+}
+
+// ship queues the record, records are dropped with an error logged if the queue is full.
+func (s *cloudWatchLogsShipper) ship(t time.Time, message string) {
+	event := &cloudwatchlogs.InputLogEvent{
+		Timestamp: aws.Int64(t.UnixNano() / int64(time.Millisecond)),
+		Message:   aws.String(message),
+	}
+	select {
+	case s.events <- event:
+	default:
+		glog.Errorf("dropped audit record for CloudWatch Logs since queue is full: %s", message)
+	}
+}
+
+func (s *cloudWatchLogsShipper) run() {
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+	var batch []*cloudwatchlogs.InputLogEvent
+	for {
+		select {
+		case event := <-s.events:
+			batch = append(batch, event)
+			if len(batch) < auditBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		if err := s.flush(batch); err != nil {
+			glog.Errorf("failed to ship %d audit records to CloudWatch Logs group %s due to %v", len(batch), s.logGroup, err)
+		}
+		batch = nil
+	}
+}
+
+// flush ships batch in chronological order, split into chunks spanning less than auditMaxBatchSpan as required by PutLogEvents.
+func (s *cloudWatchLogsShipper) flush(batch []*cloudwatchlogs.InputLogEvent) error {
+	sort.SliceStable(batch, func(i, j int) bool {
+		return aws.Int64Value(batch[i].Timestamp) < aws.Int64Value(batch[j].Timestamp)
+	})
+	for _, events := range splitLogEventsBySpan(batch, auditMaxBatchSpan) {
+		if err := s.putLogEvents(events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putLogEvents puts events to the log stream, it's retried with an refreshed sequence token if the token is invalid,
+// e.g. when another writer used the same log stream.
+func (s *cloudWatchLogsShipper) putLogEvents(events []*cloudwatchlogs.InputLogEvent) error {
+	for attempt := 0; ; attempt++ {
+		if !s.streamCreated {
+			if err := s.ensureLogStream(); err != nil {
+				return err
+			}
+			s.streamCreated = true
+		}
+		resp, err := s.client.PutLogEvents(&cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(s.logGroup),
+			LogStreamName: aws.String(s.logStream),
+			LogEvents:     events,
+			SequenceToken: s.sequenceToken,
+		})
+		if err == nil {
+			s.sequenceToken = resp.NextSequenceToken
+			return nil
+		}
+		// the sequence token is refreshed before next call.
+		s.streamCreated = false
+		awsErr, ok := err.(awserr.Error)
+		if ok && awsErr.Code() == cloudwatchlogs.ErrCodeDataAlreadyAcceptedException {
+			return nil
+		}
+		if !ok || awsErr.Code() != cloudwatchlogs.ErrCodeInvalidSequenceTokenException || attempt == auditPutRetries {
+			return err
+		}
+	}
+}
+
+// splitLogEventsBySpan splits events sorted by timestamp into chunks whose events span less than span.
+func splitLogEventsBySpan(events []*cloudwatchlogs.InputLogEvent, span time.Duration) [][]*cloudwatchlogs.InputLogEvent {
+	spanMillis := int64(span / time.Millisecond)
+	var chunks [][]*cloudwatchlogs.InputLogEvent
+	start := 0
+	for i := range events {
+		if aws.Int64Value(events[i].Timestamp)-aws.Int64Value(events[start].Timestamp) >= spanMillis {
+			chunks = append(chunks, events[start:i])
+			start = i
+		}
+	}
+	if start < len(events) {
+		chunks = append(chunks, events[start:])
+	}
+	return chunks
+}
+
+// ensureLogStream creates the log stream if it doesn't exist, and retrieves its sequence token otherwise.
+func (s *cloudWatchLogsShipper) ensureLogStream() error {
+	_, err := s.client.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(s.logGroup),
+		LogStreamName: aws.String(s.logStream),
+	})
+	if err == nil {
+		s.sequenceToken = nil
+		return nil
+	}
+	if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != cloudwatchlogs.ErrCodeResourceAlreadyExistsException {
+		return fmt.Errorf("failed to create log stream %s due to %v", s.logStream, err)
+	}
+	resp, err := s.client.DescribeLogStreams(&cloudwatchlogs.DescribeLogStreamsInput{
+		LogGroupName:        aws.String(s.logGroup),
+		LogStreamNamePrefix: aws.String(s.logStream),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe log stream %s due to %v", s.logStream, err)
+	}
+	for _, stream := range resp.LogStreams {
+		if aws.StringValue(stream.LogStreamName) == s.logStream {
+			s.sequenceToken = stream.UploadSequenceToken
+		}
+	}
+	return nil
+}
+
+// isMutatingOperation tests whether AWS operation creates, modifies or deletes resources.
+func isMutatingOperation(operation string) bool {
+	for _, prefix := range mutatingOperationPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildAuditRecord builds the audit record of completed request r, sensitive values in its input are redacted.
+func buildAuditRecord(r *request.Request) AuditRecord {
+	logger := albctx.GetLogger(r.Context())
+	record := AuditRecord{
+		Time:        r.Time,
+		Ingress:     requestIngress(r),
+		ReconcileID: logger.Field("reconcileID"),
+		Service:     r.ClientInfo.ServiceName,
+		Operation:   r.Operation.Name,
+		RequestID:   r.RequestID,
+		Result:      AuditResultSuccess,
+	}
+	if input, err := jsonutil.BuildJSON(log.Redact(r.Params)); err == nil {
+		record.Input = input
+	} else {
+		glog.Errorf("failed to encode input of %s/%s for audit record due to %v", record.Service, record.Operation, err)
+	}
+	if r.Error != nil {
+		record.Result = AuditResultFailure
+		record.Error = r.Error.Error()
+	}
+	return record
+}
+
+// requestIngress returns the namespace/name of ingress request r was made for, or empty if it wasn't made for an ingress.
+func requestIngress(r *request.Request) string {
+	logger, ok := albctx.LookupLogger(r.Context())
+	if !ok {
+		return ""
+	}
+	return logger.Name()
+}
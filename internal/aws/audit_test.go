@@ -0,0 +1,170 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isMutatingOperation(t *testing.T) {
+	assert.True(t, isMutatingOperation("CreateLoadBalancer"))
+	assert.True(t, isMutatingOperation("AuthorizeSecurityGroupIngress"))
+	assert.True(t, isMutatingOperation("AddTags"))
+	assert.True(t, isMutatingOperation("ChangeResourceRecordSets"))
+	assert.False(t, isMutatingOperation("DescribeLoadBalancers"))
+	assert.False(t, isMutatingOperation("GetResources"))
+}
+
+func Test_buildAuditRecord(t *testing.T) {
+	log.SetSensitiveTagKeys([]string{"token"})
+	defer log.SetSensitiveTagKeys(nil)
+
+	ctx := albctx.SetLogger(context.Background(), log.New("namespace/ingress").With("reconcileID", "1234"))
+	r := &request.Request{
+		Time:       time.Unix(0, 0),
+		ClientInfo: metadata.ClientInfo{ServiceName: elbv2.ServiceName},
+		Operation:  &request.Operation{Name: "AddTags"},
+		Params: &elbv2.AddTagsInput{
+			ResourceArns: aws.StringSlice([]string{"arn"}),
+			Tags:         []*elbv2.Tag{{Key: aws.String("token"), Value: aws.String("abcd")}},
+		},
+		RequestID: "request-id",
+		Error:     errors.New("AccessDenied"),
+	}
+	r.SetContext(ctx)
+
+	record := buildAuditRecord(r)
+	assert.Equal(t, AuditRecord{
+		Time:        time.Unix(0, 0),
+		Ingress:     "namespace/ingress",
+		ReconcileID: "1234",
+		Service:     elbv2.ServiceName,
+		Operation:   "AddTags",
+		Input:       json.RawMessage(`{"ResourceArns":["arn"],"Tags":[{"Key":"token","Value":"*** redacted ***"}]}`),
+		RequestID:   "request-id",
+		Result:      AuditResultFailure,
+		Error:       "AccessDenied",
+	}, record)
+}
+
+func Test_defaultAuditor_Audit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "audit.log")
+
+	auditor, err := NewAuditor(file, "", "controller-pod", &aws.Config{})
+	assert.NoError(t, err)
+	auditor.Audit(AuditRecord{Service: elbv2.ServiceName, Operation: "DeleteLoadBalancer", Result: AuditResultSuccess})
+	auditor.Audit(AuditRecord{Service: elbv2.ServiceName, Operation: "DeleteTargetGroup", Result: AuditResultSuccess})
+
+	content, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2)
+	var record AuditRecord
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, "controller-pod", record.Actor)
+	assert.Equal(t, "DeleteTargetGroup", record.Operation)
+
+	noAuditor, err := NewAuditor("", "", "controller-pod", &aws.Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, noAuditor)
+}
+
+// fakeCloudWatchLogs accepts PutLogEvents calls with the sequence token of the log stream only.
+type fakeCloudWatchLogs struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+
+	sequenceToken int
+	putEvents     [][]*cloudwatchlogs.InputLogEvent
+}
+
+func (c *fakeCloudWatchLogs) CreateLogStream(*cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	return nil, awserr.New(cloudwatchlogs.ErrCodeResourceAlreadyExistsException, "log stream exists", nil)
+}
+
+func (c *fakeCloudWatchLogs) DescribeLogStreams(input *cloudwatchlogs.DescribeLogStreamsInput) (*cloudwatchlogs.DescribeLogStreamsOutput, error) {
+	return &cloudwatchlogs.DescribeLogStreamsOutput{LogStreams: []*cloudwatchlogs.LogStream{
+		{LogStreamName: input.LogStreamNamePrefix, UploadSequenceToken: aws.String(strconv.Itoa(c.sequenceToken))},
+	}}, nil
+}
+
+func (c *fakeCloudWatchLogs) PutLogEvents(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	if aws.StringValue(input.SequenceToken) != strconv.Itoa(c.sequenceToken) {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeInvalidSequenceTokenException, "sequence token is invalid", nil)
+	}
+	c.sequenceToken++
+	c.putEvents = append(c.putEvents, input.LogEvents)
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: aws.String(strconv.Itoa(c.sequenceToken))}, nil
+}
+
+func Test_cloudWatchLogsShipper_flush(t *testing.T) {
+	event := func(timestamp time.Time) *cloudwatchlogs.InputLogEvent {
+		return &cloudwatchlogs.InputLogEvent{
+			Timestamp: aws.Int64(timestamp.UnixNano() / int64(time.Millisecond)),
+			Message:   aws.String(timestamp.Format(time.RFC3339)),
+		}
+	}
+	now := time.Now()
+	client := &fakeCloudWatchLogs{}
+	shipper := newCloudWatchLogsShipper(client, "audit", "controller-pod")
+
+	assert.NoError(t, shipper.flush([]*cloudwatchlogs.InputLogEvent{event(now.Add(time.Second)), event(now)}))
+	assert.Equal(t, [][]*cloudwatchlogs.InputLogEvent{{event(now), event(now.Add(time.Second))}}, client.putEvents)
+
+	// another writer used the log stream, so the sequence token is refreshed
+	client.sequenceToken++
+	client.putEvents = nil
+	assert.NoError(t, shipper.flush([]*cloudwatchlogs.InputLogEvent{event(now), event(now.Add(-auditMaxBatchSpan))}))
+	assert.Equal(t, [][]*cloudwatchlogs.InputLogEvent{{event(now.Add(-auditMaxBatchSpan))}, {event(now)}}, client.putEvents)
+}
+
+func Test_splitLogEventsBySpan(t *testing.T) {
+	event := func(timestamp int64) *cloudwatchlogs.InputLogEvent {
+		return &cloudwatchlogs.InputLogEvent{Timestamp: aws.Int64(timestamp)}
+	}
+	for _, tc := range []struct {
+		Name     string
+		Events   []*cloudwatchlogs.InputLogEvent
+		Expected [][]*cloudwatchlogs.InputLogEvent
+	}{
+		{
+			Name:     "no events",
+			Events:   nil,
+			Expected: nil,
+		},
+		{
+			Name:     "events within span",
+			Events:   []*cloudwatchlogs.InputLogEvent{event(0), event(999)},
+			Expected: [][]*cloudwatchlogs.InputLogEvent{{event(0), event(999)}},
+		},
+		{
+			Name:     "events spanning span or more are split",
+			Events:   []*cloudwatchlogs.InputLogEvent{event(0), event(500), event(1000), event(1999), event(2000)},
+			Expected: [][]*cloudwatchlogs.InputLogEvent{{event(0), event(500)}, {event(1000), event(1999)}, {event(2000)}},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, splitLogEventsBySpan(tc.Events, time.Second))
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents/cloudwatcheventsiface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/golang/glog"
+)
+
+const (
+	// ChangeEventSource and ChangeEventDetailType identify change events on the EventBridge event bus.
+	ChangeEventSource     = "alb-ingress-controller"
+	ChangeEventDetailType = "AWS Resource Change"
+
+	changeEventBufferSize    = 1000
+	changeEventFlushInterval = time.Second
+	// maxChangeEventBatchSize is the maximum number of entries in both SendMessageBatch and PutEvents calls.
+	maxChangeEventBatchSize = 10
+)
+
+// ChangeEvent describes an AWS resource created, modified or deleted by controller.
+type ChangeEvent struct {
+	Time        time.Time `json:"time"`
+	ClusterName string    `json:"clusterName"`
+	Ingress     string    `json:"ingress,omitempty"`
+	Action      string    `json:"action"`
+	Service     string    `json:"service"`
+	Operation   string    `json:"operation"`
+	Resources   []string  `json:"resources"`
+	RequestID   string    `json:"requestID,omitempty"`
+}
+
+// ChangeEventPublisher publishes change events to downstream automation.
+type ChangeEventPublisher interface {
+	// Publish publishes event asynchronously, ClusterName of event is set by ChangeEventPublisher.
+	Publish(event ChangeEvent)
+}
+
+// NewChangeEventPublisher creates an ChangeEventPublisher sending events to SQS queue with queueURL if it's not empty,
+// and putting events to the default EventBridge event bus if eventBridge is set, with config. nil is returned if neither is enabled.
+func NewChangeEventPublisher(queueURL string, eventBridge bool, clusterName string, config *aws.Config) (ChangeEventPublisher, error) {
+	if queueURL == "" && !eventBridge {
+		return nil, nil
+	}
+	// a session without our handlers is used, so calls publishing events are not audited themselves.
+	awsSession, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session for change events due to %v", err)
+	}
+	publisher := &changeEventPublisher{
+		clusterName: clusterName,
+		queueURL:    queueURL,
+		events:      make(chan ChangeEvent, changeEventBufferSize),
+	}
+	if queueURL != "" {
+		publisher.sqs = sqs.New(awsSession)
+	}
+	if eventBridge {
+		publisher.eventBridge = cloudwatchevents.New(awsSession)
+	}
+	go publisher.run()
+	return publisher, nil
+}
+
+type changeEventPublisher struct {
+	clusterName string
+	queueURL    string
+	sqs         sqsiface.SQSAPI
+	eventBridge cloudwatcheventsiface.CloudWatchEventsAPI
+	events      chan ChangeEvent
+}
+
+// Publish queues event, events are dropped with an error logged if the queue is full.
+func (p *changeEventPublisher) Publish(event ChangeEvent) {
+	event.ClusterName = p.clusterName
+	select {
+	case p.events <- event:
+	default:
+		glog.Errorf("dropped change event of %s/%s for %v since queue is full", event.Service, event.Operation, event.Resources)
+	}
+}
+
+func (p *changeEventPublisher) run() {
+	ticker := time.NewTicker(changeEventFlushInterval)
+	defer ticker.Stop()
+	var batch []ChangeEvent
+	for {
+		select {
+		case event := <-p.events:
+			batch = append(batch, event)
+			if len(batch) < maxChangeEventBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		p.flush(batch)
+		batch = nil
+	}
+}
+
+func (p *changeEventPublisher) flush(batch []ChangeEvent) {
+	var sqsEntries []*sqs.SendMessageBatchRequestEntry
+	var eventBridgeEntries []*cloudwatchevents.PutEventsRequestEntry
+	for i, event := range batch {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			glog.Errorf("failed to encode change event of %s/%s due to %v", event.Service, event.Operation, err)
+			continue
+		}
+		sqsEntries = append(sqsEntries, &sqs.SendMessageBatchRequestEntry{
+			Id:          aws.String(fmt.Sprintf("%d", i)),
+			MessageBody: aws.String(string(payload)),
+		})
+		eventBridgeEntries = append(eventBridgeEntries, &cloudwatchevents.PutEventsRequestEntry{
+			Time:       aws.Time(event.Time),
+			Source:     aws.String(ChangeEventSource),
+			DetailType: aws.String(ChangeEventDetailType),
+			Detail:     aws.String(string(payload)),
+			Resources:  aws.StringSlice(event.Resources),
+		})
+	}
+	if p.sqs != nil && len(sqsEntries) != 0 {
+		resp, err := p.sqs.SendMessageBatch(&sqs.SendMessageBatchInput{
+			QueueUrl: aws.String(p.queueURL),
+			Entries:  sqsEntries,
+		})
+		if err != nil {
+			glog.Errorf("failed to send %d change events to SQS queue %s due to %v", len(sqsEntries), p.queueURL, err)
+		} else if len(resp.Failed) != 0 {
+			glog.Errorf("failed to send %d change events to SQS queue %s: %v", len(resp.Failed), p.queueURL, resp.Failed)
+		}
+	}
+	if p.eventBridge != nil && len(eventBridgeEntries) != 0 {
+		resp, err := p.eventBridge.PutEvents(&cloudwatchevents.PutEventsInput{Entries: eventBridgeEntries})
+		if err != nil {
+			glog.Errorf("failed to put %d change events to EventBridge due to %v", len(eventBridgeEntries), err)
+		} else if aws.Int64Value(resp.FailedEntryCount) != 0 {
+			glog.Errorf("failed to put %d change events to EventBridge", aws.Int64Value(resp.FailedEntryCount))
+		}
+	}
+}
+
+// buildChangeEvent builds the change event of completed request r.
+func buildChangeEvent(r *request.Request) ChangeEvent {
+	return ChangeEvent{
+		Time:      r.Time,
+		Ingress:   requestIngress(r),
+		Action:    operationEventReason(r.Operation.Name),
+		Service:   r.ClientInfo.ServiceName,
+		Operation: r.Operation.Name,
+		Resources: findRequestResourceIDs(r),
+		RequestID: r.RequestID,
+	}
+}
+
+// findRequestResourceIDs returns the ARNs and IDs of resources in the input of r, or the output of r if the input doesn't have any, e.g. for creations.
+func findRequestResourceIDs(r *request.Request) []string {
+	resources := findResourceIDs(reflect.ValueOf(r.Params), 0)
+	if len(resources) == 0 && r.Error == nil {
+		resources = findResourceIDs(reflect.ValueOf(r.Data), 0)
+	}
+	return uniqueStrings(resources)
+}
@@ -0,0 +1,99 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents/cloudwatcheventsiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSQS struct {
+	sqsiface.SQSAPI
+	inputs []*sqs.SendMessageBatchInput
+}
+
+func (f *fakeSQS) SendMessageBatch(input *sqs.SendMessageBatchInput) (*sqs.SendMessageBatchOutput, error) {
+	f.inputs = append(f.inputs, input)
+	return &sqs.SendMessageBatchOutput{}, nil
+}
+
+type fakeEventBridge struct {
+	cloudwatcheventsiface.CloudWatchEventsAPI
+	inputs []*cloudwatchevents.PutEventsInput
+}
+
+func (f *fakeEventBridge) PutEvents(input *cloudwatchevents.PutEventsInput) (*cloudwatchevents.PutEventsOutput, error) {
+	f.inputs = append(f.inputs, input)
+	return &cloudwatchevents.PutEventsOutput{FailedEntryCount: aws.Int64(0)}, nil
+}
+
+func Test_buildChangeEvent(t *testing.T) {
+	r := &request.Request{
+		Time:       time.Unix(0, 0),
+		ClientInfo: metadata.ClientInfo{ServiceName: elbv2.ServiceName},
+		Operation:  &request.Operation{Name: "CreateLoadBalancer"},
+		Params:     &elbv2.CreateLoadBalancerInput{Name: aws.String("lb")},
+		Data: &elbv2.CreateLoadBalancerOutput{
+			LoadBalancers: []*elbv2.LoadBalancer{{LoadBalancerArn: aws.String("lb-arn")}},
+		},
+		RequestID: "request-id",
+	}
+	r.SetContext(albctx.SetLogger(context.Background(), log.New("namespace/ingress")))
+
+	assert.Equal(t, ChangeEvent{
+		Time:      time.Unix(0, 0),
+		Ingress:   "namespace/ingress",
+		Action:    "CREATE",
+		Service:   elbv2.ServiceName,
+		Operation: "CreateLoadBalancer",
+		Resources: []string{"lb-arn"},
+		RequestID: "request-id",
+	}, buildChangeEvent(r))
+}
+
+func Test_changeEventPublisher_flush(t *testing.T) {
+	sqsClient := &fakeSQS{}
+	eventBridgeClient := &fakeEventBridge{}
+	publisher := &changeEventPublisher{
+		clusterName: "cluster",
+		queueURL:    "queue-url",
+		sqs:         sqsClient,
+		eventBridge: eventBridgeClient,
+	}
+	event := ChangeEvent{
+		Time:        time.Unix(0, 0),
+		ClusterName: "cluster",
+		Action:      "DELETE",
+		Service:     elbv2.ServiceName,
+		Operation:   "DeleteTargetGroup",
+		Resources:   []string{"tg-arn"},
+	}
+	publisher.flush([]ChangeEvent{event, event})
+
+	assert.Len(t, sqsClient.inputs, 1)
+	assert.Equal(t, "queue-url", aws.StringValue(sqsClient.inputs[0].QueueUrl))
+	assert.Len(t, sqsClient.inputs[0].Entries, 2)
+	var sent ChangeEvent
+	assert.NoError(t, json.Unmarshal([]byte(aws.StringValue(sqsClient.inputs[0].Entries[1].MessageBody)), &sent))
+	assert.Equal(t, "DeleteTargetGroup", sent.Operation)
+	assert.Equal(t, "1", aws.StringValue(sqsClient.inputs[0].Entries[1].Id))
+
+	assert.Len(t, eventBridgeClient.inputs, 1)
+	assert.Len(t, eventBridgeClient.inputs[0].Entries, 2)
+	entry := eventBridgeClient.inputs[0].Entries[0]
+	assert.Equal(t, ChangeEventSource, aws.StringValue(entry.Source))
+	assert.Equal(t, ChangeEventDetailType, aws.StringValue(entry.DetailType))
+	assert.Equal(t, []string{"tg-arn"}, aws.StringValueSlice(entry.Resources))
+}
@@ -1,10 +1,16 @@
 package aws
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/acm"
 	"github.com/aws/aws-sdk-go/service/acm/acmiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/elbv2"
@@ -13,6 +19,14 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi/resourcegroupstaggingapiiface"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/wafregional"
 	"github.com/aws/aws-sdk-go/service/wafregional/wafregionaliface"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
@@ -21,39 +35,69 @@ import (
 
 type CloudAPI interface {
 	ACMAPI
+	CloudWatchAPI
 	EC2API
 	EC2MetadataAPI
 	ELBV2API
 	IAMAPI
 	ResourceGroupsTaggingAPIAPI
+	Route53API
+	S3API
+	SecretsManagerAPI
+	SNSAPI
 	WAFRegionalAPI
 }
 
 type Cloud struct {
-	acm         acmiface.ACMAPI
-	ec2         ec2iface.EC2API
-	ec2metadata *ec2metadata.EC2Metadata
-	elbv2       elbv2iface.ELBV2API
-	iam         iamiface.IAMAPI
-	rgt         resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
-	wafregional wafregionaliface.WAFRegionalAPI
-	clusterName string
+	acm            acmiface.ACMAPI
+	cloudwatch     cloudwatchiface.CloudWatchAPI
+	ec2            ec2iface.EC2API
+	ec2metadata    *ec2metadata.EC2Metadata
+	elbv2          elbv2iface.ELBV2API
+	iam            iamiface.IAMAPI
+	rgt            resourcegroupstaggingapiiface.ResourceGroupsTaggingAPIAPI
+	route53        route53iface.Route53API
+	s3             s3iface.S3API
+	secretsmanager secretsmanageriface.SecretsManagerAPI
+	sns            snsiface.SNSAPI
+	wafregional    wafregionaliface.WAFRegionalAPI
+
+	// clusterTagKey is the key of the tag identifying subnets used by the cluster
+	clusterTagKey string
+
+	// vpcID is the VPC of the cluster, it's discovered from instance metadata if empty
+	vpcID string
+
+	// secrets caches the values of Secrets Manager secrets until they're rotated
+	secrets *secretsCache
+
+	// buckets caches the existence of S3 buckets
+	buckets *bucketsCache
 }
 
 // Initialize the global AWS clients.
 // TODO, pass these aws clients instances to controller instead of global clients.
 // But due to huge number of aws clients, it's best to have one container AWS client that embed these aws clients.
-func New(AWSAPIMaxRetries int, AWSAPIDebug bool, clusterName string, mc metric.Collector, cc *cache.Config) CloudAPI {
-	awsSession := NewSession(&aws.Config{MaxRetries: aws.Int(AWSAPIMaxRetries)}, AWSAPIDebug, mc, cc)
+func New(AWSAPIMaxRetries int, AWSAPIDebug bool, clusterTagKey string, vpcID string, mc metric.Collector, cc *cache.Config, auditor Auditor, changeEventPublisher ChangeEventPublisher, mutationLimiter *MutationLimiter, describeCacheTTL time.Duration, config *aws.Config) CloudAPI {
+	awsConfig := request.WithRetryer(config.Copy().WithMaxRetries(AWSAPIMaxRetries), adaptiveRetryer{client.DefaultRetryer{NumMaxRetries: AWSAPIMaxRetries}})
+	awsSession := NewSession(awsConfig, AWSAPIDebug, mc, cc, auditor, changeEventPublisher, mutationLimiter, describeCacheTTL)
 
 	return &Cloud{
 		acm.New(awsSession),
+		cloudwatch.New(awsSession),
 		ec2.New(awsSession),
-		ec2metadata.New(awsSession),
+		newEC2Metadata(ec2metadata.New(awsSession)),
 		elbv2.New(awsSession),
 		iam.New(awsSession),
 		resourcegroupstaggingapi.New(awsSession),
+		route53.New(awsSession),
+		s3.New(awsSession),
+		secretsmanager.New(awsSession),
+		sns.New(awsSession),
 		wafregional.New(awsSession),
-		clusterName,
+		clusterTagKey,
+		vpcID,
+		newSecretsCache(),
+		newBucketsCache(),
 	}
 }
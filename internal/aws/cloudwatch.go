@@ -0,0 +1,262 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// CloudWatch metrics of application LoadBalancers
+const (
+	MetricConsumedLCUs        = "ConsumedLCUs"
+	MetricRequestCount        = "RequestCount"
+	MetricHTTPCodeELB5XXCount = "HTTPCode_ELB_5XX_Count"
+	MetricHTTPCodeTarget5XX   = "HTTPCode_Target_5XX_Count"
+	MetricTargetResponseTime  = "TargetResponseTime"
+	MetricUnHealthyHostCount  = "UnHealthyHostCount"
+
+	// NamespaceApplicationELB is the CloudWatch namespace of metrics of application LoadBalancers
+	NamespaceApplicationELB = "AWS/ApplicationELB"
+
+	// maxMetricDataQueries is the maximum number of queries in a single GetMetricData call
+	maxMetricDataQueries = 100
+	// maxMetricDatums is the maximum number of metrics in a single PutMetricData call
+	maxMetricDatums = 20
+	// maxDeleteAlarmNames is the maximum number of alarms deleted in a single DeleteAlarms call
+	maxDeleteAlarmNames = 100
+)
+
+// LoadBalancerMetrics are the CloudWatch metrics collected for each LoadBalancer
+var LoadBalancerMetrics = []string{MetricConsumedLCUs, MetricRequestCount, MetricHTTPCodeELB5XXCount, MetricHTTPCodeTarget5XX}
+
+// CloudWatchAPI is our wrapper CloudWatch API interface
+type CloudWatchAPI interface {
+	// GetLoadBalancerMetrics gets the sum of LoadBalancerMetrics over the latest period for each LoadBalancer in lbArns,
+	// keyed by LoadBalancer ARN and metric name. Metrics without datapoints in the period are omitted.
+	GetLoadBalancerMetrics(ctx context.Context, lbArns []string, period time.Duration) (map[string]map[string]float64, error)
+
+	// PutCountMetrics publishes the count of each metric in values to namespace, with dimensions.
+	PutCountMetrics(ctx context.Context, namespace string, dimensions map[string]string, values map[string]float64) error
+
+	// GetMetricAlarmsByPrefix gets the metric alarms whose names begin with prefix.
+	GetMetricAlarmsByPrefix(ctx context.Context, prefix string) ([]*cloudwatch.MetricAlarm, error)
+
+	// PutMetricAlarm creates or updates the metric alarm of input, tagged with tags when it's created.
+	PutMetricAlarm(ctx context.Context, input *cloudwatch.PutMetricAlarmInput, tags map[string]string) error
+
+	// DeleteMetricAlarms deletes the alarms named names.
+	DeleteMetricAlarms(ctx context.Context, names []string) error
+}
+
+func (c *Cloud) GetLoadBalancerMetrics(ctx context.Context, lbArns []string, period time.Duration) (map[string]map[string]float64, error) {
+	type queryTarget struct {
+		lbArn  string
+		metric string
+	}
+	targets := make(map[string]queryTarget)
+	var queries []*cloudwatch.MetricDataQuery
+	for i, lbArn := range lbArns {
+		for j, metric := range LoadBalancerMetrics {
+			id := fmt.Sprintf("m%d_%d", i, j)
+			targets[id] = queryTarget{lbArn: lbArn, metric: metric}
+			queries = append(queries, &cloudwatch.MetricDataQuery{
+				Id: aws.String(id),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String(NamespaceApplicationELB),
+						MetricName: aws.String(metric),
+						Dimensions: []*cloudwatch.Dimension{
+							{
+								Name:  aws.String("LoadBalancer"),
+								Value: aws.String(LoadBalancerDimension(lbArn)),
+							},
+						},
+					},
+					Period: aws.Int64(int64(period / time.Second)),
+					Stat:   aws.String(cloudwatch.StatisticSum),
+				},
+			})
+		}
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-2 * period)
+	result := make(map[string]map[string]float64)
+	for len(queries) > 0 {
+		chunkSize := len(queries)
+		if chunkSize > maxMetricDataQueries {
+			chunkSize = maxMetricDataQueries
+		}
+		input := &cloudwatch.GetMetricDataInput{
+			MetricDataQueries: queries[:chunkSize],
+			StartTime:         aws.Time(startTime),
+			EndTime:           aws.Time(endTime),
+			ScanBy:            aws.String(cloudwatch.ScanByTimestampDescending),
+		}
+		for {
+			resp, err := c.cloudwatch.GetMetricDataWithContext(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+			for _, metricData := range resp.MetricDataResults {
+				target, ok := targets[aws.StringValue(metricData.Id)]
+				if !ok || len(metricData.Values) == 0 {
+					continue
+				}
+				if _, ok := result[target.lbArn]; !ok {
+					result[target.lbArn] = make(map[string]float64)
+				}
+				if _, ok := result[target.lbArn][target.metric]; !ok {
+					result[target.lbArn][target.metric] = aws.Float64Value(metricData.Values[0])
+				}
+			}
+			if resp.NextToken == nil {
+				break
+			}
+			input.NextToken = resp.NextToken
+		}
+		queries = queries[chunkSize:]
+	}
+	return result, nil
+}
+
+func (c *Cloud) PutCountMetrics(ctx context.Context, namespace string, dimensions map[string]string, values map[string]float64) error {
+	var cwDimensions []*cloudwatch.Dimension
+	for name, value := range dimensions {
+		cwDimensions = append(cwDimensions, &cloudwatch.Dimension{Name: aws.String(name), Value: aws.String(value)})
+	}
+	sort.Slice(cwDimensions, func(i, j int) bool {
+		return aws.StringValue(cwDimensions[i].Name) < aws.StringValue(cwDimensions[j].Name)
+	})
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	for len(names) > 0 {
+		chunkSize := len(names)
+		if chunkSize > maxMetricDatums {
+			chunkSize = maxMetricDatums
+		}
+		var data []*cloudwatch.MetricDatum
+		for _, name := range names[:chunkSize] {
+			data = append(data, &cloudwatch.MetricDatum{
+				MetricName: aws.String(name),
+				Dimensions: cwDimensions,
+				Timestamp:  aws.Time(now),
+				Unit:       aws.String(cloudwatch.StandardUnitCount),
+				Value:      aws.Float64(values[name]),
+			})
+		}
+		if _, err := c.cloudwatch.PutMetricDataWithContext(ctx, &cloudwatch.PutMetricDataInput{
+			Namespace:  aws.String(namespace),
+			MetricData: data,
+		}); err != nil {
+			return err
+		}
+		names = names[chunkSize:]
+	}
+	return nil
+}
+
+func (c *Cloud) GetMetricAlarmsByPrefix(ctx context.Context, prefix string) ([]*cloudwatch.MetricAlarm, error) {
+	var alarms []*cloudwatch.MetricAlarm
+	err := c.cloudwatch.DescribeAlarmsPagesWithContext(ctx, &cloudwatch.DescribeAlarmsInput{
+		AlarmNamePrefix: aws.String(prefix),
+	}, func(output *cloudwatch.DescribeAlarmsOutput, _ bool) bool {
+		alarms = append(alarms, output.MetricAlarms...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return alarms, nil
+}
+
+func (c *Cloud) PutMetricAlarm(ctx context.Context, input *cloudwatch.PutMetricAlarmInput, tags map[string]string) error {
+	req, _ := c.cloudwatch.PutMetricAlarmRequest(input)
+	req.SetContext(ctx)
+	req.Handlers.Build.PushBack(func(r *request.Request) {
+		addQueryTags(r, tags)
+	})
+	return req.Send()
+}
+
+func (c *Cloud) DeleteMetricAlarms(ctx context.Context, names []string) error {
+	for start := 0; start < len(names); start += maxDeleteAlarmNames {
+		end := start + maxDeleteAlarmNames
+		if end > len(names) {
+			end = len(names)
+		}
+		if _, err := c.cloudwatch.DeleteAlarmsWithContext(ctx, &cloudwatch.DeleteAlarmsInput{
+			AlarmNames: aws.StringSlice(names[start:end]),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addQueryTags adds tags to the query built for request r as its Tags parameter, which the vendored SDK predates for some operations,
+// e.g. AssumeRole and PutMetricAlarm.
+func addQueryTags(r *request.Request, tags map[string]string) {
+	if r.Error != nil || len(tags) == 0 {
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		r.Error = awserr.New(request.ErrCodeSerialization, fmt.Sprintf("failed to read %v query", r.Operation.Name), err)
+		return
+	}
+	query, err := url.ParseQuery(string(body))
+	if err != nil {
+		r.Error = awserr.New(request.ErrCodeSerialization, fmt.Sprintf("failed to parse %v query", r.Operation.Name), err)
+		return
+	}
+	r.SetBufferBody([]byte(encodeQueryTags(query, tags).Encode()))
+}
+
+// encodeQueryTags adds tags to query as its Tags parameter, ordered by key.
+func encodeQueryTags(query url.Values, tags map[string]string) url.Values {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		query.Set(fmt.Sprintf("Tags.member.%d.Key", i+1), key)
+		query.Set(fmt.Sprintf("Tags.member.%d.Value", i+1), tags[key])
+	}
+	return query
+}
+
+// TargetGroupDimension returns the value of TargetGroup dimension for targetGroup with tgArn,
+// e.g. targetgroup/my-targets/73e2d6bc24d8a067 for arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/my-targets/73e2d6bc24d8a067
+func TargetGroupDimension(tgArn string) string {
+	parts := strings.SplitN(tgArn, ":targetgroup/", 2)
+	if len(parts) != 2 {
+		return tgArn
+	}
+	return "targetgroup/" + parts[1]
+}
+
+// LoadBalancerDimension returns the value of LoadBalancer dimension for LoadBalancer with lbArn,
+// e.g. app/my-load-balancer/50dc6c495c0c9188 for arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/my-load-balancer/50dc6c495c0c9188
+func LoadBalancerDimension(lbArn string) string {
+	parts := strings.SplitN(lbArn, ":loadbalancer/", 2)
+	if len(parts) != 2 {
+		return lbArn
+	}
+	return parts[1]
+}
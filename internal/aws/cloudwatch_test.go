@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"io/ioutil"
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddQueryTags(t *testing.T) {
+	sess, err := session.NewSession(&aws.Config{Region: aws.String("us-west-2")})
+	assert.NoError(t, err)
+	req, _ := cloudwatch.New(sess).PutMetricAlarmRequest(&cloudwatch.PutMetricAlarmInput{
+		AlarmName:  aws.String("k8s-ns-ingress-1a2b-target-5xx"),
+		MetricName: aws.String(MetricHTTPCodeTarget5XX),
+	})
+	req.Handlers.Build.PushBack(func(r *request.Request) {
+		addQueryTags(r, map[string]string{"kubernetes.io/namespace": "ns", "kubernetes.io/ingress-name": "ingress"})
+	})
+	assert.NoError(t, req.Build())
+
+	body, err := ioutil.ReadAll(req.Body)
+	assert.NoError(t, err)
+	query, err := url.ParseQuery(string(body))
+	assert.NoError(t, err)
+	assert.Equal(t, "PutMetricAlarm", query.Get("Action"))
+	assert.Equal(t, "k8s-ns-ingress-1a2b-target-5xx", query.Get("AlarmName"))
+	assert.Equal(t, "kubernetes.io/ingress-name", query.Get("Tags.member.1.Key"))
+	assert.Equal(t, "ingress", query.Get("Tags.member.1.Value"))
+	assert.Equal(t, "kubernetes.io/namespace", query.Get("Tags.member.2.Key"))
+	assert.Equal(t, "ns", query.Get("Tags.member.2.Value"))
+}
+
+func TestDimensions(t *testing.T) {
+	assert.Equal(t, "app/web/50dc6c495c0c9188",
+		LoadBalancerDimension("arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/web/50dc6c495c0c9188"))
+	assert.Equal(t, "targetgroup/web/73e2d6bc24d8a067",
+		TargetGroupDimension("arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/web/73e2d6bc24d8a067"))
+}
@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+)
+
+// clusterNameTagKeys are the tags naming the EKS cluster of instances, in order of precedence:
+// the tag of EKS managed node groups and the one of eksctl node groups.
+var clusterNameTagKeys = []string{"eks:cluster-name", "alpha.eksctl.io/cluster-name"}
+
+// DiscoverClusterName returns the name of the EKS cluster of the instance the controller is running on, from the tags of the instance.
+func DiscoverClusterName(config *aws.Config) (string, error) {
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to create AWS session for cluster name discovery due to %v", err)
+	}
+	return discoverClusterName(newEC2Metadata(ec2metadata.New(sess)), ec2.New(sess))
+}
+
+func discoverClusterName(metadata EC2MetadataAPI, ec2svc ec2iface.EC2API) (string, error) {
+	identityDoc, err := metadata.GetInstanceIdentityDocument()
+	if err != nil {
+		return "", fmt.Errorf("failed to discover cluster name from instance metadata due to %v, it can be specified by --cluster-name", err)
+	}
+	o, err := ec2svc.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(identityDoc.InstanceID)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe instance %v for cluster name discovery due to %v", identityDoc.InstanceID, err)
+	}
+	if len(o.Reservations) == 0 || len(o.Reservations[0].Instances) == 0 {
+		return "", fmt.Errorf("failed to describe instance %v for cluster name discovery, it wasn't found", identityDoc.InstanceID)
+	}
+	return clusterNameFromTags(identityDoc.InstanceID, o.Reservations[0].Instances[0].Tags)
+}
+
+// clusterNameFromTags returns the cluster name of the tags of instance. Without EKS or eksctl tags, it's the name of its kubernetes.io/cluster/<name> tag,
+// an error is returned if it has several so resources of another cluster aren't adopted.
+func clusterNameFromTags(instanceID string, tags []*ec2.Tag) (string, error) {
+	values := make(map[string]string, len(tags))
+	var clusterTagNames []string
+	for _, tag := range tags {
+		key := aws.StringValue(tag.Key)
+		values[key] = aws.StringValue(tag.Value)
+		if strings.HasPrefix(key, TagNameCluster+"/") {
+			clusterTagNames = append(clusterTagNames, strings.TrimPrefix(key, TagNameCluster+"/"))
+		}
+	}
+	for _, key := range clusterNameTagKeys {
+		if name := values[key]; name != "" {
+			return name, nil
+		}
+	}
+	switch len(clusterTagNames) {
+	case 0:
+		return "", fmt.Errorf("instance %v has no tag naming its cluster, it can be specified by --cluster-name", instanceID)
+	case 1:
+		return clusterTagNames[0], nil
+	default:
+		sort.Strings(clusterTagNames)
+		return "", fmt.Errorf("instance %v is tagged for clusters %v, the cluster must be specified by --cluster-name", instanceID, strings.Join(clusterTagNames, ", "))
+	}
+}
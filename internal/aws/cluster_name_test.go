@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterNameFromTags(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		tags     map[string]string
+		expected string
+		err      bool
+	}{
+		{name: "managed node group", tags: map[string]string{"eks:cluster-name": "eks", "alpha.eksctl.io/cluster-name": "eksctl", "kubernetes.io/cluster/other": "owned"}, expected: "eks"},
+		{name: "eksctl node group", tags: map[string]string{"alpha.eksctl.io/cluster-name": "eksctl", "kubernetes.io/cluster/other": "owned"}, expected: "eksctl"},
+		{name: "cluster tag", tags: map[string]string{"Name": "node", "kubernetes.io/cluster/k8s": "owned"}, expected: "k8s"},
+		{name: "several cluster tags", tags: map[string]string{"kubernetes.io/cluster/a": "shared", "kubernetes.io/cluster/b": "shared"}, err: true},
+		{name: "no cluster tags", tags: map[string]string{"Name": "node"}, err: true},
+	} {
+		var tags []*ec2.Tag
+		for k, v := range tc.tags {
+			tags = append(tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+		}
+		name, err := clusterNameFromTags("i-0123456789abcdef0", tags)
+		if tc.err {
+			assert.Error(t, err, tc.name)
+			continue
+		}
+		assert.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, name, tc.name)
+	}
+}
@@ -0,0 +1,196 @@
+package aws
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/wafregional"
+	"golang.org/x/net/http/httpproxy"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// STSRegionalEndpoints and STSLegacyEndpoints are the settings of STS endpoints, as of the sts_regional_endpoints setting of the AWS CLI
+	STSRegionalEndpoints = "regional"
+	STSLegacyEndpoints   = "legacy"
+
+	// globalSTSEndpoint is the endpoint the vendored SDK resolves STS to in the regions of the standard partition
+	globalSTSEndpoint = "https://sts.amazonaws.com"
+
+	// ec2MetadataHost is the host of the instance metadata endpoint, which is never proxied
+	ec2MetadataHost = "169.254.169.254"
+)
+
+// NewConfig returns the base config of all AWS clients of the controller, in region unless it's empty.
+// The region otherwise defaults to the one configured for the SDK, e.g. by AWS_REGION, or else the region of the instance from its metadata.
+// Calls to the services of endpointOverrides, in service=URL format keyed by the endpoint prefix of services, e.g. elasticloadbalancing, ec2, acm or waf-regional,
+// are made to their URLs, so the controller can run against e.g. LocalStack. Calls to other services are made to their FIPS endpoints with useFIPS, where available.
+// STS is called at the endpoint of the region rather than its global endpoint with stsRegional, so assuming roles doesn't depend on us-east-1.
+// The TLS certificates of endpoints aren't verified with insecureSkipTLSVerify.
+// Calls are made through the HTTP(S) proxy of proxyURL except to the hosts or domains of noProxy and instance metadata,
+// or else through the proxy of the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables as by default.
+func NewConfig(region string, endpointOverrides []string, useFIPS bool, stsRegional bool, insecureSkipTLSVerify bool, proxyURL string, noProxy []string) (*aws.Config, error) {
+	overrides, err := ParseEndpointOverrides(endpointOverrides)
+	if err != nil {
+		return nil, err
+	}
+	config := &aws.Config{}
+	if len(overrides) != 0 || useFIPS || stsRegional {
+		config.EndpointResolver = newEndpointResolver(overrides, useFIPS, stsRegional)
+	}
+	if insecureSkipTLSVerify || proxyURL != "" {
+		proxy := http.ProxyFromEnvironment
+		if proxyURL != "" {
+			if proxy, err = newProxyFunc(proxyURL, noProxy); err != nil {
+				return nil, err
+			}
+		}
+		config.HTTPClient = newHTTPClient(proxy, insecureSkipTLSVerify)
+	}
+	if err := resolveRegion(config, region); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// resolveRegion sets the region of config to region if it's not empty, or the region of the instance from its metadata
+// if none is configured for the SDK.
+func resolveRegion(config *aws.Config, region string) error {
+	if region != "" {
+		config.Region = aws.String(region)
+		return nil
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return fmt.Errorf("failed to create AWS session for region discovery due to %v", err)
+	}
+	if aws.StringValue(sess.Config.Region) != "" {
+		return nil
+	}
+	region, err = newEC2Metadata(ec2metadata.New(sess)).Region()
+	if err != nil {
+		return fmt.Errorf("failed to discover AWS region from instance metadata due to %v, it can be specified by --aws-region", err)
+	}
+	config.Region = aws.String(region)
+	return nil
+}
+
+// newHTTPClient returns the HTTP client of AWS calls through proxy, with the settings of http.DefaultTransport otherwise.
+func newHTTPClient(proxy func(*http.Request) (*url.URL, error), insecureSkipTLSVerify bool) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig:       &tls.Config{InsecureSkipVerify: insecureSkipTLSVerify},
+	}}
+}
+
+// newProxyFunc returns the proxy of requests, proxyURL unless their host is in noProxy or it's the instance metadata endpoint,
+// which is local to each instance. noProxy has the format of NO_PROXY entries, e.g. .amazonaws.com or 10.0.0.0/8.
+func newProxyFunc(proxyURL string, noProxy []string) (func(*http.Request) (*url.URL, error), error) {
+	if u, err := url.Parse(proxyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("AWS proxy must be an http or https URL, e.g. http://proxy.example.com:3128")
+	}
+	proxyConfig := &httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    strings.Join(append([]string{ec2MetadataHost}, noProxy...), ","),
+	}
+	proxyFunc := proxyConfig.ProxyFunc()
+	return func(r *http.Request) (*url.URL, error) {
+		return proxyFunc(r.URL)
+	}, nil
+}
+
+// ParseEndpointOverrides parses endpoint overrides in service=URL format.
+func ParseEndpointOverrides(entries []string) (map[string]string, error) {
+	overrides := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("unable to parse AWS endpoint `%s` into service=URL pair", entry)
+		}
+		endpointURL := strings.TrimSpace(parts[1])
+		if u, err := url.Parse(endpointURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("AWS endpoint of %s must be an http or https URL, e.g. https://elasticloadbalancing.us-gov-west-1.amazonaws.com", strings.TrimSpace(parts[0]))
+		}
+		overrides[strings.TrimSpace(parts[0])] = endpointURL
+	}
+	return overrides, nil
+}
+
+// fipsServices are the endpoint prefixes of services used by the controller with FIPS endpoints in fipsRegions,
+// the vendored SDK predates FIPS endpoint resolution.
+var fipsServices = sets.NewString(
+	elbv2.EndpointsID,
+	ec2.EndpointsID,
+	acm.EndpointsID,
+	wafregional.EndpointsID,
+	sts.EndpointsID,
+	cloudwatch.EndpointsID,
+	cloudwatchlogs.EndpointsID,
+)
+
+// fipsRegions are the regions with FIPS endpoints of fipsServices
+var fipsRegions = sets.NewString("us-east-1", "us-east-2", "us-west-1", "us-west-2", "us-gov-east-1", "us-gov-west-1")
+
+// newEndpointResolver resolves the endpoints of services in overrides to their URLs, FIPS endpoints if useFIPS, the regional endpoint of STS if stsRegional,
+// and others by the default resolver of the SDK.
+func newEndpointResolver(overrides map[string]string, useFIPS bool, stsRegional bool) endpoints.Resolver {
+	return endpoints.ResolverFunc(func(service string, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		if endpointURL, ok := overrides[service]; ok {
+			return endpoints.ResolvedEndpoint{URL: endpointURL, SigningRegion: region}, nil
+		}
+		if useFIPS {
+			if resolved, ok := fipsEndpoint(service, region); ok {
+				return resolved, nil
+			}
+		}
+		resolved, err := endpoints.DefaultResolver().EndpointFor(service, region, opts...)
+		if err == nil && stsRegional && service == sts.EndpointsID && resolved.URL == globalSTSEndpoint && region != "aws-global" {
+			// the other partitions have regional STS endpoints only.
+			return endpoints.ResolvedEndpoint{URL: fmt.Sprintf("https://sts.%s.amazonaws.com", region), SigningRegion: region}, nil
+		}
+		return resolved, err
+	})
+}
+
+// fipsEndpoint returns the FIPS endpoint of service in region, it's false if there isn't one.
+func fipsEndpoint(service string, region string) (endpoints.ResolvedEndpoint, bool) {
+	if service == iam.EndpointsID {
+		// IAM is global and signed for the first region of its partition, GovCloud's endpoint is FIPS compliant already.
+		if strings.HasPrefix(region, "us-gov-") {
+			return endpoints.ResolvedEndpoint{URL: "https://iam.us-gov.amazonaws.com", SigningRegion: "us-gov-west-1"}, true
+		}
+		if fipsRegions.Has(region) {
+			return endpoints.ResolvedEndpoint{URL: "https://iam-fips.amazonaws.com", SigningRegion: "us-east-1"}, true
+		}
+		return endpoints.ResolvedEndpoint{}, false
+	}
+	if !fipsServices.Has(service) || !fipsRegions.Has(region) {
+		return endpoints.ResolvedEndpoint{}, false
+	}
+	return endpoints.ResolvedEndpoint{URL: fmt.Sprintf("https://%s-fips.%s.amazonaws.com", service, region), SigningRegion: region}, true
+}
@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEndpointOverrides(t *testing.T) {
+	overrides, err := ParseEndpointOverrides([]string{"elasticloadbalancing=http://localhost:4566", " ec2 = https://ec2.example.com "})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"elasticloadbalancing": "http://localhost:4566", "ec2": "https://ec2.example.com"}, overrides)
+
+	for _, entry := range []string{"elasticloadbalancing", "=http://localhost:4566", "ec2=localhost:4566", "ec2=ftp://localhost"} {
+		_, err := ParseEndpointOverrides([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	config, err := NewConfig("us-west-2", nil, false, false, false, "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config.EndpointResolver)
+	assert.Nil(t, config.HTTPClient)
+	assert.Equal(t, "us-west-2", aws.StringValue(config.Region))
+
+	config, err = NewConfig("us-west-2", []string{"elasticloadbalancing=http://localhost:4566"}, false, false, true, "", nil)
+	assert.NoError(t, err)
+	resolved, err := config.EndpointResolver.EndpointFor(elbv2.EndpointsID, "us-west-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:4566", resolved.URL)
+	assert.Equal(t, "us-west-2", resolved.SigningRegion)
+	resolved, err = config.EndpointResolver.EndpointFor(ec2.EndpointsID, "us-gov-west-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://ec2.us-gov-west-1.amazonaws.com", resolved.URL)
+	assert.True(t, config.HTTPClient.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+
+	_, err = NewConfig("us-west-2", []string{"elasticloadbalancing"}, false, false, false, "", nil)
+	assert.Error(t, err)
+}
+
+func TestNewConfigFIPS(t *testing.T) {
+	config, err := NewConfig("us-west-2", []string{"ec2=http://localhost:4566"}, true, false, false, "", nil)
+	assert.NoError(t, err)
+	for _, tc := range []struct {
+		service  string
+		region   string
+		expected string
+	}{
+		{service: elbv2.EndpointsID, region: "us-east-1", expected: "https://elasticloadbalancing-fips.us-east-1.amazonaws.com"},
+		{service: elbv2.EndpointsID, region: "us-gov-west-1", expected: "https://elasticloadbalancing-fips.us-gov-west-1.amazonaws.com"},
+		{service: iam.EndpointsID, region: "us-west-2", expected: "https://iam-fips.amazonaws.com"},
+		{service: iam.EndpointsID, region: "us-gov-east-1", expected: "https://iam.us-gov.amazonaws.com"},
+		// overrides take precedence, services and regions without FIPS endpoints use the standard ones.
+		{service: ec2.EndpointsID, region: "us-east-1", expected: "http://localhost:4566"},
+		{service: elbv2.EndpointsID, region: "eu-west-1", expected: "https://elasticloadbalancing.eu-west-1.amazonaws.com"},
+		{service: resourcegroupstaggingapi.EndpointsID, region: "us-east-1", expected: "https://tagging.us-east-1.amazonaws.com"},
+	} {
+		resolved, err := config.EndpointResolver.EndpointFor(tc.service, tc.region)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, resolved.URL, tc.service+" in "+tc.region)
+	}
+	resolved, err := config.EndpointResolver.EndpointFor(iam.EndpointsID, "us-west-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1", resolved.SigningRegion)
+}
+
+func TestNewConfigSTSRegional(t *testing.T) {
+	config, err := NewConfig("us-west-2", nil, false, true, false, "", nil)
+	assert.NoError(t, err)
+	for _, tc := range []struct {
+		region   string
+		expected string
+	}{
+		{region: "us-west-2", expected: "https://sts.us-west-2.amazonaws.com"},
+		{region: "eu-central-1", expected: "https://sts.eu-central-1.amazonaws.com"},
+		{region: "us-gov-west-1", expected: "https://sts.us-gov-west-1.amazonaws.com"},
+		{region: "cn-north-1", expected: "https://sts.cn-north-1.amazonaws.com.cn"},
+	} {
+		resolved, err := config.EndpointResolver.EndpointFor(sts.EndpointsID, tc.region)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, resolved.URL, tc.region)
+		assert.Equal(t, tc.region, resolved.SigningRegion, tc.region)
+	}
+	resolved, err := config.EndpointResolver.EndpointFor(elbv2.EndpointsID, "us-west-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://elasticloadbalancing.us-west-2.amazonaws.com", resolved.URL)
+
+	config, err = NewConfig("us-west-2", nil, false, false, false, "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, config.EndpointResolver)
+}
+
+func TestNewConfigProxy(t *testing.T) {
+	config, err := NewConfig("us-west-2", nil, false, false, false, "http://proxy.example.com:3128", []string{".internal.example.com"})
+	assert.NoError(t, err)
+	transport := config.HTTPClient.Transport.(*http.Transport)
+	assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+	for _, tc := range []struct {
+		url      string
+		expected string
+	}{
+		{url: "https://elasticloadbalancing.us-west-2.amazonaws.com/", expected: "http://proxy.example.com:3128"},
+		{url: "http://169.254.169.254/latest/api/token"},
+		{url: "https://elb.internal.example.com/"},
+	} {
+		req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+		assert.NoError(t, err)
+		proxy, err := transport.Proxy(req)
+		assert.NoError(t, err)
+		if tc.expected == "" {
+			assert.Nil(t, proxy, tc.url)
+		} else {
+			assert.Equal(t, tc.expected, proxy.String(), tc.url)
+		}
+	}
+
+	_, err = NewConfig("us-west-2", nil, false, false, false, "proxy.example.com:3128", nil)
+	assert.Error(t, err)
+}
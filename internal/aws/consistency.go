@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// createdResourceConsistencyWindow is how long after an resource is created requests failing as it's not found are retried,
+// since ELBV2 is eventually consistent, e.g. an targetGroup just created may not be found when creating an listener forwarding to it.
+const createdResourceConsistencyWindow = time.Minute
+
+// notFoundErrorCodes are the codes of errors returned by ELBV2 for resources not found.
+var notFoundErrorCodes = sets.NewString(
+	elbv2.ErrCodeLoadBalancerNotFoundException,
+	elbv2.ErrCodeTargetGroupNotFoundException,
+	elbv2.ErrCodeListenerNotFoundException,
+	elbv2.ErrCodeRuleNotFoundException,
+)
+
+// createdResources tracks the resources created recently, so requests failing as they're not found are retried
+// instead of failing the reconcile, while requests for other resources not found still fail straight away.
+type createdResources struct {
+	mutex     sync.Mutex
+	createdAt map[string]time.Time
+	now       func() time.Time
+}
+
+func newCreatedResources() *createdResources {
+	return &createdResources{createdAt: make(map[string]time.Time), now: time.Now}
+}
+
+// recordCreated records the ARNs of resources in the output of successful Create requests, it's an Complete handler.
+func (c *createdResources) recordCreated(r *request.Request) {
+	if r.Error != nil || !strings.HasPrefix(r.Operation.Name, "Create") || getDryRunPlan(r.Context()) != nil {
+		return
+	}
+	arns := findResourceIDs(reflect.ValueOf(r.Data), 0)
+	if len(arns) == 0 {
+		return
+	}
+	now := c.now()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for arn, createdAt := range c.createdAt {
+		if now.Sub(createdAt) > createdResourceConsistencyWindow {
+			delete(c.createdAt, arn)
+		}
+	}
+	for _, arn := range arns {
+		c.createdAt[arn] = now
+	}
+}
+
+// retryNotFound marks requests failing as an resource created recently is not found as retryable, it's an Retry handler.
+// They're retried with the jittered exponential backoff of the retryer, up to --aws-max-retries times.
+func (c *createdResources) retryNotFound(r *request.Request) {
+	if r.Error == nil || !notFoundErrorCodes.Has(apiErrorCode(r.Error)) {
+		return
+	}
+	now := c.now()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, arn := range findResourceIDs(reflect.ValueOf(r.Params), 0) {
+		if createdAt, ok := c.createdAt[arn]; ok && now.Sub(createdAt) <= createdResourceConsistencyWindow {
+			r.Retryable = aws.Bool(true)
+			return
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatedResources(t *testing.T) {
+	now := time.Now()
+	created := newCreatedResources()
+	created.now = func() time.Time { return now }
+	created.recordCreated(&request.Request{
+		Operation: &request.Operation{Name: "CreateTargetGroup"},
+		Params:    &elbv2.CreateTargetGroupInput{Name: aws.String("tg")},
+		Data: &elbv2.CreateTargetGroupOutput{TargetGroups: []*elbv2.TargetGroup{
+			{TargetGroupArn: aws.String("tg-arn")},
+		}},
+	})
+
+	notFound := awserr.New(elbv2.ErrCodeTargetGroupNotFoundException, "not found", nil)
+	for _, tc := range []struct {
+		name              string
+		params            interface{}
+		err               error
+		elapsed           time.Duration
+		expectedRetryable *bool
+	}{
+		{
+			name: "created resource not found",
+			params: &elbv2.CreateListenerInput{DefaultActions: []*elbv2.Action{
+				{TargetGroupArn: aws.String("tg-arn")},
+			}},
+			err:               notFound,
+			expectedRetryable: aws.Bool(true),
+		},
+		{
+			name:    "created resource not found after consistency window",
+			params:  &elbv2.ModifyTargetGroupAttributesInput{TargetGroupArn: aws.String("tg-arn")},
+			err:     notFound,
+			elapsed: createdResourceConsistencyWindow + time.Second,
+		},
+		{
+			name:   "other resource not found",
+			params: &elbv2.ModifyTargetGroupAttributesInput{TargetGroupArn: aws.String("other-arn")},
+			err:    notFound,
+		},
+		{
+			name:   "other error",
+			params: &elbv2.ModifyTargetGroupAttributesInput{TargetGroupArn: aws.String("tg-arn")},
+			err:    awserr.New("AccessDenied", "access denied", nil),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			created.now = func() time.Time { return now.Add(tc.elapsed) }
+			r := &request.Request{
+				Operation: &request.Operation{Name: "Operation"},
+				Params:    tc.params,
+				Error:     tc.err,
+			}
+			created.retryNotFound(r)
+			assert.Equal(t, tc.expectedRetryable, r.Retryable)
+		})
+	}
+}
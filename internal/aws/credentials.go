@@ -0,0 +1,218 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+const (
+	// the environment variables set by IAM Roles for Service Accounts on pods of annotated service accounts
+	envRoleARN              = "AWS_ROLE_ARN"
+	envWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	envRoleSessionName      = "AWS_ROLE_SESSION_NAME"
+
+	defaultRoleSessionName = "aws-alb-ingress-controller"
+
+	webIdentityProviderName = "WebIdentityProvider"
+	// errCodeWebIdentity is the code of errors retrieving the credentials of the web identity
+	errCodeWebIdentity = "WebIdentityErr"
+	// webIdentityExpiryWindow is how long before they expire credentials of the web identity are retrieved again
+	webIdentityExpiryWindow = 5 * time.Minute
+)
+
+// NewCredentials returns the credentials of AWS calls, nil if the default credential chain of the SDK is used.
+// With IAM Roles for Service Accounts, i.e. AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE are set, the role of the service account is assumed by its token,
+// then roleARN is assumed with these credentials unless it's empty. STS is called with config.
+func NewCredentials(config *aws.Config, roleARN string) (*credentials.Credentials, error) {
+	stsSession, err := session.NewSession(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session for credentials due to %v", err)
+	}
+	var creds *credentials.Credentials
+	if tokenFile, serviceAccountRoleARN := os.Getenv(envWebIdentityTokenFile), os.Getenv(envRoleARN); tokenFile != "" && serviceAccountRoleARN != "" {
+		creds = credentials.NewCredentials(&webIdentityProvider{
+			sts:         sts.New(stsSession),
+			roleARN:     serviceAccountRoleARN,
+			tokenFile:   tokenFile,
+			sessionName: roleSessionName(),
+		})
+	}
+	if roleARN == "" {
+		return creds, nil
+	}
+	if creds != nil {
+		stsSession = stsSession.Copy(&aws.Config{Credentials: creds})
+	}
+	return stscreds.NewCredentials(stsSession, roleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.RoleSessionName = roleSessionName()
+	}), nil
+}
+
+func roleSessionName() string {
+	if name := os.Getenv(envRoleSessionName); name != "" {
+		return name
+	}
+	return defaultRoleSessionName
+}
+
+// webIdentityProvider retrieves the credentials of roleARN by exchanging the web identity token in tokenFile, which is rotated by the kubelet,
+// so it's read again on every retrieval. The vendored SDK predates its own web identity support.
+type webIdentityProvider struct {
+	credentials.Expiry
+
+	sts         stsiface.STSAPI
+	roleARN     string
+	tokenFile   string
+	sessionName string
+}
+
+var _ credentials.Provider = (*webIdentityProvider)(nil)
+
+func (p *webIdentityProvider) Retrieve() (credentials.Value, error) {
+	token, err := ioutil.ReadFile(p.tokenFile)
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName}, awserr.New(errCodeWebIdentity, "failed to read web identity token", err)
+	}
+	resp, err := p.sts.AssumeRoleWithWebIdentity(&sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(p.roleARN),
+		RoleSessionName:  aws.String(p.sessionName),
+		WebIdentityToken: aws.String(string(token)),
+	})
+	if err != nil {
+		return credentials.Value{ProviderName: webIdentityProviderName}, awserr.New(errCodeWebIdentity, "failed to assume role "+p.roleARN+" with web identity", err)
+	}
+	p.SetExpiration(aws.TimeValue(resp.Credentials.Expiration), webIdentityExpiryWindow)
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(resp.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(resp.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(resp.Credentials.SessionToken),
+		ProviderName:    webIdentityProviderName,
+	}, nil
+}
+
+// credentialsErrorCodes are the codes of errors retrieving credentials, e.g. while kube2iam, kiam or the web identity of IRSA briefly fail to vend them.
+var credentialsErrorCodes = []string{
+	credentials.ErrNoValidProvidersFoundInChain.Code(),
+	"EC2RoleRequestError",
+	errCodeWebIdentity,
+	"ExpiredToken",
+	"ExpiredTokenException",
+}
+
+// IsCredentialsError returns whether err is caused by AWS credentials failing to be retrieved or having expired.
+// Errors wrapped by fmt.Errorf with %v are recognized by their messages, which start with their codes.
+func IsCredentialsError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if awsErr, ok := err.(awserr.Error); ok {
+		for _, code := range credentialsErrorCodes {
+			if awsErr.Code() == code {
+				return true
+			}
+		}
+	}
+	for _, code := range credentialsErrorCodes {
+		if strings.Contains(err.Error(), code+": ") {
+			return true
+		}
+	}
+	return false
+}
+
+type roleKey struct{}
+
+// role is an role AWS calls are made with, assumed with sessionTags
+type role struct {
+	arn         string
+	sessionTags map[string]string
+}
+
+// WithRole returns an context whose AWS calls are made with the credentials of roleARN, assumed with the controller's own credentials,
+// e.g. to manage an ingress's resources in another account. The role session is tagged with sessionTags, so CloudTrail attributes
+// the calls to e.g. the ingress made for. ctx is returned as is if roleARN is empty.
+func WithRole(ctx context.Context, roleARN string, sessionTags map[string]string) context.Context {
+	if roleARN == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, roleKey{}, &role{arn: roleARN, sessionTags: sessionTags})
+}
+
+// getRole returns the role AWS calls with ctx are made with, it's empty for the controller's own credentials.
+func getRole(ctx context.Context) string {
+	if r, ok := ctx.Value(roleKey{}).(*role); ok {
+		return r.arn
+	}
+	return ""
+}
+
+// roleCredentials assumes the roles of requests made with an context of WithRole, the credentials of each role and session tags
+// are refreshed by the SDK before they expire.
+type roleCredentials struct {
+	// sts is the client roles are assumed with
+	sts *sts.STS
+
+	// mutex protects credentials by role ARN and session tags
+	mutex       sync.Mutex
+	credentials map[string]*credentials.Credentials
+}
+
+func newRoleCredentials(stsSession *session.Session) *roleCredentials {
+	return &roleCredentials{
+		sts:         sts.New(stsSession),
+		credentials: make(map[string]*credentials.Credentials),
+	}
+}
+
+// apply signs request r with the credentials of its role, if any.
+func (c *roleCredentials) apply(r *request.Request) {
+	assumed, ok := r.Context().Value(roleKey{}).(*role)
+	if !ok {
+		return
+	}
+	key := assumed.arn + "?" + encodeQueryTags(url.Values{}, assumed.sessionTags).Encode()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	creds, ok := c.credentials[key]
+	if !ok {
+		creds = stscreds.NewCredentialsWithClient(&sessionTaggingAssumeRoler{sts: c.sts, sessionTags: assumed.sessionTags}, assumed.arn, func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = roleSessionName()
+		})
+		c.credentials[key] = creds
+	}
+	r.Config.Credentials = creds
+}
+
+// sessionTaggingAssumeRoler assumes roles with session tags, which the vendored SDK predates, by adding them to the query of AssumeRole calls.
+type sessionTaggingAssumeRoler struct {
+	sts         *sts.STS
+	sessionTags map[string]string
+}
+
+var _ stscreds.AssumeRoler = (*sessionTaggingAssumeRoler)(nil)
+
+func (a *sessionTaggingAssumeRoler) AssumeRole(input *sts.AssumeRoleInput) (*sts.AssumeRoleOutput, error) {
+	req, output := a.sts.AssumeRoleRequest(input)
+	req.Handlers.Build.PushBack(a.addSessionTags)
+	return output, req.Send()
+}
+
+// addSessionTags adds the session tags to the query built for request r.
+func (a *sessionTaggingAssumeRoler) addSessionTags(r *request.Request) {
+	addQueryTags(r, a.sessionTags)
+}
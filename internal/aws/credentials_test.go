@@ -0,0 +1,132 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSTS struct {
+	stsiface.STSAPI
+	inputs []*sts.AssumeRoleWithWebIdentityInput
+}
+
+func (f *fakeSTS) AssumeRoleWithWebIdentity(input *sts.AssumeRoleWithWebIdentityInput) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	f.inputs = append(f.inputs, input)
+	return &sts.AssumeRoleWithWebIdentityOutput{Credentials: &sts.Credentials{
+		AccessKeyId:     aws.String("access-key"),
+		SecretAccessKey: aws.String("secret-key"),
+		SessionToken:    aws.String("session-token"),
+		Expiration:      aws.Time(time.Now().Add(time.Hour)),
+	}}, nil
+}
+
+func TestWebIdentityProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "web-identity")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(t, ioutil.WriteFile(tokenFile, []byte("token"), 0600))
+
+	fake := &fakeSTS{}
+	p := &webIdentityProvider{sts: fake, roleARN: "arn:aws:iam::123456789012:role/alb", tokenFile: tokenFile, sessionName: defaultRoleSessionName}
+	assert.True(t, p.IsExpired())
+
+	value, err := p.Retrieve()
+	assert.NoError(t, err)
+	assert.Equal(t, "access-key", value.AccessKeyID)
+	assert.Equal(t, "session-token", value.SessionToken)
+	assert.False(t, p.IsExpired())
+
+	// the token rotated by the kubelet is read again.
+	assert.NoError(t, ioutil.WriteFile(tokenFile, []byte("rotated-token"), 0600))
+	_, err = p.Retrieve()
+	assert.NoError(t, err)
+	assert.Equal(t, []*sts.AssumeRoleWithWebIdentityInput{
+		{RoleArn: aws.String("arn:aws:iam::123456789012:role/alb"), RoleSessionName: aws.String(defaultRoleSessionName), WebIdentityToken: aws.String("token")},
+		{RoleArn: aws.String("arn:aws:iam::123456789012:role/alb"), RoleSessionName: aws.String(defaultRoleSessionName), WebIdentityToken: aws.String("rotated-token")},
+	}, fake.inputs)
+
+	assert.NoError(t, os.Remove(tokenFile))
+	_, err = p.Retrieve()
+	assert.Error(t, err)
+	assert.True(t, IsCredentialsError(err))
+}
+
+func TestIsCredentialsError(t *testing.T) {
+	assert.True(t, IsCredentialsError(credentials.ErrNoValidProvidersFoundInChain))
+	assert.True(t, IsCredentialsError(awserr.New("EC2RoleRequestError", "no EC2 instance role found", nil)))
+	assert.True(t, IsCredentialsError(fmt.Errorf("unable to fetch subnets due to %v", awserr.New("ExpiredToken", "the security token included in the request is expired", nil))))
+	assert.False(t, IsCredentialsError(nil))
+	assert.False(t, IsCredentialsError(awserr.New("AccessDenied", "not authorized", nil)))
+	assert.False(t, IsCredentialsError(fmt.Errorf("unable to fetch subnets due to %v", awserr.New("Throttling", "rate exceeded", nil))))
+}
+
+func TestRoleCredentials(t *testing.T) {
+	stsSession, err := session.NewSession()
+	assert.NoError(t, err)
+	roles := newRoleCredentials(stsSession)
+	newRequest := func(ctx context.Context) *request.Request {
+		r := &request.Request{}
+		r.SetContext(ctx)
+		roles.apply(r)
+		return r
+	}
+
+	assert.Nil(t, newRequest(context.Background()).Config.Credentials)
+	assert.Equal(t, context.Background(), WithRole(context.Background(), "", nil))
+
+	ingressTags := map[string]string{"kubernetes.io/namespace": "namespace", "kubernetes.io/ingress-name": "ingress"}
+	ctx := WithRole(context.Background(), "arn:aws:iam::111111111111:role/alb", ingressTags)
+	creds := newRequest(ctx).Config.Credentials
+	assert.NotNil(t, creds)
+	assert.True(t, creds == newRequest(WithRole(context.Background(), "arn:aws:iam::111111111111:role/alb", ingressTags)).Config.Credentials,
+		"credentials of an role and session tags are shared by their requests")
+	assert.Equal(t, "arn:aws:iam::111111111111:role/alb", getRole(ctx))
+
+	otherRoleCreds := newRequest(WithRole(context.Background(), "arn:aws:iam::222222222222:role/alb", ingressTags)).Config.Credentials
+	assert.False(t, creds == otherRoleCreds)
+	otherIngressCreds := newRequest(WithRole(context.Background(), "arn:aws:iam::111111111111:role/alb",
+		map[string]string{"kubernetes.io/namespace": "namespace", "kubernetes.io/ingress-name": "other"})).Config.Credentials
+	assert.False(t, creds == otherIngressCreds)
+}
+
+func TestSessionTaggingAssumeRoler(t *testing.T) {
+	stsSession, err := session.NewSession(&aws.Config{Region: aws.String("us-west-2")})
+	assert.NoError(t, err)
+	a := &sessionTaggingAssumeRoler{
+		sts:         sts.New(stsSession),
+		sessionTags: map[string]string{"kubernetes.io/namespace": "namespace", "kubernetes.io/ingress-name": "ingress"},
+	}
+	req, _ := a.sts.AssumeRoleRequest(&sts.AssumeRoleInput{
+		RoleArn:         aws.String("arn:aws:iam::111111111111:role/alb"),
+		RoleSessionName: aws.String(defaultRoleSessionName),
+	})
+	req.Handlers.Build.PushBack(a.addSessionTags)
+	assert.NoError(t, req.Build())
+
+	body, err := ioutil.ReadAll(req.Body)
+	assert.NoError(t, err)
+	query, err := url.ParseQuery(string(body))
+	assert.NoError(t, err)
+	assert.Equal(t, "AssumeRole", query.Get("Action"))
+	assert.Equal(t, "arn:aws:iam::111111111111:role/alb", query.Get("RoleArn"))
+	assert.Equal(t, "kubernetes.io/ingress-name", query.Get("Tags.member.1.Key"))
+	assert.Equal(t, "ingress", query.Get("Tags.member.1.Value"))
+	assert.Equal(t, "kubernetes.io/namespace", query.Get("Tags.member.2.Key"))
+	assert.Equal(t, "namespace", query.Get("Tags.member.2.Value"))
+}
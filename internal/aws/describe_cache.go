@@ -0,0 +1,136 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// cachedDescribeOperations are the ELBV2 operations whose responses are cached by the describeCache.
+var cachedDescribeOperations = sets.NewString(
+	"DescribeLoadBalancers",
+	"DescribeListeners",
+	"DescribeRules",
+	"DescribeTargetGroups",
+	"DescribeTags",
+)
+
+// describeCache caches the responses of ELBV2 Describe calls for ttl, so reconciles of unchanged ingresses don't describe
+// the same LoadBalancers, listeners, rules and targetGroups over and over again.
+// Responses are invalidated as soon as the controller mutates an resource they reference, in their input or output,
+// so reconciles always see their own changes. Changes made out-of-band are seen once the responses expire.
+type describeCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	// mutex protects entries, the cached responses by operation and input
+	mutex   sync.Mutex
+	entries map[string]*describeCacheEntry
+}
+
+type describeCacheEntry struct {
+	data      interface{}
+	expiresAt time.Time
+	// resourceIDs are the IDs of the resources referenced by the input and output of the response
+	resourceIDs sets.String
+	// unfiltered is whether the input doesn't reference any resource, e.g. describing all LoadBalancers,
+	// so the response is invalidated by any mutation, which may create resources it'd include
+	unfiltered bool
+}
+
+// describeCacheHitKey is the key in the context of requests served from the describeCache.
+type describeCacheHitKey struct{}
+
+// newDescribeCache constructs an describeCache caching responses for ttl, it returns nil if ttl is 0.
+func newDescribeCache(ttl time.Duration) *describeCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &describeCache{ttl: ttl, now: time.Now, entries: make(map[string]*describeCacheEntry)}
+}
+
+// lookup serves request r from the cache if its response is cached, it's an Validate handler so it runs once per request.
+func (c *describeCache) lookup(r *request.Request) {
+	key, ok := describeCacheKey(r)
+	if !ok {
+		return
+	}
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	if ok && c.now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mutex.Unlock()
+	if !ok {
+		return
+	}
+	// callers may modify outputs, so they get copies of cached responses.
+	awsutil.Copy(r.Data, entry.data)
+	skipSend(r)
+	r.SetContext(context.WithValue(r.Context(), describeCacheHitKey{}, true))
+}
+
+// complete caches the response of successful Describe request r, and invalidates the responses referencing the resources of
+// mutating request r, it's an Complete handler. Mutations are invalidated even if they failed, since they may have partially succeeded.
+// Requests skipped in dry-run neither cache nor invalidate responses.
+func (c *describeCache) complete(r *request.Request) {
+	if getDryRunPlan(r.Context()) != nil || r.Context().Value(describeCacheHitKey{}) != nil {
+		return
+	}
+	if r.ClientInfo.ServiceName == elbv2.ServiceName && isMutatingOperation(r.Operation.Name) {
+		c.invalidate(append(findResourceIDs(reflect.ValueOf(r.Params), 0), findResourceIDs(reflect.ValueOf(r.Data), 0)...))
+		return
+	}
+	key, ok := describeCacheKey(r)
+	if !ok || r.Error != nil {
+		return
+	}
+	inputIDs := findResourceIDs(reflect.ValueOf(r.Params), 0)
+	entry := &describeCacheEntry{
+		data:        awsutil.CopyOf(r.Data),
+		expiresAt:   c.now().Add(c.ttl),
+		resourceIDs: sets.NewString(inputIDs...).Insert(findResourceIDs(reflect.ValueOf(r.Data), 0)...),
+		unfiltered:  len(inputIDs) == 0,
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := c.now()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+	c.entries[key] = entry
+}
+
+// invalidate removes the responses referencing any of resourceIDs, and the unfiltered responses.
+func (c *describeCache) invalidate(resourceIDs []string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key, entry := range c.entries {
+		if entry.unfiltered || entry.resourceIDs.HasAny(resourceIDs...) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// describeCacheKey returns the key of the response of request r, it's false if the response of r isn't cached.
+func describeCacheKey(r *request.Request) (string, bool) {
+	if r.ClientInfo.ServiceName != elbv2.ServiceName || !cachedDescribeOperations.Has(r.Operation.Name) {
+		return "", false
+	}
+	input, err := json.Marshal(r.Params)
+	if err != nil {
+		return "", false
+	}
+	// responses of different roles are of different accounts.
+	return getRole(r.Context()) + r.Operation.Name + string(input), true
+}
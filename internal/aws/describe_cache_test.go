@@ -0,0 +1,99 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newELBV2Request(operation string, params interface{}, data interface{}) *request.Request {
+	r := &request.Request{
+		ClientInfo: metadata.ClientInfo{ServiceName: elbv2.ServiceName},
+		Operation:  &request.Operation{Name: operation},
+		Params:     params,
+		Data:       data,
+	}
+	r.SetContext(context.Background())
+	return r
+}
+
+func describeListeners(c *describeCache, lbArn string) (*elbv2.DescribeListenersOutput, bool) {
+	output := &elbv2.DescribeListenersOutput{}
+	r := newELBV2Request("DescribeListeners", &elbv2.DescribeListenersInput{LoadBalancerArn: aws.String(lbArn)}, output)
+	c.lookup(r)
+	return output, r.Context().Value(describeCacheHitKey{}) != nil
+}
+
+func TestNewDescribeCache(t *testing.T) {
+	assert.Nil(t, newDescribeCache(0))
+	assert.NotNil(t, newDescribeCache(time.Minute))
+}
+
+func TestDescribeCache(t *testing.T) {
+	now := time.Now()
+	c := newDescribeCache(time.Minute)
+	c.now = func() time.Time { return now }
+	cacheListeners := func() {
+		c.complete(newELBV2Request("DescribeListeners",
+			&elbv2.DescribeListenersInput{LoadBalancerArn: aws.String("lb-arn")},
+			&elbv2.DescribeListenersOutput{Listeners: []*elbv2.Listener{{ListenerArn: aws.String("ls-arn"), LoadBalancerArn: aws.String("lb-arn")}}}))
+	}
+
+	t.Run("hit", func(t *testing.T) {
+		cacheListeners()
+		output, hit := describeListeners(c, "lb-arn")
+		assert.True(t, hit)
+		assert.Equal(t, "ls-arn", aws.StringValue(output.Listeners[0].ListenerArn))
+
+		// outputs are copies of cached responses.
+		output.Listeners[0].ListenerArn = aws.String("modified")
+		output, _ = describeListeners(c, "lb-arn")
+		assert.Equal(t, "ls-arn", aws.StringValue(output.Listeners[0].ListenerArn))
+
+		_, hit = describeListeners(c, "other-lb-arn")
+		assert.False(t, hit)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		cacheListeners()
+		now = now.Add(2 * time.Minute)
+		_, hit := describeListeners(c, "lb-arn")
+		assert.False(t, hit)
+	})
+
+	t.Run("invalidated by mutation of resource in input", func(t *testing.T) {
+		cacheListeners()
+		c.complete(newELBV2Request("CreateListener", &elbv2.CreateListenerInput{LoadBalancerArn: aws.String("lb-arn")}, &elbv2.CreateListenerOutput{}))
+		_, hit := describeListeners(c, "lb-arn")
+		assert.False(t, hit)
+	})
+
+	t.Run("invalidated by mutation of resource in output", func(t *testing.T) {
+		cacheListeners()
+		c.complete(newELBV2Request("ModifyListener", &elbv2.ModifyListenerInput{ListenerArn: aws.String("ls-arn")}, &elbv2.ModifyListenerOutput{}))
+		_, hit := describeListeners(c, "lb-arn")
+		assert.False(t, hit)
+	})
+
+	t.Run("not invalidated by mutation of other resource", func(t *testing.T) {
+		cacheListeners()
+		c.complete(newELBV2Request("DeleteRule", &elbv2.DeleteRuleInput{RuleArn: aws.String("other-rule-arn")}, &elbv2.DeleteRuleOutput{}))
+		_, hit := describeListeners(c, "lb-arn")
+		assert.True(t, hit)
+	})
+
+	t.Run("unfiltered invalidated by any mutation", func(t *testing.T) {
+		c.complete(newELBV2Request("DescribeLoadBalancers", &elbv2.DescribeLoadBalancersInput{}, &elbv2.DescribeLoadBalancersOutput{}))
+		c.complete(newELBV2Request("CreateLoadBalancer", &elbv2.CreateLoadBalancerInput{Name: aws.String("lb")},
+			&elbv2.CreateLoadBalancerOutput{LoadBalancers: []*elbv2.LoadBalancer{{LoadBalancerArn: aws.String("new-lb-arn")}}}))
+		r := newELBV2Request("DescribeLoadBalancers", &elbv2.DescribeLoadBalancersInput{}, &elbv2.DescribeLoadBalancersOutput{})
+		c.lookup(r)
+		assert.Nil(t, r.Context().Value(describeCacheHitKey{}))
+	})
+}
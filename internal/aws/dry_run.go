@@ -0,0 +1,215 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DryRunIDPrefix prefixes the placeholder IDs of resources planned to be created in dry-run.
+const DryRunIDPrefix = "dry-run-"
+
+type dryRunContextKey struct{}
+
+// PlannedChange is an AWS call planned in dry-run.
+type PlannedChange struct {
+	Service   string `json:"service"`
+	Operation string `json:"operation"`
+	// Input is the input of the call, with sensitive values redacted.
+	Input interface{} `json:"input"`
+}
+
+// Resource returns the kind of resource changed, i.e. the operation without its verb, e.g. TargetGroup for CreateTargetGroup.
+func (c PlannedChange) Resource() string {
+	for _, prefix := range mutatingOperationPrefixes {
+		if strings.HasPrefix(c.Operation, prefix) {
+			return strings.TrimPrefix(c.Operation, prefix)
+		}
+	}
+	return c.Operation
+}
+
+func (c PlannedChange) String() string {
+	return fmt.Sprintf("%s/%s %s", c.Service, c.Operation, log.Prettify(c.Input))
+}
+
+// DryRunPlan collects the AWS calls planned while reconciling in dry-run.
+type DryRunPlan struct {
+	mutex   sync.Mutex
+	changes []PlannedChange
+	// placeholders is the number of resources planned to be created, which are identified by placeholder IDs.
+	placeholders int
+}
+
+// WithDryRun returns a copy of ctx in which AWS calls creating, modifying or deleting resources are not made but collected in the returned plan.
+func WithDryRun(ctx context.Context) (context.Context, *DryRunPlan) {
+	plan := &DryRunPlan{}
+	return context.WithValue(ctx, dryRunContextKey{}, plan), plan
+}
+
+// IsDryRun returns whether ctx is an dry-run context returned by WithDryRun.
+func IsDryRun(ctx context.Context) bool {
+	return getDryRunPlan(ctx) != nil
+}
+
+func getDryRunPlan(ctx context.Context) *DryRunPlan {
+	plan, _ := ctx.Value(dryRunContextKey{}).(*DryRunPlan)
+	return plan
+}
+
+// Changes returns the planned AWS calls, in the order they'd be made.
+func (p *DryRunPlan) Changes() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var changes []string
+	for _, change := range p.changes {
+		changes = append(changes, change.String())
+	}
+	return changes
+}
+
+// PlannedChanges returns the planned AWS calls with their inputs, in the order they'd be made.
+func (p *DryRunPlan) PlannedChanges() []PlannedChange {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]PlannedChange(nil), p.changes...)
+}
+
+// Incomplete tests whether creations are planned. Resources planned to be created don't exist, so reconciling
+// the resources depending on them may fail, in which case the changes afterwards aren't planned.
+func (p *DryRunPlan) Incomplete() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.placeholders > 0
+}
+
+func (p *DryRunPlan) add(change PlannedChange) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.changes = append(p.changes, change)
+}
+
+func (p *DryRunPlan) newPlaceholderID(resource string) string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.placeholders++
+	return fmt.Sprintf("%s%s-%d", DryRunIDPrefix, strings.ToLower(resource), p.placeholders)
+}
+
+// skipDryRunRequest skips request r mutating resources if it's made in dry-run, the planned call is logged and emitted as event.
+// Calls succeed with empty outputs, except creations whose outputs are the created resources identified by placeholder IDs.
+// Reads of resources identified by placeholder IDs are skipped as well, with empty outputs.
+func skipDryRunRequest(r *request.Request) {
+	plan := getDryRunPlan(r.Context())
+	if plan == nil {
+		return
+	}
+	if !isMutatingOperation(r.Operation.Name) {
+		if hasPlaceholderIDs(r) {
+			skipSend(r)
+		}
+		return
+	}
+	change := PlannedChange{
+		Service:   r.ClientInfo.ServiceName,
+		Operation: r.Operation.Name,
+		Input:     log.Redact(r.Params),
+	}
+	plan.add(change)
+	albctx.GetLogger(r.Context()).Infof("dry-run: planned %s", change)
+	albctx.GetEventf(r.Context())(corev1.EventTypeNormal, "DRYRUN", "planned %s", change)
+
+	if strings.HasPrefix(r.Operation.Name, "Create") {
+		fillPlaceholderOutput(r.Data, r.Params, func() string { return plan.newPlaceholderID(change.Resource()) })
+	}
+	skipSend(r)
+}
+
+// skipSend makes request r succeed without sending it, its output is left as is.
+func skipSend(r *request.Request) {
+	r.Handlers.Sign.Clear()
+	r.Handlers.Send.Clear()
+	r.Handlers.ValidateResponse.Clear()
+	r.Handlers.UnmarshalMeta.Clear()
+	r.Handlers.Unmarshal.Clear()
+	r.Handlers.UnmarshalError.Clear()
+	r.HTTPResponse = &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(bytes.NewReader(nil))}
+}
+
+func hasPlaceholderIDs(r *request.Request) bool {
+	for _, id := range findResourceIDs(reflect.ValueOf(r.Params), 0) {
+		if strings.HasPrefix(id, DryRunIDPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fillPlaceholderOutput fills output of an creation with the resource it'd create: resource IDs of output are placeholders,
+// and other fields of the resource are copied from the fields of same names in input, e.g. Name of CreateTargetGroupInput to TargetGroupName.
+func fillPlaceholderOutput(output interface{}, input interface{}, newID func() string) {
+	out := reflect.ValueOf(output)
+	if out.Kind() != reflect.Ptr || out.IsNil() || out.Elem().Kind() != reflect.Struct {
+		return
+	}
+	out = out.Elem()
+	in := reflect.Indirect(reflect.ValueOf(input))
+	stringPtrType := reflect.TypeOf((*string)(nil))
+	for i := 0; i < out.NumField(); i++ {
+		field := out.Field(i)
+		fieldType := out.Type().Field(i)
+		switch {
+		case fieldType.PkgPath != "":
+			continue
+		case isResourceIDField(fieldType.Name) && fieldType.Type == stringPtrType:
+			field.Set(reflect.ValueOf(aws.String(newID())))
+		case fieldType.Type.Kind() == reflect.Slice && isStructPtr(fieldType.Type.Elem()):
+			resource := newPlaceholderResource(fieldType.Type.Elem().Elem(), in, newID)
+			field.Set(reflect.Append(reflect.MakeSlice(fieldType.Type, 0, 1), resource))
+		case isStructPtr(fieldType.Type):
+			field.Set(newPlaceholderResource(fieldType.Type.Elem(), in, newID))
+		}
+	}
+}
+
+func newPlaceholderResource(resourceType reflect.Type, in reflect.Value, newID func() string) reflect.Value {
+	resource := reflect.New(resourceType)
+	// the primary ID of resource is named after its type, e.g. TargetGroupArn of TargetGroup.
+	idFields := map[string]bool{resourceType.Name() + "Arn": true, resourceType.Name() + "Id": true}
+	nameField := resourceType.Name() + "Name"
+	for i := 0; i < resourceType.NumField(); i++ {
+		field := resourceType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		var value reflect.Value
+		switch {
+		case idFields[field.Name]:
+			value = reflect.ValueOf(aws.String(newID()))
+		case in.IsValid() && in.Kind() == reflect.Struct:
+			value = in.FieldByName(field.Name)
+			if !value.IsValid() && field.Name == nameField {
+				value = in.FieldByName("Name")
+			}
+		}
+		if value.IsValid() && value.Type() == field.Type {
+			resource.Elem().Field(i).Set(value)
+		}
+	}
+	return resource
+}
+
+func isStructPtr(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
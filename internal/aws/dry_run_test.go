@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_skipDryRunRequest(t *testing.T) {
+	awsSession := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-west-2"),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		// requests must never be sent in dry-run
+		Endpoint:   aws.String("http://127.0.0.1:1"),
+		MaxRetries: aws.Int(0),
+	}))
+	awsSession.Handlers.Validate.PushBack(skipDryRunRequest)
+	client := elbv2.New(awsSession)
+	ctx, plan := WithDryRun(context.Background())
+
+	output, err := client.ModifyListenerWithContext(ctx, &elbv2.ModifyListenerInput{ListenerArn: aws.String("listener-arn")})
+	assert.NoError(t, err)
+	assert.Empty(t, output.Listeners)
+	assert.False(t, plan.Incomplete())
+
+	_, err = client.DescribeListenersWithContext(ctx, &elbv2.DescribeListenersInput{ListenerArns: aws.StringSlice([]string{"listener-arn"})})
+	assert.Error(t, err)
+
+	createOutput, err := client.CreateTargetGroupWithContext(ctx, &elbv2.CreateTargetGroupInput{
+		Name:     aws.String("tg-name"),
+		Port:     aws.Int64(8080),
+		Protocol: aws.String("HTTP"),
+		VpcId:    aws.String("vpc-id"),
+	})
+	assert.NoError(t, err)
+	assert.True(t, plan.Incomplete())
+	if assert.Len(t, createOutput.TargetGroups, 1) {
+		tg := createOutput.TargetGroups[0]
+		assert.Equal(t, "dry-run-targetgroup-1", aws.StringValue(tg.TargetGroupArn))
+		assert.Equal(t, "tg-name", aws.StringValue(tg.TargetGroupName))
+		assert.Equal(t, int64(8080), aws.Int64Value(tg.Port))
+		assert.Equal(t, "vpc-id", aws.StringValue(tg.VpcId))
+	}
+
+	// reads of resources planned to be created are not sent
+	describeOutput, err := client.DescribeTargetGroupsWithContext(ctx, &elbv2.DescribeTargetGroupsInput{TargetGroupArns: []*string{createOutput.TargetGroups[0].TargetGroupArn}})
+	assert.NoError(t, err)
+	assert.Empty(t, describeOutput.TargetGroups)
+
+	changes := plan.PlannedChanges()
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "ModifyListener", changes[0].Operation)
+	assert.Equal(t, "Listener", changes[0].Resource())
+	assert.Equal(t, "CreateTargetGroup", changes[1].Operation)
+	assert.Equal(t, "TargetGroup", changes[1].Resource())
+	assert.Contains(t, plan.Changes()[1], "elasticloadbalancing/CreateTargetGroup")
+}
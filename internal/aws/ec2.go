@@ -23,10 +23,16 @@ const (
 	TagNameSubnetPublicELB   = "kubernetes.io/role/elb"
 )
 
+// errCodeDuplicateSecurityGroup is the code of the error creating an securityGroup whose name is already used in vpc.
+const errCodeDuplicateSecurityGroup = "InvalidGroup.Duplicate"
+
 // EC2API is our wrapper EC2 API interface
 type EC2API interface {
 	GetSubnetsByNameOrID(context.Context, []string) ([]*ec2.Subnet, error)
 
+	// GetSubnetsByTagFilters retrieves subnets within vpc that matches all tagFilters, a tag matches if its value is any of the values in filter.
+	GetSubnetsByTagFilters(context.Context, map[string][]string) ([]*ec2.Subnet, error)
+
 	// GetVPCID returns the VPC of the instance the controller is currently running on.
 	// This is achieved by getting the identity document of the EC2 instance and using
 	// the DescribeInstances call to determine its VPC ID.
@@ -36,6 +42,9 @@ type EC2API interface {
 	// StatusEC2 validates EC2 connectivity
 	StatusEC2() func() error
 
+	// StatusVPCEndpoints validates the VPC of the cluster has available interface VPC endpoints of services
+	StatusVPCEndpoints(services []string) func() error
+
 	// IsNodeHealthy returns true if the node is ready
 	IsNodeHealthy(string) (bool, error)
 
@@ -49,6 +58,12 @@ type EC2API interface {
 	GetSecurityGroupByName(string, string) (*ec2.SecurityGroup, error)
 	GetSecurityGroupsByName(context.Context, []string) ([]*ec2.SecurityGroup, error)
 
+	// GetSecurityGroupsReferencingGroups retrieves securityGroups within vpc that have inbound rules from any of groupIDs
+	GetSecurityGroupsReferencingGroups(context.Context, []string) ([]*ec2.SecurityGroup, error)
+
+	// GetBranchNetworkInterfacesByPrivateIPs retrieves branch ENIs(used by pods with securityGroups) within vpc that own any of private IPs
+	GetBranchNetworkInterfacesByPrivateIPs(context.Context, []string) ([]*ec2.NetworkInterface, error)
+
 	// DeleteSecurityGroupByID delete securityGroup by securityGroupID
 	DeleteSecurityGroupByID(string) error
 
@@ -57,13 +72,36 @@ type EC2API interface {
 	AuthorizeSecurityGroupIngressWithContext(context.Context, *ec2.AuthorizeSecurityGroupIngressInput) (*ec2.AuthorizeSecurityGroupIngressOutput, error)
 	CreateTagsWithContext(context.Context, *ec2.CreateTagsInput) (*ec2.CreateTagsOutput, error)
 	RevokeSecurityGroupIngressWithContext(context.Context, *ec2.RevokeSecurityGroupIngressInput) (*ec2.RevokeSecurityGroupIngressOutput, error)
+	UpdateSecurityGroupRuleDescriptionsIngressWithContext(context.Context, *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput) (*ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput, error)
 }
 
 func (c *Cloud) ModifyNetworkInterfaceAttributeWithContext(ctx context.Context, i *ec2.ModifyNetworkInterfaceAttributeInput) (*ec2.ModifyNetworkInterfaceAttributeOutput, error) {
 	return c.ec2.ModifyNetworkInterfaceAttributeWithContext(ctx, i)
 }
+
+// CreateSecurityGroupWithContext creates an securityGroup, an securityGroup with the same name in vpc is returned instead of an duplicate error,
+// since EC2 has no client token for it and the request may be retried after an earlier attempt that timed out created it.
 func (c *Cloud) CreateSecurityGroupWithContext(ctx context.Context, i *ec2.CreateSecurityGroupInput) (*ec2.CreateSecurityGroupOutput, error) {
-	return c.ec2.CreateSecurityGroupWithContext(ctx, i)
+	o, err := c.ec2.CreateSecurityGroupWithContext(ctx, i)
+	if awsErr, ok := err.(awserr.Error); !ok || awsErr.Code() != errCodeDuplicateSecurityGroup {
+		return o, err
+	}
+	describeOutput, describeErr := c.ec2.DescribeSecurityGroupsWithContext(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{i.VpcId},
+			},
+			{
+				Name:   aws.String("group-name"),
+				Values: []*string{i.GroupName},
+			},
+		},
+	})
+	if describeErr != nil || len(describeOutput.SecurityGroups) == 0 {
+		return o, err
+	}
+	return &ec2.CreateSecurityGroupOutput{GroupId: describeOutput.SecurityGroups[0].GroupId}, nil
 }
 func (c *Cloud) AuthorizeSecurityGroupIngressWithContext(ctx context.Context, i *ec2.AuthorizeSecurityGroupIngressInput) (*ec2.AuthorizeSecurityGroupIngressOutput, error) {
 	return c.ec2.AuthorizeSecurityGroupIngressWithContext(ctx, i)
@@ -74,6 +112,9 @@ func (c *Cloud) CreateTagsWithContext(ctx context.Context, i *ec2.CreateTagsInpu
 func (c *Cloud) RevokeSecurityGroupIngressWithContext(ctx context.Context, i *ec2.RevokeSecurityGroupIngressInput) (*ec2.RevokeSecurityGroupIngressOutput, error) {
 	return c.ec2.RevokeSecurityGroupIngressWithContext(ctx, i)
 }
+func (c *Cloud) UpdateSecurityGroupRuleDescriptionsIngressWithContext(ctx context.Context, i *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput) (*ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput, error) {
+	return c.ec2.UpdateSecurityGroupRuleDescriptionsIngressWithContext(ctx, i)
+}
 
 func (c *Cloud) GetSubnetsByNameOrID(ctx context.Context, nameOrIDs []string) (subnets []*ec2.Subnet, err error) {
 	vpcID, err := c.GetVPCID()
@@ -130,6 +171,32 @@ func (c *Cloud) GetSubnetsByNameOrID(ctx context.Context, nameOrIDs []string) (s
 	return
 }
 
+func (c *Cloud) GetSubnetsByTagFilters(ctx context.Context, tagFilters map[string][]string) ([]*ec2.Subnet, error) {
+	vpcID, err := c.GetVPCID()
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []*ec2.Filter{
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{vpcID},
+		},
+	}
+	for key, values := range tagFilters {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("tag:" + key),
+			Values: aws.StringSlice(values),
+		})
+	}
+
+	describeSubnetsOutput, err := c.ec2.DescribeSubnetsWithContext(ctx, &ec2.DescribeSubnetsInput{Filters: filters})
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch subnets due to %v", err)
+	}
+	return describeSubnetsOutput.Subnets, nil
+}
+
 func (c *Cloud) GetSecurityGroupsByName(ctx context.Context, names []string) (groups []*ec2.SecurityGroup, err error) {
 	vpcID, err := c.GetVPCID()
 	if err != nil {
@@ -147,16 +214,66 @@ func (c *Cloud) GetSecurityGroupsByName(ctx context.Context, names []string) (gr
 		},
 	}}
 
-	describeSecurityGroupsOutput, err := c.ec2.DescribeSecurityGroupsWithContext(ctx, in)
+	securityGroups, err := c.describeSecurityGroupsHelper(ctx, in)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to fetch security groups %v: %v", in.Filters, err)
 	}
+	return securityGroups, nil
+}
 
-	if describeSecurityGroupsOutput == nil {
-		return nil, nil
+func (c *Cloud) GetSecurityGroupsReferencingGroups(ctx context.Context, groupIDs []string) ([]*ec2.SecurityGroup, error) {
+	vpcID, err := c.GetVPCID()
+	if err != nil {
+		return nil, err
 	}
+	return c.describeSecurityGroupsHelper(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: []*string{vpcID},
+			},
+			{
+				Name:   aws.String("ip-permission.group-id"),
+				Values: aws.StringSlice(groupIDs),
+			},
+		},
+	})
+}
 
-	return describeSecurityGroupsOutput.SecurityGroups, nil
+func (c *Cloud) GetBranchNetworkInterfacesByPrivateIPs(ctx context.Context, privateIPs []string) ([]*ec2.NetworkInterface, error) {
+	vpcID, err := c.GetVPCID()
+	if err != nil {
+		return nil, err
+	}
+	var result []*ec2.NetworkInterface
+	// DescribeNetworkInterfaces accepts at most 200 values per filter.
+	for start := 0; start < len(privateIPs); start += 200 {
+		end := start + 200
+		if end > len(privateIPs) {
+			end = len(privateIPs)
+		}
+		enis, err := c.describeNetworkInterfacesHelper(ctx, &ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("vpc-id"),
+					Values: []*string{vpcID},
+				},
+				{
+					Name:   aws.String("interface-type"),
+					Values: aws.StringSlice([]string{"branch"}),
+				},
+				{
+					Name:   aws.String("addresses.private-ip-address"),
+					Values: aws.StringSlice(privateIPs[start:end]),
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, enis...)
+	}
+	return result, nil
 }
 
 func (c *Cloud) GetInstancesByIDs(instanceIDs []string) ([]*ec2.Instance, error) {
@@ -174,7 +291,7 @@ func (c *Cloud) GetInstancesByIDs(instanceIDs []string) ([]*ec2.Instance, error)
 }
 
 func (c *Cloud) GetSecurityGroupByID(groupID string) (*ec2.SecurityGroup, error) {
-	securityGroups, err := c.describeSecurityGroupsHelper(&ec2.DescribeSecurityGroupsInput{
+	securityGroups, err := c.describeSecurityGroupsHelper(aws.BackgroundContext(), &ec2.DescribeSecurityGroupsInput{
 		GroupIds: []*string{aws.String(groupID)},
 	})
 	if err != nil {
@@ -187,7 +304,7 @@ func (c *Cloud) GetSecurityGroupByID(groupID string) (*ec2.SecurityGroup, error)
 }
 
 func (c *Cloud) GetSecurityGroupByName(vpcID string, groupName string) (*ec2.SecurityGroup, error) {
-	securityGroups, err := c.describeSecurityGroupsHelper(&ec2.DescribeSecurityGroupsInput{
+	securityGroups, err := c.describeSecurityGroupsHelper(aws.BackgroundContext(), &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{
 			{
 				Name:   aws.String("vpc-id"),
@@ -225,11 +342,12 @@ func (c *Cloud) DeleteSecurityGroupByID(groupID string) error {
 }
 
 // describeSecurityGroups is an helper to handle pagination for DescribeSecurityGroups API call
-func (c *Cloud) describeSecurityGroupsHelper(params *ec2.DescribeSecurityGroupsInput) (results []*ec2.SecurityGroup, err error) {
+func (c *Cloud) describeSecurityGroupsHelper(ctx context.Context, params *ec2.DescribeSecurityGroupsInput) (results []*ec2.SecurityGroup, err error) {
 	p := request.Pagination{
 		EndPageOnSameToken: true,
 		NewRequest: func() (*request.Request, error) {
 			req, _ := c.ec2.DescribeSecurityGroupsRequest(params)
+			req.SetContext(ctx)
 			return req, nil
 		},
 	}
@@ -241,6 +359,24 @@ func (c *Cloud) describeSecurityGroupsHelper(params *ec2.DescribeSecurityGroupsI
 	return results, err
 }
 
+// describeNetworkInterfacesHelper is an helper to handle pagination for DescribeNetworkInterfaces API call
+func (c *Cloud) describeNetworkInterfacesHelper(ctx context.Context, params *ec2.DescribeNetworkInterfacesInput) (results []*ec2.NetworkInterface, err error) {
+	p := request.Pagination{
+		EndPageOnSameToken: true,
+		NewRequest: func() (*request.Request, error) {
+			req, _ := c.ec2.DescribeNetworkInterfacesRequest(params)
+			req.SetContext(ctx)
+			return req, nil
+		},
+	}
+	for p.Next() {
+		page := p.Page().(*ec2.DescribeNetworkInterfacesOutput)
+		results = append(results, page.NetworkInterfaces...)
+	}
+	err = p.Err()
+	return results, err
+}
+
 func (c *Cloud) describeInstancesHelper(params *ec2.DescribeInstancesInput) (result []*ec2.Reservation, err error) {
 	err = c.ec2.DescribeInstancesPages(params, func(output *ec2.DescribeInstancesOutput, _ bool) bool {
 		result = append(result, output.Reservations...)
@@ -249,12 +385,16 @@ func (c *Cloud) describeInstancesHelper(params *ec2.DescribeInstancesInput) (res
 	return result, err
 }
 
-// GetVPCID returns the VPC of the instance the controller is currently running on.
+// GetVPCID returns the VPC of the instance the controller is currently running on, unless it's specified.
 // This is achieved by getting the identity document of the EC2 instance and using
 // the DescribeInstances call to determine its VPC ID.
 func (c *Cloud) GetVPCID() (*string, error) {
 	var vpc *string
 
+	if c.vpcID != "" {
+		return aws.String(c.vpcID), nil
+	}
+
 	if v := os.Getenv("AWS_VPC_ID"); v != "" {
 		return &v, nil
 	}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	"github.com/stretchr/testify/assert"
@@ -83,6 +84,27 @@ func TestCloud_CreateSecurityGroupWithContext(t *testing.T) {
 		assert.Equal(t, b, e)
 		svc.AssertExpectations(t)
 	})
+	t.Run("duplicate", func(t *testing.T) {
+		ctx := context.Background()
+		svc := &mocks.EC2API{}
+
+		i := &ec2.CreateSecurityGroupInput{VpcId: aws.String("vpc-id"), GroupName: aws.String("sg-name")}
+		svc.On("CreateSecurityGroupWithContext", ctx, i).Return(nil, awserr.New("InvalidGroup.Duplicate", "duplicate", nil))
+		svc.On("DescribeSecurityGroupsWithContext", ctx, &ec2.DescribeSecurityGroupsInput{
+			Filters: []*ec2.Filter{
+				{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{"vpc-id"})},
+				{Name: aws.String("group-name"), Values: aws.StringSlice([]string{"sg-name"})},
+			},
+		}).Return(&ec2.DescribeSecurityGroupsOutput{SecurityGroups: []*ec2.SecurityGroup{{GroupId: aws.String("sg-id")}}}, nil)
+		cloud := &Cloud{
+			ec2: svc,
+		}
+
+		a, b := cloud.CreateSecurityGroupWithContext(ctx, i)
+		assert.Equal(t, &ec2.CreateSecurityGroupOutput{GroupId: aws.String("sg-id")}, a)
+		assert.NoError(t, b)
+		svc.AssertExpectations(t)
+	})
 }
 
 func TestCloud_AuthorizeSecurityGroupIngressWithContext(t *testing.T) {
@@ -1,6 +1,28 @@
 package aws
 
-import "github.com/aws/aws-sdk-go/aws/ec2metadata"
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/golang/glog"
+)
+
+const (
+	imdsTokenHeader    = "X-aws-ec2-metadata-token"
+	imdsTokenTTLHeader = "X-aws-ec2-metadata-token-ttl-seconds"
+	// imdsTokenTTL is how long IMDSv2 tokens are valid, they're fetched again a minute before they expire
+	imdsTokenTTL = 6 * time.Hour
+	// imdsTokenTimeout bounds fetching an token, whose response doesn't reach pods if the hop limit of the instance is too low
+	imdsTokenTimeout = 2 * time.Second
+	// imdsV1Fallback is how long IMDSv1 is used once tokens can't be fetched, before they're tried again
+	imdsV1Fallback = 5 * time.Minute
+)
 
 type EC2MetadataAPI interface {
 	GetInstanceIdentityDocument() (ec2metadata.EC2InstanceIdentityDocument, error)
@@ -9,3 +31,83 @@ type EC2MetadataAPI interface {
 func (c *Cloud) GetInstanceIdentityDocument() (ec2metadata.EC2InstanceIdentityDocument, error) {
 	return c.ec2metadata.GetInstanceIdentityDocument()
 }
+
+// newEC2Metadata returns an EC2 metadata client using IMDSv2 session tokens, which the vendored SDK predates,
+// since IMDSv1 is disabled on hardened instances.
+func newEC2Metadata(client *ec2metadata.EC2Metadata) *ec2metadata.EC2Metadata {
+	tokens := newIMDSTokens(client.Endpoint)
+	client.Handlers.Build.PushBack(tokens.addToken)
+	return client
+}
+
+// imdsTokens fetches the IMDSv2 tokens of metadata requests, falling back to IMDSv1 if they can't be fetched,
+// e.g. from instances without IMDSv2.
+type imdsTokens struct {
+	endpoint   string
+	httpClient *http.Client
+	now        func() time.Time
+
+	// mutex protects token, the latest token fetched valid until expiresAt, and fallbackUntil, the time until which IMDSv1 is used
+	mutex         sync.Mutex
+	token         string
+	expiresAt     time.Time
+	fallbackUntil time.Time
+}
+
+func newIMDSTokens(endpoint string) *imdsTokens {
+	return &imdsTokens{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: imdsTokenTimeout},
+		now:        time.Now,
+	}
+}
+
+// addToken adds the IMDSv2 token to metadata request r, if one can be fetched.
+func (t *imdsTokens) addToken(r *request.Request) {
+	if token := t.get(); token != "" {
+		r.HTTPRequest.Header.Set(imdsTokenHeader, token)
+	}
+}
+
+func (t *imdsTokens) get() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	now := t.now()
+	if t.token != "" && now.Add(time.Minute).Before(t.expiresAt) {
+		return t.token
+	}
+	if now.Before(t.fallbackUntil) {
+		return ""
+	}
+	token, err := t.fetch()
+	if err != nil {
+		glog.Warningf("falling back to IMDSv1 for %v since IMDSv2 token couldn't be fetched due to %v", imdsV1Fallback, err)
+		t.token = ""
+		t.fallbackUntil = now.Add(imdsV1Fallback)
+		return ""
+	}
+	t.token = token
+	t.expiresAt = now.Add(imdsTokenTTL)
+	return token
+}
+
+func (t *imdsTokens) fetch() (string, error) {
+	req, err := http.NewRequest(http.MethodPut, t.endpoint+"/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(imdsTokenTTLHeader, strconv.Itoa(int(imdsTokenTTL.Seconds())))
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	token, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
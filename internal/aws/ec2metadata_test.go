@@ -0,0 +1,64 @@
+package aws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEC2MetadataIMDSv2(t *testing.T) {
+	tokenRequests := 0
+	imdsV2 := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/latest/api/token" && r.Method == http.MethodPut:
+			tokenRequests++
+			if !imdsV2 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			assert.Equal(t, "21600", r.Header.Get(imdsTokenTTLHeader))
+			w.Write([]byte("token"))
+		case r.URL.Path == "/latest/meta-data/instance-id":
+			if imdsV2 && r.Header.Get(imdsTokenHeader) != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("i-0123456789abcdef0"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String("us-west-2")})
+	assert.NoError(t, err)
+	client := newEC2Metadata(ec2metadata.New(sess, &aws.Config{Endpoint: aws.String(server.URL + "/latest")}))
+
+	for i := 0; i < 2; i++ {
+		instanceID, err := client.GetMetadata("instance-id")
+		assert.NoError(t, err)
+		assert.Equal(t, "i-0123456789abcdef0", instanceID)
+	}
+	assert.Equal(t, 1, tokenRequests, "tokens are reused until they expire")
+
+	// without IMDSv2, IMDSv1 is used, and tokens aren't fetched again for a while.
+	tokens := newIMDSTokens(server.URL + "/latest")
+	now := time.Now()
+	tokens.now = func() time.Time { return now }
+	imdsV2 = false
+	assert.Equal(t, "", tokens.get())
+	assert.Equal(t, "", tokens.get())
+	assert.Equal(t, 2, tokenRequests)
+
+	imdsV2 = true
+	now = now.Add(imdsV1Fallback)
+	assert.Equal(t, "token", tokens.get())
+	assert.Equal(t, 3, tokenRequests)
+}
@@ -3,13 +3,24 @@ package aws
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 )
 
+const (
+	// LimitSecurityGroupsPerALB is the limit of securityGroups attached to a LoadBalancer returned by GetAccountLimits.
+	LimitSecurityGroupsPerALB = "security-groups-per-application-load-balancer"
+
+	ec2AttributeMaxSecurityGroupsPerInterface = "vpc-max-security-groups-per-interface"
+)
+
 type ELBV2API interface {
 	StatusELBV2() func() error
 
@@ -18,6 +29,13 @@ type ELBV2API interface {
 	// ListListenersByLoadBalancer gets all listeners for loadbalancer.
 	ListListenersByLoadBalancer(context.Context, string) ([]*elbv2.Listener, error)
 
+	// GetAccountLimits gets the ELBV2 limits of current account, keyed by limit name, e.g. rules-per-application-load-balancer,
+	// and the securityGroups per network interface limit of EC2 as LimitSecurityGroupsPerALB.
+	GetAccountLimits(context.Context) (map[string]int64, error)
+
+	// GetSSLPolicyNames gets the names of SSL policies available to HTTPS listeners.
+	GetSSLPolicyNames(context.Context) ([]string, error)
+
 	// DeleteListenersByArn deletes listener
 	DeleteListenersByArn(context.Context, string) error
 
@@ -55,6 +73,9 @@ type ELBV2API interface {
 	DescribeLoadBalancerAttributesWithContext(context.Context, *elbv2.DescribeLoadBalancerAttributesInput) (*elbv2.DescribeLoadBalancerAttributesOutput, error)
 	ModifyLoadBalancerAttributesWithContext(context.Context, *elbv2.ModifyLoadBalancerAttributesInput) (*elbv2.ModifyLoadBalancerAttributesOutput, error)
 	CreateLoadBalancerWithContext(context.Context, *elbv2.CreateLoadBalancerInput) (*elbv2.CreateLoadBalancerOutput, error)
+
+	// CreateLoadBalancerWithCustomerOwnedIpv4PoolWithContext creates an LoadBalancer on AWS Outposts with addresses from an customer-owned IPv4 pool
+	CreateLoadBalancerWithCustomerOwnedIpv4PoolWithContext(context.Context, *elbv2.CreateLoadBalancerInput, string) (*elbv2.CreateLoadBalancerOutput, error)
 	SetIpAddressTypeWithContext(context.Context, *elbv2.SetIpAddressTypeInput) (*elbv2.SetIpAddressTypeOutput, error)
 	SetSubnetsWithContext(context.Context, *elbv2.SetSubnetsInput) (*elbv2.SetSubnetsOutput, error)
 	DescribeELBV2TagsWithContext(context.Context, *elbv2.DescribeTagsInput) (*elbv2.DescribeTagsOutput, error)
@@ -110,6 +131,29 @@ func (c *Cloud) ModifyLoadBalancerAttributesWithContext(ctx context.Context, i *
 func (c *Cloud) CreateLoadBalancerWithContext(ctx context.Context, i *elbv2.CreateLoadBalancerInput) (*elbv2.CreateLoadBalancerOutput, error) {
 	return c.elbv2.CreateLoadBalancerWithContext(ctx, i)
 }
+func (c *Cloud) CreateLoadBalancerWithCustomerOwnedIpv4PoolWithContext(ctx context.Context, i *elbv2.CreateLoadBalancerInput, pool string) (*elbv2.CreateLoadBalancerOutput, error) {
+	return c.elbv2.CreateLoadBalancerWithContext(ctx, i, withCustomerOwnedIpv4Pool(pool))
+}
+
+// withCustomerOwnedIpv4Pool appends the CustomerOwnedIpv4Pool parameter to the body of an CreateLoadBalancer request,
+// since the parameter isn't modeled by CreateLoadBalancerInput of the SDK version we use.
+func withCustomerOwnedIpv4Pool(pool string) request.Option {
+	return func(r *request.Request) {
+		r.Handlers.Build.PushBack(func(r *request.Request) {
+			if r.Error != nil {
+				return
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				r.Error = awserr.New(request.ErrCodeSerialization, "failed to read CreateLoadBalancer request body", err)
+				return
+			}
+			params := url.Values{"CustomerOwnedIpv4Pool": []string{pool}}
+			r.SetBufferBody(append(body, []byte("&"+params.Encode())...))
+		})
+	}
+}
+
 func (c *Cloud) SetIpAddressTypeWithContext(ctx context.Context, i *elbv2.SetIpAddressTypeInput) (*elbv2.SetIpAddressTypeOutput, error) {
 	return c.elbv2.SetIpAddressTypeWithContext(ctx, i)
 }
@@ -120,22 +164,22 @@ func (c *Cloud) DescribeELBV2TagsWithContext(ctx context.Context, i *elbv2.Descr
 	return c.elbv2.DescribeTagsWithContext(ctx, i)
 }
 
+// GetRules returns all rules of listener, DescribeRules has no paginator in the SDK so pages are followed by NextMarker.
 func (c *Cloud) GetRules(ctx context.Context, listenerArn string) ([]*elbv2.Rule, error) {
 	var rules []*elbv2.Rule
-
-	p := request.Pagination{
-		EndPageOnSameToken: true,
-		NewRequest: func() (*request.Request, error) {
-			req, _ := c.elbv2.DescribeRulesRequest(&elbv2.DescribeRulesInput{ListenerArn: aws.String(listenerArn)})
-			req.SetContext(ctx)
-			return req, nil
-		},
-	}
-	for p.Next() {
-		page := p.Page().(*elbv2.DescribeRulesOutput)
-		rules = append(rules, page.Rules...)
+	input := &elbv2.DescribeRulesInput{ListenerArn: aws.String(listenerArn)}
+	for {
+		resp, err := c.elbv2.DescribeRulesWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, resp.Rules...)
+		if resp.NextMarker == nil {
+			break
+		}
+		input = &elbv2.DescribeRulesInput{ListenerArn: aws.String(listenerArn), Marker: resp.NextMarker}
 	}
-	return rules, p.Err()
+	return rules, nil
 }
 
 // StatusELBV2 validates ELBV2 connectivity
@@ -168,6 +212,66 @@ func (c *Cloud) ListListenersByLoadBalancer(ctx context.Context, lbArn string) (
 	return listeners, nil
 }
 
+func (c *Cloud) GetAccountLimits(ctx context.Context) (map[string]int64, error) {
+	limits := make(map[string]int64)
+	input := &elbv2.DescribeAccountLimitsInput{}
+	for {
+		resp, err := c.elbv2.DescribeAccountLimitsWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, limit := range resp.Limits {
+			max, err := strconv.ParseInt(aws.StringValue(limit.Max), 10, 64)
+			if err != nil {
+				continue
+			}
+			limits[aws.StringValue(limit.Name)] = max
+		}
+		if resp.NextMarker == nil {
+			break
+		}
+		input.Marker = resp.NextMarker
+	}
+
+	// securityGroups of LoadBalancers are limited by the securityGroups per network interface quota of EC2.
+	resp, err := c.ec2.DescribeAccountAttributesWithContext(ctx, &ec2.DescribeAccountAttributesInput{
+		AttributeNames: []*string{aws.String(ec2AttributeMaxSecurityGroupsPerInterface)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range resp.AccountAttributes {
+		if aws.StringValue(attribute.AttributeName) != ec2AttributeMaxSecurityGroupsPerInterface || len(attribute.AttributeValues) == 0 {
+			continue
+		}
+		max, err := strconv.ParseInt(aws.StringValue(attribute.AttributeValues[0].AttributeValue), 10, 64)
+		if err != nil {
+			continue
+		}
+		limits[LimitSecurityGroupsPerALB] = max
+	}
+	return limits, nil
+}
+
+func (c *Cloud) GetSSLPolicyNames(ctx context.Context) ([]string, error) {
+	var names []string
+	input := &elbv2.DescribeSSLPoliciesInput{}
+	for {
+		resp, err := c.elbv2.DescribeSSLPoliciesWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range resp.SslPolicies {
+			names = append(names, aws.StringValue(policy.Name))
+		}
+		if resp.NextMarker == nil {
+			break
+		}
+		input.Marker = resp.NextMarker
+	}
+	return names, nil
+}
+
 func (c *Cloud) DeleteListenersByArn(ctx context.Context, lsArn string) error {
 	_, err := c.elbv2.DeleteListenerWithContext(ctx, &elbv2.DeleteListenerInput{
 		ListenerArn: aws.String(lsArn),
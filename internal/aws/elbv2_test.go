@@ -3,11 +3,14 @@ package aws
 import (
 	"context"
 	"errors"
+	"io/ioutil"
+	"net/http"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
 	"github.com/stretchr/testify/assert"
@@ -52,6 +55,7 @@ func TestCloud_GetRules(t *testing.T) {
 		ListenerArn         string
 		DescribeRulesOutput *elbv2.DescribeRulesOutput
 		DescribeRulesError  error
+		NextPageOutput      *elbv2.DescribeRulesOutput
 		ExpectedRules       []*elbv2.Rule
 		ExpectedError       error
 	}{
@@ -69,6 +73,21 @@ func TestCloud_GetRules(t *testing.T) {
 				{RuleArn: aws.String("some other arn")},
 			},
 		},
+		{
+			Name:        "Rules of all pages are returned",
+			ListenerArn: "arn",
+			DescribeRulesOutput: &elbv2.DescribeRulesOutput{
+				Rules:      []*elbv2.Rule{{RuleArn: aws.String("some arn")}},
+				NextMarker: aws.String("marker"),
+			},
+			NextPageOutput: &elbv2.DescribeRulesOutput{
+				Rules: []*elbv2.Rule{{RuleArn: aws.String("some other arn")}},
+			},
+			ExpectedRules: []*elbv2.Rule{
+				{RuleArn: aws.String("some arn")},
+				{RuleArn: aws.String("some other arn")},
+			},
+		},
 		{
 			Name:               "DescribeRules has an API timeout",
 			ListenerArn:        "arn",
@@ -80,14 +99,19 @@ func TestCloud_GetRules(t *testing.T) {
 			ctx := context.Background()
 			elbv2svc := &mocks.ELBV2API{}
 
-			elbv2svc.On("DescribeRulesRequest",
+			elbv2svc.On("DescribeRulesWithContext", ctx,
 				&elbv2.DescribeRulesInput{
 					ListenerArn: aws.String(tc.ListenerArn),
 				},
-			).Return(
-				newReq(tc.DescribeRulesOutput, tc.DescribeRulesError),
-				nil,
-			)
+			).Return(tc.DescribeRulesOutput, tc.DescribeRulesError)
+			if tc.NextPageOutput != nil {
+				elbv2svc.On("DescribeRulesWithContext", ctx,
+					&elbv2.DescribeRulesInput{
+						ListenerArn: aws.String(tc.ListenerArn),
+						Marker:      tc.DescribeRulesOutput.NextMarker,
+					},
+				).Return(tc.NextPageOutput, nil)
+			}
 			cloud := &Cloud{
 				elbv2: elbv2svc,
 			}
@@ -824,6 +848,40 @@ func TestCloud_CreateLoadBalancerWithContext(t *testing.T) {
 	})
 }
 
+func TestCloud_CreateLoadBalancerWithCustomerOwnedIpv4PoolWithContext(t *testing.T) {
+	t.Run("apiwrapper", func(t *testing.T) {
+		ctx := context.Background()
+		svc := &mocks.ELBV2API{}
+
+		i := &elbv2.CreateLoadBalancerInput{}
+		o := &elbv2.CreateLoadBalancerOutput{}
+		var e error
+
+		svc.On("CreateLoadBalancerWithContext", ctx, i, mock.Anything).Return(o, e)
+		cloud := &Cloud{
+			elbv2: svc,
+		}
+
+		a, b := cloud.CreateLoadBalancerWithCustomerOwnedIpv4PoolWithContext(ctx, i, "ipv4pool-coip-12345678")
+		assert.Equal(t, o, a)
+		assert.Equal(t, b, e)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("pool is appended to request body", func(t *testing.T) {
+		r := &request.Request{HTTPRequest: &http.Request{Header: http.Header{}}}
+		withCustomerOwnedIpv4Pool("ipv4pool-coip-12345678")(r)
+		r.Handlers.Build.PushFront(func(r *request.Request) {
+			r.SetBufferBody([]byte("Action=CreateLoadBalancer&Version=2015-12-01"))
+		})
+		r.Handlers.Build.Run(r)
+
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "Action=CreateLoadBalancer&Version=2015-12-01&CustomerOwnedIpv4Pool=ipv4pool-coip-12345678", string(body))
+	})
+}
+
 func TestCloud_SetIpAddressTypeWithContext(t *testing.T) {
 	t.Run("apiwrapper", func(t *testing.T) {
 		ctx := context.Background()
@@ -886,3 +944,63 @@ func TestCloud_DescribeELBV2TagsWithContext(t *testing.T) {
 		svc.AssertExpectations(t)
 	})
 }
+
+func TestCloud_GetAccountLimits(t *testing.T) {
+	for _, tc := range []struct {
+		Name           string
+		Limits         []*elbv2.Limit
+		Attributes     []*ec2.AccountAttribute
+		ELBV2Error     error
+		EC2Error       error
+		ExpectedLimits map[string]int64
+		ExpectedError  error
+	}{
+		{
+			Name: "ELBV2 and securityGroup limits",
+			Limits: []*elbv2.Limit{
+				{Name: aws.String("rules-per-application-load-balancer"), Max: aws.String("100")},
+				{Name: aws.String("target-groups"), Max: aws.String("unlimited")},
+			},
+			Attributes: []*ec2.AccountAttribute{
+				{
+					AttributeName:   aws.String("vpc-max-security-groups-per-interface"),
+					AttributeValues: []*ec2.AccountAttributeValue{{AttributeValue: aws.String("5")}},
+				},
+			},
+			ExpectedLimits: map[string]int64{
+				"rules-per-application-load-balancer": 100,
+				LimitSecurityGroupsPerALB:             5,
+			},
+		},
+		{
+			Name:          "Error from ELBV2 API call",
+			ELBV2Error:    errors.New("Some API error"),
+			ExpectedError: errors.New("Some API error"),
+		},
+		{
+			Name:          "Error from EC2 API call",
+			EC2Error:      errors.New("Some API error"),
+			ExpectedError: errors.New("Some API error"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			elbv2svc := &mocks.ELBV2API{}
+			elbv2svc.On("DescribeAccountLimitsWithContext", ctx, &elbv2.DescribeAccountLimitsInput{}).Return(&elbv2.DescribeAccountLimitsOutput{Limits: tc.Limits}, tc.ELBV2Error)
+			ec2svc := &mocks.EC2API{}
+			ec2svc.On("DescribeAccountAttributesWithContext", ctx, &ec2.DescribeAccountAttributesInput{
+				AttributeNames: aws.StringSlice([]string{"vpc-max-security-groups-per-interface"}),
+			}).Return(&ec2.DescribeAccountAttributesOutput{AccountAttributes: tc.Attributes}, tc.EC2Error)
+
+			cloud := &Cloud{
+				elbv2: elbv2svc,
+				ec2:   ec2svc,
+			}
+
+			limits, err := cloud.GetAccountLimits(ctx)
+			assert.Equal(t, tc.ExpectedLimits, limits)
+			assert.Equal(t, tc.ExpectedError, err)
+			elbv2svc.AssertExpectations(t)
+		})
+	}
+}
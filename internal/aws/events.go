@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxResourceIDDepth limits how deep resource IDs are searched in the input and output of AWS calls.
+const maxResourceIDDepth = 3
+
+// emitRequestEvent emits an event on the ingress request r was made for, with the resources and request ID of r,
+// so AWS support cases can be opened without controller logs. Requests not made for an ingress are ignored.
+func emitRequestEvent(r *request.Request) {
+	eventf, ok := albctx.LookupEventf(r.Context())
+	if !ok {
+		return
+	}
+	resources := findRequestResourceIDs(r)
+	if r.Error != nil {
+		eventf(corev1.EventTypeWarning, "ERROR", "%s/%s failed for %v, request ID: %s, error: %v",
+			r.ClientInfo.ServiceName, r.Operation.Name, resources, r.RequestID, r.Error)
+		return
+	}
+	eventf(corev1.EventTypeNormal, operationEventReason(r.Operation.Name), "%s/%s succeeded for %v, request ID: %s",
+		r.ClientInfo.ServiceName, r.Operation.Name, resources, r.RequestID)
+}
+
+// operationEventReason returns the reason of events for successful AWS operation, which is consistent with the events emitted by controllers.
+func operationEventReason(operation string) string {
+	switch {
+	case strings.HasPrefix(operation, "Create"):
+		return "CREATE"
+	case strings.HasPrefix(operation, "Delete"):
+		return "DELETE"
+	default:
+		return "MODIFY"
+	}
+}
+
+// findResourceIDs returns the ARNs and IDs of resources found in v, i.e. values of fields named like LoadBalancerArn, ResourceArns or GroupId.
+func findResourceIDs(v reflect.Value, depth int) []string {
+	if depth > maxResourceIDDepth {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return findResourceIDs(v.Elem(), depth)
+	case reflect.Slice:
+		var ids []string
+		for i := 0; i < v.Len(); i++ {
+			ids = append(ids, findResourceIDs(v.Index(i), depth+1)...)
+		}
+		return ids
+	case reflect.Struct:
+		var ids []string
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if isResourceIDField(field.Name) {
+				ids = append(ids, stringValues(v.Field(i))...)
+			} else {
+				ids = append(ids, findResourceIDs(v.Field(i), depth+1)...)
+			}
+		}
+		return ids
+	default:
+		return nil
+	}
+}
+
+func isResourceIDField(name string) bool {
+	return strings.HasSuffix(name, "Arn") || strings.HasSuffix(name, "Arns") || name == "GroupId"
+}
+
+// stringValues returns the non-empty values of string pointer or slice of string pointers v.
+func stringValues(v reflect.Value) []string {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || v.Elem().Kind() != reflect.String || v.Elem().String() == "" {
+			return nil
+		}
+		return []string{v.Elem().String()}
+	case reflect.String:
+		if v.String() == "" {
+			return nil
+		}
+		return []string{v.String()}
+	case reflect.Slice:
+		var values []string
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, stringValues(v.Index(i))...)
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
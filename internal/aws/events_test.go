@@ -0,0 +1,86 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/stretchr/testify/assert"
+)
+
+type event struct {
+	eventType string
+	reason    string
+	message   string
+}
+
+func Test_emitRequestEvent(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		operation     string
+		params        interface{}
+		data          interface{}
+		err           error
+		expectedEvent event
+	}{
+		{
+			name:      "resource ARN from input",
+			operation: "ModifyListener",
+			params:    &elbv2.ModifyListenerInput{ListenerArn: aws.String("listener-arn")},
+			expectedEvent: event{"Normal", "MODIFY",
+				"elasticloadbalancing/ModifyListener succeeded for [listener-arn], request ID: request-id"},
+		},
+		{
+			name:      "resource ARN from output of create",
+			operation: "CreateTargetGroup",
+			params:    &elbv2.CreateTargetGroupInput{Name: aws.String("tg")},
+			data: &elbv2.CreateTargetGroupOutput{TargetGroups: []*elbv2.TargetGroup{
+				{TargetGroupArn: aws.String("tg-arn"), LoadBalancerArns: aws.StringSlice([]string{"lb-arn"})},
+			}},
+			expectedEvent: event{"Normal", "CREATE",
+				"elasticloadbalancing/CreateTargetGroup succeeded for [tg-arn lb-arn], request ID: request-id"},
+		},
+		{
+			name:      "failed request",
+			operation: "DeleteRule",
+			params:    &elbv2.DeleteRuleInput{RuleArn: aws.String("rule-arn")},
+			err:       errors.New("AccessDenied"),
+			expectedEvent: event{"Warning", "ERROR",
+				"elasticloadbalancing/DeleteRule failed for [rule-arn], request ID: request-id, error: AccessDenied"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var events []event
+			ctx := albctx.SetEventf(context.Background(), func(eventType string, reason string, format string, args ...interface{}) {
+				events = append(events, event{eventType, reason, fmt.Sprintf(format, args...)})
+			})
+			r := &request.Request{
+				ClientInfo: metadata.ClientInfo{ServiceName: elbv2.ServiceName},
+				Operation:  &request.Operation{Name: tc.operation},
+				Params:     tc.params,
+				Data:       tc.data,
+				RequestID:  "request-id",
+				Error:      tc.err,
+			}
+			r.SetContext(ctx)
+			emitRequestEvent(r)
+			assert.Equal(t, []event{tc.expectedEvent}, events)
+		})
+	}
+}
+
+func Test_emitRequestEvent_withoutIngress(t *testing.T) {
+	r := &request.Request{
+		ClientInfo: metadata.ClientInfo{ServiceName: elbv2.ServiceName},
+		Operation:  &request.Operation{Name: "DeleteRule"},
+		Params:     &elbv2.DeleteRuleInput{RuleArn: aws.String("rule-arn")},
+	}
+	r.SetContext(context.Background())
+	assert.NotPanics(t, func() { emitRequestEvent(r) })
+}
@@ -1,14 +1,30 @@
 package aws
 
 import (
+	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/apiserver/pkg/server/healthz"
 )
 
+// HealthChecker checks the AWS APIs used by the controller are reachable with its credentials.
+// The APIs are checked every period by Run, and checks return the result of the latest check,
+// so probes don't call AWS on every request.
 type HealthChecker struct {
 	Cloud CloudAPI
+
+	// VPCEndpointServices are the services the VPC must have interface VPC endpoints of, IAM isn't checked when they're set
+	// since it has no VPC endpoints.
+	VPCEndpointServices []string
+
+	// mutex protects checked, whether the APIs have been checked yet, and err, the result of the latest check
+	mutex   sync.RWMutex
+	checked bool
+	err     error
 }
 
 var _ healthz.HealthzChecker = (*HealthChecker)(nil)
@@ -18,18 +34,52 @@ func (c *HealthChecker) Name() string {
 	return "aws-alb-ingress-controller"
 }
 
-// TODO, validate the call health check frequency
+// Check returns the result of the latest check, it succeeds before the APIs are first checked.
 func (c *HealthChecker) Check(_ *http.Request) error {
-	for _, fn := range []func() error{
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.err
+}
+
+// Readiness returns an checker failing until the APIs are checked successfully, and whenever the latest check failed,
+// so pods with broken credentials or unreachable AWS endpoints are NotReady.
+func (c *HealthChecker) Readiness() healthz.HealthzChecker {
+	return healthz.NamedCheck("aws", func(_ *http.Request) error {
+		c.mutex.RLock()
+		defer c.mutex.RUnlock()
+		if !c.checked {
+			return fmt.Errorf("AWS APIs haven't been checked yet")
+		}
+		return c.err
+	})
+}
+
+// Run checks the APIs every period until stopCh is closed.
+func (c *HealthChecker) Run(period time.Duration, stopCh <-chan struct{}) {
+	wait.Until(c.check, period, stopCh)
+}
+
+func (c *HealthChecker) check() {
+	var err error
+	checks := []func() error{
 		c.Cloud.StatusACM(),
 		c.Cloud.StatusEC2(),
-		c.Cloud.StatusIAM(),
-	} {
-		err := fn()
-		if err != nil {
+		c.Cloud.StatusELBV2(),
+	}
+	if len(c.VPCEndpointServices) != 0 {
+		// the endpoints are checked first, so their absence is reported rather than the timeouts of the other APIs.
+		checks = append([]func() error{c.Cloud.StatusVPCEndpoints(c.VPCEndpointServices)}, checks...)
+	} else {
+		checks = append(checks, c.Cloud.StatusIAM())
+	}
+	for _, fn := range checks {
+		if err = fn(); err != nil {
 			glog.Errorf("Controller health check failed: %v", err.Error())
-			return err
+			break
 		}
 	}
-	return nil
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.checked = true
+	c.err = err
 }
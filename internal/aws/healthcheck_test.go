@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthChecker(t *testing.T) {
+	var elbv2Err error
+	cloud := &mocks.CloudAPI{}
+	cloud.On("StatusACM").Return(func() error { return nil })
+	cloud.On("StatusEC2").Return(func() error { return nil })
+	cloud.On("StatusELBV2").Return(func() error { return elbv2Err })
+	cloud.On("StatusIAM").Return(func() error { return nil })
+	checker := &HealthChecker{Cloud: cloud}
+	readiness := checker.Readiness()
+
+	assert.NoError(t, checker.Check(nil))
+	assert.Error(t, readiness.Check(nil), "not ready until checked")
+
+	checker.check()
+	assert.NoError(t, checker.Check(nil))
+	assert.NoError(t, readiness.Check(nil))
+
+	elbv2Err = errors.New("[elbv2.DescribeLoadBalancersWithContext]: AccessDenied")
+	checker.check()
+	assert.Equal(t, elbv2Err, checker.Check(nil))
+	assert.Equal(t, elbv2Err, readiness.Check(nil))
+}
+
+func TestHealthCheckerVPCEndpoints(t *testing.T) {
+	vpcEndpointsErr := errors.New("interface VPC endpoints of acm, sts are missing in vpc-0123456789abcdef0, they're required by --aws-vpc-endpoints-only")
+	cloud := &mocks.CloudAPI{}
+	cloud.On("StatusVPCEndpoints", VPCEndpointServices).Return(func() error { return vpcEndpointsErr })
+	cloud.On("StatusACM").Return(func() error { return nil })
+	cloud.On("StatusEC2").Return(func() error { return nil })
+	cloud.On("StatusELBV2").Return(func() error { return nil })
+	checker := &HealthChecker{Cloud: cloud, VPCEndpointServices: VPCEndpointServices}
+	readiness := checker.Readiness()
+
+	checker.check()
+	assert.Equal(t, vpcEndpointsErr, readiness.Check(nil))
+
+	vpcEndpointsErr = nil
+	checker.check()
+	assert.NoError(t, readiness.Check(nil))
+	cloud.AssertNotCalled(t, "StatusIAM")
+}
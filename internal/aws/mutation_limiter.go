@@ -0,0 +1,141 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// errCodeMutationLimiterCanceled is the code of the error of requests canceled while waiting for the MutationLimiter.
+const errCodeMutationLimiterCanceled = "MutationLimiterCanceled"
+
+// lbIDPattern matches the ID of the LoadBalancer in the ARNs of LoadBalancers, listeners and rules, e.g. app/my-lb/50dc6c495c0c9188.
+var lbIDPattern = regexp.MustCompile(`:(?:loadbalancer|listener|listener-rule)/((?:app|net)/[^/]+/[^/]+)`)
+
+// MutationLimiter limits the number of concurrent requests mutating AWS resources, both globally and per LoadBalancer,
+// so parallel reconciles don't exceed the API rate limits of the account or conflict changing the listeners of an LoadBalancer.
+// Requests hold their slots while they're retried, and release them when they're complete.
+type MutationLimiter struct {
+	global chan struct{}
+
+	perLB int
+	// mutex protects lbs, the semaphores of LoadBalancers by ID, which are removed once no request holds them
+	mutex sync.Mutex
+	lbs   map[string]*lbSemaphore
+}
+
+type lbSemaphore struct {
+	slots   chan struct{}
+	holders int
+}
+
+// NewMutationLimiter constructs an MutationLimiter allowing global concurrent mutations, and perLB concurrent mutations of an LoadBalancer,
+// with 0 meaning unlimited. It returns nil if neither is limited.
+func NewMutationLimiter(global int, perLB int) *MutationLimiter {
+	if global <= 0 && perLB <= 0 {
+		return nil
+	}
+	limiter := &MutationLimiter{perLB: perLB, lbs: make(map[string]*lbSemaphore)}
+	if global > 0 {
+		limiter.global = make(chan struct{}, global)
+	}
+	return limiter
+}
+
+// mutationLimiterSlots are the slots acquired for an request.
+type mutationLimiterSlots struct {
+	global bool
+	lbID   string
+}
+
+// mutationLimiterSlotsKey is the key of the slots acquired for an request in its context.
+type mutationLimiterSlotsKey struct{}
+
+// acquire waits for the slots of mutating request r, it's an Build handler so it runs once per request.
+// Requests skipped in dry-run are never sent, so they don't wait.
+func (l *MutationLimiter) acquire(r *request.Request) {
+	if !isMutatingOperation(r.Operation.Name) || getDryRunPlan(r.Context()) != nil {
+		return
+	}
+	slots := &mutationLimiterSlots{}
+	if l.global != nil {
+		select {
+		case l.global <- struct{}{}:
+			slots.global = true
+		case <-r.Context().Done():
+			r.Error = awserr.New(errCodeMutationLimiterCanceled, "canceled while waiting for concurrent AWS mutations", r.Context().Err())
+			return
+		}
+	}
+	if lbID := requestLBID(r); l.perLB > 0 && lbID != "" {
+		semaphore := l.holdLB(lbID)
+		select {
+		case semaphore.slots <- struct{}{}:
+			slots.lbID = lbID
+		case <-r.Context().Done():
+			l.unholdLB(lbID)
+			l.releaseSlots(slots)
+			r.Error = awserr.New(errCodeMutationLimiterCanceled, "canceled while waiting for concurrent AWS mutations of "+lbID, r.Context().Err())
+			return
+		}
+	}
+	r.SetContext(context.WithValue(r.Context(), mutationLimiterSlotsKey{}, slots))
+}
+
+// release releases the slots acquired for request r, it's an Complete handler.
+func (l *MutationLimiter) release(r *request.Request) {
+	slots, ok := r.Context().Value(mutationLimiterSlotsKey{}).(*mutationLimiterSlots)
+	if !ok {
+		return
+	}
+	l.releaseSlots(slots)
+}
+
+func (l *MutationLimiter) releaseSlots(slots *mutationLimiterSlots) {
+	if slots.lbID != "" {
+		l.mutex.Lock()
+		<-l.lbs[slots.lbID].slots
+		l.mutex.Unlock()
+		l.unholdLB(slots.lbID)
+	}
+	if slots.global {
+		<-l.global
+	}
+}
+
+func (l *MutationLimiter) holdLB(lbID string) *lbSemaphore {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	semaphore, ok := l.lbs[lbID]
+	if !ok {
+		semaphore = &lbSemaphore{slots: make(chan struct{}, l.perLB)}
+		l.lbs[lbID] = semaphore
+	}
+	semaphore.holders++
+	return semaphore
+}
+
+func (l *MutationLimiter) unholdLB(lbID string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	semaphore := l.lbs[lbID]
+	semaphore.holders--
+	if semaphore.holders == 0 {
+		delete(l.lbs, lbID)
+	}
+}
+
+// requestLBID returns the ID of the LoadBalancer whose ARN, or the ARN of whose listeners or rules, is in the input of request r.
+// It's empty for requests not specific to an LoadBalancer, e.g. modifying targetGroups.
+func requestLBID(r *request.Request) string {
+	for _, id := range findResourceIDs(reflect.ValueOf(r.Params), 0) {
+		if match := lbIDPattern.FindStringSubmatch(id); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMutationRequest(ctx context.Context, operation string, params interface{}) *request.Request {
+	r := &request.Request{
+		Operation: &request.Operation{Name: operation},
+		Params:    params,
+	}
+	r.SetContext(ctx)
+	return r
+}
+
+func TestNewMutationLimiter(t *testing.T) {
+	assert.Nil(t, NewMutationLimiter(0, 0))
+	assert.NotNil(t, NewMutationLimiter(0, 1))
+}
+
+func TestMutationLimiter(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	lb1Listener := &elbv2.ModifyListenerInput{ListenerArn: aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:listener/app/lb1/50dc6c495c0c9188/f2f7dc8efc522ab2")}
+	lb1Rule := &elbv2.DeleteRuleInput{RuleArn: aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:listener-rule/app/lb1/50dc6c495c0c9188/f2f7dc8efc522ab2/9683b2d02a6cabee")}
+	lb2 := &elbv2.DeleteLoadBalancerInput{LoadBalancerArn: aws.String("arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/lb2/6d0ecf831eec9f09")}
+
+	t.Run("per ALB", func(t *testing.T) {
+		limiter := NewMutationLimiter(0, 1)
+		r1 := newMutationRequest(context.Background(), "ModifyListener", lb1Listener)
+		limiter.acquire(r1)
+		assert.NoError(t, r1.Error)
+
+		// requests for other ALBs and reads aren't limited.
+		r2 := newMutationRequest(canceled, "DeleteLoadBalancer", lb2)
+		limiter.acquire(r2)
+		assert.NoError(t, r2.Error)
+		read := newMutationRequest(canceled, "DescribeRules", &elbv2.DescribeRulesInput{ListenerArn: lb1Listener.ListenerArn})
+		limiter.acquire(read)
+		assert.NoError(t, read.Error)
+
+		r3 := newMutationRequest(canceled, "DeleteRule", lb1Rule)
+		limiter.acquire(r3)
+		assert.Error(t, r3.Error)
+
+		limiter.release(r1)
+		limiter.release(r2)
+		r4 := newMutationRequest(canceled, "DeleteRule", lb1Rule)
+		limiter.acquire(r4)
+		assert.NoError(t, r4.Error)
+		limiter.release(r4)
+		assert.Empty(t, limiter.lbs)
+	})
+
+	t.Run("global", func(t *testing.T) {
+		limiter := NewMutationLimiter(1, 0)
+		r1 := newMutationRequest(context.Background(), "ModifyListener", lb1Listener)
+		limiter.acquire(r1)
+		assert.NoError(t, r1.Error)
+
+		r2 := newMutationRequest(canceled, "DeleteLoadBalancer", lb2)
+		limiter.acquire(r2)
+		assert.Error(t, r2.Error)
+
+		limiter.release(r1)
+		r3 := newMutationRequest(canceled, "DeleteLoadBalancer", lb2)
+		limiter.acquire(r3)
+		assert.NoError(t, r3.Error)
+	})
+}
@@ -14,14 +14,19 @@ import (
 )
 
 const (
-	ResourceTypeEnumELBTargetGroup = "elasticloadbalancing:targetgroup"
+	ResourceTypeEnumELBTargetGroup  = "elasticloadbalancing:targetgroup"
+	ResourceTypeEnumELBLoadBalancer = "elasticloadbalancing:loadbalancer"
 )
 
 type ResourceGroupsTaggingAPIAPI interface {
+	// GetClusterSubnets fetches the tags of subnets tagged for the cluster, keyed by subnet ID
 	GetClusterSubnets() (map[string]util.EC2Tags, error)
 
-	// GetResourcesByFilters fetches resources ARNs by tagFilters and 0 or more resourceTypesFilters
-	GetResourcesByFilters(tagFilters map[string][]string, resourceTypeFilters ...string) ([]string, error)
+	// GetResourcesByFilters fetches resources ARNs by tagFilters and 0 or more resourceTypesFilters, with the role of ctx if any
+	GetResourcesByFilters(ctx context.Context, tagFilters map[string][]string, resourceTypeFilters ...string) ([]string, error)
+
+	// GetResourceTagsByFilters fetches the tags of resources by tagFilters and 0 or more resourceTypesFilters, keyed by resource ARN
+	GetResourceTagsByFilters(tagFilters map[string][]string, resourceTypeFilters ...string) (map[string]map[string]string, error)
 
 	TagResourcesWithContext(context.Context, *resourcegroupstaggingapi.TagResourcesInput) (*resourcegroupstaggingapi.TagResourcesOutput, error)
 	UntagResourcesWithContext(context.Context, *resourcegroupstaggingapi.UntagResourcesInput) (*resourcegroupstaggingapi.UntagResourcesOutput, error)
@@ -35,6 +40,8 @@ func (c *Cloud) UntagResourcesWithContext(ctx context.Context, i *resourcegroups
 }
 
 // GetClusterSubnets looks up all subnets in AWS that are tagged for the cluster.
+// Subnets of a shared VPC owned by another account, e.g. shared by AWS RAM, aren't covered by the tagging API of the account,
+// so subnets of the cluster's VPC tagged in the account are looked up by EC2 too.
 func (c *Cloud) GetClusterSubnets() (map[string]util.EC2Tags, error) {
 	subnets := make(map[string]util.EC2Tags)
 
@@ -50,7 +57,7 @@ func (c *Cloud) GetClusterSubnets() (map[string]util.EC2Tags, error) {
 					Values: []*string{aws.String(""), aws.String("1")},
 				},
 				{
-					Key:    aws.String("kubernetes.io/cluster/" + c.clusterName),
+					Key:    aws.String(c.clusterTagKey),
 					Values: []*string{aws.String("owned"), aws.String("shared")},
 				},
 			},
@@ -66,7 +73,7 @@ func (c *Cloud) GetClusterSubnets() (map[string]util.EC2Tags, error) {
 					Values: []*string{aws.String(""), aws.String("1")},
 				},
 				{
-					Key:    aws.String("kubernetes.io/cluster/" + c.clusterName),
+					Key:    aws.String(c.clusterTagKey),
 					Values: []*string{aws.String("owned"), aws.String("shared")},
 				},
 			},
@@ -81,7 +88,8 @@ func (c *Cloud) GetClusterSubnets() (map[string]util.EC2Tags, error) {
 			for _, rtm := range page.ResourceTagMappingList {
 				switch {
 				case strings.Contains(*rtm.ResourceARN, ":subnet/"):
-					subnets[*rtm.ResourceARN] = rgtTagAsEC2Tag(rtm.Tags)
+					p := strings.Split(*rtm.ResourceARN, "/")
+					subnets[p[len(p)-1]] = rgtTagAsEC2Tag(rtm.Tags)
 				}
 			}
 			return true
@@ -91,6 +99,29 @@ func (c *Cloud) GetClusterSubnets() (map[string]util.EC2Tags, error) {
 		}
 	}
 
+	vpcID, err := c.GetVPCID()
+	if err != nil {
+		return nil, err
+	}
+	o, err := c.ec2.DescribeSubnets(&ec2.DescribeSubnetsInput{Filters: []*ec2.Filter{
+		{
+			Name:   aws.String("vpc-id"),
+			Values: []*string{vpcID},
+		},
+		{
+			Name:   aws.String("tag:" + c.clusterTagKey),
+			Values: []*string{aws.String("owned"), aws.String("shared")},
+		},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	for _, subnet := range o.Subnets {
+		if _, ok := subnets[aws.StringValue(subnet.SubnetId)]; !ok {
+			subnets[aws.StringValue(subnet.SubnetId)] = subnet.Tags
+		}
+	}
+
 	return subnets, nil
 }
 
@@ -104,7 +135,7 @@ func rgtTagAsEC2Tag(in []*resourcegroupstaggingapi.Tag) (tags util.EC2Tags) {
 	return tags
 }
 
-func (c *Cloud) GetResourcesByFilters(tagFilters map[string][]string, resourceTypeFilters ...string) ([]string, error) {
+func (c *Cloud) GetResourcesByFilters(ctx context.Context, tagFilters map[string][]string, resourceTypeFilters ...string) ([]string, error) {
 	var awsTagFilters []*resourcegroupstaggingapi.TagFilter
 	for k, v := range tagFilters {
 		awsTagFilters = append(awsTagFilters, &resourcegroupstaggingapi.TagFilter{
@@ -118,7 +149,7 @@ func (c *Cloud) GetResourcesByFilters(tagFilters map[string][]string, resourceTy
 	}
 
 	var result []string
-	err := c.rgt.GetResourcesPages(req, func(output *resourcegroupstaggingapi.GetResourcesOutput, b bool) bool {
+	err := c.rgt.GetResourcesPagesWithContext(ctx, req, func(output *resourcegroupstaggingapi.GetResourcesOutput, b bool) bool {
 		if output == nil {
 			return false
 		}
@@ -129,3 +160,33 @@ func (c *Cloud) GetResourcesByFilters(tagFilters map[string][]string, resourceTy
 	})
 	return result, err
 }
+
+func (c *Cloud) GetResourceTagsByFilters(tagFilters map[string][]string, resourceTypeFilters ...string) (map[string]map[string]string, error) {
+	var awsTagFilters []*resourcegroupstaggingapi.TagFilter
+	for k, v := range tagFilters {
+		awsTagFilters = append(awsTagFilters, &resourcegroupstaggingapi.TagFilter{
+			Key:    aws.String(k),
+			Values: aws.StringSlice(v),
+		})
+	}
+	req := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice(resourceTypeFilters),
+		TagFilters:          awsTagFilters,
+	}
+
+	result := make(map[string]map[string]string)
+	err := c.rgt.GetResourcesPages(req, func(output *resourcegroupstaggingapi.GetResourcesOutput, b bool) bool {
+		if output == nil {
+			return false
+		}
+		for _, i := range output.ResourceTagMappingList {
+			resourceTags := make(map[string]string, len(i.Tags))
+			for _, t := range i.Tags {
+				resourceTags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+			}
+			result[aws.StringValue(i.ResourceARN)] = resourceTags
+		}
+		return true
+	})
+	return result, err
+}
@@ -110,6 +110,7 @@ func TestCloud_GetClusterSubnets(t *testing.T) {
 		GetResourcesOutput *resourcegroupstaggingapi.GetResourcesOutput
 		GetResourcesError  awserr.Error
 		Params             int
+		SharedSubnets      []*ec2.Subnet
 		ExpectedResult     map[string]util.EC2Tags
 		ExpectedError      error
 	}{
@@ -146,9 +147,29 @@ func TestCloud_GetClusterSubnets(t *testing.T) {
 				},
 			},
 			ExpectedResult: map[string]util.EC2Tags{
-				"arn:aws:ec2:region:account-id:subnet/subnet-id1": {ec2Tag("tag1", "val1")},
-				"arn:aws:ec2:region:account-id:subnet/subnet-id2": {ec2Tag("tag2", "val2")},
-				"arn:aws:ec2:region:account-id:subnet/subnet-id3": {ec2Tag("tag3", "val3")},
+				"subnet-id1": {ec2Tag("tag1", "val1")},
+				"subnet-id2": {ec2Tag("tag2", "val2")},
+				"subnet-id3": {ec2Tag("tag3", "val3")},
+			},
+		},
+		{
+			Name:   "Subnets shared by another account returned",
+			Params: 2,
+			GetResourcesOutput: &resourcegroupstaggingapi.GetResourcesOutput{
+				ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+					{
+						ResourceARN: aws.String("arn:aws:ec2:region:account-id:subnet/subnet-id1"),
+						Tags:        tags(tag("tag1", "val1")),
+					},
+				},
+			},
+			SharedSubnets: []*ec2.Subnet{
+				{SubnetId: aws.String("subnet-id1"), Tags: []*ec2.Tag{ec2Tag("tag1", "val1")}},
+				{SubnetId: aws.String("subnet-shared"), Tags: []*ec2.Tag{ec2Tag("kubernetes.io/cluster/"+clusterName, "shared")}},
+			},
+			ExpectedResult: map[string]util.EC2Tags{
+				"subnet-id1":    {ec2Tag("tag1", "val1")},
+				"subnet-shared": {ec2Tag("kubernetes.io/cluster/"+clusterName, "shared")},
 			},
 		},
 		{
@@ -171,14 +192,25 @@ func TestCloud_GetClusterSubnets(t *testing.T) {
 				})
 			}
 
+			ec2svc := &mocks.EC2API{}
+			if tc.GetResourcesError == nil {
+				ec2svc.On("DescribeSubnets", &ec2.DescribeSubnetsInput{Filters: []*ec2.Filter{
+					{Name: aws.String("vpc-id"), Values: []*string{aws.String("vpc-id")}},
+					{Name: aws.String("tag:kubernetes.io/cluster/" + clusterName), Values: []*string{aws.String("owned"), aws.String("shared")}},
+				}}).Return(&ec2.DescribeSubnetsOutput{Subnets: tc.SharedSubnets}, nil)
+			}
+
 			cloud := &Cloud{
-				clusterName: clusterName,
-				rgt:         rgtsvc,
+				clusterTagKey: "kubernetes.io/cluster/" + clusterName,
+				vpcID:         "vpc-id",
+				ec2:           ec2svc,
+				rgt:           rgtsvc,
 			}
 			subnets, err := cloud.GetClusterSubnets()
 			assert.Equal(t, tc.ExpectedResult, subnets)
 			assert.Equal(t, tc.ExpectedError, err)
 			rgtsvc.AssertExpectations(t)
+			ec2svc.AssertExpectations(t)
 		})
 	}
 }
@@ -249,20 +281,22 @@ func TestCloud_GetResourcesByFilters(t *testing.T) {
 		},
 	} {
 		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
 			rgtsvc := &mocks.ResourceGroupsTaggingAPIAPI{}
 
-			rgtsvc.On("GetResourcesPages",
+			rgtsvc.On("GetResourcesPagesWithContext",
+				ctx,
 				tc.GetResourcesInput,
 				mock.AnythingOfType("func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool"),
 			).Return(tc.GetResourcesError).Run(func(args mock.Arguments) {
-				arg := args.Get(1).(func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool)
+				arg := args.Get(2).(func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool)
 				arg(tc.GetResourcesOutput, false)
 			})
 
 			cloud := &Cloud{
 				rgt: rgtsvc,
 			}
-			arns, err := cloud.GetResourcesByFilters(tc.TagFilters, tc.ResourceTypeFilters...)
+			arns, err := cloud.GetResourcesByFilters(ctx, tc.TagFilters, tc.ResourceTypeFilters...)
 			assert.Equal(t, tc.ExpectedResult, arns)
 			assert.Equal(t, tc.ExpectedError, err)
 			rgtsvc.AssertExpectations(t)
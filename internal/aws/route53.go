@@ -0,0 +1,54 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// Route53API is our wrapper Route53 API interface
+type Route53API interface {
+	// GetHostedZoneName gets the domain name of hosted zone with zoneID, e.g. example.com.
+	GetHostedZoneName(ctx context.Context, zoneID string) (string, error)
+
+	// ListResourceRecordSets lists all record sets of hosted zone with zoneID.
+	ListResourceRecordSets(ctx context.Context, zoneID string) ([]*route53.ResourceRecordSet, error)
+
+	// ChangeResourceRecordSets makes changes to the record sets of hosted zone with zoneID in a single batch.
+	ChangeResourceRecordSets(ctx context.Context, zoneID string, changes []*route53.Change) error
+}
+
+func (c *Cloud) GetHostedZoneName(ctx context.Context, zoneID string) (string, error) {
+	o, err := c.route53.GetHostedZoneWithContext(ctx, &route53.GetHostedZoneInput{
+		Id: aws.String(zoneID),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(o.HostedZone.Name), nil
+}
+
+func (c *Cloud) ListResourceRecordSets(ctx context.Context, zoneID string) ([]*route53.ResourceRecordSet, error) {
+	var recordSets []*route53.ResourceRecordSet
+	err := c.route53.ListResourceRecordSetsPagesWithContext(ctx, &route53.ListResourceRecordSetsInput{
+		HostedZoneId: aws.String(zoneID),
+	}, func(output *route53.ListResourceRecordSetsOutput, _ bool) bool {
+		recordSets = append(recordSets, output.ResourceRecordSets...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return recordSets, nil
+}
+
+func (c *Cloud) ChangeResourceRecordSets(ctx context.Context, zoneID string, changes []*route53.Change) error {
+	_, err := c.route53.ChangeResourceRecordSetsWithContext(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(zoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: changes,
+		},
+	})
+	return err
+}
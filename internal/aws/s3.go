@@ -0,0 +1,129 @@
+package aws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// bucketExistsCacheTTL is how long the existence of buckets is cached, so buckets aren't checked on every reconcile.
+const bucketExistsCacheTTL = time.Hour
+
+// S3API is our wrapper S3 API interface
+type S3API interface {
+	// BucketExists checks whether the bucket exists and is accessible.
+	// It fails with an error IsBucketForbidden tests for if the bucket exists but isn't accessible.
+	BucketExists(ctx context.Context, bucket string) (bool, error)
+
+	// GetBucketPolicy returns the JSON policy document attached to the bucket.
+	GetBucketPolicy(ctx context.Context, bucket string) (*string, error)
+
+	CreateBucketWithContext(context.Context, *s3.CreateBucketInput) (*s3.CreateBucketOutput, error)
+	PutBucketPolicyWithContext(context.Context, *s3.PutBucketPolicyInput) (*s3.PutBucketPolicyOutput, error)
+	PutBucketLifecycleConfigurationWithContext(context.Context, *s3.PutBucketLifecycleConfigurationInput) (*s3.PutBucketLifecycleConfigurationOutput, error)
+}
+
+// BucketExists checks whether the bucket exists and is accessible.
+// Buckets found, as well as buckets found inaccessible, are cached for bucketExistsCacheTTL. Missing buckets aren't, since they're about to be created.
+func (c *Cloud) BucketExists(ctx context.Context, bucket string) (bool, error) {
+	if entry, ok := c.buckets.get(bucket); ok {
+		return entry.err == nil, entry.err
+	}
+	_, err := c.s3.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if awsError, ok := err.(awserr.Error); ok {
+			if awsError.Code() == "NotFound" || awsError.Code() == s3.ErrCodeNoSuchBucket {
+				return false, nil
+			}
+		}
+		if IsBucketForbidden(err) {
+			c.buckets.put(bucket, err)
+		}
+		return false, err
+	}
+	c.buckets.put(bucket, nil)
+	return true, nil
+}
+
+// IsBucketForbidden tests whether err is S3 denying access to an bucket, which means the bucket exists,
+// but is owned by another account or the controller isn't allowed to access it.
+// HeadBucket responses have no body, so the error code is the status text of 403 responses.
+func IsBucketForbidden(err error) bool {
+	awsError, ok := err.(awserr.Error)
+	return ok && (awsError.Code() == "Forbidden" || awsError.Code() == "AccessDenied")
+}
+
+// GetBucketPolicy returns the JSON policy document attached to the bucket.
+func (c *Cloud) GetBucketPolicy(ctx context.Context, bucket string) (*string, error) {
+	o, err := c.s3.GetBucketPolicyWithContext(ctx, &s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return o.Policy, nil
+}
+
+// CreateBucketWithContext creates an bucket, it succeeds if the bucket is already owned by the account,
+// e.g. created by an earlier attempt of the request that timed out.
+func (c *Cloud) CreateBucketWithContext(ctx context.Context, i *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+	o, err := c.s3.CreateBucketWithContext(ctx, i)
+	if awsError, ok := err.(awserr.Error); ok && awsError.Code() == s3.ErrCodeBucketAlreadyOwnedByYou {
+		return &s3.CreateBucketOutput{}, nil
+	}
+	return o, err
+}
+
+func (c *Cloud) PutBucketPolicyWithContext(ctx context.Context, i *s3.PutBucketPolicyInput) (*s3.PutBucketPolicyOutput, error) {
+	return c.s3.PutBucketPolicyWithContext(ctx, i)
+}
+
+func (c *Cloud) PutBucketLifecycleConfigurationWithContext(ctx context.Context, i *s3.PutBucketLifecycleConfigurationInput) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	return c.s3.PutBucketLifecycleConfigurationWithContext(ctx, i)
+}
+
+// bucketsCache caches the results of checking the existence of buckets by their names, nil errors for buckets found.
+type bucketsCache struct {
+	now func() time.Time
+
+	// mutex protects entries
+	mutex   sync.Mutex
+	entries map[string]bucketsCacheEntry
+}
+
+type bucketsCacheEntry struct {
+	err       error
+	checkedAt time.Time
+}
+
+func newBucketsCache() *bucketsCache {
+	return &bucketsCache{now: time.Now, entries: make(map[string]bucketsCacheEntry)}
+}
+
+func (c *bucketsCache) get(bucket string) (bucketsCacheEntry, bool) {
+	if c == nil {
+		return bucketsCacheEntry{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[bucket]
+	if !ok || c.now().Sub(entry.checkedAt) >= bucketExistsCacheTTL {
+		return bucketsCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *bucketsCache) put(bucket string, err error) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[bucket] = bucketsCacheEntry{err: err, checkedAt: c.now()}
+}
@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeS3 struct {
+	s3iface.S3API
+
+	err   error
+	calls int
+}
+
+func (s *fakeS3) HeadBucketWithContext(ctx aws.Context, input *s3.HeadBucketInput, opts ...request.Option) (*s3.HeadBucketOutput, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &s3.HeadBucketOutput{}, nil
+}
+
+func TestCloud_BucketExists(t *testing.T) {
+	for _, tc := range []struct {
+		Name              string
+		Err               error
+		Elapsed           time.Duration
+		ExpectedExists    bool
+		ExpectedForbidden bool
+		ExpectedCalls     int
+	}{
+		{
+			Name:           "buckets found are cached",
+			ExpectedExists: true,
+			ExpectedCalls:  1,
+		},
+		{
+			Name:           "buckets found are checked again once cached for bucketExistsCacheTTL",
+			Elapsed:        bucketExistsCacheTTL,
+			ExpectedExists: true,
+			ExpectedCalls:  2,
+		},
+		{
+			Name:          "missing buckets aren't cached",
+			Err:           awserr.New("NotFound", "Not Found", nil),
+			ExpectedCalls: 2,
+		},
+		{
+			Name:              "buckets S3 denies access to exist and are cached",
+			Err:               awserr.New("Forbidden", "Forbidden", nil),
+			ExpectedForbidden: true,
+			ExpectedCalls:     1,
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Now()
+			client := &fakeS3{err: tc.Err}
+			buckets := newBucketsCache()
+			buckets.now = func() time.Time { return now }
+			cloud := &Cloud{s3: client, buckets: buckets}
+
+			for i := 0; i < 2; i++ {
+				exists, err := cloud.BucketExists(ctx, "my-bucket")
+				assert.Equal(t, tc.ExpectedExists, exists)
+				assert.Equal(t, tc.ExpectedForbidden, IsBucketForbidden(err))
+				if !tc.ExpectedForbidden {
+					assert.NoError(t, err)
+				}
+				now = now.Add(tc.Elapsed)
+			}
+			assert.Equal(t, tc.ExpectedCalls, client.calls)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+)
+
+const (
+	// secretVersionCheckInterval is how often the current version of cached secrets is checked, so rotated secrets are fetched again.
+	secretVersionCheckInterval = 5 * time.Minute
+
+	// secretCurrentStage is the staging label of the current version of secrets, which is moved to the new version when secrets are rotated
+	secretCurrentStage = "AWSCURRENT"
+)
+
+// SecretsManagerAPI is our wrapper Secrets Manager API interface
+type SecretsManagerAPI interface {
+	// GetSecretString returns the current value of the secret secretArn. It's cached until the secret is rotated.
+	GetSecretString(ctx context.Context, secretArn string) (string, error)
+}
+
+func (c *Cloud) GetSecretString(ctx context.Context, secretArn string) (string, error) {
+	return c.secrets.get(ctx, c.secretsmanager, secretArn)
+}
+
+// secretsCache caches the values of secrets by their ARNs, their current version is checked every secretVersionCheckInterval,
+// which is a cheap DescribeSecret call, and their value is only fetched again once the version changed, i.e. once they're rotated.
+type secretsCache struct {
+	now func() time.Time
+
+	// mutex protects entries
+	mutex   sync.Mutex
+	entries map[string]secretsCacheEntry
+}
+
+type secretsCacheEntry struct {
+	value     string
+	versionID string
+	checkedAt time.Time
+}
+
+func newSecretsCache() *secretsCache {
+	return &secretsCache{now: time.Now, entries: make(map[string]secretsCacheEntry)}
+}
+
+func (c *secretsCache) get(ctx context.Context, client secretsmanageriface.SecretsManagerAPI, secretArn string) (string, error) {
+	c.mutex.Lock()
+	entry, ok := c.entries[secretArn]
+	c.mutex.Unlock()
+	now := c.now()
+	if ok && now.Sub(entry.checkedAt) < secretVersionCheckInterval {
+		return entry.value, nil
+	}
+
+	if ok {
+		versionID, err := currentSecretVersionID(ctx, client, secretArn)
+		if err != nil {
+			return "", err
+		}
+		if versionID == entry.versionID {
+			entry.checkedAt = now
+			c.put(secretArn, entry)
+			return entry.value, nil
+		}
+	}
+
+	resp, err := client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId:     aws.String(secretArn),
+		VersionStage: aws.String(secretCurrentStage),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get value of secret %v due to %v", secretArn, err)
+	}
+	if resp.SecretString == nil {
+		return "", fmt.Errorf("secret %v doesn't have a string value", secretArn)
+	}
+	entry = secretsCacheEntry{value: aws.StringValue(resp.SecretString), versionID: aws.StringValue(resp.VersionId), checkedAt: now}
+	c.put(secretArn, entry)
+	return entry.value, nil
+}
+
+func (c *secretsCache) put(secretArn string, entry secretsCacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[secretArn] = entry
+}
+
+// currentSecretVersionID returns the ID of the version of secret secretArn staged as current.
+func currentSecretVersionID(ctx context.Context, client secretsmanageriface.SecretsManagerAPI, secretArn string) (string, error) {
+	resp, err := client.DescribeSecretWithContext(ctx, &secretsmanager.DescribeSecretInput{SecretId: aws.String(secretArn)})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe secret %v due to %v", secretArn, err)
+	}
+	for versionID, stages := range resp.VersionIdsToStages {
+		for _, stage := range stages {
+			if aws.StringValue(stage) == secretCurrentStage {
+				return versionID, nil
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,87 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+
+	versionID string
+	value     string
+	calls     []string
+}
+
+func (s *fakeSecretsManager) DescribeSecretWithContext(ctx aws.Context, input *secretsmanager.DescribeSecretInput, opts ...request.Option) (*secretsmanager.DescribeSecretOutput, error) {
+	s.calls = append(s.calls, "DescribeSecret")
+	return &secretsmanager.DescribeSecretOutput{VersionIdsToStages: map[string][]*string{
+		"previous":  aws.StringSlice([]string{"AWSPREVIOUS"}),
+		s.versionID: aws.StringSlice([]string{secretCurrentStage}),
+	}}, nil
+}
+
+func (s *fakeSecretsManager) GetSecretValueWithContext(ctx aws.Context, input *secretsmanager.GetSecretValueInput, opts ...request.Option) (*secretsmanager.GetSecretValueOutput, error) {
+	s.calls = append(s.calls, "GetSecretValue")
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(s.value), VersionId: aws.String(s.versionID)}, nil
+}
+
+func TestCloud_GetSecretString(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	client := &fakeSecretsManager{versionID: "v1", value: "secret"}
+	secrets := newSecretsCache()
+	secrets.now = func() time.Time { return now }
+	cloud := &Cloud{secretsmanager: client, secrets: secrets}
+
+	for _, tc := range []struct {
+		Name          string
+		Elapsed       time.Duration
+		Rotated       bool
+		Expected      string
+		ExpectedCalls []string
+	}{
+		{
+			Name:          "secrets are fetched once",
+			Expected:      "secret",
+			ExpectedCalls: []string{"GetSecretValue"},
+		},
+		{
+			Name:     "cached secrets aren't checked within the interval",
+			Elapsed:  secretVersionCheckInterval - time.Second,
+			Expected: "secret",
+		},
+		{
+			Name:          "cached secrets are only described once the interval passed",
+			Elapsed:       secretVersionCheckInterval,
+			Expected:      "secret",
+			ExpectedCalls: []string{"DescribeSecret"},
+		},
+		{
+			Name:          "rotated secrets are fetched again",
+			Elapsed:       secretVersionCheckInterval,
+			Rotated:       true,
+			Expected:      "rotated",
+			ExpectedCalls: []string{"DescribeSecret", "GetSecretValue"},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			now = now.Add(tc.Elapsed)
+			if tc.Rotated {
+				client.versionID, client.value = "v2", "rotated"
+			}
+			client.calls = nil
+			value, err := cloud.GetSecretString(ctx, "arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, value)
+			assert.Equal(t, tc.ExpectedCalls, client.calls)
+		})
+	}
+}
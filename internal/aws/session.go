@@ -5,31 +5,60 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
 	"github.com/golang/glog"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/ticketmaster/aws-sdk-go-cache/cache"
 )
 
-// NewSession returns an AWS session based off of the provided AWS config
-func NewSession(awsconfig *aws.Config, AWSDebug bool, mc metric.Collector, cc *cache.Config) *session.Session {
+// NewSession returns an AWS session based off of the provided AWS config.
+// Calls mutating AWS resources are recorded by auditor and published by changeEventPublisher, and limited by mutationLimiter, unless they're nil.
+// ELBV2 Describe responses are cached for describeCacheTTL, they're not cached if 0.
+func NewSession(awsconfig *aws.Config, AWSDebug bool, mc metric.Collector, cc *cache.Config, auditor Auditor, changeEventPublisher ChangeEventPublisher, mutationLimiter *MutationLimiter, describeCacheTTL time.Duration) *session.Session {
 	session, err := session.NewSession(awsconfig)
 	if err != nil {
-		mc.IncAPIErrorCount(prometheus.Labels{"service": "AWS", "request": "NewSession"})
+		mc.IncAPIErrorCount(prometheus.Labels{"service": "AWS", "operation": "NewSession", "error_code": apiErrorCode(err)})
 		glog.ErrorDepth(4, fmt.Sprintf("Failed to create AWS session: %s", err.Error()))
 		return nil
 	}
 
+	// roles of ingresses are assumed with an copy of the session before our handlers are added, so calls assuming them are not rate limited or audited.
+	roles := newRoleCredentials(session.Copy())
+	session.Handlers.Build.PushFront(roles.apply)
+
 	// Adds caching to session
 	cache.AddCaching(session, cc)
 	cc.SetCacheTTL(resourcegroupstaggingapi.ServiceName, "GetResources", time.Hour)
 	cc.SetCacheTTL(ec2.ServiceName, "DescribeInstanceStatus", time.Minute)
 
+	session.Handlers.Validate.PushBack(skipDryRunRequest)
+	if describeCache := newDescribeCache(describeCacheTTL); describeCache != nil {
+		session.Handlers.Validate.PushBack(describeCache.lookup)
+		session.Handlers.Complete.PushBack(describeCache.complete)
+	}
+	if mutationLimiter != nil {
+		session.Handlers.Build.PushFront(mutationLimiter.acquire)
+		session.Handlers.Complete.PushBack(mutationLimiter.release)
+	}
+
+	// requests failing as resources just created aren't found yet are retried, before the retryer decides whether others are retried.
+	created := newCreatedResources()
+	session.Handlers.Retry.PushBack(created.retryNotFound)
+	session.Handlers.Complete.PushBack(created.recordCreated)
+
+	// APIs throttled are rate limited client-side until they recover.
+	throttler := newThrottler(mc)
+	session.Handlers.Sign.PushFront(throttler.wait)
+	session.Handlers.Retry.PushBack(throttler.observeThrottle)
+	session.Handlers.Complete.PushBack(throttler.observeSuccess)
+
 	session.Handlers.Retry.PushFront(func(r *request.Request) {
 		mc.IncAPIRetryCount(prometheus.Labels{"service": r.ClientInfo.ServiceName, "operation": r.Operation.Name})
 	})
@@ -42,16 +71,42 @@ func NewSession(awsconfig *aws.Config, AWSDebug bool, mc metric.Collector, cc *c
 	})
 
 	session.Handlers.Complete.PushFront(func(r *request.Request) {
+		if getDryRunPlan(r.Context()) != nil && isMutatingOperation(r.Operation.Name) {
+			// calls skipped in dry-run are neither measured nor audited.
+			return
+		}
+		mc.ObserveAPIRequestDuration(prometheus.Labels{"service": r.ClientInfo.ServiceName, "operation": r.Operation.Name}, time.Since(r.Time).Seconds())
+		// requests made on behalf of an ingress are logged with its logger, so they can be correlated by reconcileID.
+		logger := albctx.GetLogger(r.Context()).With("awsRequestID", r.RequestID)
 		if r.Error != nil {
-			mc.IncAPIErrorCount(prometheus.Labels{"service": r.ClientInfo.ServiceName, "operation": r.Operation.Name})
+			logger.Debugf("request %s/%s(%s) failed after %v due to %v", r.ClientInfo.ServiceName, r.Operation.Name, r.RequestID, time.Since(r.Time), r.Error)
+			mc.IncAPIErrorCount(prometheus.Labels{"service": r.ClientInfo.ServiceName, "operation": r.Operation.Name, "error_code": apiErrorCode(r.Error)})
 			if AWSDebug {
 				glog.ErrorDepth(4, fmt.Sprintf("Failed request: %s/%s, Payload: %s, Error: %s", r.ClientInfo.ServiceName, r.Operation.Name, log.Prettify(r.Params), r.Error))
 			}
 		} else {
+			logger.Debugf("request %s/%s(%s) succeeded after %v", r.ClientInfo.ServiceName, r.Operation.Name, r.RequestID, time.Since(r.Time))
 			if AWSDebug {
 				glog.InfoDepth(4, fmt.Sprintf("Response: %s/%s, Body: %s", r.ClientInfo.ServiceName, r.Operation.Name, log.Prettify(r.Data)))
 			}
 		}
+		if isMutatingOperation(r.Operation.Name) {
+			emitRequestEvent(r)
+			if auditor != nil {
+				auditor.Audit(buildAuditRecord(r))
+			}
+			if changeEventPublisher != nil && r.Error == nil {
+				changeEventPublisher.Publish(buildChangeEvent(r))
+			}
+		}
 	})
 	return session
 }
+
+// apiErrorCode returns the AWS error code of err, e.g. Throttling or AccessDenied.
+func apiErrorCode(err error) string {
+	if awsErr, ok := err.(awserr.Error); ok {
+		return awsErr.Code()
+	}
+	return "Unknown"
+}
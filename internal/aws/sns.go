@@ -0,0 +1,23 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+)
+
+// SNSAPI is our wrapper SNS API interface
+type SNSAPI interface {
+	// PublishNotification publishes message with subject to SNS topic with topicArn.
+	PublishNotification(ctx context.Context, topicArn string, subject string, message string) error
+}
+
+func (c *Cloud) PublishNotification(ctx context.Context, topicArn string, subject string, message string) error {
+	_, err := c.sns.PublishWithContext(ctx, &sns.PublishInput{
+		TopicArn: aws.String(topicArn),
+		Subject:  aws.String(subject),
+		Message:  aws.String(message),
+	})
+	return err
+}
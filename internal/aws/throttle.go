@@ -0,0 +1,152 @@
+package aws
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// errCodeThrottlerCanceled is the code of the error of requests canceled while waiting for the throttler.
+	errCodeThrottlerCanceled = "ThrottlerCanceled"
+
+	// throttledInitialRate is the rate in requests per second an API is limited to once it's first throttled.
+	throttledInitialRate = 10
+	// throttledMinRate is the lowest rate in requests per second an API is limited to however often it's throttled.
+	throttledMinRate = 0.5
+	// throttledMaxRate is the rate in requests per second above which an API is no longer limited.
+	throttledMaxRate = 20
+	// throttledRateDecrease is the factor the rate of an API is multiplied by each time it's throttled.
+	throttledRateDecrease = 0.5
+	// throttledRateIncrease is the rate in requests per second the rate of an API is increased by on each successful request.
+	throttledRateIncrease = 0.1
+	// throttledRateCooldown is how long after the rate of an API is decreased further throttling doesn't decrease it again,
+	// since concurrent requests sent before it was decreased are throttled too.
+	throttledRateCooldown = time.Second
+
+	// throttledRetryBaseDelay and throttledRetryMaxDelay bound the backoff of retries of throttled requests.
+	throttledRetryBaseDelay = time.Second
+	throttledRetryMaxDelay  = 20 * time.Second
+)
+
+// throttler limits the rate of requests to each AWS API, i.e. operation of an service, after it's throttled.
+// APIs are not limited until they're throttled, then they're limited with an token bucket whose rate is decreased
+// multiplicatively each time they're throttled, and increased additively by each successful request until they're no longer limited.
+// This way concurrent reconciles back off together instead of each of them retrying into the throttled API.
+type throttler struct {
+	mc  metric.Collector
+	now func() time.Time
+
+	// mutex protects apis, the throttle state of APIs by service and operation
+	mutex sync.Mutex
+	apis  map[string]*apiThrottle
+}
+
+type apiThrottle struct {
+	limiter     *rate.Limiter
+	decreasedAt time.Time
+}
+
+func newThrottler(mc metric.Collector) *throttler {
+	return &throttler{mc: mc, now: time.Now, apis: make(map[string]*apiThrottle)}
+}
+
+// wait waits for an token of the API of request r, it's an Sign handler so it runs before each attempt, including retries.
+// Requests skipped in dry-run are never signed, so they don't wait.
+func (t *throttler) wait(r *request.Request) {
+	limiter := t.limiter(r)
+	if limiter == nil {
+		return
+	}
+	if err := limiter.Wait(r.Context()); err != nil {
+		r.Error = awserr.New(errCodeThrottlerCanceled, "canceled while waiting for throttled AWS API "+r.ClientInfo.ServiceName+"/"+r.Operation.Name, err)
+	}
+}
+
+// observeThrottle decreases the rate of the API of request r if it's throttled, it's an Retry handler so it runs after each failed attempt.
+func (t *throttler) observeThrottle(r *request.Request) {
+	if r.Error == nil || !request.IsErrorThrottle(r.Error) {
+		return
+	}
+	now := t.now()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	key := throttleKey(r)
+	api, ok := t.apis[key]
+	if !ok {
+		api = &apiThrottle{limiter: rate.NewLimiter(throttledInitialRate, 1), decreasedAt: now}
+		t.apis[key] = api
+	} else if now.Sub(api.decreasedAt) > throttledRateCooldown {
+		api.limiter.SetLimit(rate.Limit(math.Max(float64(api.limiter.Limit())*throttledRateDecrease, throttledMinRate)))
+		api.decreasedAt = now
+	}
+	t.mc.SetAPIRateLimit(throttleLabels(r), float64(api.limiter.Limit()))
+}
+
+// observeSuccess increases the rate of the API of request r if it succeeded, it's an Complete handler.
+func (t *throttler) observeSuccess(r *request.Request) {
+	if r.Error != nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	key := throttleKey(r)
+	api, ok := t.apis[key]
+	if !ok {
+		return
+	}
+	limit := float64(api.limiter.Limit()) + throttledRateIncrease
+	if limit > throttledMaxRate {
+		delete(t.apis, key)
+		t.mc.SetAPIRateLimit(throttleLabels(r), 0)
+		return
+	}
+	api.limiter.SetLimit(rate.Limit(limit))
+	t.mc.SetAPIRateLimit(throttleLabels(r), limit)
+}
+
+func (t *throttler) limiter(r *request.Request) *rate.Limiter {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if api, ok := t.apis[throttleKey(r)]; ok {
+		return api.limiter
+	}
+	return nil
+}
+
+func throttleKey(r *request.Request) string {
+	return r.ClientInfo.ServiceName + "/" + r.Operation.Name
+}
+
+func throttleLabels(r *request.Request) prometheus.Labels {
+	return prometheus.Labels{"service": r.ClientInfo.ServiceName, "operation": r.Operation.Name}
+}
+
+// adaptiveRetryer retries requests like the default retryer of the SDK, except throttled requests, e.g. failing with
+// Throttling or RequestLimitExceeded, are retried after an longer, fully jittered, exponential backoff, so retries of
+// concurrent requests are spread out instead of being throttled again together.
+type adaptiveRetryer struct {
+	client.DefaultRetryer
+}
+
+// RetryRules implements request.Retryer.
+func (r adaptiveRetryer) RetryRules(req *request.Request) time.Duration {
+	if !req.IsErrorThrottle() {
+		return r.DefaultRetryer.RetryRules(req)
+	}
+	delay := throttledRetryMaxDelay
+	if req.RetryCount < 16 {
+		if backoff := throttledRetryBaseDelay << uint(req.RetryCount); backoff < delay {
+			delay = backoff
+		}
+	}
+	return time.Duration(rand.Int63n(int64(delay)))
+}
@@ -0,0 +1,65 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func newThrottleRequest(err error) *request.Request {
+	return &request.Request{
+		ClientInfo: metadata.ClientInfo{ServiceName: "elasticloadbalancing"},
+		Operation:  &request.Operation{Name: "DescribeTags"},
+		Error:      err,
+	}
+}
+
+func TestThrottler(t *testing.T) {
+	now := time.Now()
+	throttler := newThrottler(metric.DummyCollector{})
+	throttler.now = func() time.Time { return now }
+	throttled := awserr.New("Throttling", "Rate exceeded", nil)
+
+	assert.Nil(t, throttler.limiter(newThrottleRequest(nil)), "APIs aren't limited until throttled")
+
+	throttler.observeThrottle(newThrottleRequest(awserr.New("AccessDenied", "access denied", nil)))
+	assert.Nil(t, throttler.limiter(newThrottleRequest(nil)), "other errors don't limit APIs")
+
+	throttler.observeThrottle(newThrottleRequest(throttled))
+	assert.Equal(t, rate.Limit(throttledInitialRate), throttler.limiter(newThrottleRequest(nil)).Limit())
+
+	throttler.observeThrottle(newThrottleRequest(throttled))
+	assert.Equal(t, rate.Limit(throttledInitialRate), throttler.limiter(newThrottleRequest(nil)).Limit(), "rate isn't decreased again within cooldown")
+
+	now = now.Add(2 * throttledRateCooldown)
+	throttler.observeThrottle(newThrottleRequest(throttled))
+	assert.Equal(t, rate.Limit(throttledInitialRate*throttledRateDecrease), throttler.limiter(newThrottleRequest(nil)).Limit())
+
+	throttler.observeSuccess(newThrottleRequest(nil))
+	assert.InDelta(t, throttledInitialRate*throttledRateDecrease+throttledRateIncrease, float64(throttler.limiter(newThrottleRequest(nil)).Limit()), 0.001)
+
+	for i := 0; i < 200; i++ {
+		throttler.observeSuccess(newThrottleRequest(nil))
+	}
+	assert.Nil(t, throttler.limiter(newThrottleRequest(nil)), "APIs are no longer limited once recovered")
+}
+
+func TestAdaptiveRetryer_RetryRules(t *testing.T) {
+	retryer := adaptiveRetryer{client.DefaultRetryer{NumMaxRetries: 10}}
+	for retryCount := 0; retryCount < 20; retryCount++ {
+		r := newThrottleRequest(awserr.New("RequestLimitExceeded", "Request limit exceeded.", nil))
+		r.RetryCount = retryCount
+		delay := retryer.RetryRules(r)
+		assert.True(t, delay >= 0 && delay < throttledRetryMaxDelay)
+		if retryCount == 0 {
+			assert.True(t, delay < throttledRetryBaseDelay)
+		}
+	}
+}
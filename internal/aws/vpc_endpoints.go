@@ -0,0 +1,156 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// VPCEndpointServices are the endpoint prefixes of the services ingresses can't be reconciled without,
+// which need interface VPC endpoints in clusters without internet egress.
+var VPCEndpointServices = []string{elbv2.EndpointsID, ec2.EndpointsID, acm.EndpointsID, sts.EndpointsID}
+
+// UseVPCEndpoints makes calls of config to VPCEndpointServices through the interface VPC endpoints of vpcID, or of the VPC of the instance if it's empty.
+// Services whose endpoint has private DNS enabled are called at their regional endpoint, which resolves to the VPC endpoint, others at the DNS name of the VPC endpoint.
+// Services of endpointOverrides keep their endpoints. The endpoints are looked up with the credentials of roleARN as by NewCredentials,
+// services without an available endpoint are reported by StatusVPCEndpoints.
+func UseVPCEndpoints(config *aws.Config, vpcID string, roleARN string, endpointOverrides []string) error {
+	overrides, err := ParseEndpointOverrides(endpointOverrides)
+	if err != nil {
+		return err
+	}
+	creds, err := NewCredentials(config, roleARN)
+	if err != nil {
+		return err
+	}
+	sess, err := session.NewSession(config.Copy(&aws.Config{Credentials: creds}))
+	if err != nil {
+		return fmt.Errorf("failed to create AWS session for VPC endpoint discovery due to %v", err)
+	}
+	ec2svc := ec2.New(sess)
+	if vpcID == "" {
+		vpcID = os.Getenv("AWS_VPC_ID")
+	}
+	if vpcID == "" {
+		if vpcID, err = discoverVPCID(newEC2Metadata(ec2metadata.New(sess)), ec2svc); err != nil {
+			return err
+		}
+	}
+	vpcEndpoints, err := describeVPCEndpoints(context.Background(), ec2svc, vpcID)
+	if err != nil {
+		return fmt.Errorf("failed to describe VPC endpoints of %v due to %v", vpcID, err)
+	}
+	endpointURLs := make(map[string]string)
+	for _, service := range VPCEndpointServices {
+		vpcEndpoint := findVPCEndpoint(vpcEndpoints, service)
+		if _, ok := overrides[service]; ok || vpcEndpoint == nil || aws.BoolValue(vpcEndpoint.PrivateDnsEnabled) || len(vpcEndpoint.DnsEntries) == 0 {
+			continue
+		}
+		// the first DNS entry is the regional one, the others are zonal.
+		endpointURLs[service] = "https://" + aws.StringValue(vpcEndpoint.DnsEntries[0].DnsName)
+	}
+	if len(endpointURLs) != 0 {
+		config.EndpointResolver = newVPCEndpointResolver(config.EndpointResolver, endpointURLs)
+	}
+	return nil
+}
+
+// StatusVPCEndpoints validates the VPC of the cluster has available interface VPC endpoints of services
+func (c *Cloud) StatusVPCEndpoints(services []string) func() error {
+	return func() error {
+		vpcID, err := c.GetVPCID()
+		if err != nil {
+			return fmt.Errorf("failed to get VPC ID due to %v", err)
+		}
+		vpcEndpoints, err := describeVPCEndpoints(context.TODO(), c.ec2, aws.StringValue(vpcID))
+		if err != nil {
+			return fmt.Errorf("[ec2.DescribeVpcEndpointsWithContext]: %v", err)
+		}
+		var missing []string
+		for _, service := range services {
+			if findVPCEndpoint(vpcEndpoints, service) == nil {
+				missing = append(missing, service)
+			}
+		}
+		if len(missing) != 0 {
+			return fmt.Errorf("interface VPC endpoints of %v are missing in %v, they're required by --aws-vpc-endpoints-only", strings.Join(missing, ", "), aws.StringValue(vpcID))
+		}
+		return nil
+	}
+}
+
+// describeVPCEndpoints describes the interface VPC endpoints of vpcID.
+func describeVPCEndpoints(ctx context.Context, ec2svc ec2iface.EC2API, vpcID string) ([]*ec2.VpcEndpoint, error) {
+	input := &ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{vpcID})},
+			{Name: aws.String("vpc-endpoint-type"), Values: aws.StringSlice([]string{ec2.VpcEndpointTypeInterface})},
+		},
+	}
+	var vpcEndpoints []*ec2.VpcEndpoint
+	for {
+		o, err := ec2svc.DescribeVpcEndpointsWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		vpcEndpoints = append(vpcEndpoints, o.VpcEndpoints...)
+		if aws.StringValue(o.NextToken) == "" {
+			return vpcEndpoints, nil
+		}
+		input.NextToken = o.NextToken
+	}
+}
+
+// findVPCEndpoint returns the available endpoint of service among vpcEndpoints, nil if there isn't one.
+// Endpoints are named com.amazonaws.<region>.<service>, service being the endpoint prefix of the service.
+func findVPCEndpoint(vpcEndpoints []*ec2.VpcEndpoint, service string) *ec2.VpcEndpoint {
+	for _, vpcEndpoint := range vpcEndpoints {
+		serviceName := aws.StringValue(vpcEndpoint.ServiceName)
+		if serviceName[strings.LastIndex(serviceName, ".")+1:] == service && strings.EqualFold(aws.StringValue(vpcEndpoint.State), "available") {
+			return vpcEndpoint
+		}
+	}
+	return nil
+}
+
+// newVPCEndpointResolver resolves the endpoints of services in endpointURLs to their URLs, and others by next or the default resolver of the SDK if it's nil.
+func newVPCEndpointResolver(next endpoints.Resolver, endpointURLs map[string]string) endpoints.Resolver {
+	if next == nil {
+		next = endpoints.DefaultResolver()
+	}
+	return endpoints.ResolverFunc(func(service string, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		if endpointURL, ok := endpointURLs[service]; ok {
+			return endpoints.ResolvedEndpoint{URL: endpointURL, SigningRegion: region}, nil
+		}
+		return next.EndpointFor(service, region, opts...)
+	})
+}
+
+// discoverVPCID returns the VPC of the instance the controller is running on.
+func discoverVPCID(metadata EC2MetadataAPI, ec2svc ec2iface.EC2API) (string, error) {
+	identityDoc, err := metadata.GetInstanceIdentityDocument()
+	if err != nil {
+		return "", fmt.Errorf("failed to discover VPC from instance metadata due to %v, it can be specified by --aws-vpc-id", err)
+	}
+	o, err := ec2svc.DescribeInstances(&ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(identityDoc.InstanceID)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe instance %v for VPC discovery due to %v", identityDoc.InstanceID, err)
+	}
+	if err := instanceVPCIsValid(o); err != nil {
+		return "", err
+	}
+	return aws.StringValue(o.Reservations[0].Instances[0].VpcId), nil
+}
@@ -0,0 +1,92 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func vpcEndpoint(service string, state string) *ec2.VpcEndpoint {
+	return &ec2.VpcEndpoint{
+		ServiceName: aws.String("com.amazonaws.us-west-2." + service),
+		State:       aws.String(state),
+	}
+}
+
+func TestCloud_StatusVPCEndpoints(t *testing.T) {
+	input := &ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{"vpc-0123456789abcdef0"})},
+			{Name: aws.String("vpc-endpoint-type"), Values: aws.StringSlice([]string{ec2.VpcEndpointTypeInterface})},
+		},
+	}
+	for _, tc := range []struct {
+		Name          string
+		VPCEndpoints  []*ec2.VpcEndpoint
+		Error         error
+		ExpectedError error
+	}{
+		{
+			Name: "all endpoints available",
+			VPCEndpoints: []*ec2.VpcEndpoint{
+				vpcEndpoint("elasticloadbalancing", "available"),
+				vpcEndpoint("ec2", "available"),
+				vpcEndpoint("acm", "available"),
+				vpcEndpoint("sts", "available"),
+			},
+		},
+		{
+			Name: "endpoints missing or pending",
+			VPCEndpoints: []*ec2.VpcEndpoint{
+				vpcEndpoint("elasticloadbalancing", "available"),
+				vpcEndpoint("ec2messages", "available"),
+				vpcEndpoint("acm-pca", "available"),
+				vpcEndpoint("sts", "pending"),
+			},
+			ExpectedError: errors.New("interface VPC endpoints of ec2, acm, sts are missing in vpc-0123456789abcdef0, they're required by --aws-vpc-endpoints-only"),
+		},
+		{
+			Name:          "Error from API call",
+			Error:         errors.New("Some API error"),
+			ExpectedError: errors.New("[ec2.DescribeVpcEndpointsWithContext]: Some API error"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ec2svc := &mocks.EC2API{}
+			ec2svc.On("DescribeVpcEndpointsWithContext", context.TODO(), input).Return(&ec2.DescribeVpcEndpointsOutput{VpcEndpoints: tc.VPCEndpoints}, tc.Error)
+
+			cloud := &Cloud{
+				ec2:   ec2svc,
+				vpcID: "vpc-0123456789abcdef0",
+			}
+
+			err := cloud.StatusVPCEndpoints(VPCEndpointServices)()
+			assert.Equal(t, tc.ExpectedError, err)
+			ec2svc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNewVPCEndpointResolver(t *testing.T) {
+	resolver := newVPCEndpointResolver(nil, map[string]string{
+		elbv2.EndpointsID: "https://vpce-0123456789abcdef0-abcdefgh.elasticloadbalancing.us-west-2.vpce.amazonaws.com",
+	})
+
+	resolved, err := resolver.EndpointFor(elbv2.EndpointsID, "us-west-2")
+	assert.NoError(t, err)
+	assert.Equal(t, endpoints.ResolvedEndpoint{
+		URL:           "https://vpce-0123456789abcdef0-abcdefgh.elasticloadbalancing.us-west-2.vpce.amazonaws.com",
+		SigningRegion: "us-west-2",
+	}, resolved)
+
+	resolved, err = resolver.EndpointFor(ec2.EndpointsID, "us-west-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://ec2.us-west-2.amazonaws.com", resolved.URL)
+}
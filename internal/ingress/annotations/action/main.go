@@ -18,6 +18,24 @@ const default404ServiceName = "Default 404"
 
 type Config struct {
 	Actions map[string]*elbv2.Action
+
+	// ConfigMapNames are the names of the ConfigMaps in the namespace of the ingress that MessageBody of fixed-response actions are read from
+	ConfigMapNames []string
+}
+
+// configMapKeyRef selects the key of a ConfigMap in the namespace of the ingress
+type configMapKeyRef struct {
+	Name string
+	Key  string
+}
+
+// fixedResponseRefs are the references of fixed-response actions, which aren't part of elbv2.Action
+type fixedResponseRefs struct {
+	FixedResponseConfig *struct {
+		MessageBodyFrom *struct {
+			ConfigMapKeyRef *configMapKeyRef
+		}
+	}
 }
 
 type action struct {
@@ -32,6 +50,7 @@ func NewParser(r resolver.Resolver) parser.IngressAnnotation {
 // Parse parses the annotations contained in the resource
 func (a action) Parse(ing parser.AnnotationInterface) (interface{}, error) {
 	actions := make(map[string]*elbv2.Action)
+	var configMapNames []string
 	annos, err := parser.GetStringAnnotations("actions", ing)
 	if err != nil {
 		return nil, err
@@ -52,6 +71,13 @@ func (a action) Parse(ing parser.AnnotationInterface) (interface{}, error) {
 			if data.FixedResponseConfig == nil {
 				return nil, fmt.Errorf("%v is type fixed-response but did not include a valid FixedResponseConfig configuration", serviceName)
 			}
+			configMapName, err := a.resolveMessageBody(ing, serviceName, raw, data.FixedResponseConfig)
+			if err != nil {
+				return nil, err
+			}
+			if configMapName != "" {
+				configMapNames = append(configMapNames, configMapName)
+			}
 		case "redirect":
 			if data.RedirectConfig == nil {
 				return nil, fmt.Errorf("%v is type redirect but did not include a valid RedirectConfig configuration", serviceName)
@@ -64,10 +90,51 @@ func (a action) Parse(ing parser.AnnotationInterface) (interface{}, error) {
 	}
 
 	return &Config{
-		Actions: actions,
+		Actions:        actions,
+		ConfigMapNames: configMapNames,
 	}, nil
 }
 
+// resolveMessageBody sets MessageBody of config to the value of the ConfigMap key referenced by MessageBodyFrom of the action raw, if any, and returns the name of the ConfigMap.
+func (a action) resolveMessageBody(ing parser.AnnotationInterface, serviceName string, raw string, config *elbv2.FixedResponseActionConfig) (string, error) {
+	var refs fixedResponseRefs
+	if err := json.Unmarshal([]byte(raw), &refs); err != nil {
+		return "", err
+	}
+	if refs.FixedResponseConfig == nil || refs.FixedResponseConfig.MessageBodyFrom == nil || refs.FixedResponseConfig.MessageBodyFrom.ConfigMapKeyRef == nil {
+		return "", nil
+	}
+	ref := refs.FixedResponseConfig.MessageBodyFrom.ConfigMapKeyRef
+	if config.MessageBody != nil {
+		return "", fmt.Errorf("%v sets both MessageBody and MessageBodyFrom, only one of them may be set", serviceName)
+	}
+
+	key := fmt.Sprintf("%v/%v", parser.GetNamespace(ing), ref.Name)
+	configMap, err := a.r.GetConfigMap(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to get configmap %v of %v due to %v", key, serviceName, err)
+	}
+	body, ok := configMap.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("configmap %v of %v doesn't contain key %v", key, serviceName, ref.Key)
+	}
+	config.MessageBody = aws.String(body)
+	return ref.Name, nil
+}
+
+// ReferencesConfigMap tests whether MessageBody of fixed-response actions is read from the ConfigMap name in the namespace of the ingress.
+func (c *Config) ReferencesConfigMap(name string) bool {
+	if c == nil {
+		return false
+	}
+	for _, configMapName := range c.ConfigMapNames {
+		if configMapName == name {
+			return true
+		}
+	}
+	return false
+}
+
 // GetAction returns the action named serviceName configured by an annotation
 func (c *Config) GetAction(serviceName string) (*elbv2.Action, error) {
 	if serviceName == default404ServiceName {
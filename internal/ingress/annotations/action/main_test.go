@@ -1,10 +1,14 @@
 package action
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/dummy"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/resolver"
@@ -56,3 +60,63 @@ func TestInvalidIngressActions(t *testing.T) {
 		t.Errorf("invalid annotation configuration was provided but an error was not returned: %v", err)
 	}
 }
+
+type configMapBackend struct {
+	resolver.Mock
+}
+
+func (configMapBackend) GetConfigMap(key string) (*corev1.ConfigMap, error) {
+	if key != "default/error-pages" {
+		return nil, errors.New("not found")
+	}
+	return &corev1.ConfigMap{Data: map[string]string{"503.html": "<h1>unavailable</h1>"}}, nil
+}
+
+func TestIngressActions_messageBodyFrom(t *testing.T) {
+	for _, tc := range []struct {
+		Name                   string
+		Action                 string
+		ExpectedMessageBody    string
+		ExpectedConfigMapNames []string
+		ExpectedError          error
+	}{
+		{
+			Name:                   "message body is read from the configmap",
+			Action:                 `{"Type": "fixed-response", "FixedResponseConfig": {"ContentType": "text/html", "StatusCode": "503", "MessageBodyFrom": {"ConfigMapKeyRef": {"Name": "error-pages", "Key": "503.html"}}}}`,
+			ExpectedMessageBody:    "<h1>unavailable</h1>",
+			ExpectedConfigMapNames: []string{"error-pages"},
+		},
+		{
+			Name:          "configmap doesn't contain the key",
+			Action:        `{"Type": "fixed-response", "FixedResponseConfig": {"ContentType": "text/html", "StatusCode": "503", "MessageBodyFrom": {"ConfigMapKeyRef": {"Name": "error-pages", "Key": "404.html"}}}}`,
+			ExpectedError: errors.New("configmap default/error-pages of fixed-response-action doesn't contain key 404.html"),
+		},
+		{
+			Name:          "configmap is missing",
+			Action:        `{"Type": "fixed-response", "FixedResponseConfig": {"ContentType": "text/html", "StatusCode": "503", "MessageBodyFrom": {"ConfigMapKeyRef": {"Name": "missing", "Key": "503.html"}}}}`,
+			ExpectedError: errors.New("failed to get configmap default/missing of fixed-response-action due to not found"),
+		},
+		{
+			Name:          "message body is set as well",
+			Action:        `{"Type": "fixed-response", "FixedResponseConfig": {"ContentType": "text/html", "StatusCode": "503", "MessageBody": "body", "MessageBodyFrom": {"ConfigMapKeyRef": {"Name": "error-pages", "Key": "503.html"}}}}`,
+			ExpectedError: errors.New("fixed-response-action sets both MessageBody and MessageBodyFrom, only one of them may be set"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ing := dummy.NewIngress()
+			ing.SetAnnotations(map[string]string{parser.GetAnnotationWithPrefix("actions.fixed-response-action"): tc.Action})
+
+			ai, err := NewParser(configMapBackend{}).Parse(ing)
+			if tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			a := ai.(*Config)
+			assert.Equal(t, tc.ExpectedMessageBody, aws.StringValue(a.Actions["fixed-response-action"].FixedResponseConfig.MessageBody))
+			assert.Equal(t, tc.ExpectedConfigMapNames, a.ConfigMapNames)
+			assert.True(t, a.ReferencesConfigMap("error-pages"))
+			assert.False(t, a.ReferencesConfigMap("other"))
+		})
+	}
+}
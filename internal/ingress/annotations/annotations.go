@@ -17,6 +17,8 @@ limitations under the License.
 package annotations
 
 import (
+	"strings"
+
 	"github.com/golang/glog"
 	"github.com/imdario/mergo"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
@@ -26,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/auth"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/listener"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
@@ -41,6 +44,7 @@ type Ingress struct {
 	// TODO: found out why the ObjectMeta is needed?
 	metav1.ObjectMeta
 	Action       *action.Config
+	Auth         *auth.Config
 	HealthCheck  *healthcheck.Config
 	TargetGroup  *targetgroup.Config
 	LoadBalancer *loadbalancer.Config
@@ -52,6 +56,7 @@ type Ingress struct {
 func NewIngressDummy() *Ingress {
 	return &Ingress{
 		Action:       action.Dummy(),
+		Auth:         auth.Dummy(),
 		HealthCheck:  &healthcheck.Config{},
 		TargetGroup:  targetgroup.Dummy(),
 		LoadBalancer: loadbalancer.Dummy(),
@@ -60,6 +65,16 @@ func NewIngressDummy() *Ingress {
 	}
 }
 
+// ReferencesSecret tests whether the annotations reference the Secret name in the namespace of the ingress.
+func (i *Ingress) ReferencesSecret(name string) bool {
+	return i.Auth.ReferencesSecret(name)
+}
+
+// ReferencesConfigMap tests whether the annotations reference the ConfigMap name in the namespace of the ingress.
+func (i *Ingress) ReferencesConfigMap(name string) bool {
+	return i.Action.ReferencesConfigMap(name)
+}
+
 // Service contains the same annotations as Ingress
 type Service Ingress
 
@@ -89,6 +104,9 @@ func NewServiceDummy() *Service {
 // Extractor defines the annotation parsers to be used in the extraction of annotations
 type Extractor struct {
 	annotations map[string]parser.IngressAnnotation
+
+	// cfg provides the annotation defaults of ingresses, none are applied if it's nil
+	cfg resolver.Resolver
 }
 
 // NewIngressAnnotationExtractor creates a new annotations extractor
@@ -96,12 +114,14 @@ func NewIngressAnnotationExtractor(cfg resolver.Resolver) Extractor {
 	return Extractor{
 		map[string]parser.IngressAnnotation{
 			"Action":       action.NewParser(cfg),
+			"Auth":         auth.NewParser(cfg),
 			"HealthCheck":  healthcheck.NewParser(cfg),
 			"TargetGroup":  targetgroup.NewParser(cfg),
 			"LoadBalancer": loadbalancer.NewParser(cfg),
 			"Listener":     listener.NewParser(cfg),
 			"Tags":         tags.NewParser(cfg),
 		},
+		cfg,
 	}
 }
 
@@ -114,6 +134,7 @@ func NewServiceAnnotationExtractor(cfg resolver.Resolver) Extractor {
 			"Listener":    listener.NewParser(cfg),
 			"Tags":        tags.NewParser(cfg),
 		},
+		nil,
 	}
 }
 
@@ -123,11 +144,53 @@ func (e Extractor) ExtractIngress(ing *extensions.Ingress) *Ingress {
 		ObjectMeta: ing.ObjectMeta,
 	}
 
-	i, err := e.extract(pia, ing)
+	i, err := e.extract(pia, e.withAnnotationDefaults(ing))
 	pia.Error = err
 	return i.(*Ingress)
 }
 
+// withAnnotationDefaults returns a copy of ing with the annotation defaults of the controller, ing if it has no configuration.
+func (e Extractor) withAnnotationDefaults(ing *extensions.Ingress) *extensions.Ingress {
+	if e.cfg == nil {
+		return ing
+	}
+	return WithAnnotationDefaults(e.cfg, ing)
+}
+
+// WithAnnotationDefaults returns a copy of ing whose annotations are the controller-wide annotation defaults of r,
+// overridden by the annotations of its namespace if NamespaceAnnotationDefaults is set, overridden by its own annotations.
+func WithAnnotationDefaults(r resolver.Resolver, ing *extensions.Ingress) *extensions.Ingress {
+	cfg := r.GetConfig()
+	var namespaceAnnos map[string]string
+	if cfg.NamespaceAnnotationDefaults {
+		namespace, err := r.GetNamespace(ing.Namespace)
+		if err != nil {
+			// annotations are parsed again when the namespace is watched.
+			glog.V(3).Infof("ingress %v/%v doesn't inherit annotations of its namespace due to %v", ing.Namespace, ing.Name, err)
+		} else {
+			namespaceAnnos = namespace.Annotations
+		}
+	}
+	if len(cfg.AnnotationDefaults) == 0 && len(namespaceAnnos) == 0 {
+		return ing
+	}
+	annos := make(map[string]string)
+	for key, value := range cfg.AnnotationDefaults {
+		annos[parser.GetAnnotationWithPrefix(key)] = value
+	}
+	for key, value := range namespaceAnnos {
+		if strings.HasPrefix(key, parser.GetAnnotationWithPrefix("")) {
+			annos[key] = value
+		}
+	}
+	for key, value := range ing.Annotations {
+		annos[key] = value
+	}
+	withDefaults := *ing
+	withDefaults.Annotations = annos
+	return &withDefaults
+}
+
 // ExtractService extracts the annotations from a Service
 func (e Extractor) ExtractService(svc *corev1.Service) *Service {
 	psa := &Service{
@@ -99,7 +99,7 @@ func buildIngress() *extensions.Ingress {
 func TestHealthCheck(t *testing.T) {
 	cfg := mockCfg{}
 	ec := Extractor{
-		map[string]parser.IngressAnnotation{
+		annotations: map[string]parser.IngressAnnotation{
 			"HealthCheck": healthcheck.NewParser(cfg),
 		},
 	}
@@ -305,3 +305,71 @@ func TestMerge(t *testing.T) {
 		assert.Equal(t, tc.ExpectedResult, actualResult)
 	}
 }
+
+type annotationDefaultsCfg struct {
+	mockCfg
+	defaults  map[string]string
+	namespace *apiv1.Namespace
+}
+
+func (m annotationDefaultsCfg) GetConfig() *config.Configuration {
+	return &config.Configuration{AnnotationDefaults: m.defaults, NamespaceAnnotationDefaults: m.namespace != nil}
+}
+
+func (m annotationDefaultsCfg) GetNamespace(name string) (*apiv1.Namespace, error) {
+	return m.namespace, nil
+}
+
+func TestExtractIngressWithAnnotationDefaults(t *testing.T) {
+	cfg := annotationDefaultsCfg{defaults: map[string]string{
+		"healthcheck-interval-seconds": "20",
+		"healthcheck-path":             "/healthz",
+	}}
+	ec := Extractor{
+		annotations: map[string]parser.IngressAnnotation{
+			"HealthCheck": healthcheck.NewParser(cfg),
+		},
+		cfg: cfg,
+	}
+	ing := buildIngress()
+	ing.SetAnnotations(map[string]string{annotationHealthcheckIntervalSeconds: "15"})
+
+	r := ec.ExtractIngress(ing)
+	assert.NoError(t, r.Error)
+	assert.Equal(t, aws.Int64(15), r.HealthCheck.IntervalSeconds)
+	assert.Equal(t, aws.String("/healthz"), r.HealthCheck.Path)
+	assert.Equal(t, map[string]string{annotationHealthcheckIntervalSeconds: "15"}, r.Annotations)
+}
+
+func TestExtractIngressWithNamespaceAnnotationDefaults(t *testing.T) {
+	cfg := annotationDefaultsCfg{
+		defaults: map[string]string{
+			"healthcheck-interval-seconds": "20",
+			"healthcheck-path":             "/healthz",
+			"healthcheck-port":             "8080",
+		},
+		namespace: &apiv1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: apiv1.NamespaceDefault,
+				Annotations: map[string]string{
+					parser.GetAnnotationWithPrefix("healthcheck-path"):             "/ready",
+					parser.GetAnnotationWithPrefix("healthcheck-interval-seconds"): "30",
+				},
+			},
+		},
+	}
+	ec := Extractor{
+		annotations: map[string]parser.IngressAnnotation{
+			"HealthCheck": healthcheck.NewParser(cfg),
+		},
+		cfg: cfg,
+	}
+	ing := buildIngress()
+	ing.SetAnnotations(map[string]string{annotationHealthcheckIntervalSeconds: "15"})
+
+	r := ec.ExtractIngress(ing)
+	assert.NoError(t, r.Error)
+	assert.Equal(t, aws.Int64(15), r.HealthCheck.IntervalSeconds)
+	assert.Equal(t, aws.String("/ready"), r.HealthCheck.Path)
+	assert.Equal(t, aws.String("8080"), r.HealthCheck.Port)
+}
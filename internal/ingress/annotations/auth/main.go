@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/resolver"
+)
+
+const (
+	// TypeNone doesn't authenticate users
+	TypeNone = "none"
+	// TypeOIDC authenticates users through an OpenID Connect identity provider
+	TypeOIDC = "oidc"
+
+	DefaultScope                    = "openid"
+	DefaultSessionCookie            = "AWSELBAuthSessionCookie"
+	DefaultSessionTimeout           = 604800
+	DefaultOnUnauthenticatedRequest = elbv2.AuthenticateOidcActionConditionalBehaviorEnumAuthenticate
+
+	// clientIDKey and clientSecretKey are the keys of the client credentials in the Secret of IDPOIDC
+	clientIDKey     = "clientId"
+	clientSecretKey = "clientSecret"
+)
+
+// IDPOIDC configures the OpenID Connect identity provider users are authenticated through
+type IDPOIDC struct {
+	Issuer                string
+	AuthorizationEndpoint string
+	TokenEndpoint         string
+	UserInfoEndpoint      string
+
+	// SecretName is the name of the Secret in the namespace of the ingress holding the client credentials under the keys clientId and clientSecret
+	SecretName string
+
+	// SecretArn is the ARN of the Secrets Manager secret whose value is the client secret, it's set instead of SecretName along with ClientID.
+	// The client secret is fetched when rules are reconciled, so it never lives in Kubernetes.
+	SecretArn string
+
+	// ClientID is read from the Secret SecretName unless SecretArn is set
+	ClientID     string
+	ClientSecret string `json:"-"`
+}
+
+type Config struct {
+	Type                     string
+	IDPOIDC                  *IDPOIDC
+	Scope                    string
+	SessionCookie            string
+	SessionTimeout           int64
+	OnUnauthenticatedRequest string
+}
+
+type auth struct {
+	r resolver.Resolver
+}
+
+// NewParser creates a new authentication annotation parser
+func NewParser(r resolver.Resolver) parser.IngressAnnotation {
+	return auth{r}
+}
+
+// Parse parses the annotations contained in the resource
+func (a auth) Parse(ing parser.AnnotationInterface) (interface{}, error) {
+	authType, err := parser.GetStringAnnotation("auth-type", ing)
+	if err != nil {
+		authType = aws.String(TypeNone)
+	}
+
+	scope, err := parser.GetStringAnnotation("auth-scope", ing)
+	if err != nil {
+		scope = aws.String(DefaultScope)
+	}
+
+	sessionCookie, err := parser.GetStringAnnotation("auth-session-cookie", ing)
+	if err != nil {
+		sessionCookie = aws.String(DefaultSessionCookie)
+	}
+
+	sessionTimeout, err := parser.GetInt64Annotation("auth-session-timeout", ing)
+	if err != nil {
+		sessionTimeout = aws.Int64(DefaultSessionTimeout)
+	}
+
+	onUnauthenticatedRequest, err := parser.GetStringAnnotation("auth-on-unauthenticated-request", ing)
+	if err != nil {
+		onUnauthenticatedRequest = aws.String(DefaultOnUnauthenticatedRequest)
+	}
+	switch *onUnauthenticatedRequest {
+	case elbv2.AuthenticateOidcActionConditionalBehaviorEnumDeny, elbv2.AuthenticateOidcActionConditionalBehaviorEnumAllow, elbv2.AuthenticateOidcActionConditionalBehaviorEnumAuthenticate:
+	default:
+		return nil, fmt.Errorf("invalid auth-on-unauthenticated-request %v, must be one of deny, allow or authenticate", *onUnauthenticatedRequest)
+	}
+
+	config := &Config{
+		Type:                     *authType,
+		Scope:                    *scope,
+		SessionCookie:            *sessionCookie,
+		SessionTimeout:           *sessionTimeout,
+		OnUnauthenticatedRequest: *onUnauthenticatedRequest,
+	}
+
+	switch config.Type {
+	case TypeNone:
+	case TypeOIDC:
+		if config.IDPOIDC, err = a.parseIDPOIDC(ing); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid auth-type %v, must be one of none or oidc", config.Type)
+	}
+	return config, nil
+}
+
+// parseIDPOIDC parses the auth-idp-oidc annotation, reading the client credentials from the Secret it references unless the client secret is read from Secrets Manager.
+func (a auth) parseIDPOIDC(ing parser.AnnotationInterface) (*IDPOIDC, error) {
+	raw, err := parser.GetStringAnnotation("auth-idp-oidc", ing)
+	if err != nil {
+		return nil, fmt.Errorf("auth-idp-oidc must be set when auth-type is oidc")
+	}
+	var idp IDPOIDC
+	if err := json.Unmarshal([]byte(*raw), &idp); err != nil {
+		return nil, fmt.Errorf("failed to parse auth-idp-oidc due to %v", err)
+	}
+	if idp.Issuer == "" || idp.AuthorizationEndpoint == "" || idp.TokenEndpoint == "" || idp.UserInfoEndpoint == "" {
+		return nil, fmt.Errorf("auth-idp-oidc must set issuer, authorizationEndpoint, tokenEndpoint and userInfoEndpoint")
+	}
+	switch {
+	case idp.SecretName != "" && idp.SecretArn != "":
+		return nil, fmt.Errorf("auth-idp-oidc must set only one of secretName or secretArn")
+	case idp.SecretArn != "":
+		if idp.ClientID == "" {
+			return nil, fmt.Errorf("auth-idp-oidc must set clientId along with secretArn")
+		}
+		return &idp, nil
+	case idp.SecretName == "":
+		return nil, fmt.Errorf("auth-idp-oidc must set secretName or secretArn")
+	case idp.ClientID != "":
+		return nil, fmt.Errorf("auth-idp-oidc must not set clientId along with secretName, it's read from the secret")
+	}
+
+	secretKey := fmt.Sprintf("%v/%v", parser.GetNamespace(ing), idp.SecretName)
+	secret, err := a.r.GetSecret(secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %v of auth-idp-oidc due to %v", secretKey, err)
+	}
+	clientID, ok := secret.Data[clientIDKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %v of auth-idp-oidc doesn't contain key %v", secretKey, clientIDKey)
+	}
+	clientSecret, ok := secret.Data[clientSecretKey]
+	if !ok {
+		return nil, fmt.Errorf("secret %v of auth-idp-oidc doesn't contain key %v", secretKey, clientSecretKey)
+	}
+	idp.ClientID = string(clientID)
+	idp.ClientSecret = string(clientSecret)
+	return &idp, nil
+}
+
+// Action returns the action authenticating users before the actions of rules, it's nil if users aren't authenticated.
+func (c *Config) Action() *elbv2.Action {
+	if c == nil || c.Type != TypeOIDC || c.IDPOIDC == nil {
+		return nil
+	}
+	// client secrets read from Secrets Manager are set when rules are reconciled
+	var clientSecret *string
+	if c.IDPOIDC.SecretArn == "" {
+		clientSecret = aws.String(c.IDPOIDC.ClientSecret)
+	}
+	return &elbv2.Action{
+		Type: aws.String(elbv2.ActionTypeEnumAuthenticateOidc),
+		AuthenticateOidcConfig: &elbv2.AuthenticateOidcActionConfig{
+			Issuer:                   aws.String(c.IDPOIDC.Issuer),
+			AuthorizationEndpoint:    aws.String(c.IDPOIDC.AuthorizationEndpoint),
+			TokenEndpoint:            aws.String(c.IDPOIDC.TokenEndpoint),
+			UserInfoEndpoint:         aws.String(c.IDPOIDC.UserInfoEndpoint),
+			ClientId:                 aws.String(c.IDPOIDC.ClientID),
+			ClientSecret:             clientSecret,
+			Scope:                    aws.String(c.Scope),
+			SessionCookieName:        aws.String(c.SessionCookie),
+			SessionTimeout:           aws.Int64(c.SessionTimeout),
+			OnUnauthenticatedRequest: aws.String(c.OnUnauthenticatedRequest),
+		},
+	}
+}
+
+// ReferencesSecret tests whether the client credentials are read from the Secret name in the namespace of the ingress.
+func (c *Config) ReferencesSecret(name string) bool {
+	return c != nil && c.IDPOIDC != nil && c.IDPOIDC.SecretName != "" && c.IDPOIDC.SecretName == name
+}
+
+// ClientSecretArn returns the ARN of the Secrets Manager secret whose value is the client secret, it's empty unless the client secret is read from Secrets Manager.
+func (c *Config) ClientSecretArn() string {
+	if c == nil || c.Type != TypeOIDC || c.IDPOIDC == nil {
+		return ""
+	}
+	return c.IDPOIDC.SecretArn
+}
+
+func Dummy() *Config {
+	return &Config{
+		Type:                     TypeNone,
+		Scope:                    DefaultScope,
+		SessionCookie:            DefaultSessionCookie,
+		SessionTimeout:           DefaultSessionTimeout,
+		OnUnauthenticatedRequest: DefaultOnUnauthenticatedRequest,
+	}
+}
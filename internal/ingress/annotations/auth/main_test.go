@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/dummy"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/resolver"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+type mockBackend struct {
+	resolver.Mock
+	secrets map[string]*corev1.Secret
+}
+
+func (m mockBackend) GetSecret(key string) (*corev1.Secret, error) {
+	secret, ok := m.secrets[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return secret, nil
+}
+
+func TestParse(t *testing.T) {
+	idpOIDC := `{"issuer": "https://idp", "authorizationEndpoint": "https://idp/authorize", "tokenEndpoint": "https://idp/token", "userInfoEndpoint": "https://idp/userinfo", "secretName": "oidc"}`
+	for _, tc := range []struct {
+		Name          string
+		Annotations   map[string]string
+		Secrets       map[string]*corev1.Secret
+		Expected      *Config
+		ExpectedError error
+	}{
+		{
+			Name:     "users aren't authenticated by default",
+			Expected: Dummy(),
+		},
+		{
+			Name: "oidc reads the client credentials from the secret",
+			Annotations: map[string]string{
+				"auth-type":            TypeOIDC,
+				"auth-idp-oidc":        idpOIDC,
+				"auth-session-timeout": "3600",
+			},
+			Secrets: map[string]*corev1.Secret{
+				"default/oidc": {Data: map[string][]byte{"clientId": []byte("id"), "clientSecret": []byte("secret")}},
+			},
+			Expected: &Config{
+				Type: TypeOIDC,
+				IDPOIDC: &IDPOIDC{
+					Issuer:                "https://idp",
+					AuthorizationEndpoint: "https://idp/authorize",
+					TokenEndpoint:         "https://idp/token",
+					UserInfoEndpoint:      "https://idp/userinfo",
+					SecretName:            "oidc",
+					ClientID:              "id",
+					ClientSecret:          "secret",
+				},
+				Scope:                    DefaultScope,
+				SessionCookie:            DefaultSessionCookie,
+				SessionTimeout:           3600,
+				OnUnauthenticatedRequest: DefaultOnUnauthenticatedRequest,
+			},
+		},
+		{
+			Name: "oidc reads the client secret from secrets manager",
+			Annotations: map[string]string{
+				"auth-type":     TypeOIDC,
+				"auth-idp-oidc": `{"issuer": "https://idp", "authorizationEndpoint": "https://idp/authorize", "tokenEndpoint": "https://idp/token", "userInfoEndpoint": "https://idp/userinfo", "clientId": "id", "secretArn": "arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc"}`,
+			},
+			Expected: &Config{
+				Type: TypeOIDC,
+				IDPOIDC: &IDPOIDC{
+					Issuer:                "https://idp",
+					AuthorizationEndpoint: "https://idp/authorize",
+					TokenEndpoint:         "https://idp/token",
+					UserInfoEndpoint:      "https://idp/userinfo",
+					SecretArn:             "arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc",
+					ClientID:              "id",
+				},
+				Scope:                    DefaultScope,
+				SessionCookie:            DefaultSessionCookie,
+				SessionTimeout:           DefaultSessionTimeout,
+				OnUnauthenticatedRequest: DefaultOnUnauthenticatedRequest,
+			},
+		},
+		{
+			Name: "oidc secretArn without clientId",
+			Annotations: map[string]string{
+				"auth-type":     TypeOIDC,
+				"auth-idp-oidc": `{"issuer": "https://idp", "authorizationEndpoint": "https://idp/authorize", "tokenEndpoint": "https://idp/token", "userInfoEndpoint": "https://idp/userinfo", "secretArn": "arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc"}`,
+			},
+			ExpectedError: errors.New("auth-idp-oidc must set clientId along with secretArn"),
+		},
+		{
+			Name: "oidc sets both secretName and secretArn",
+			Annotations: map[string]string{
+				"auth-type":     TypeOIDC,
+				"auth-idp-oidc": `{"issuer": "https://idp", "authorizationEndpoint": "https://idp/authorize", "tokenEndpoint": "https://idp/token", "userInfoEndpoint": "https://idp/userinfo", "secretName": "oidc", "secretArn": "arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc"}`,
+			},
+			ExpectedError: errors.New("auth-idp-oidc must set only one of secretName or secretArn"),
+		},
+		{
+			Name: "oidc secret is missing",
+			Annotations: map[string]string{
+				"auth-type":     TypeOIDC,
+				"auth-idp-oidc": idpOIDC,
+			},
+			ExpectedError: errors.New("failed to get secret default/oidc of auth-idp-oidc due to not found"),
+		},
+		{
+			Name: "oidc secret lacks the client secret",
+			Annotations: map[string]string{
+				"auth-type":     TypeOIDC,
+				"auth-idp-oidc": idpOIDC,
+			},
+			Secrets: map[string]*corev1.Secret{
+				"default/oidc": {Data: map[string][]byte{"clientId": []byte("id")}},
+			},
+			ExpectedError: errors.New("secret default/oidc of auth-idp-oidc doesn't contain key clientSecret"),
+		},
+		{
+			Name: "oidc without auth-idp-oidc",
+			Annotations: map[string]string{
+				"auth-type": TypeOIDC,
+			},
+			ExpectedError: errors.New("auth-idp-oidc must be set when auth-type is oidc"),
+		},
+		{
+			Name: "invalid auth-type",
+			Annotations: map[string]string{
+				"auth-type": "cognito",
+			},
+			ExpectedError: errors.New("invalid auth-type cognito, must be one of none or oidc"),
+		},
+		{
+			Name: "invalid auth-on-unauthenticated-request",
+			Annotations: map[string]string{
+				"auth-on-unauthenticated-request": "redirect",
+			},
+			ExpectedError: errors.New("invalid auth-on-unauthenticated-request redirect, must be one of deny, allow or authenticate"),
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ing := dummy.NewIngress()
+			data := map[string]string{}
+			for key, value := range tc.Annotations {
+				data[parser.GetAnnotationWithPrefix(key)] = value
+			}
+			ing.SetAnnotations(data)
+
+			config, err := NewParser(mockBackend{secrets: tc.Secrets}).Parse(ing)
+			if tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, config)
+		})
+	}
+}
+
+func TestConfig_Action(t *testing.T) {
+	assert.Nil(t, Dummy().Action())
+	assert.Nil(t, (*Config)(nil).Action())
+
+	config := Dummy()
+	config.Type = TypeOIDC
+	config.IDPOIDC = &IDPOIDC{
+		Issuer:                "https://idp",
+		AuthorizationEndpoint: "https://idp/authorize",
+		TokenEndpoint:         "https://idp/token",
+		UserInfoEndpoint:      "https://idp/userinfo",
+		SecretName:            "oidc",
+		ClientID:              "id",
+		ClientSecret:          "secret",
+	}
+	assert.Equal(t, &elbv2.Action{
+		Type: aws.String(elbv2.ActionTypeEnumAuthenticateOidc),
+		AuthenticateOidcConfig: &elbv2.AuthenticateOidcActionConfig{
+			Issuer:                   aws.String("https://idp"),
+			AuthorizationEndpoint:    aws.String("https://idp/authorize"),
+			TokenEndpoint:            aws.String("https://idp/token"),
+			UserInfoEndpoint:         aws.String("https://idp/userinfo"),
+			ClientId:                 aws.String("id"),
+			ClientSecret:             aws.String("secret"),
+			Scope:                    aws.String(DefaultScope),
+			SessionCookieName:        aws.String(DefaultSessionCookie),
+			SessionTimeout:           aws.Int64(DefaultSessionTimeout),
+			OnUnauthenticatedRequest: aws.String(DefaultOnUnauthenticatedRequest),
+		},
+	}, config.Action())
+	assert.True(t, config.ReferencesSecret("oidc"))
+	assert.False(t, config.ReferencesSecret("other"))
+}
+
+func TestConfig_ActionClientSecretArn(t *testing.T) {
+	config := Dummy()
+	assert.Equal(t, "", config.ClientSecretArn())
+
+	config.Type = TypeOIDC
+	config.IDPOIDC = &IDPOIDC{
+		Issuer:                "https://idp",
+		AuthorizationEndpoint: "https://idp/authorize",
+		TokenEndpoint:         "https://idp/token",
+		UserInfoEndpoint:      "https://idp/userinfo",
+		SecretArn:             "arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc",
+		ClientID:              "id",
+	}
+	assert.Equal(t, "arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc", config.ClientSecretArn())
+	// the client secret is set when rules are reconciled
+	assert.Nil(t, config.Action().AuthenticateOidcConfig.ClientSecret)
+	assert.False(t, config.ReferencesSecret(""))
+}
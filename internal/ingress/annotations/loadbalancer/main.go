@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"regexp"
 	"strings"
 
 	"github.com/aws/aws-sdk-go/service/elbv2"
@@ -44,6 +45,39 @@ type Config struct {
 	SecurityGroups []string
 	Subnets        []string
 	Attributes     []*elbv2.LoadBalancerAttribute
+
+	// ManageBackendSecurityGroupRules makes the controller manage inbound rules on the worker nodes
+	// for traffic from user provided SecurityGroups.
+	ManageBackendSecurityGroupRules bool
+
+	// Name is the name of LoadBalancer specified by user, which overrides the generated name.
+	Name *string
+
+	// RetainOnDelete makes the controller leave LoadBalancer and targetGroups in place when ingress is deleted.
+	RetainOnDelete bool
+
+	// AllowSchemeChange allows the controller to replace LoadBalancer when scheme changes, since scheme can't be modified in place.
+	AllowSchemeChange bool
+
+	// CustomerOwnedIPv4Pool is the ID of customer-owned IPv4 pool(CoIP) to allocate addresses from, for LoadBalancers on AWS Outposts.
+	CustomerOwnedIPv4Pool *string
+
+	// SplitBy is the policy to split ingress into multiple LoadBalancers, e.g. "host" gives each distinct host its own LoadBalancer.
+	SplitBy *string
+
+	// ExistingLoadBalancer is the ARN or name of an pre-provisioned LoadBalancer to be used by ingress.
+	// Only listeners, rules and targetGroups are managed on it, and it's never deleted.
+	ExistingLoadBalancer *string
+
+	// DryRun makes the controller plan changes to AWS resources of ingress without making them.
+	DryRun bool
+
+	// RoleARN is the ARN of an IAM role assumed for all AWS calls managing resources of ingress, e.g. in another account.
+	RoleARN *string
+
+	// CloudWatchAlarms makes the controller provision baseline CloudWatch alarms for LoadBalancer and its targetGroups,
+	// it defaults to the setting of controller if nil.
+	CloudWatchAlarms *bool
 }
 
 type loadBalancer struct {
@@ -53,6 +87,30 @@ type loadBalancer struct {
 const (
 	DefaultIPAddressType = elbv2.IpAddressTypeIpv4
 	DefaultScheme        = elbv2.LoadBalancerSchemeEnumInternal
+
+	// SplitByHost splits ingress into one LoadBalancer per distinct host of its rules.
+	SplitByHost = "host"
+
+	// maxNameLength is the maximum length of LoadBalancer name allowed by ELBV2
+	maxNameLength = 32
+
+	accessLogsS3EnabledKey = "access_logs.s3.enabled"
+	accessLogsS3BucketKey  = "access_logs.s3.bucket"
+	accessLogsS3PrefixKey  = "access_logs.s3.prefix"
+
+	connectionLogsS3EnabledKey = "connection_logs.s3.enabled"
+	connectionLogsS3BucketKey  = "connection_logs.s3.bucket"
+	connectionLogsS3PrefixKey  = "connection_logs.s3.prefix"
+
+	deletionProtectionEnabledKey = "deletion_protection.enabled"
+	idleTimeoutTimeoutSecondsKey = "idle_timeout.timeout_seconds"
+	routingHTTP2EnabledKey       = "routing.http2.enabled"
+	dropInvalidHeaderFieldsKey   = "routing.http.drop_invalid_header_fields.enabled"
+	desyncMitigationModeKey      = "routing.http.desync_mitigation_mode"
+
+	desyncMitigationModeMonitor   = "monitor"
+	desyncMitigationModeDefensive = "defensive"
+	desyncMitigationModeStrictest = "strictest"
 )
 
 // NewParser creates a new target group annotation parser
@@ -97,14 +155,114 @@ func (lb loadBalancer) Parse(ing parser.AnnotationInterface) (interface{}, error
 		return nil, err
 	}
 
+	attributes, err = parseBoolAttribute(ing, attributes, "deletion-protection-enabled", deletionProtectionEnabledKey)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err = parseBoolAttribute(ing, attributes, "http2-enabled", routingHTTP2EnabledKey)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err = parseBoolAttribute(ing, attributes, "drop-invalid-header-fields-enabled", dropInvalidHeaderFieldsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err = parseIdleTimeout(ing, attributes)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode, err := parser.GetStringAnnotation("desync-mitigation-mode", ing); err == nil {
+		switch *mode {
+		case desyncMitigationModeMonitor, desyncMitigationModeDefensive, desyncMitigationModeStrictest:
+		default:
+			return nil, errors.NewInvalidAnnotationContentReason(fmt.Sprintf("desync mitigation mode must be either `%v`, `%v` or `%v`",
+				desyncMitigationModeMonitor, desyncMitigationModeDefensive, desyncMitigationModeStrictest))
+		}
+		attributes = setAttribute(attributes, desyncMitigationModeKey, *mode)
+	}
+
+	attributes, err = parseLogs(ing, attributes, "access-logs", accessLogsS3EnabledKey, accessLogsS3BucketKey, accessLogsS3PrefixKey)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err = parseLogs(ing, attributes, "connection-logs", connectionLogsS3EnabledKey, connectionLogsS3BucketKey, connectionLogsS3PrefixKey)
+	if err != nil {
+		return nil, err
+	}
+
 	securityGroups := parser.GetStringSliceAnnotation("security-groups", ing)
 	subnets := parser.GetStringSliceAnnotation("subnets", ing)
 
+	manageBackendSGRules := false
+	if v, err := parser.GetBoolAnnotation("manage-backend-security-group-rules", ing); err == nil {
+		manageBackendSGRules = *v
+	} else if !errors.IsMissingAnnotations(err) {
+		return nil, err
+	}
+
 	cidrs, err := parseCidrs(ing)
 	if err != nil {
 		return nil, err
 	}
 
+	name, err := parseName(ing)
+	if err != nil {
+		return nil, err
+	}
+
+	allowSchemeChange := false
+	if v, err := parser.GetBoolAnnotation("allow-scheme-change", ing); err == nil {
+		allowSchemeChange = *v
+	} else if !errors.IsMissingAnnotations(err) {
+		return nil, err
+	}
+
+	retainOnDelete := false
+	if v, err := parser.GetBoolAnnotation("retain-on-delete", ing); err == nil {
+		retainOnDelete = *v
+	} else if !errors.IsMissingAnnotations(err) {
+		return nil, err
+	}
+
+	dryRun := false
+	if v, err := parser.GetBoolAnnotation("dry-run", ing); err == nil {
+		dryRun = *v
+	} else if !errors.IsMissingAnnotations(err) {
+		return nil, err
+	}
+
+	cloudWatchAlarms, err := parser.GetBoolAnnotation("cloudwatch-alarms", ing)
+	if err != nil && !errors.IsMissingAnnotations(err) {
+		return nil, err
+	}
+
+	coipPool, _ := parser.GetStringAnnotation("customer-owned-ipv4-pool", ing)
+	if coipPool != nil && !strings.HasPrefix(*coipPool, "ipv4pool-coip-") {
+		return nil, errors.NewInvalidAnnotationContentReason("customer-owned IPv4 pool must be an ID in `ipv4pool-coip-` format")
+	}
+
+	existingLB, _ := parser.GetStringAnnotation("existing-load-balancer", ing)
+
+	roleARN, _ := parser.GetStringAnnotation("role-arn", ing)
+	if roleARN != nil && !strings.HasPrefix(*roleARN, "arn:") {
+		return nil, errors.NewInvalidAnnotationContentReason("role-arn must be an IAM role ARN, e.g. `arn:aws:iam::123456789012:role/alb`")
+	}
+
+	splitBy, _ := parser.GetStringAnnotation("split-by", ing)
+	if splitBy != nil {
+		if *splitBy != SplitByHost {
+			return nil, errors.NewInvalidAnnotationContentReason(fmt.Sprintf("split-by must be `%s`", SplitByHost))
+		}
+		if name != nil || existingLB != nil {
+			return nil, errors.NewInvalidAnnotationContentReason("split-by can't be used with load-balancer-name or existing-load-balancer, since each host needs its own LoadBalancer")
+		}
+	}
+
 	return &Config{
 		WebACLId:      webACLId,
 		Scheme:        scheme,
@@ -114,11 +272,42 @@ func (lb loadBalancer) Parse(ing parser.AnnotationInterface) (interface{}, error
 		InboundCidrs: cidrs,
 		Ports:        ports,
 
-		Subnets:        subnets,
-		SecurityGroups: securityGroups,
+		Subnets:                         subnets,
+		SecurityGroups:                  securityGroups,
+		ManageBackendSecurityGroupRules: manageBackendSGRules,
+		Name:                            name,
+		CustomerOwnedIPv4Pool:           coipPool,
+		AllowSchemeChange:               allowSchemeChange,
+		RetainOnDelete:                  retainOnDelete,
+		ExistingLoadBalancer:            existingLB,
+		SplitBy:                         splitBy,
+		DryRun:                          dryRun,
+		RoleARN:                         roleARN,
+		CloudWatchAlarms:                cloudWatchAlarms,
 	}, nil
 }
 
+var namePattern = regexp.MustCompile("^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$")
+
+// parseName parses the load-balancer-name annotation, which must be an valid ELBV2 LoadBalancer name:
+// at most 32 alphanumeric characters or hyphens, not beginning or ending with an hyphen, and not beginning with "internal-".
+func parseName(ing parser.AnnotationInterface) (*string, error) {
+	name, err := parser.GetStringAnnotation("load-balancer-name", ing)
+	if err != nil {
+		return nil, nil
+	}
+	if len(*name) > maxNameLength {
+		return nil, errors.NewInvalidAnnotationContentReason(fmt.Sprintf("load balancer name must be no more than %d characters", maxNameLength))
+	}
+	if !namePattern.MatchString(*name) {
+		return nil, errors.NewInvalidAnnotationContentReason("load balancer name must contain only alphanumeric characters or hyphens, and must not begin or end with an hyphen")
+	}
+	if strings.HasPrefix(*name, "internal-") {
+		return nil, errors.NewInvalidAnnotationContentReason("load balancer name must not begin with `internal-`")
+	}
+	return name, nil
+}
+
 func parseAttributes(ing parser.AnnotationInterface) ([]*elbv2.LoadBalancerAttribute, error) {
 	var badAttrs []string
 	var lbattrs []*elbv2.LoadBalancerAttribute
@@ -154,6 +343,80 @@ func parseAttributes(ing parser.AnnotationInterface) ([]*elbv2.LoadBalancerAttri
 	return lbattrs, nil
 }
 
+// parseBoolAttribute merges the boolean annotation name into attrs as key. The annotation takes precedence over
+// the same key specified in the load-balancer-attributes annotation.
+func parseBoolAttribute(ing parser.AnnotationInterface, attrs []*elbv2.LoadBalancerAttribute, name, key string) ([]*elbv2.LoadBalancerAttribute, error) {
+	v, err := parser.GetBoolAnnotation(name, ing)
+	if err != nil {
+		if errors.IsMissingAnnotations(err) {
+			return attrs, nil
+		}
+		return nil, err
+	}
+	return setAttribute(attrs, key, fmt.Sprintf("%v", *v)), nil
+}
+
+// parseIdleTimeout merges the idle-timeout-seconds annotation into attrs. The annotation takes precedence over
+// idle_timeout.timeout_seconds specified in the load-balancer-attributes annotation.
+func parseIdleTimeout(ing parser.AnnotationInterface, attrs []*elbv2.LoadBalancerAttribute) ([]*elbv2.LoadBalancerAttribute, error) {
+	v, err := parser.GetInt64Annotation("idle-timeout-seconds", ing)
+	if err != nil {
+		if errors.IsMissingAnnotations(err) {
+			return attrs, nil
+		}
+		return nil, err
+	}
+	if *v < 1 || *v > 4000 {
+		return nil, errors.NewInvalidAnnotationContentReason(fmt.Sprintf("idle timeout must be within 1-4000 seconds, it was %d", *v))
+	}
+	return setAttribute(attrs, idleTimeoutTimeoutSecondsKey, fmt.Sprintf("%v", *v)), nil
+}
+
+// parseLogs merges the <name>-s3-enabled, <name>-s3-bucket and <name>-s3-prefix annotations into attrs. These
+// annotations take precedence over the same keys specified in the load-balancer-attributes annotation.
+func parseLogs(ing parser.AnnotationInterface, attrs []*elbv2.LoadBalancerAttribute, name, enabledKey, bucketKey, prefixKey string) ([]*elbv2.LoadBalancerAttribute, error) {
+	if enabled, err := parser.GetBoolAnnotation(name+"-s3-enabled", ing); err == nil {
+		attrs = setAttribute(attrs, enabledKey, fmt.Sprintf("%v", *enabled))
+	} else if !errors.IsMissingAnnotations(err) {
+		return nil, err
+	}
+	if bucket, err := parser.GetStringAnnotation(name+"-s3-bucket", ing); err == nil {
+		attrs = setAttribute(attrs, bucketKey, *bucket)
+	}
+	if prefix, err := parser.GetStringAnnotation(name+"-s3-prefix", ing); err == nil {
+		attrs = setAttribute(attrs, prefixKey, *prefix)
+	}
+
+	var enabled bool
+	var bucket string
+	for _, attr := range attrs {
+		switch aws.StringValue(attr.Key) {
+		case enabledKey:
+			enabled = aws.StringValue(attr.Value) == "true"
+		case bucketKey:
+			bucket = aws.StringValue(attr.Value)
+		}
+	}
+	if enabled && bucket == "" {
+		return nil, errors.NewInvalidAnnotationContentReason(fmt.Sprintf("an S3 bucket must be specified when %v are enabled", strings.Replace(name, "-", " ", -1)))
+	}
+	return attrs, nil
+}
+
+// setAttribute sets the value of key in attrs, appending it when it's not present yet.
+func setAttribute(attrs []*elbv2.LoadBalancerAttribute, key, value string) []*elbv2.LoadBalancerAttribute {
+	for _, attr := range attrs {
+		if aws.StringValue(attr.Key) == key {
+			attr.Value = aws.String(value)
+			return attrs
+		}
+	}
+	return append(attrs, &elbv2.LoadBalancerAttribute{
+		Key:   aws.String(key),
+		Value: aws.String(value),
+	})
+}
+
 // parsePorts takes a JSON array describing what ports and protocols should be used. When the JSON
 // is empty, implying the annotation was not present, desired ports are set to the default. The
 // default port value is 80 when a certArn is not present and 443 when it is.
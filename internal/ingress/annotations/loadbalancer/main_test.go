@@ -1 +1,68 @@
 package loadbalancer
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseName(t *testing.T) {
+	for _, tc := range []struct {
+		Name         string
+		Annotations  map[string]string
+		ExpectedName *string
+		ExpectErr    bool
+	}{
+		{
+			Name:         "annotation not specified",
+			Annotations:  map[string]string{},
+			ExpectedName: nil,
+		},
+		{
+			Name:         "valid name",
+			Annotations:  map[string]string{parser.GetAnnotationWithPrefix("load-balancer-name"): "team-a-prod-web"},
+			ExpectedName: aws.String("team-a-prod-web"),
+		},
+		{
+			Name:        "name too long",
+			Annotations: map[string]string{parser.GetAnnotationWithPrefix("load-balancer-name"): "a123456789012345678901234567890123"},
+			ExpectErr:   true,
+		},
+		{
+			Name:        "name with invalid characters",
+			Annotations: map[string]string{parser.GetAnnotationWithPrefix("load-balancer-name"): "team_a.web"},
+			ExpectErr:   true,
+		},
+		{
+			Name:        "name ends with hyphen",
+			Annotations: map[string]string{parser.GetAnnotationWithPrefix("load-balancer-name"): "team-a-"},
+			ExpectErr:   true,
+		},
+		{
+			Name:        "name begins with internal-",
+			Annotations: map[string]string{parser.GetAnnotationWithPrefix("load-balancer-name"): "internal-web"},
+			ExpectErr:   true,
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			ing := &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "namespace",
+					Name:        "ingress",
+					Annotations: tc.Annotations,
+				},
+			}
+			name, err := parseName(ing)
+			if tc.ExpectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.ExpectedName, name)
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var (
@@ -157,6 +158,14 @@ func GetInt64Annotation(name string, ing AnnotationInterface) (*int64, error) {
 	return ingAnnotations(ing.GetAnnotations()).parseInt64(v)
 }
 
+// GetNamespace returns the namespace of the annotated resource, which objects referenced by its annotations are looked up in.
+func GetNamespace(ing AnnotationInterface) string {
+	if o, ok := ing.(metav1.Object); ok {
+		return o.GetNamespace()
+	}
+	return ""
+}
+
 // GetAnnotationWithPrefix returns the prefix of ingress annotations
 func GetAnnotationWithPrefix(suffix string) string {
 	return fmt.Sprintf("%v/%v", AnnotationsPrefix, suffix)
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// credentialsRetryBaseDelay and credentialsRetryMaxDelay bound the retries of ingresses failing as AWS credentials couldn't be retrieved,
+	// which kube2iam, kiam or IRSA usually vend again within seconds.
+	credentialsRetryBaseDelay = time.Second
+	credentialsRetryMaxDelay  = 30 * time.Second
+)
+
+// reconcileBackoff tracks the consecutive reconcile failures of ingresses, each ingress is retried after an exponential backoff of its own failures,
+// so ingresses failing persistently don't delay the others.
+// Retries across all ingresses are further limited by an token bucket, if any, so many ingresses failing at once don't flood the AWS APIs with retries.
+type reconcileBackoff struct {
+	baseDelay time.Duration
+	maxDelay  time.Duration
+
+	// limiter is nil unless retries are limited across ingresses
+	limiter *rate.Limiter
+	now     func() time.Time
+	// random returns the jitter of credentials retries, in [0, 1)
+	random func() float64
+
+	mutex               sync.Mutex
+	failures            map[string]int
+	credentialsFailures map[string]int
+}
+
+func newReconcileBackoff(baseDelay time.Duration, maxDelay time.Duration, retryRate float32, retryBurst int) *reconcileBackoff {
+	b := &reconcileBackoff{
+		baseDelay: baseDelay,
+		maxDelay:  maxDelay,
+		now:       time.Now,
+		random:    rand.Float64,
+
+		failures:            make(map[string]int),
+		credentialsFailures: make(map[string]int),
+	}
+	if retryBurst > 0 {
+		b.limiter = rate.NewLimiter(rate.Limit(retryRate), retryBurst)
+	}
+	return b
+}
+
+// RecordFailure records an reconcile failure of ingress, and returns its consecutive failures and the delay before it's retried.
+func (b *reconcileBackoff) RecordFailure(ingressKey string) (int, time.Duration) {
+	b.mutex.Lock()
+	b.failures[ingressKey]++
+	failures := b.failures[ingressKey]
+	b.mutex.Unlock()
+
+	delay := b.baseDelay
+	for i := 1; i < failures && delay < b.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > b.maxDelay {
+		delay = b.maxDelay
+	}
+	if b.limiter != nil {
+		// the retry takes an token once it's due, it's delayed further if they're used up by then.
+		retryAt := b.now().Add(delay)
+		if limited := b.limiter.ReserveN(retryAt, 1).DelayFrom(retryAt); limited > 0 {
+			delay += limited
+		}
+	}
+	return failures, delay
+}
+
+// RecordCredentialsFailure records an reconcile failure of ingress as AWS credentials couldn't be retrieved, and returns the delay before it's retried.
+// Such failures don't count towards the backoff of ingress, they're retried after an short exponential backoff of their own with jitter,
+// so ingresses failing at once don't retry at once. They aren't limited by the token bucket since they fail before calling AWS APIs.
+func (b *reconcileBackoff) RecordCredentialsFailure(ingressKey string) time.Duration {
+	b.mutex.Lock()
+	b.credentialsFailures[ingressKey]++
+	failures := b.credentialsFailures[ingressKey]
+	b.mutex.Unlock()
+
+	delay := credentialsRetryBaseDelay
+	for i := 1; i < failures && delay < credentialsRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > credentialsRetryMaxDelay {
+		delay = credentialsRetryMaxDelay
+	}
+	return delay/2 + time.Duration(b.random()*float64(delay/2))
+}
+
+// RecordSuccess records an successful reconcile of ingress, resetting its backoff.
+func (b *reconcileBackoff) RecordSuccess(ingressKey string) {
+	b.mutex.Lock()
+	delete(b.failures, ingressKey)
+	delete(b.credentialsFailures, ingressKey)
+	b.mutex.Unlock()
+}
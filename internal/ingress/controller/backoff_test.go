@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileBackoff(t *testing.T) {
+	backoff := newReconcileBackoff(5*time.Second, time.Minute, 0, 0)
+	for _, expected := range []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute} {
+		_, delay := backoff.RecordFailure("namespace/ingress")
+		assert.Equal(t, expected, delay)
+	}
+	failures, delay := backoff.RecordFailure("namespace/other")
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, 5*time.Second, delay)
+
+	backoff.RecordSuccess("namespace/ingress")
+	failures, delay = backoff.RecordFailure("namespace/ingress")
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, 5*time.Second, delay)
+}
+
+func TestReconcileBackoffRateLimited(t *testing.T) {
+	now := time.Now()
+	backoff := newReconcileBackoff(5*time.Second, time.Minute, 0.1, 2)
+	backoff.now = func() time.Time { return now }
+	// retries within the burst are only backed off per ingress, later ones are also delayed by 10s each.
+	for i, expected := range []time.Duration{5 * time.Second, 5 * time.Second, 15 * time.Second, 25 * time.Second} {
+		_, delay := backoff.RecordFailure(fmt.Sprintf("namespace/ingress-%d", i))
+		assert.Equal(t, expected, delay)
+	}
+
+	now = now.Add(time.Minute)
+	_, delay := backoff.RecordFailure("namespace/ingress-0")
+	assert.Equal(t, 10*time.Second, delay)
+}
+
+func TestReconcileBackoffCredentialsFailures(t *testing.T) {
+	backoff := newReconcileBackoff(5*time.Second, time.Minute, 0.1, 1)
+	backoff.random = func() float64 { return 0.5 }
+	// credentials failures are retried within seconds, with jitter of up to half their delay.
+	for _, expected := range []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond, 3 * time.Second, 6 * time.Second, 12 * time.Second, 22500 * time.Millisecond, 22500 * time.Millisecond} {
+		assert.Equal(t, expected, backoff.RecordCredentialsFailure("namespace/ingress"))
+	}
+	backoff.random = func() float64 { return 0 }
+	assert.Equal(t, 15*time.Second, backoff.RecordCredentialsFailure("namespace/ingress"))
+
+	// they don't count towards the backoff of other failures.
+	failures, delay := backoff.RecordFailure("namespace/ingress")
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, 5*time.Second, delay)
+
+	backoff.RecordSuccess("namespace/ingress")
+	assert.Equal(t, 500*time.Millisecond, backoff.RecordCredentialsFailure("namespace/ingress"))
+}
@@ -1,9 +1,15 @@
 package config
 
 import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
 	"github.com/aws/aws-sdk-go/service/elbv2"
 	"github.com/spf13/pflag"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 const (
@@ -15,39 +21,234 @@ const (
 	defaultRestrictScheme          = false
 	defaultRestrictSchemeNamespace = corev1.NamespaceDefault
 	defaultSyncRateLimit           = 0.3
+	defaultSyncRateBurst           = 0
+	defaultMaxConcurrentReconciles = 1
+	defaultReconcileBackoffBase    = 5 * time.Second
+	defaultReconcileBackoffMax     = 15 * time.Minute
+	defaultAWSResyncPeriod         = 0 * time.Second
+	defaultDriftDetectionInterval  = 0 * time.Second
+
+	defaultAccessLogsBucketProvisioning   = false
+	defaultAccessLogsBucketExpirationDays = 90
+
+	defaultDisableDeletionProtectionOnDelete = false
+	defaultSharedSecurityGroups              = false
+	defaultSecurityGroupRulesLimit           = 60
+	defaultRetainResourcesOnDelete           = false
+	defaultIngressFinalizer                  = false
+	defaultDefaultTagsPrecedence             = false
+	defaultCloudWatchMetricsInterval         = 0 * time.Second
+	defaultHealthMetricsInterval             = time.Minute
+	defaultFailureNotificationThreshold      = 5
+
+	defaultClusterTagValue   = "owned"
+	defaultManagedByTagKey   = "ManagedBy"
+	defaultManagedByTagValue = "alb-ingress"
 )
 
 // Configuration contains all the settings required by an Ingress controller
 type Configuration struct {
 	ClusterName string
 
+	// ClusterTagKey and ClusterTagValue is the tag identifying resources owned by the cluster, the key defaults to kubernetes.io/cluster/<ClusterName>
+	ClusterTagKey   string
+	ClusterTagValue string
+
+	// ManagedByTagKey and ManagedByTagValue is the tag identifying securityGroups created by the controller
+	ManagedByTagKey   string
+	ManagedByTagValue string
+
 	// VpcID is the ID of worker node's VPC
 	VpcID string
 
 	// IngressClass is the ingress class that this controller will monitor for
 	IngressClass string
 
+	// WatchNamespaces are the namespaces of ingresses this controller reconciles, all namespaces are reconciled if empty
+	WatchNamespaces []string
+
+	// IngressLabelSelector selects the ingresses this controller reconciles by label, IngressSelector is parsed from it
+	IngressLabelSelector string
+	IngressSelector      labels.Selector
+
+	// ShardCount and ShardIndex make this controller reconcile the ingresses whose hashed namespace/name falls in shard ShardIndex of ShardCount, all ingresses are reconciled if ShardCount is less than 2
+	ShardCount int
+	ShardIndex int
+
 	AnnotationPrefix       string
 	ALBNamePrefix          string
 	DefaultTargetType      string
 	DefaultBackendProtocol string
 
+	// SyncRateLimit and SyncRateBurst are the token bucket limiting the retries of ingresses failing to reconcile, across all ingresses, retries are only backed off per ingress if SyncRateBurst is zero
 	SyncRateLimit float32
+	SyncRateBurst int
+
+	// MaxConcurrentReconciles is the number of ingresses reconciled concurrently
+	MaxConcurrentReconciles int
+
+	// ReconcileBackoffBase and ReconcileBackoffMax bound the exponential backoff of ingresses failing to reconcile
+	ReconcileBackoffBase time.Duration
+	ReconcileBackoffMax  time.Duration
+
+	// AWSResyncPeriod is the minimum interval between reconciles of an unchanged ingress by periodic resyncs, every resync reconciles it if zero
+	AWSResyncPeriod time.Duration
+	// DriftDetectionInterval is the interval at which AWS resources are checked for out-of-band changes, disabled if zero
+	DriftDetectionInterval time.Duration
 
 	RestrictScheme          bool
 	RestrictSchemeNamespace string
 
+	// AccessLogsBucketProvisioning enables creation of missing access logs buckets
+	AccessLogsBucketProvisioning bool
+	// AccessLogsBucketExpirationDays is the number of days access logs are retained in provisioned buckets
+	AccessLogsBucketExpirationDays int64
+
+	// DisableDeletionProtectionOnDelete allows the controller to turn off deletion protection of ALBs whose ingress was deleted
+	DisableDeletionProtectionOnDelete bool
+
+	// SharedSecurityGroups makes all ALBs share a single managed LoadBalancer and Instance securityGroup
+	SharedSecurityGroups bool
+
+	// SecurityGroupRulesLimit is the quota of inbound rules per securityGroup
+	SecurityGroupRulesLimit int
+
+	// RetainResourcesOnDelete leaves ALBs and targetGroups in place when their ingress is deleted
+	RetainResourcesOnDelete bool
+
+	// IngressFinalizer adds an finalizer to ingresses, so they're only removed once their AWS resources are deleted
+	IngressFinalizer bool
+
+	// SubnetDiscoveryTags are additional Key=Value tags that subnets must have to be auto-discovered
+	SubnetDiscoveryTags []string
+
+	// DefaultTags are tags in Key=Value format applied to every AWS resource created
+	DefaultTags []string
+
+	// DefaultTagsPrecedence makes DefaultTags and TagTemplates take precedence over the tags annotation of ingresses
+	DefaultTagsPrecedence bool
+
+	// TagTemplates are tags in Key=Template format applied to every AWS resource created, rendered with the namespace and ingress
+	TagTemplates []string
+
+	// IgnoredTagPrefixes are prefixes of tag keys that are never removed from ALBs and targetGroups, e.g. tags added by other tools
+	IgnoredTagPrefixes []string
+
+	// AllowedRoleARNs are the roles that ingresses can assume by the role-arn annotation, none is allowed by default
+	AllowedRoleARNs []string
+
+	// RequiredTags are tag keys that every ALB must have, ingresses without them are rejected
+	RequiredTags []string
+
+	// RequireWAF makes internet-facing ALBs without webACL rejected
+	RequireWAF bool
+	// DefaultWebACLID is the webACL associated with internet-facing ALBs without one
+	DefaultWebACLID string
+
+	// CloudWatchMetricsInterval is the interval to pull CloudWatch metrics of ALBs at, it's disabled if not positive
+	CloudWatchMetricsInterval time.Duration
+
+	// HealthMetricsNamespace is the CloudWatch namespace health metrics of the controller are published to every HealthMetricsInterval, it's disabled if empty
+	HealthMetricsNamespace string
+	HealthMetricsInterval  time.Duration
+
+	// CloudWatchAlarms provisions baseline CloudWatch alarms for ALBs of ingresses without the cloudwatch-alarms annotation,
+	// CloudWatchAlarmActions are the ARNs notified when they go into alarm
+	CloudWatchAlarms       bool
+	CloudWatchAlarmActions []string
+
+	// Route53HostedZoneID is the hosted zone alias records of the hosts of ingresses pointing at their ALB are managed in, they're not managed if empty
+	Route53HostedZoneID string
+
+	// FailureNotificationTopicARN is the SNS topic notified of ingresses failed to reconcile FailureNotificationThreshold times in a row, it's disabled if empty
+	FailureNotificationTopicARN  string
+	FailureNotificationThreshold int
+
+	// DryRun makes the controller plan changes to AWS resources of all ingresses without making them
+	DryRun bool
+
+	// DebugEndpointTokenFile is the file containing the bearer token of the debug endpoint, it's disabled if empty
+	DebugEndpointTokenFile string
+
+	// AnnotationDefaultsFile is the YAML file of annotations injected into ingresses without them by the mutating admission webhook, it's disabled if empty
+	AnnotationDefaultsFile string
+
+	// PolicyFile is the YAML file of policies constraining the annotations of ingresses, they're not constrained if empty
+	PolicyFile string
+
+	// NamespaceAnnotationDefaults makes ingresses inherit the annotations of their namespace, taking precedence over AnnotationDefaults
+	NamespaceAnnotationDefaults bool
+
+	// AnnotationDefaultsConfigMap is the namespace/name of the ConfigMap of controller-wide annotation defaults, none are applied if empty
+	AnnotationDefaultsConfigMap string
+
+	// StateConfigMap is the namespace/name of the ConfigMap the state of reconciled ingresses is persisted in across restarts, it's not persisted if empty
+	StateConfigMap string
+
 	// InternetFacingIngresses is an dynamic setting that can be updated by configMaps
 	InternetFacingIngresses map[string][]string
+
+	// AnnotationDefaults is an dynamic setting that can be updated by configMaps, mapping annotations without prefix to the values of ingresses without them
+	AnnotationDefaults map[string]string
+}
+
+// IsWatchedNamespace tests whether ingresses in namespace are reconciled, which are ingresses in WatchNamespaces if it's not empty.
+func (config *Configuration) IsWatchedNamespace(namespace string) bool {
+	if len(config.WatchNamespaces) == 0 {
+		return true
+	}
+	for _, watched := range config.WatchNamespaces {
+		if watched == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// IsShardedIngress tests whether the ingress namespace/name falls in the shard of this controller.
+func (config *Configuration) IsShardedIngress(namespace string, name string) bool {
+	if config.ShardCount < 2 {
+		return true
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(fmt.Sprintf("%v/%v", namespace, name)))
+	return int(hash.Sum32()%uint32(config.ShardCount)) == config.ShardIndex
+}
+
+// IsWatchedIngress tests whether ingress is reconciled by this controller, by its namespace, labels and shard.
+func (config *Configuration) IsWatchedIngress(ingress metav1.Object) bool {
+	if !config.IsWatchedNamespace(ingress.GetNamespace()) || !config.IsShardedIngress(ingress.GetNamespace(), ingress.GetName()) {
+		return false
+	}
+	return config.IngressSelector == nil || config.IngressSelector.Matches(labels.Set(ingress.GetLabels()))
 }
 
 // BindFlags will bind the commandline flags to fields in config
 func (config *Configuration) BindFlags(flags *pflag.FlagSet) {
-	flags.StringVar(&config.ClusterName, "cluster-name", "", `Kubernetes cluster name (required)`)
+	flags.StringVar(&config.ClusterName, "cluster-name", "", `Kubernetes cluster name. Discovered from the tags of the node if empty, on EKS and eksctl clusters.`)
+	flags.StringVar(&config.VpcID, "aws-vpc-id", "",
+		`ID of the VPC of the cluster. Defaults to the AWS_VPC_ID environment variable, or the VPC of the node from its instance metadata.`)
+	flags.StringVar(&config.ClusterTagKey, "cluster-tag-key", "",
+		`Key of the tag identifying AWS resources owned by the cluster, and subnets used by the cluster. Defaults to kubernetes.io/cluster/<cluster-name>.`)
+	flags.StringVar(&config.ClusterTagValue, "cluster-tag-value", defaultClusterTagValue,
+		`Value of the tag identifying AWS resources owned by the cluster.`)
+	flags.StringVar(&config.ManagedByTagKey, "managed-by-tag-key", defaultManagedByTagKey,
+		`Key of the tag identifying security groups created by the controller.`)
+	flags.StringVar(&config.ManagedByTagValue, "managed-by-tag-value", defaultManagedByTagValue,
+		`Value of the tag identifying security groups created by the controller.`)
 	flags.StringVar(&config.IngressClass, "ingress-class", defaultIngressClass,
 		`Name of the ingress class this controller satisfies.
 		The class of an Ingress object is set using the annotation "kubernetes.io/ingress.class".
 		All ingress classes are satisfied if this parameter is left empty.`)
+	flags.StringSliceVar(&config.WatchNamespaces, "watch-namespaces", nil,
+		`Namespaces of ingresses the controller reconciles, e.g. --watch-namespaces=team-a,team-b, so multiple controllers can split a cluster by namespace.
+		With a single namespace, only objects in it are watched like with --watch-namespace. Mutually exclusive with --watch-namespace.`)
+	flags.StringVar(&config.IngressLabelSelector, "ingress-label-selector", "",
+		`Label selector of ingresses the controller reconciles, e.g. shard=a, so multiple controllers can claim disjoint sets of ingresses. All ingresses are reconciled if empty.`)
+	flags.IntVar(&config.ShardCount, "shard-count", 1,
+		`Number of controllers splitting ingresses by the hash of their namespace/name, each reconciling the ingresses in its --shard-index.`)
+	flags.IntVar(&config.ShardIndex, "shard-index", 0,
+		`Index of the shard of ingresses the controller reconciles, from 0 to --shard-count - 1.`)
 	flags.StringVar(&config.AnnotationPrefix, "annotations-prefix", defaultAnnotationPrefix,
 		`Prefix of the Ingress annotations specific to the AWS ALB controller.`)
 
@@ -58,9 +259,83 @@ func (config *Configuration) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&config.DefaultBackendProtocol, "backend-protocol", defaultBackendProtocol,
 		`Default target type to use for target groups, must be "instance" or "ip"`)
 	flags.Float32Var(&config.SyncRateLimit, "sync-rate-limit", defaultSyncRateLimit,
-		`Define the sync frequency upper limit`)
+		`Retries per second of ingresses failing to reconcile, across all ingresses, once the burst of --sync-rate-burst is used up`)
+	flags.IntVar(&config.SyncRateBurst, "sync-rate-burst", defaultSyncRateBurst,
+		`Number of retries of ingresses failing to reconcile made before they're limited to --sync-rate-limit per second, retries are only backed off per ingress if 0`)
+	flags.IntVar(&config.MaxConcurrentReconciles, "max-concurrent-reconciles", defaultMaxConcurrentReconciles,
+		`Number of ingresses reconciled concurrently, e.g. 10 for clusters with hundreds of ingresses. An ingress is never reconciled concurrently with itself, and ingresses sharing an existing LoadBalancer or shared securityGroups are serialized on them.`)
+	flags.DurationVar(&config.ReconcileBackoffBase, "reconcile-backoff-base-delay", defaultReconcileBackoffBase,
+		`Delay before an ingress failing to reconcile is retried by the sync queue, doubled on each consecutive failure of the ingress`)
+	flags.DurationVar(&config.ReconcileBackoffMax, "reconcile-backoff-max-delay", defaultReconcileBackoffMax,
+		`Maximum delay before an ingress failing to reconcile is retried by the sync queue`)
+	flags.DurationVar(&config.AWSResyncPeriod, "aws-resync-period", defaultAWSResyncPeriod,
+		`Minimum interval between full reconciles of an unchanged ingress against AWS by the periodic resync of --sync-period, e.g. 1h. The resync reconciles every ingress if 0.`)
+	flags.DurationVar(&config.DriftDetectionInterval, "drift-detection-interval", defaultDriftDetectionInterval,
+		`Interval at which the tags and attributes of AWS resources are checked for out-of-band changes, e.g. 2m. Ingresses whose resources changed are reconciled. Disabled if 0.`)
 	flags.BoolVar(&config.RestrictScheme, "restrict-scheme", defaultRestrictScheme,
 		`Restrict the scheme to internal except for whitelisted namespaces`)
 	flags.StringVar(&config.RestrictSchemeNamespace, "restrict-scheme-namespace", defaultRestrictSchemeNamespace,
 		`The namespace with the ConfigMap containing the allowed ingresses. Only respected when restrict-scheme is true.`)
+	flags.BoolVar(&config.AccessLogsBucketProvisioning, "access-logs-bucket-provisioning", defaultAccessLogsBucketProvisioning,
+		`Create the access logs S3 bucket, with a bucket policy allowing log delivery, when it doesn't exist`)
+	flags.Int64Var(&config.AccessLogsBucketExpirationDays, "access-logs-bucket-expiration-days", defaultAccessLogsBucketExpirationDays,
+		`Number of days access logs are kept in buckets created by the controller. Only respected when access-logs-bucket-provisioning is true.`)
+	flags.BoolVar(&config.DisableDeletionProtectionOnDelete, "disable-deletion-protection-on-delete", defaultDisableDeletionProtectionOnDelete,
+		`Disable deletion protection of an ALB when its ingress is deleted. When false, ALBs with deletion protection enabled are left in place and an event is emitted.`)
+	flags.BoolVar(&config.SharedSecurityGroups, "shared-security-groups", defaultSharedSecurityGroups,
+		`Use a single managed securityGroup shared by all ALBs, and a single securityGroup for worker nodes, instead of creating them per ALB`)
+	flags.IntVar(&config.SecurityGroupRulesLimit, "security-group-rules-limit", defaultSecurityGroupRulesLimit,
+		`Maximum number of inbound rules per securityGroup, it should match the quota of your account`)
+	flags.BoolVar(&config.RetainResourcesOnDelete, "retain-resources-on-delete", defaultRetainResourcesOnDelete,
+		`Leave the ALB and target groups of an ingress in place, tagged as orphaned, when the ingress is deleted`)
+	flags.BoolVar(&config.IngressFinalizer, "ingress-finalizer", defaultIngressFinalizer,
+		`Add a finalizer to ingresses, so they're only removed from the cluster once their AWS resources are deleted. Disabled by default. Finalizers already added are still removed after cleanup when disabled.`)
+	flags.StringSliceVar(&config.SubnetDiscoveryTags, "subnet-discovery-tags", nil,
+		`Additional tags in Key=Value format that subnets must have to be auto-discovered for ALBs, e.g. Tier=public. Multiple values of the same key match any of them.`)
+	flags.StringSliceVar(&config.DefaultTags, "default-tags", nil,
+		`Tags in Key=Value format applied to every AWS resource created, e.g. owner=platform. Tags of the same keys in the tags annotation of ingresses take precedence unless --default-tags-precedence is set.`)
+	flags.BoolVar(&config.DefaultTagsPrecedence, "default-tags-precedence", defaultDefaultTagsPrecedence,
+		`Make the default tags and tag templates take precedence over tags of the same keys in the tags annotation of ingresses.`)
+	flags.StringSliceVar(&config.TagTemplates, "tag-templates", nil,
+		`Tags in Key=Template format applied to every AWS resource created, where Template is an Go template rendered with the namespace and ingress, e.g. team={{ .Namespace.Labels.team }}. Tags rendered to empty values are omitted.`)
+	flags.StringSliceVar(&config.IgnoredTagPrefixes, "ignored-tag-prefixes", nil,
+		`Prefixes of tag keys that are left in place when they're not specified for an ALB or target group, e.g. tags added out-of-band by backup or cost tools. Tags prefixed by aws: are always left in place.`)
+	flags.StringSliceVar(&config.AllowedRoleARNs, "allowed-role-arns", nil,
+		`ARNs of the IAM roles that ingresses can assume by the role-arn annotation. Ingresses with other roles are not reconciled, the annotation is denied unless its role is listed.`)
+	flags.StringSliceVar(&config.RequiredTags, "required-tags", nil,
+		`Tag keys that the ALB of every ingress must have, via the tags annotation or tag templates. Ingresses missing any of them are not reconciled.`)
+	flags.BoolVar(&config.RequireWAF, "require-waf", false,
+		`Require every internet-facing ALB to have an WAF webACL, via the web-acl-id annotation or --default-web-acl-id. Ingresses without one are not reconciled.`)
+	flags.StringVar(&config.DefaultWebACLID, "default-web-acl-id", "",
+		`ID of the WAF webACL associated with internet-facing ALBs without the web-acl-id annotation.`)
+	flags.DurationVar(&config.CloudWatchMetricsInterval, "cloudwatch-metrics-interval", defaultCloudWatchMetricsInterval,
+		`Interval to pull CloudWatch metrics(ConsumedLCUs, RequestCount, 5XX counts) of ALBs at and expose them as Prometheus metrics labeled by ingress, e.g. 5m. Rounded to whole minutes. Disabled if not set.`)
+	flags.StringVar(&config.HealthMetricsNamespace, "cloudwatch-health-metrics-namespace", "",
+		`CloudWatch namespace to publish health metrics of the controller to, e.g. ALBIngressController. Disabled if not set.`)
+	flags.DurationVar(&config.HealthMetricsInterval, "cloudwatch-health-metrics-interval", defaultHealthMetricsInterval,
+		`Interval to publish health metrics of the controller to CloudWatch at.`)
+	flags.BoolVar(&config.CloudWatchAlarms, "cloudwatch-alarms", false,
+		`Create baseline CloudWatch alarms(target 5XX count, target response time, unhealthy hosts per target group) for the ALBs of ingresses without the cloudwatch-alarms annotation. Alarms are deleted with their ALB.`)
+	flags.StringSliceVar(&config.CloudWatchAlarmActions, "cloudwatch-alarm-actions", nil,
+		`ARNs of the actions of CloudWatch alarms created by the controller when they go into alarm, e.g. SNS topics.`)
+	flags.StringVar(&config.Route53HostedZoneID, "route53-hosted-zone-id", "",
+		`ID of the Route53 hosted zone to manage alias records in, pointing the hosts of ingresses within the zone at their ALB, for clusters without external-dns. Disabled if not set.`)
+	flags.StringVar(&config.FailureNotificationTopicARN, "failure-notification-topic-arn", "",
+		`ARN of the SNS topic notified when an ingress fails to reconcile --failure-notification-threshold times in a row, and when it's reconciled again afterwards. Disabled if not set.`)
+	flags.BoolVar(&config.DryRun, "dry-run", false,
+		`Plan the changes to AWS resources of ingresses without making them, the planned changes are logged and emitted as events on ingresses. Status of ingresses is not updated.`)
+	flags.StringVar(&config.DebugEndpointTokenFile, "debug-endpoint-token-file", "",
+		`Path of the file containing the bearer token of the /debug/ingresses/<namespace>/<name> endpoint on the healthz port, e.g. mounted from a secret. Disabled if not set.`)
+	flags.StringVar(&config.AnnotationDefaultsFile, "annotation-defaults-file", "",
+		`Path of an YAML file mapping annotations without prefix to default values, e.g. scheme: internal, which the mutating admission webhook injects into ingresses without them. Requires --webhook-port.`)
+	flags.StringVar(&config.AnnotationDefaultsConfigMap, "annotation-defaults-configmap", "",
+		`Namespace/name of the ConfigMap mapping annotations without prefix to default values, e.g. scheme: internal, which ingresses without them inherit. Ingresses are reconciled again when it changes.`)
+	flags.StringVar(&config.StateConfigMap, "state-configmap", "",
+		`Namespace/name of the ConfigMap the target groups of reconciled ingresses are persisted in, so that ingresses unchanged since they were last reconciled aren't reconciled in full again when the controller restarts. Disabled if not set.`)
+	flags.BoolVar(&config.NamespaceAnnotationDefaults, "namespace-annotation-defaults", false,
+		`Make ingresses inherit the annotations of their namespace, e.g. alb.ingress.kubernetes.io/scheme: internal, unless they specify them. They take precedence over the defaults of --annotation-defaults-configmap.`)
+	flags.StringVar(&config.PolicyFile, "policy-file", "",
+		`Path of an YAML file with policies constraining the annotations of ingresses per namespace, e.g. allowed schemes or CIDRs inbound CIDRs must be within. Ingresses violating them are not reconciled, and rejected by the validating admission webhook.`)
+	flags.IntVar(&config.FailureNotificationThreshold, "failure-notification-threshold", defaultFailureNotificationThreshold,
+		`Number of consecutive reconcile failures of an ingress, retried with backoff, before the SNS topic is notified.`)
 }
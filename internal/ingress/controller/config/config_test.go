@@ -1 +1,70 @@
 package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestIsShardedIngress(t *testing.T) {
+	assert.True(t, (&Configuration{}).IsShardedIngress("namespace", "ingress"))
+
+	counts := make([]int, 3)
+	for i := 0; i < 300; i++ {
+		claimed := 0
+		for index := range counts {
+			config := &Configuration{ShardCount: len(counts), ShardIndex: index}
+			if config.IsShardedIngress("namespace", fmt.Sprintf("ingress-%v", i)) {
+				counts[index]++
+				claimed++
+			}
+		}
+		assert.Equal(t, 1, claimed)
+	}
+	for _, count := range counts {
+		assert.NotZero(t, count)
+	}
+}
+
+func TestIsWatchedIngress(t *testing.T) {
+	selector, err := labels.Parse("shard=a")
+	assert.NoError(t, err)
+	for _, tc := range []struct {
+		name     string
+		config   *Configuration
+		ingress  *metav1.ObjectMeta
+		expected bool
+	}{
+		{
+			name:     "all ingresses",
+			config:   &Configuration{},
+			ingress:  &metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"},
+			expected: true,
+		},
+		{
+			name:     "selected by labels",
+			config:   &Configuration{IngressSelector: selector},
+			ingress:  &metav1.ObjectMeta{Namespace: "namespace", Name: "ingress", Labels: map[string]string{"shard": "a"}},
+			expected: true,
+		},
+		{
+			name:     "not selected by labels",
+			config:   &Configuration{IngressSelector: selector},
+			ingress:  &metav1.ObjectMeta{Namespace: "namespace", Name: "ingress", Labels: map[string]string{"shard": "b"}},
+			expected: false,
+		},
+		{
+			name:     "other namespace",
+			config:   &Configuration{WatchNamespaces: []string{"team-a"}, IngressSelector: selector},
+			ingress:  &metav1.ObjectMeta{Namespace: "namespace", Name: "ingress", Labels: map[string]string{"shard": "a"}},
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.config.IsWatchedIngress(tc.ingress))
+		})
+	}
+}
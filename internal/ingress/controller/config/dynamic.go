@@ -2,12 +2,15 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -21,7 +24,8 @@ const restrictIngressConfigMap = "alb-ingress-controller-internet-facing-ingress
 
 // TODO: I'd prefer to keep config an plain data structure, and move this logic into the object that manages configuration, like current "store" object. Will move this logic there once i clean up the store object.
 // BindDynamicSettings will force initial load of these dynamic settings from configMaps, and setup watcher for configMap changes.
-func (config *Configuration) BindDynamicSettings(mgr manager.Manager, c controller.Controller, cloud aws.CloudAPI) error {
+// onAnnotationDefaultsChange is called after the annotation defaults are reloaded, to reconcile ingresses inheriting them again.
+func (config *Configuration) BindDynamicSettings(mgr manager.Manager, c controller.Controller, cloud aws.CloudAPI, onAnnotationDefaultsChange func(workqueue.RateLimitingInterface)) error {
 	if config.RestrictScheme {
 		if err := config.initInternetFacingIngresses(mgr.GetClient()); err != nil {
 			return err
@@ -30,6 +34,14 @@ func (config *Configuration) BindDynamicSettings(mgr manager.Manager, c controll
 			return err
 		}
 	}
+	if config.AnnotationDefaultsConfigMap != "" {
+		if err := config.initAnnotationDefaults(mgr.GetClient()); err != nil {
+			return err
+		}
+		if err := config.watchAnnotationDefaults(c, onAnnotationDefaultsChange); err != nil {
+			return err
+		}
+	}
 	if config.VpcID == "" {
 		vpcID, err := cloud.GetVPCID()
 		if err != nil {
@@ -98,3 +110,72 @@ func (config *Configuration) isRestrictIngressConfigMap(meta metav1.Object) bool
 	return (meta.GetNamespace() == config.RestrictSchemeNamespace) &&
 		(meta.GetName() == restrictIngressConfigMap)
 }
+
+func (config *Configuration) initAnnotationDefaults(client client.Client) error {
+	configMap := &corev1.ConfigMap{}
+	if err := client.Get(context.Background(), config.annotationDefaultsConfigMapKey(), configMap); err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get annotation defaults configMap %v due to %v", config.AnnotationDefaultsConfigMap, err)
+		}
+		configMap = nil
+	}
+	config.loadAnnotationDefaults(configMap)
+
+	return nil
+}
+
+func (config *Configuration) watchAnnotationDefaults(c controller.Controller, onChange func(workqueue.RateLimitingInterface)) error {
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.Funcs{
+		CreateFunc: func(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+			if config.isAnnotationDefaultsConfigMap(e.Meta) {
+				config.loadAnnotationDefaults(e.Object.(*corev1.ConfigMap))
+				onChange(q)
+			}
+		},
+		UpdateFunc: func(e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+			if config.isAnnotationDefaultsConfigMap(e.MetaNew) {
+				config.loadAnnotationDefaults(e.ObjectNew.(*corev1.ConfigMap))
+				onChange(q)
+			}
+		},
+		DeleteFunc: func(e event.DeleteEvent, q workqueue.RateLimitingInterface) {
+			if config.isAnnotationDefaultsConfigMap(e.Meta) {
+				config.loadAnnotationDefaults(nil)
+				onChange(q)
+			}
+		},
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// loadAnnotationDefaults will load the AnnotationDefaults settings from configMap.
+// The Key:Value pair are interpreted as "annotation without prefix: default value"
+func (config *Configuration) loadAnnotationDefaults(configMap *corev1.ConfigMap) {
+	defaults := make(map[string]string)
+	if configMap != nil {
+		for annotation, value := range configMap.Data {
+			defaults[annotation] = value
+		}
+	}
+	config.AnnotationDefaults = defaults
+}
+
+func (config *Configuration) annotationDefaultsConfigMapKey() types.NamespacedName {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(config.AnnotationDefaultsConfigMap)
+	return types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+// StateConfigMapKey returns the key of the ConfigMap the state of ingresses is persisted in.
+func (config *Configuration) StateConfigMapKey() types.NamespacedName {
+	namespace, name, _ := cache.SplitMetaNamespaceKey(config.StateConfigMap)
+	return types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+func (config *Configuration) isAnnotationDefaultsConfigMap(meta metav1.Object) bool {
+	key := config.annotationDefaultsConfigMapKey()
+	return (meta.GetNamespace() == key.Namespace) &&
+		(meta.GetName() == key.Name)
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
+)
+
+// ReloadableFlags are the flags of settings read when they're used, which are reloaded from the config file without restarting the controller.
+// They must match the settings set by Reload.
+var ReloadableFlags = []string{
+	"target-type",
+	"backend-protocol",
+	"access-logs-bucket-provisioning",
+	"access-logs-bucket-expiration-days",
+	"disable-deletion-protection-on-delete",
+	"security-group-rules-limit",
+	"retain-resources-on-delete",
+	"subnet-discovery-tags",
+	"default-tags",
+	"default-tags-precedence",
+	"required-tags",
+	"require-waf",
+	"default-web-acl-id",
+	"cloudwatch-alarms",
+	"cloudwatch-alarm-actions",
+	"dry-run",
+}
+
+// Reloaded returns an copy of config with the settings of ReloadableFlags set to those of other.
+// config itself is left as is, since it may be read concurrently.
+func (config *Configuration) Reloaded(other *Configuration) *Configuration {
+	reloaded := *config
+	reloaded.DefaultTargetType = other.DefaultTargetType
+	reloaded.DefaultBackendProtocol = other.DefaultBackendProtocol
+	reloaded.AccessLogsBucketProvisioning = other.AccessLogsBucketProvisioning
+	reloaded.AccessLogsBucketExpirationDays = other.AccessLogsBucketExpirationDays
+	reloaded.DisableDeletionProtectionOnDelete = other.DisableDeletionProtectionOnDelete
+	reloaded.SecurityGroupRulesLimit = other.SecurityGroupRulesLimit
+	reloaded.RetainResourcesOnDelete = other.RetainResourcesOnDelete
+	reloaded.SubnetDiscoveryTags = other.SubnetDiscoveryTags
+	reloaded.DefaultTags = other.DefaultTags
+	reloaded.DefaultTagsPrecedence = other.DefaultTagsPrecedence
+	reloaded.RequiredTags = other.RequiredTags
+	reloaded.RequireWAF = other.RequireWAF
+	reloaded.DefaultWebACLID = other.DefaultWebACLID
+	reloaded.CloudWatchAlarms = other.CloudWatchAlarms
+	reloaded.CloudWatchAlarmActions = other.CloudWatchAlarmActions
+	reloaded.DryRun = other.DryRun
+	return &reloaded
+}
+
+// Holder holds the current Configuration of the controller. Configurations are never modified once they're held,
+// reloading replaces the held one atomically, so readers get an consistent snapshot from Get.
+type Holder struct {
+	value atomic.Value
+}
+
+// NewHolder returns an Holder of config.
+func NewHolder(config *Configuration) *Holder {
+	holder := &Holder{}
+	holder.value.Store(config)
+	return holder
+}
+
+// Get returns the current Configuration, which mustn't be modified.
+func (h *Holder) Get() *Configuration {
+	return h.value.Load().(*Configuration)
+}
+
+// Reload replaces the current Configuration by one with the settings of ReloadableFlags of other. It mustn't be called concurrently.
+func (h *Holder) Reload(other *Configuration) {
+	h.value.Store(h.Get().Reloaded(other))
+}
+
+// ReadFile reads the settings of an YAML config file mapping flag names to values, e.g. cluster-name: prod.
+// Lists are flattened to comma-separated values and maps to comma-separated Key=Value pairs, e.g. for --default-tags.
+func ReadFile(file string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file due to %v", err)
+	}
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %v due to %v", file, err)
+	}
+	settings := make(map[string]string, len(values))
+	for name, value := range values {
+		setting, err := settingValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("setting %v in config file %v is invalid due to %v", name, file, err)
+		}
+		settings[name] = setting
+	}
+	return settings, nil
+}
+
+// ApplySettings sets the flags in flags to settings, except the flags skip returns true for, e.g. flags set on command line.
+func ApplySettings(flags *pflag.FlagSet, settings map[string]string, skip func(name string) bool) error {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if flags.Lookup(name) == nil {
+			return fmt.Errorf("unknown setting %v in config file", name)
+		}
+		if skip(name) {
+			continue
+		}
+		if err := flags.Set(name, settings[name]); err != nil {
+			return fmt.Errorf("setting %v in config file is invalid due to %v", name, err)
+		}
+	}
+	return nil
+}
+
+func settingValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := settingValue(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(v))
+		for _, key := range keys {
+			s, err := settingValue(v[key])
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, key+"="+s)
+		}
+		return strings.Join(pairs, ","), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v", value)
+	}
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+const configFileYAML = `
+cluster-name: prod
+target-type: ip
+security-group-rules-limit: 100
+require-waf: true
+required-tags: [owner, cost-center]
+default-tags:
+  owner: platform
+  env: prod
+`
+
+func readConfigFile(t *testing.T, content string) (map[string]string, error) {
+	f, err := ioutil.TempFile("", "config")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(content)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	return ReadFile(f.Name())
+}
+
+func TestReadFile(t *testing.T) {
+	settings, err := readConfigFile(t, configFileYAML)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"cluster-name":               "prod",
+		"target-type":                "ip",
+		"security-group-rules-limit": "100",
+		"require-waf":                "true",
+		"required-tags":              "owner,cost-center",
+		"default-tags":               "env=prod,owner=platform",
+	}, settings)
+
+	_, err = readConfigFile(t, "- cluster-name\n")
+	assert.Error(t, err)
+}
+
+func TestApplySettings(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		args           []string
+		settings       map[string]string
+		expectedConfig func(*Configuration)
+		expectedError  string
+	}{
+		{
+			name: "settings",
+			settings: map[string]string{
+				"cluster-name":  "prod",
+				"target-type":   "ip",
+				"required-tags": "owner,cost-center",
+			},
+			expectedConfig: func(config *Configuration) {
+				config.ClusterName = "prod"
+				config.DefaultTargetType = "ip"
+				config.RequiredTags = []string{"owner", "cost-center"}
+			},
+		},
+		{
+			name: "command line takes precedence",
+			args: []string{"--cluster-name=staging"},
+			settings: map[string]string{
+				"cluster-name": "prod",
+				"target-type":  "ip",
+			},
+			expectedConfig: func(config *Configuration) {
+				config.ClusterName = "staging"
+				config.DefaultTargetType = "ip"
+			},
+		},
+		{
+			name:          "unknown setting",
+			settings:      map[string]string{"cluster-nme": "prod"},
+			expectedError: "unknown setting cluster-nme in config file",
+		},
+		{
+			name:          "invalid setting",
+			settings:      map[string]string{"require-waf": "maybe"},
+			expectedError: "setting require-waf in config file is invalid",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config, flags := &Configuration{}, pflag.NewFlagSet("", pflag.ContinueOnError)
+			config.BindFlags(flags)
+			assert.NoError(t, flags.Parse(tc.args))
+			expected, expectedFlags := &Configuration{}, pflag.NewFlagSet("", pflag.ContinueOnError)
+			expected.BindFlags(expectedFlags)
+
+			err := ApplySettings(flags, tc.settings, flags.Changed)
+			if tc.expectedError != "" {
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			tc.expectedConfig(expected)
+			assert.Equal(t, expected, config)
+		})
+	}
+}
+
+func TestReloaded(t *testing.T) {
+	config := &Configuration{ClusterName: "prod", DefaultTargetType: "instance"}
+	reloaded := config.Reloaded(&Configuration{ClusterName: "staging", DefaultTargetType: "ip", RequireWAF: true})
+	assert.Equal(t, &Configuration{ClusterName: "prod", DefaultTargetType: "ip", RequireWAF: true}, reloaded)
+	assert.Equal(t, &Configuration{ClusterName: "prod", DefaultTargetType: "instance"}, config)
+}
+
+func TestHolder(t *testing.T) {
+	config := &Configuration{ClusterName: "prod", DefaultTargetType: "instance"}
+	holder := NewHolder(config)
+	snapshot := holder.Get()
+	assert.True(t, snapshot == config)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = holder.Get().DefaultTargetType
+		}
+	}()
+	holder.Reload(&Configuration{DefaultTargetType: "ip"})
+	<-done
+	assert.Equal(t, &Configuration{ClusterName: "prod", DefaultTargetType: "ip"}, holder.Get())
+	assert.Equal(t, "instance", snapshot.DefaultTargetType)
+}
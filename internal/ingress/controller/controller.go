@@ -1,8 +1,13 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
 
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/dns"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/generator"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/lb"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/ls"
@@ -11,80 +16,232 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/backend"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/handlers"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/policy"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
-func Initialize(config *config.Configuration, mgr manager.Manager, mc metric.Collector, cloud aws.CloudAPI) error {
-	reconciler, err := newReconciler(config, mgr, mc, cloud)
+// Initialize sets up the controller with mgr, the debug and render endpoints are registered on mux if they're enabled.
+// The validating and mutating admission webhooks are registered on webhookMux unless it's nil.
+// The returned Drainer must be drained before mgr is stopped, so in-flight reconciles finish.
+func Initialize(configHolder *config.Holder, mgr manager.Manager, mc metric.Collector, cloud aws.CloudAPI, mux *http.ServeMux, webhookMux *http.ServeMux) (*Drainer, error) {
+	config := configHolder.Get()
+	reconciler, err := newReconciler(configHolder, mgr, mc, cloud)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if config.StateConfigMap != "" {
+		// the state is loaded before any ingress is reconciled, and written by the leader.
+		reconciler.stateCache = newStateCache(mgr.GetClient(), config.StateConfigMapKey())
+		if err := reconciler.stateCache.load(context.Background()); err != nil {
+			return nil, err
+		}
+		if err := mgr.Add(reconciler.stateCache); err != nil {
+			return nil, err
+		}
 	}
-	c, err := controller.New("alb-ingress-controller", mgr, controller.Options{Reconciler: reconciler})
+	if webhookMux != nil {
+		webhookMux.Handle(ValidatingWebhookPath, newValidatingWebhookHandler(newIngressValidator(reconciler.store, cloud, reconciler.policies)))
+		if config.AnnotationDefaultsFile != "" {
+			defaults, err := loadAnnotationDefaults(config.AnnotationDefaultsFile)
+			if err != nil {
+				return nil, err
+			}
+			webhookMux.Handle(MutatingWebhookPath, newMutatingWebhookHandler(config.IngressClass, defaults))
+		}
+	}
+	if config.DebugEndpointTokenFile != "" {
+		token, err := ioutil.ReadFile(config.DebugEndpointTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read debug endpoint token due to %v", err)
+		}
+		if len(strings.TrimSpace(string(token))) == 0 {
+			return nil, fmt.Errorf("debug endpoint token file %v is empty", config.DebugEndpointTokenFile)
+		}
+		mux.Handle(DebugIngressPathPrefix, newDebugHandler(reconciler, strings.TrimSpace(string(token))))
+		mux.Handle(RenderPath, newRenderHandler(reconciler, strings.TrimSpace(string(token))))
+	}
+	c, err := controller.New("alb-ingress-controller", mgr, controller.Options{
+		Reconciler:              reconciler,
+		MaxConcurrentReconciles: config.MaxConcurrentReconciles,
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if err := config.BindDynamicSettings(mgr, c, cloud); err != nil {
-		return err
+	// ingresses are reconciled again with their annotations parsed again when annotation defaults change.
+	if err := config.BindDynamicSettings(mgr, c, cloud, func(q workqueue.RateLimitingInterface) {
+		for _, key := range reconciler.store.ReloadIngressAnnotations(corev1.NamespaceAll) {
+			q.Add(reconcile.Request{NamespacedName: key})
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	// ingresses enqueued by periodic resyncs are held back, so changed ingresses are reconciled first.
+	resyncQueue := handlers.NewResyncQueue(config.AWSResyncPeriod)
+	if err := mgr.Add(resyncQueue); err != nil {
+		return nil, err
+	}
+	if err := watchClusterEvents(c, mgr.GetCache(), config.IngressClass, config.IngressSelector, resyncQueue); err != nil {
+		return nil, fmt.Errorf("failed to watch cluster events due to %v", err)
+	}
+	if config.NamespaceAnnotationDefaults {
+		if err := c.Watch(&source.Kind{Type: &corev1.Namespace{}}, &handlers.EnqueueRequestsForNamespaceEvent{
+			Store: reconciler.store,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to watch namespaces due to %v", err)
+		}
+	}
+	// ingresses are reconciled again with their annotations parsed again when the Secrets or ConfigMaps they reference change.
+	if err := c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handlers.EnqueueRequestsForReferenceEvent{
+		Store:      reconciler.store,
+		References: (*annotations.Ingress).ReferencesSecret,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to watch secrets due to %v", err)
+	}
+	if err := c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handlers.EnqueueRequestsForReferenceEvent{
+		Store:      reconciler.store,
+		References: (*annotations.Ingress).ReferencesConfigMap,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to watch configmaps due to %v", err)
+	}
+	// ingressEvents enqueues ingresses from outside of the cluster events watched, e.g. by drift detection.
+	ingressEvents := make(chan event.GenericEvent)
+	if err := c.Watch(&source.Channel{Source: ingressEvents}, &handler.EnqueueRequestForObject{}); err != nil {
+		return nil, fmt.Errorf("failed to watch ingress events due to %v", err)
 	}
 
-	if err := watchClusterEvents(c, mgr.GetCache(), config.IngressClass); err != nil {
-		return fmt.Errorf("failed to watch cluster events due to %v", err)
+	// endpoints changes only reconcile the targets of their services, by an controller of its own.
+	tc, err := controller.New("alb-ingress-controller-targets", mgr, controller.Options{
+		Reconciler: &targetsReconciler{
+			reconciler:        reconciler,
+			registry:          reconciler.targetsRegistry,
+			targetsController: tg.NewTargetsController(cloud, backend.NewEndpointResolver(reconciler.store, cloud)),
+			ingressEvents:     ingressEvents,
+		},
+		MaxConcurrentReconciles: config.MaxConcurrentReconciles,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tc.Watch(&source.Kind{Type: &corev1.Endpoints{}}, &handlers.EnqueueRequestsForEndpointsEvent{}); err != nil {
+		return nil, fmt.Errorf("failed to watch endpoints due to %v", err)
 	}
 
-	return nil
+	if config.DriftDetectionInterval > 0 {
+		if err := mgr.Add(&driftDetector{
+			cloud:           cloud,
+			mc:              mc,
+			clusterTagKey:   config.ClusterTagKey,
+			clusterTagValue: config.ClusterTagValue,
+			interval:        config.DriftDetectionInterval,
+			events:          ingressEvents,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if config.CloudWatchMetricsInterval > 0 {
+		if err := mgr.Add(&lbMetricsPoller{
+			cloud:           cloud,
+			mc:              mc,
+			clusterTagKey:   config.ClusterTagKey,
+			clusterTagValue: config.ClusterTagValue,
+			interval:        config.CloudWatchMetricsInterval,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.HealthMetricsNamespace != "" {
+		if err := mgr.Add(&healthMetricsPublisher{
+			cloud:       cloud,
+			mc:          mc,
+			namespace:   config.HealthMetricsNamespace,
+			clusterName: config.ClusterName,
+			interval:    config.HealthMetricsInterval,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	return reconciler.drainer, nil
 }
 
-func newReconciler(config *config.Configuration, mgr manager.Manager, mc metric.Collector, cloud aws.CloudAPI) (reconcile.Reconciler, error) {
-	store, err := store.New(mgr, config)
+func newReconciler(configHolder *config.Holder, mgr manager.Manager, mc metric.Collector, cloud aws.CloudAPI) (*Reconciler, error) {
+	config := configHolder.Get()
+	store, err := store.New(mgr, configHolder)
 	if err != nil {
 		return nil, err
 	}
-	nameTagGenerator := generator.NewNameTagGenerator(*config)
-	tagsController := tags.NewController(cloud)
-	endpointResolver := backend.NewEndpointResolver(store, cloud)
-	tgGroupController := tg.NewGroupController(cloud, store, nameTagGenerator, tagsController, endpointResolver)
-	rsController := rs.NewController(cloud)
-	lsGroupController := ls.NewGroupController(store, cloud, rsController)
-	sgAssociationController := sg.NewAssociationController(store, cloud)
-	lbController := lb.NewController(cloud, store,
-		nameTagGenerator, tgGroupController, lsGroupController, sgAssociationController)
-
-	return &Reconciler{
+	var policies []*policy.Policy
+	if config.PolicyFile != "" {
+		if policies, err = policy.Load(config.PolicyFile); err != nil {
+			return nil, err
+		}
+	}
+	targetsRegistry := tg.NewTargetsRegistry()
+	reconciler := &Reconciler{
 		client:          mgr.GetClient(),
 		cache:           mgr.GetCache(),
 		recorder:        mgr.GetRecorder("alb-ingress-controller"),
 		store:           store,
-		lbController:    lbController,
+		lbController:    newLBController(config, store, cloud, mc, targetsRegistry),
+		cloud:           cloud,
+		policies:        policies,
 		metricCollector: mc,
-	}, nil
+		backoff:         newReconcileBackoff(config.ReconcileBackoffBase, config.ReconcileBackoffMax, config.SyncRateLimit, config.SyncRateBurst),
+		roles:           newIngressRoles(config.AllowedRoleARNs),
+		drainer:         newDrainer(),
+		targetsRegistry: targetsRegistry,
+		partitions:      newIngressPartitions(),
+	}
+	if config.FailureNotificationTopicARN != "" {
+		reconciler.failureNotifier = newFailureNotifier(cloud, config.FailureNotificationTopicARN, config.ClusterName, config.FailureNotificationThreshold)
+	}
+	return reconciler, nil
 }
 
-func watchClusterEvents(c controller.Controller, cache cache.Cache, ingressClass string) error {
+// newLBController constructs the lb.Controller of ingresses, the targetGroups reconciled are recorded in targetsRegistry unless it's nil.
+func newLBController(config *config.Configuration, store store.Storer, cloud aws.CloudAPI, mc metric.Collector, targetsRegistry *tg.TargetsRegistry) lb.Controller {
+	nameTagGenerator := generator.NewNameTagGenerator(*config)
+	tagsController := tags.NewController(cloud, config.IgnoredTagPrefixes)
+	endpointResolver := backend.NewEndpointResolver(store, cloud)
+	tgGroupController := tg.NewGroupController(cloud, store, nameTagGenerator, tagsController, endpointResolver, targetsRegistry)
+	rsController := rs.NewController(cloud, nameTagGenerator)
+	lsGroupController := ls.NewGroupController(store, cloud, rsController, nameTagGenerator)
+	sgAssociationController := sg.NewAssociationController(store, cloud)
+	dnsController := dns.NewController(cloud, config.Route53HostedZoneID, config.ClusterName)
+	return lb.NewController(cloud, store,
+		nameTagGenerator, tgGroupController, lsGroupController, sgAssociationController, tagsController, dnsController, mc)
+}
+
+func watchClusterEvents(c controller.Controller, cache cache.Cache, ingressClass string, ingressSelector labels.Selector, resyncQueue *handlers.ResyncQueue) error {
 	if err := c.Watch(&source.Kind{Type: &extensions.Ingress{}}, &handlers.EnqueueRequestsForIngressEvent{
-		IngressClass: ingressClass,
+		IngressClass:    ingressClass,
+		IngressSelector: ingressSelector,
+		ResyncQueue:     resyncQueue,
 	}); err != nil {
 		return err
 	}
 	if err := c.Watch(&source.Kind{Type: &corev1.Service{}}, &handlers.EnqueueRequestsForServiceEvent{
 		IngressClass: ingressClass,
 		Cache:        cache,
-	}); err != nil {
-		return err
-	}
-	if err := c.Watch(&source.Kind{Type: &corev1.Endpoints{}}, &handlers.EnqueueRequestsForEndpointsEvent{
-		IngressClass: ingressClass,
-		Cache:        cache,
+		ResyncQueue:  resyncQueue,
 	}); err != nil {
 		return err
 	}
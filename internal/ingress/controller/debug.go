@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/action"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/healthcheck"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/listener"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/targetgroup"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DebugIngressPathPrefix is the path prefix of the debug endpoint, followed by namespace/name of an ingress.
+const DebugIngressPathPrefix = "/debug/ingresses/"
+
+// IngressDebugInfo describes the desired and observed state of an ingress' AWS resources, and the diff between them.
+type IngressDebugInfo struct {
+	Ingress string `json:"ingress"`
+	// Desired is the configuration of ingress parsed from its annotations.
+	Desired *DesiredIngressConfig `json:"desired,omitempty"`
+	// Observed are the LoadBalancers of ingress in AWS, with their listeners and rules.
+	Observed []ObservedLoadBalancer `json:"observed"`
+	// Diff are the AWS calls planned to make the observed state desired.
+	Diff []string `json:"diff"`
+	// DiffIncomplete is set if planning stopped at an resource depending on resources planned to be created.
+	DiffIncomplete bool   `json:"diffIncomplete,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// DesiredIngressConfig is the configuration of an ingress parsed from its annotations.
+type DesiredIngressConfig struct {
+	Action       *action.Config       `json:"action,omitempty"`
+	HealthCheck  *healthcheck.Config  `json:"healthCheck,omitempty"`
+	TargetGroup  *targetgroup.Config  `json:"targetGroup,omitempty"`
+	LoadBalancer *loadbalancer.Config `json:"loadBalancer,omitempty"`
+	Listener     *listener.Config     `json:"listener,omitempty"`
+	Tags         *tags.Config         `json:"tags,omitempty"`
+}
+
+func newDesiredIngressConfig(ingressAnnos *annotations.Ingress) *DesiredIngressConfig {
+	return &DesiredIngressConfig{
+		Action:       ingressAnnos.Action,
+		HealthCheck:  ingressAnnos.HealthCheck,
+		TargetGroup:  ingressAnnos.TargetGroup,
+		LoadBalancer: ingressAnnos.LoadBalancer,
+		Listener:     ingressAnnos.Listener,
+		Tags:         ingressAnnos.Tags,
+	}
+}
+
+// ObservedLoadBalancer is an LoadBalancer in AWS.
+type ObservedLoadBalancer struct {
+	LoadBalancer *elbv2.LoadBalancer `json:"loadBalancer"`
+	Listeners    []ObservedListener  `json:"listeners"`
+}
+
+// ObservedListener is an listener in AWS.
+type ObservedListener struct {
+	Listener *elbv2.Listener `json:"listener"`
+	Rules    []*elbv2.Rule   `json:"rules"`
+}
+
+// debugIngress builds the debug info of ingress with ingressKey, by reconciling it in dry-run without emitting events.
+func (r *Reconciler) debugIngress(ctx context.Context, ingressKey types.NamespacedName) (*IngressDebugInfo, error) {
+	ingress := &extensions.Ingress{}
+	if err := r.cache.Get(ctx, ingressKey, ingress); err != nil {
+		return nil, err
+	}
+	info := &IngressDebugInfo{Ingress: ingressKey.String()}
+	ingressAnnos, err := r.store.GetIngressAnnotations(ingressKey.String())
+	if err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+	info.Desired = newDesiredIngressConfig(ingressAnnos)
+
+	ctx = r.buildReconcileContext(ctx, ingressKey, ingress)
+	ctx = albctx.SetEventf(ctx, func(string, string, string, ...interface{}) {})
+	dryRunCtx, plan := aws.WithDryRun(ctx)
+	lbInfos, err := r.reconcilePartitions(dryRunCtx, ingressKey, ingress, buildPartitions(ingress, ingressAnnos))
+	if err != nil && !plan.Incomplete() {
+		info.Error = err.Error()
+	}
+	info.Diff = plan.Changes()
+	info.DiffIncomplete = err != nil && plan.Incomplete()
+
+	for _, lbInfo := range lbInfos {
+		if lbInfo == nil || lbInfo.Arn == "" {
+			continue
+		}
+		observed, err := r.observeLoadBalancer(ctx, lbInfo.Arn)
+		if err != nil {
+			return nil, err
+		}
+		info.Observed = append(info.Observed, observed)
+	}
+	return info, nil
+}
+
+func (r *Reconciler) observeLoadBalancer(ctx context.Context, lbArn string) (ObservedLoadBalancer, error) {
+	instance, err := r.cloud.GetLoadBalancerByArn(ctx, lbArn)
+	if err != nil {
+		return ObservedLoadBalancer{}, fmt.Errorf("failed to get LoadBalancer %v due to %v", lbArn, err)
+	}
+	observed := ObservedLoadBalancer{LoadBalancer: instance}
+	listeners, err := r.cloud.ListListenersByLoadBalancer(ctx, lbArn)
+	if err != nil {
+		return ObservedLoadBalancer{}, fmt.Errorf("failed to list listeners of %v due to %v", lbArn, err)
+	}
+	for _, instance := range listeners {
+		rules, err := r.cloud.GetRules(ctx, aws.StringValue(instance.ListenerArn))
+		if err != nil {
+			return ObservedLoadBalancer{}, fmt.Errorf("failed to get rules of %v due to %v", aws.StringValue(instance.ListenerArn), err)
+		}
+		observed.Listeners = append(observed.Listeners, ObservedListener{Listener: instance, Rules: rules})
+	}
+	return observed, nil
+}
+
+// isAuthorized tests whether req is authenticated by the bearer token.
+func isAuthorized(req *http.Request, token string) bool {
+	bearer := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return token != "" && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
+
+// newDebugHandler serves the debug info of ingresses at DebugIngressPathPrefix<namespace>/<name>,
+// to requests authenticated by the bearer token. Sensitive values are redacted.
+func newDebugHandler(reconciler *Reconciler, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !isAuthorized(req, token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		parts := strings.Split(strings.TrimPrefix(req.URL.Path, DebugIngressPathPrefix), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			http.Error(w, fmt.Sprintf("path must be %s<namespace>/<name>", DebugIngressPathPrefix), http.StatusBadRequest)
+			return
+		}
+		info, err := reconciler.debugIngress(req.Context(), types.NamespacedName{Namespace: parts[0], Name: parts[1]})
+		if errors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		payload, err := json.MarshalIndent(log.Redact(info), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(payload)
+	})
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugHandler_rejectsRequests(t *testing.T) {
+	handler := newDebugHandler(&Reconciler{}, "token")
+	for _, tc := range []struct {
+		name           string
+		path           string
+		authorization  string
+		expectedStatus int
+	}{
+		{
+			name:           "missing token",
+			path:           "/debug/ingresses/namespace/ingress",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong token",
+			path:           "/debug/ingresses/namespace/ingress",
+			authorization:  "Bearer wrong",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "missing ingress name",
+			path:           "/debug/ingresses/namespace",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
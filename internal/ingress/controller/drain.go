@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// Drainer tracks in-flight reconciles, so that the controller can wait for them to finish on shutdown
+// instead of exiting halfway through changing AWS resources, e.g. with listeners created but not their rules.
+type Drainer struct {
+	mutex    sync.Mutex
+	draining bool
+	inflight sync.WaitGroup
+}
+
+func newDrainer() *Drainer {
+	return &Drainer{}
+}
+
+// begin records the start of an reconcile, it returns false when draining, in which case the reconcile must not start.
+func (d *Drainer) begin() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.draining {
+		return false
+	}
+	d.inflight.Add(1)
+	return true
+}
+
+// end records the end of an reconcile started by begin.
+func (d *Drainer) end() {
+	d.inflight.Done()
+}
+
+// Drain stops new reconciles from starting, and waits up to timeout for in-flight reconciles to finish.
+// It returns false if they didn't finish within timeout.
+func (d *Drainer) Drain(timeout time.Duration) bool {
+	d.mutex.Lock()
+	d.draining = true
+	d.mutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
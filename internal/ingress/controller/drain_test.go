@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainer(t *testing.T) {
+	d := newDrainer()
+	assert.True(t, d.begin())
+	assert.False(t, d.Drain(10*time.Millisecond))
+	assert.False(t, d.begin())
+
+	go d.end()
+	assert.True(t, d.Drain(time.Second))
+}
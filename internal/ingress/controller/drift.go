@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var driftLogger = log.New("drift-detection")
+
+// driftDetector periodically hashes the tags and attributes of the AWS resources created for the cluster,
+// and enqueues the ingresses whose resources changed since the last check, e.g. by out-of-band console changes.
+type driftDetector struct {
+	cloud           aws.CloudAPI
+	mc              metric.Collector
+	clusterTagKey   string
+	clusterTagValue string
+	interval        time.Duration
+
+	// events is the source of ingresses enqueued for reconcile
+	events chan event.GenericEvent
+	// hashes are the hashes of AWS resources by ingress key at the last check, nil before the first check
+	hashes map[types.NamespacedName]uint32
+}
+
+// Start implements manager.Runnable, so that only the leader detects drift.
+func (d *driftDetector) Start(stop <-chan struct{}) error {
+	wait.Until(func() { d.detect(stop) }, d.interval, stop)
+	return nil
+}
+
+func (d *driftDetector) detect(stop <-chan struct{}) {
+	hashes, err := d.hashResources(context.Background())
+	if err != nil {
+		driftLogger.Errorf("failed to detect drift of AWS resources due to %v", err)
+		return
+	}
+	previous := d.hashes
+	d.hashes = hashes
+	if previous == nil {
+		return
+	}
+	for _, ingressKey := range driftedIngresses(previous, hashes) {
+		driftLogger.Infof("AWS resources of ingress %v changed, reconciling it", ingressKey)
+		d.mc.IncDriftCorrectionCount()
+		select {
+		case d.events <- event.GenericEvent{
+			Meta: &metav1.ObjectMeta{Namespace: ingressKey.Namespace, Name: ingressKey.Name},
+		}:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// hashResources returns the hash of the tags of LoadBalancers and targetGroups, and the attributes of LoadBalancers, by ingress key.
+// It makes an single paginated call for the tags of all resources, and an call for the attributes of each LoadBalancer.
+func (d *driftDetector) hashResources(ctx context.Context) (map[types.NamespacedName]uint32, error) {
+	resources, err := d.cloud.GetResourceTagsByFilters(map[string][]string{
+		d.clusterTagKey: {d.clusterTagValue},
+	}, aws.ResourceTypeEnumELBLoadBalancer, aws.ResourceTypeEnumELBTargetGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find AWS resources due to %v", err)
+	}
+
+	states := make(map[types.NamespacedName][]string)
+	for arn, resourceTags := range resources {
+		ingressKey, ok := resourceIngressKey(resourceTags)
+		if !ok {
+			continue
+		}
+		for key, value := range resourceTags {
+			states[ingressKey] = append(states[ingressKey], fmt.Sprintf("%v tag %v=%v", arn, key, value))
+		}
+		if !isLoadBalancerArn(arn) {
+			continue
+		}
+		resp, err := d.cloud.DescribeLoadBalancerAttributesWithContext(ctx, &elbv2.DescribeLoadBalancerAttributesInput{
+			LoadBalancerArn: aws.String(arn),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe attributes of LoadBalancer %v due to %v", arn, err)
+		}
+		for _, attribute := range resp.Attributes {
+			states[ingressKey] = append(states[ingressKey], fmt.Sprintf("%v attribute %v=%v", arn, aws.StringValue(attribute.Key), aws.StringValue(attribute.Value)))
+		}
+	}
+
+	hashes := make(map[types.NamespacedName]uint32, len(states))
+	for ingressKey, state := range states {
+		sort.Strings(state)
+		h := fnv.New32a()
+		for _, line := range state {
+			h.Write([]byte(line))
+			h.Write([]byte{'\n'})
+		}
+		hashes[ingressKey] = h.Sum32()
+	}
+	return hashes, nil
+}
+
+// resourceIngressKey returns the key of the ingress an AWS resource is created for by its tags, partitions of ingresses split by host map to the ingress.
+func resourceIngressKey(resourceTags map[string]string) (types.NamespacedName, bool) {
+	name := resourceTags[tags.IngressName]
+	if partitionOf, ok := resourceTags[tags.PartitionOf]; ok {
+		name = partitionOf
+	}
+	if resourceTags[tags.Namespace] == "" || name == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: resourceTags[tags.Namespace], Name: name}, true
+}
+
+// isLoadBalancerArn returns whether arn is the ARN of an LoadBalancer, e.g. arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188.
+func isLoadBalancerArn(arn string) bool {
+	return strings.Contains(arn, ":loadbalancer/")
+}
+
+// driftedIngresses returns the ingresses whose hashes differ between previous and current, including those whose resources are created or deleted.
+func driftedIngresses(previous map[types.NamespacedName]uint32, current map[types.NamespacedName]uint32) []types.NamespacedName {
+	var ingressKeys []types.NamespacedName
+	for ingressKey, hash := range current {
+		if previousHash, ok := previous[ingressKey]; !ok || previousHash != hash {
+			ingressKeys = append(ingressKeys, ingressKey)
+		}
+	}
+	for ingressKey := range previous {
+		if _, ok := current[ingressKey]; !ok {
+			ingressKeys = append(ingressKeys, ingressKey)
+		}
+	}
+	sort.Slice(ingressKeys, func(i, j int) bool {
+		return ingressKeys[i].String() < ingressKeys[j].String()
+	})
+	return ingressKeys
+}
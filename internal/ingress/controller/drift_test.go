@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestHashResources(t *testing.T) {
+	ctx := context.Background()
+	lbArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/lb/50dc6c495c0c9188"
+	tgArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:targetgroup/tg/73e2d6bc24d8a067"
+	partitionArn := "arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/partition/6d0ecf831eec9f09"
+	resources := map[string]map[string]string{
+		lbArn: {"kubernetes.io/namespace": "namespace", "kubernetes.io/ingress-name": "ingress"},
+		tgArn: {"kubernetes.io/namespace": "namespace", "kubernetes.io/ingress-name": "ingress", "kubernetes.io/service-name": "service"},
+		partitionArn: {
+			"kubernetes.io/namespace":                "namespace",
+			"kubernetes.io/ingress-name":             "split-a-example-com",
+			"alb.ingress.kubernetes.io/partition-of": "split",
+		},
+		"arn:aws:elasticloadbalancing:us-west-2:123456789012:loadbalancer/app/other/8e4497da625e2d8a": {},
+	}
+	attributes := []*elbv2.LoadBalancerAttribute{{Key: aws.String("idle_timeout.timeout_seconds"), Value: aws.String("60")}}
+	cloud := &mocks.CloudAPI{}
+	cloud.On("GetResourceTagsByFilters", map[string][]string{"kubernetes.io/cluster/cluster": {"owned"}}, aws.ResourceTypeEnumELBLoadBalancer, aws.ResourceTypeEnumELBTargetGroup).
+		Return(resources, nil)
+	cloud.On("DescribeLoadBalancerAttributesWithContext", ctx, &elbv2.DescribeLoadBalancerAttributesInput{LoadBalancerArn: aws.String(lbArn)}).
+		Return(&elbv2.DescribeLoadBalancerAttributesOutput{Attributes: attributes}, nil)
+	cloud.On("DescribeLoadBalancerAttributesWithContext", ctx, &elbv2.DescribeLoadBalancerAttributesInput{LoadBalancerArn: aws.String(partitionArn)}).
+		Return(&elbv2.DescribeLoadBalancerAttributesOutput{Attributes: attributes}, nil)
+
+	detector := &driftDetector{
+		cloud:           cloud,
+		clusterTagKey:   "kubernetes.io/cluster/cluster",
+		clusterTagValue: "owned",
+	}
+	hashes, err := detector.hashResources(ctx)
+	assert.NoError(t, err)
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	splitKey := types.NamespacedName{Namespace: "namespace", Name: "split"}
+	assert.Len(t, hashes, 2)
+	assert.Contains(t, hashes, ingressKey)
+	assert.Contains(t, hashes, splitKey)
+
+	// the hash changes when the attributes of LoadBalancers are changed out-of-band.
+	attributes[0].Value = aws.String("120")
+	changed, err := detector.hashResources(ctx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hashes[ingressKey], changed[ingressKey])
+	assert.Equal(t, []types.NamespacedName{ingressKey, splitKey}, driftedIngresses(hashes, changed))
+	cloud.AssertExpectations(t)
+}
+
+func TestDriftedIngresses(t *testing.T) {
+	a := types.NamespacedName{Namespace: "namespace", Name: "a"}
+	b := types.NamespacedName{Namespace: "namespace", Name: "b"}
+	c := types.NamespacedName{Namespace: "namespace", Name: "c"}
+	previous := map[types.NamespacedName]uint32{a: 1, b: 2}
+	assert.Empty(t, driftedIngresses(previous, previous))
+	assert.Equal(t, []types.NamespacedName{b, c}, driftedIngresses(previous, map[types.NamespacedName]uint32{a: 1, c: 3}))
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+)
+
+const (
+	FailureNotificationStatusFailing   = "Failing"
+	FailureNotificationStatusRecovered = "Recovered"
+)
+
+// FailureNotification is the message published to SNS topic for ingresses failing to reconcile persistently.
+type FailureNotification struct {
+	Status              string    `json:"status"`
+	ClusterName         string    `json:"clusterName"`
+	Ingress             string    `json:"ingress"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	Error               string    `json:"error,omitempty"`
+	Time                time.Time `json:"time"`
+}
+
+// failureNotifier notifies SNS topic once an ingress failed to reconcile threshold times in a row,
+// and once it's reconciled again afterwards.
+type failureNotifier struct {
+	cloud       aws.CloudAPI
+	topicArn    string
+	clusterName string
+	threshold   int
+
+	mutex    sync.Mutex
+	failures map[string]int
+}
+
+func newFailureNotifier(cloud aws.CloudAPI, topicArn string, clusterName string, threshold int) *failureNotifier {
+	return &failureNotifier{
+		cloud:       cloud,
+		topicArn:    topicArn,
+		clusterName: clusterName,
+		threshold:   threshold,
+		failures:    make(map[string]int),
+	}
+}
+
+// RecordFailure records an reconcile failure of ingress, and notifies when the consecutive failures reach threshold.
+func (n *failureNotifier) RecordFailure(ctx context.Context, ingressKey string, reconcileErr error) {
+	n.mutex.Lock()
+	n.failures[ingressKey]++
+	failures := n.failures[ingressKey]
+	n.mutex.Unlock()
+
+	if failures != n.threshold {
+		return
+	}
+	n.publish(ctx, FailureNotification{
+		Status:              FailureNotificationStatusFailing,
+		ClusterName:         n.clusterName,
+		Ingress:             ingressKey,
+		ConsecutiveFailures: failures,
+		Error:               reconcileErr.Error(),
+		Time:                time.Now(),
+	})
+}
+
+// RecordSuccess records an successful reconcile of ingress, and notifies if it's been notified as failing.
+func (n *failureNotifier) RecordSuccess(ctx context.Context, ingressKey string) {
+	n.mutex.Lock()
+	failures := n.failures[ingressKey]
+	delete(n.failures, ingressKey)
+	n.mutex.Unlock()
+
+	if failures < n.threshold {
+		return
+	}
+	n.publish(ctx, FailureNotification{
+		Status:              FailureNotificationStatusRecovered,
+		ClusterName:         n.clusterName,
+		Ingress:             ingressKey,
+		ConsecutiveFailures: failures,
+		Time:                time.Now(),
+	})
+}
+
+func (n *failureNotifier) publish(ctx context.Context, notification FailureNotification) {
+	message, err := json.Marshal(notification)
+	if err != nil {
+		albctx.GetLogger(ctx).Errorf("failed to encode failure notification due to %v", err)
+		return
+	}
+	// SNS subjects are limited to 100 characters.
+	subject := fmt.Sprintf("ALB ingress %s: %s", notification.Status, notification.Ingress)
+	if len(subject) > 100 {
+		subject = subject[:100]
+	}
+	if err := n.cloud.PublishNotification(ctx, n.topicArn, subject, string(message)); err != nil {
+		albctx.GetLogger(ctx).Errorf("failed to publish failure notification to %s due to %v", n.topicArn, err)
+	}
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFailureNotifier(t *testing.T) {
+	ctx := context.Background()
+	cloud := &mocks.CloudAPI{}
+	var notifications []FailureNotification
+	cloud.On("PublishNotification", ctx, "topic-arn", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		var notification FailureNotification
+		assert.NoError(t, json.Unmarshal([]byte(args.String(3)), &notification))
+		notifications = append(notifications, notification)
+	})
+	notifier := newFailureNotifier(cloud, "topic-arn", "cluster", 3)
+
+	notifier.RecordFailure(ctx, "namespace/ingress", errors.New("AccessDenied"))
+	notifier.RecordFailure(ctx, "namespace/ingress", errors.New("AccessDenied"))
+	assert.Empty(t, notifications)
+
+	notifier.RecordFailure(ctx, "namespace/ingress", errors.New("AccessDenied"))
+	notifier.RecordFailure(ctx, "namespace/ingress", errors.New("AccessDenied"))
+	assert.Len(t, notifications, 1)
+	assert.Equal(t, FailureNotificationStatusFailing, notifications[0].Status)
+	assert.Equal(t, "cluster", notifications[0].ClusterName)
+	assert.Equal(t, "namespace/ingress", notifications[0].Ingress)
+	assert.Equal(t, 3, notifications[0].ConsecutiveFailures)
+	assert.Equal(t, "AccessDenied", notifications[0].Error)
+
+	notifier.RecordSuccess(ctx, "namespace/ingress")
+	assert.Len(t, notifications, 2)
+	assert.Equal(t, FailureNotificationStatusRecovered, notifications[1].Status)
+	assert.Equal(t, 4, notifications[1].ConsecutiveFailures)
+
+	notifier.RecordFailure(ctx, "namespace/other", errors.New("Throttling"))
+	notifier.RecordSuccess(ctx, "namespace/other")
+	assert.Len(t, notifications, 2)
+	cloud.AssertExpectations(t)
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/class"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -14,22 +16,26 @@ var _ handler.EventHandler = (*EnqueueRequestsForIngressEvent)(nil)
 
 type EnqueueRequestsForIngressEvent struct {
 	IngressClass string
+	// IngressSelector selects the ingresses enqueued by label, all ingresses of IngressClass are enqueued if it's nil
+	IngressSelector labels.Selector
+	// ResyncQueue holds back the ingresses enqueued by periodic resyncs, so changed ingresses are reconciled first
+	ResyncQueue *ResyncQueue
 }
 
 // Create is called in response to an create event - e.g. Pod Creation.
 func (h *EnqueueRequestsForIngressEvent) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
-	h.enqueueIfIngressClassMatched(e.Object.(*extensions.Ingress), queue)
+	h.enqueueIfIngressClassMatched(e.Object.(*extensions.Ingress), queue, false)
 }
 
 // Update is called in response to an update event -  e.g. Pod Updated.
 func (h *EnqueueRequestsForIngressEvent) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
-	h.enqueueIfIngressClassMatched(e.ObjectOld.(*extensions.Ingress), queue)
-	h.enqueueIfIngressClassMatched(e.ObjectNew.(*extensions.Ingress), queue)
+	h.enqueueIfIngressClassMatched(e.ObjectOld.(*extensions.Ingress), queue, isResync(e))
+	h.enqueueIfIngressClassMatched(e.ObjectNew.(*extensions.Ingress), queue, isResync(e))
 }
 
 // Delete is called in response to a delete event - e.g. Pod Deleted.
 func (h *EnqueueRequestsForIngressEvent) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
-	h.enqueueIfIngressClassMatched(e.Object.(*extensions.Ingress), queue)
+	h.enqueueIfIngressClassMatched(e.Object.(*extensions.Ingress), queue, false)
 }
 
 // Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
@@ -43,14 +49,19 @@ func (h *EnqueueRequestsForIngressEvent) Generic(e event.GenericEvent, queue wor
 	})
 }
 
-func (h *EnqueueRequestsForIngressEvent) enqueueIfIngressClassMatched(ingress *extensions.Ingress, queue workqueue.RateLimitingInterface) {
-	if !class.IsValidIngress(h.IngressClass, ingress) {
+func (h *EnqueueRequestsForIngressEvent) enqueueIfIngressClassMatched(ingress *extensions.Ingress, queue workqueue.RateLimitingInterface, resync bool) {
+	// ingresses being deleted with the finalizer are enqueued even if they no longer match by class or labels, so their finalizer is removed.
+	finalizing := ingress.DeletionTimestamp != nil && k8s.HasFinalizer(ingress, k8s.IngressFinalizer)
+	if !class.IsValidIngress(h.IngressClass, ingress) && !finalizing {
 		return
 	}
-	queue.Add(reconcile.Request{
+	if h.IngressSelector != nil && !h.IngressSelector.Matches(labels.Set(ingress.Labels)) && !finalizing {
+		return
+	}
+	h.ResyncQueue.Enqueue(queue, reconcile.Request{
 		NamespacedName: types.NamespacedName{
 			Namespace: ingress.Namespace,
 			Name:      ingress.Name,
 		},
-	})
+	}, resync)
 }
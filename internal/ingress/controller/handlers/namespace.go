@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"reflect"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.EventHandler = (*EnqueueRequestsForNamespaceEvent)(nil)
+
+// EnqueueRequestsForNamespaceEvent enqueues the ingresses in namespaces whose annotations change, which ingresses inherit as defaults.
+type EnqueueRequestsForNamespaceEvent struct {
+	Store store.Storer
+}
+
+// Create is called in response to an create event - e.g. Pod Creation.
+func (h *EnqueueRequestsForNamespaceEvent) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueIngressesInNamespace(e.Meta.GetName(), queue)
+}
+
+// Update is called in response to an update event -  e.g. Pod Updated.
+func (h *EnqueueRequestsForNamespaceEvent) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+	if !reflect.DeepEqual(e.MetaOld.GetAnnotations(), e.MetaNew.GetAnnotations()) {
+		h.enqueueIngressesInNamespace(e.MetaNew.GetName(), queue)
+	}
+}
+
+// Delete is called in response to a delete event - e.g. Pod Deleted.
+// Ingresses in deleted namespaces are deleted as well, so they're not enqueued.
+func (h *EnqueueRequestsForNamespaceEvent) Delete(event.DeleteEvent, workqueue.RateLimitingInterface) {
+}
+
+// Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
+// external trigger request - e.g. reconcile Autoscaling, or a Webhook.
+func (h *EnqueueRequestsForNamespaceEvent) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
+}
+
+// enqueueIngressesInNamespace parses the annotations of ingresses in namespace again, before they're reconciled.
+func (h *EnqueueRequestsForNamespaceEvent) enqueueIngressesInNamespace(namespace string, queue workqueue.RateLimitingInterface) {
+	for _, key := range h.Store.ReloadIngressAnnotations(namespace) {
+		queue.Add(reconcile.Request{NamespacedName: key})
+	}
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.EventHandler = (*EnqueueRequestsForReferenceEvent)(nil)
+
+// EnqueueRequestsForReferenceEvent enqueues the ingresses whose annotations reference Secrets or ConfigMaps that change, e.g. when the client secret of authenticate actions is rotated.
+type EnqueueRequestsForReferenceEvent struct {
+	Store store.Storer
+
+	// References tests whether the annotations of an ingress reference the object named name in its namespace
+	References func(ingressAnnos *annotations.Ingress, name string) bool
+}
+
+// Create is called in response to an create event - e.g. Pod Creation.
+func (h *EnqueueRequestsForReferenceEvent) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueReferencingIngresses(e.Meta, queue)
+}
+
+// Update is called in response to an update event -  e.g. Pod Updated.
+// Periodic resyncs don't change the objects, which are skipped.
+func (h *EnqueueRequestsForReferenceEvent) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+	if e.MetaOld.GetResourceVersion() != e.MetaNew.GetResourceVersion() {
+		h.enqueueReferencingIngresses(e.MetaNew, queue)
+	}
+}
+
+// Delete is called in response to a delete event - e.g. Pod Deleted.
+// The referencing ingresses fail to parse afterwards, which is reported when they're reconciled.
+func (h *EnqueueRequestsForReferenceEvent) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
+	h.enqueueReferencingIngresses(e.Meta, queue)
+}
+
+// Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
+// external trigger request - e.g. reconcile Autoscaling, or a Webhook.
+func (h *EnqueueRequestsForReferenceEvent) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {
+}
+
+// enqueueReferencingIngresses parses the annotations of ingresses referencing the object again, before they're reconciled.
+func (h *EnqueueRequestsForReferenceEvent) enqueueReferencingIngresses(object metav1.Object, queue workqueue.RateLimitingInterface) {
+	keys := h.Store.ReloadIngressAnnotationsReferencing(object.GetNamespace(), func(ingressAnnos *annotations.Ingress) bool {
+		return h.References(ingressAnnos, object.GetName())
+	})
+	for _, key := range keys {
+		queue.Add(reconcile.Request{NamespacedName: key})
+	}
+}
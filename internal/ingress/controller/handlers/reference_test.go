@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/auth"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestEnqueueRequestsForReferenceEvent(t *testing.T) {
+	referencing := &annotations.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "referencing"},
+		Auth:       &auth.Config{Type: auth.TypeOIDC, IDPOIDC: &auth.IDPOIDC{SecretName: "oidc"}},
+	}
+	other := &annotations.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "other"},
+		Auth:       auth.Dummy(),
+	}
+	s := &store.MockStorer{}
+	s.On("ReloadIngressAnnotationsReferencing", "namespace", mock.Anything).Return(func(namespace string, references func(*annotations.Ingress) bool) []types.NamespacedName {
+		var keys []types.NamespacedName
+		for _, ingressAnnos := range []*annotations.Ingress{referencing, other} {
+			if references(ingressAnnos) {
+				keys = append(keys, types.NamespacedName{Namespace: ingressAnnos.Namespace, Name: ingressAnnos.Name})
+			}
+		}
+		return keys
+	})
+	h := &EnqueueRequestsForReferenceEvent{
+		Store:      s,
+		References: (*annotations.Ingress).ReferencesSecret,
+	}
+	secret := func(resourceVersion string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "oidc", ResourceVersion: resourceVersion}}
+	}
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+
+	// periodic resyncs don't change the secret
+	h.Update(event.UpdateEvent{MetaOld: secret("1"), ObjectOld: secret("1"), MetaNew: secret("1"), ObjectNew: secret("1")}, queue)
+	assert.Equal(t, 0, queue.Len())
+	s.AssertNotCalled(t, "ReloadIngressAnnotationsReferencing", "namespace", mock.Anything)
+
+	h.Update(event.UpdateEvent{MetaOld: secret("1"), ObjectOld: secret("1"), MetaNew: secret("2"), ObjectNew: secret("2")}, queue)
+	assert.Equal(t, 1, queue.Len())
+	item, _ := queue.Get()
+	assert.Equal(t, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "namespace", Name: "referencing"}}, item)
+	queue.Done(item)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// resyncFeedInterval is the interval at which the work queue is checked for being idle to feed it resync requests.
+const resyncFeedInterval = 100 * time.Millisecond
+
+// ResyncQueue holds the requests enqueued by periodic resyncs, and feeds them to the work queue only when no other requests are waiting,
+// so that new or modified ingresses are reconciled ahead of an resync of all ingresses.
+type ResyncQueue struct {
+	// minInterval is the minimum interval between reconciles of an unchanged ingress, resync requests within it are dropped
+	minInterval time.Duration
+
+	mutex   sync.Mutex
+	queue   workqueue.RateLimitingInterface
+	pending []reconcile.Request
+	// pendingSet dedupes pending, requests enqueued by changes are removed from it as they're reconciled anyway
+	pendingSet map[reconcile.Request]bool
+	// lastEnqueued is when requests were last added to the work queue, only tracked if minInterval is set
+	lastEnqueued map[reconcile.Request]time.Time
+}
+
+func NewResyncQueue(minInterval time.Duration) *ResyncQueue {
+	return &ResyncQueue{
+		minInterval:  minInterval,
+		pendingSet:   make(map[reconcile.Request]bool),
+		lastEnqueued: make(map[reconcile.Request]time.Time),
+	}
+}
+
+// Start implements manager.Runnable, so that resync requests are fed to the work queue.
+func (q *ResyncQueue) Start(stop <-chan struct{}) error {
+	wait.Until(q.feed, resyncFeedInterval, stop)
+	return nil
+}
+
+// Len returns the number of resync requests not yet fed to the work queue.
+func (q *ResyncQueue) Len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.pendingSet)
+}
+
+// Enqueue adds request to queue, it's held back until queue is idle if it's enqueued by an resync.
+// Enqueue can be called on an nil ResyncQueue, which adds request to queue straight away.
+func (q *ResyncQueue) Enqueue(queue workqueue.RateLimitingInterface, request reconcile.Request, resync bool) {
+	if q == nil {
+		queue.Add(request)
+		return
+	}
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if !resync {
+		delete(q.pendingSet, request)
+		q.add(queue, request)
+		return
+	}
+	q.queue = queue
+	if q.minInterval > 0 && time.Since(q.lastEnqueued[request]) < q.minInterval {
+		return
+	}
+	if !q.pendingSet[request] {
+		q.pendingSet[request] = true
+		q.pending = append(q.pending, request)
+	}
+}
+
+// feed adds the next pending request to the work queue if no other requests are waiting in it.
+func (q *ResyncQueue) feed() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	for len(q.pending) > 0 && q.queue.Len() == 0 {
+		request := q.pending[0]
+		q.pending = q.pending[1:]
+		if !q.pendingSet[request] {
+			continue
+		}
+		delete(q.pendingSet, request)
+		q.add(q.queue, request)
+	}
+	if len(q.pending) == 0 {
+		for request, enqueued := range q.lastEnqueued {
+			if time.Since(enqueued) >= q.minInterval {
+				delete(q.lastEnqueued, request)
+			}
+		}
+	}
+}
+
+func (q *ResyncQueue) add(queue workqueue.RateLimitingInterface, request reconcile.Request) {
+	if q.minInterval > 0 {
+		q.lastEnqueued[request] = time.Now()
+	}
+	queue.Add(request)
+}
+
+// isResync returns whether e is an periodic resync of an unchanged object.
+func isResync(e event.UpdateEvent) bool {
+	return e.MetaOld.GetResourceVersion() == e.MetaNew.GetResourceVersion()
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestResyncQueue(t *testing.T) {
+	request := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "namespace", Name: name}}
+	}
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	resync := NewResyncQueue(0)
+
+	resync.Enqueue(queue, request("a"), true)
+	resync.Enqueue(queue, request("b"), true)
+	resync.Enqueue(queue, request("a"), true)
+	resync.Enqueue(queue, request("c"), true)
+	resync.Enqueue(queue, request("b"), false)
+	assert.Equal(t, 1, queue.Len())
+	assert.Equal(t, 2, resync.Len())
+
+	// changes are reconciled ahead of resyncs, which are fed one at a time once the queue is idle.
+	resync.feed()
+	assert.Equal(t, 1, queue.Len())
+	for _, expected := range []string{"b", "a", "c"} {
+		item, _ := queue.Get()
+		assert.Equal(t, request(expected), item)
+		queue.Done(item)
+		resync.feed()
+	}
+	assert.Equal(t, 0, queue.Len())
+	assert.Equal(t, 0, resync.Len())
+
+	var nilResync *ResyncQueue
+	nilResync.Enqueue(queue, request("a"), true)
+	assert.Equal(t, 1, queue.Len())
+}
+
+func TestResyncQueueMinInterval(t *testing.T) {
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "namespace", Name: "ingress"}}
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	resync := NewResyncQueue(time.Hour)
+
+	resync.Enqueue(queue, request, true)
+	resync.feed()
+	item, _ := queue.Get()
+	queue.Done(item)
+
+	// the ingress was reconciled within the interval, so resyncs of it are dropped, but not its changes.
+	resync.Enqueue(queue, request, true)
+	assert.Equal(t, 0, resync.Len())
+	resync.Enqueue(queue, request, false)
+	assert.Equal(t, 1, queue.Len())
+}
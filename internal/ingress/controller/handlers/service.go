@@ -22,22 +22,24 @@ type EnqueueRequestsForServiceEvent struct {
 	IngressClass string
 
 	Cache cache.Cache
+	// ResyncQueue holds back the ingresses enqueued by periodic resyncs, so changed ingresses are reconciled first
+	ResyncQueue *ResyncQueue
 }
 
 // Create is called in response to an create event - e.g. Pod Creation.
 func (h *EnqueueRequestsForServiceEvent) Create(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
-	h.enqueueImpactedIngresses(e.Object.(*corev1.Service), queue)
+	h.enqueueImpactedIngresses(e.Object.(*corev1.Service), queue, false)
 }
 
 // Update is called in response to an update event -  e.g. Pod Updated.
 func (h *EnqueueRequestsForServiceEvent) Update(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
-	h.enqueueImpactedIngresses(e.ObjectOld.(*corev1.Service), queue)
-	h.enqueueImpactedIngresses(e.ObjectNew.(*corev1.Service), queue)
+	h.enqueueImpactedIngresses(e.ObjectOld.(*corev1.Service), queue, isResync(e))
+	h.enqueueImpactedIngresses(e.ObjectNew.(*corev1.Service), queue, isResync(e))
 }
 
 // Delete is called in response to a delete event - e.g. Pod Deleted.
 func (h *EnqueueRequestsForServiceEvent) Delete(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
-	h.enqueueImpactedIngresses(e.Object.(*corev1.Service), queue)
+	h.enqueueImpactedIngresses(e.Object.(*corev1.Service), queue, false)
 }
 
 // Generic is called in response to an event of an unknown type or a synthetic event triggered as a cron or
@@ -46,7 +48,7 @@ func (h *EnqueueRequestsForServiceEvent) Generic(event.GenericEvent, workqueue.R
 }
 
 //TODO: this can be further optimized to only included ingresses referenced this service :D
-func (h *EnqueueRequestsForServiceEvent) enqueueImpactedIngresses(service *corev1.Service, queue workqueue.RateLimitingInterface) {
+func (h *EnqueueRequestsForServiceEvent) enqueueImpactedIngresses(service *corev1.Service, queue workqueue.RateLimitingInterface, resync bool) {
 	ingressList := &extensions.IngressList{}
 	if err := h.Cache.List(context.Background(), client.InNamespace(service.Namespace), ingressList); err != nil {
 		glog.Errorf("failed to fetch impacted ingresses by service due to %v", err)
@@ -56,11 +58,11 @@ func (h *EnqueueRequestsForServiceEvent) enqueueImpactedIngresses(service *corev
 		if !class.IsValidIngress(h.IngressClass, &ingress) {
 			continue
 		}
-		queue.Add(reconcile.Request{
+		h.ResyncQueue.Enqueue(queue, reconcile.Request{
 			NamespacedName: types.NamespacedName{
 				Namespace: ingress.Namespace,
 				Name:      ingress.Name,
 			},
-		})
+		}, resync)
 	}
 }
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var healthMetricsLogger = log.New("health-metrics")
+
+// healthMetricsPublisher periodically publishes the health metrics of the controller to CloudWatch, dimensioned by ClusterName,
+// so the controller can be alarmed on without scraping its Prometheus metrics.
+type healthMetricsPublisher struct {
+	cloud       aws.CloudAPI
+	mc          metric.Collector
+	namespace   string
+	clusterName string
+	interval    time.Duration
+
+	// previous are the cumulative values of counters at the last publish, nil before the first one
+	previous map[string]float64
+}
+
+// Start implements manager.Runnable, so that only the leader, which reconciles ingresses, publishes metrics.
+func (p *healthMetricsPublisher) Start(stop <-chan struct{}) error {
+	wait.Until(p.publish, p.interval, stop)
+	return nil
+}
+
+func (p *healthMetricsPublisher) publish() {
+	values, err := p.mc.GetHealthMetrics()
+	if err != nil {
+		healthMetricsLogger.Errorf("failed to gather health metrics due to %v", err)
+		return
+	}
+	deltas := p.deltas(values)
+	if deltas == nil {
+		return
+	}
+	if err := p.cloud.PutCountMetrics(context.Background(), p.namespace, map[string]string{"ClusterName": p.clusterName}, deltas); err != nil {
+		healthMetricsLogger.Errorf("failed to publish health metrics to CloudWatch due to %v", err)
+	}
+}
+
+// deltas returns the values of counters since the last publish and the current queue depth, it's nil on the first publish,
+// whose counters cover the time since the controller started.
+func (p *healthMetricsPublisher) deltas(values map[string]float64) map[string]float64 {
+	previous := p.previous
+	p.previous = values
+	if previous == nil {
+		return nil
+	}
+	deltas := make(map[string]float64, len(values))
+	for name, value := range values {
+		if name == metric.HealthMetricQueueDepth {
+			deltas[name] = value
+		} else {
+			deltas[name] = value - previous[name]
+		}
+	}
+	return deltas
+}
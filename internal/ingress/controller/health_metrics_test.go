@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+)
+
+type fakeHealthMetricsCollector struct {
+	metric.DummyCollector
+	values map[string]float64
+}
+
+func (c *fakeHealthMetricsCollector) GetHealthMetrics() (map[string]float64, error) {
+	return c.values, nil
+}
+
+func TestHealthMetricsPublisher(t *testing.T) {
+	cloud := &mocks.CloudAPI{}
+	mc := &fakeHealthMetricsCollector{}
+	publisher := &healthMetricsPublisher{
+		cloud:       cloud,
+		mc:          mc,
+		namespace:   "ALBIngressController",
+		clusterName: "cluster",
+	}
+
+	// counters since the controller started aren't published.
+	mc.values = map[string]float64{metric.HealthMetricReconcileErrors: 3, metric.HealthMetricQueueDepth: 2}
+	publisher.publish()
+
+	cloud.On("PutCountMetrics", context.Background(), "ALBIngressController", map[string]string{"ClusterName": "cluster"},
+		map[string]float64{metric.HealthMetricReconcileErrors: 2, metric.HealthMetricQueueDepth: 1}).Return(nil)
+	mc.values = map[string]float64{metric.HealthMetricReconcileErrors: 5, metric.HealthMetricQueueDepth: 1}
+	publisher.publish()
+	cloud.AssertExpectations(t)
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// maxDescribeTagsResources is the maximum number of resources can be described in a single ELBV2 DescribeTags call
+const maxDescribeTagsResources = 20
+
+var lbMetricsLogger = log.New("cloudwatch-metrics")
+
+// lbMetricsPoller periodically pulls the CloudWatch metrics of ALBs created for the cluster, and exposes them labeled by ingress.
+type lbMetricsPoller struct {
+	cloud           aws.CloudAPI
+	mc              metric.Collector
+	clusterTagKey   string
+	clusterTagValue string
+	interval        time.Duration
+}
+
+// Start implements manager.Runnable, so that only the leader pulls metrics.
+func (p *lbMetricsPoller) Start(stop <-chan struct{}) error {
+	wait.Until(p.poll, p.interval, stop)
+	return nil
+}
+
+func (p *lbMetricsPoller) poll() {
+	metrics, err := p.pullLoadBalancerMetrics(context.Background())
+	if err != nil {
+		lbMetricsLogger.Errorf("failed to pull CloudWatch metrics of LoadBalancers due to %v", err)
+		return
+	}
+	p.mc.SetLoadBalancerMetrics(metrics)
+}
+
+// pullLoadBalancerMetrics returns the CloudWatch metrics of ALBs created for the cluster, keyed by the key of their ingress.
+func (p *lbMetricsPoller) pullLoadBalancerMetrics(ctx context.Context) (map[string]map[string]float64, error) {
+	lbArns, err := p.cloud.GetResourcesByFilters(ctx, map[string][]string{
+		p.clusterTagKey: {p.clusterTagValue},
+	}, aws.ResourceTypeEnumELBLoadBalancer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find LoadBalancers due to %v", err)
+	}
+
+	ingressByLB := make(map[string]string)
+	for start := 0; start < len(lbArns); start += maxDescribeTagsResources {
+		end := start + maxDescribeTagsResources
+		if end > len(lbArns) {
+			end = len(lbArns)
+		}
+		resp, err := p.cloud.DescribeELBV2TagsWithContext(ctx, &elbv2.DescribeTagsInput{
+			ResourceArns: aws.StringSlice(lbArns[start:end]),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe tags of LoadBalancers due to %v", err)
+		}
+		for _, tagDescription := range resp.TagDescriptions {
+			lbTags := make(map[string]string)
+			for _, tag := range tagDescription.Tags {
+				lbTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+			}
+			if lbTags[tags.Namespace] == "" || lbTags[tags.IngressName] == "" {
+				continue
+			}
+			ingressByLB[aws.StringValue(tagDescription.ResourceArn)] = lbTags[tags.Namespace] + "/" + lbTags[tags.IngressName]
+		}
+	}
+	if len(ingressByLB) == 0 {
+		return nil, nil
+	}
+
+	var ingressLBArns []string
+	for _, lbArn := range lbArns {
+		if _, ok := ingressByLB[lbArn]; ok {
+			ingressLBArns = append(ingressLBArns, lbArn)
+		}
+	}
+	lbMetrics, err := p.cloud.GetLoadBalancerMetrics(ctx, ingressLBArns, metricsPeriod(p.interval))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics of LoadBalancers due to %v", err)
+	}
+	metrics := make(map[string]map[string]float64)
+	for lbArn, values := range lbMetrics {
+		metrics[ingressByLB[lbArn]] = values
+	}
+	return metrics, nil
+}
+
+// metricsPeriod returns the CloudWatch period to aggregate metrics over for interval, which must be whole minutes.
+func metricsPeriod(interval time.Duration) time.Duration {
+	period := interval.Truncate(time.Minute)
+	if period < time.Minute {
+		period = time.Minute
+	}
+	return period
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullLoadBalancerMetrics(t *testing.T) {
+	ctx := context.Background()
+	cloud := &mocks.CloudAPI{}
+	cloud.On("GetResourcesByFilters", ctx, map[string][]string{"kubernetes.io/cluster/cluster": {"owned"}}, aws.ResourceTypeEnumELBLoadBalancer).
+		Return([]string{"lbArn1", "lbArn2"}, nil)
+	cloud.On("DescribeELBV2TagsWithContext", ctx, &elbv2.DescribeTagsInput{ResourceArns: aws.StringSlice([]string{"lbArn1", "lbArn2"})}).
+		Return(&elbv2.DescribeTagsOutput{
+			TagDescriptions: []*elbv2.TagDescription{
+				{
+					ResourceArn: aws.String("lbArn1"),
+					Tags: []*elbv2.Tag{
+						{Key: aws.String("kubernetes.io/namespace"), Value: aws.String("namespace")},
+						{Key: aws.String("kubernetes.io/ingress-name"), Value: aws.String("ingress")},
+					},
+				},
+				{
+					ResourceArn: aws.String("lbArn2"),
+				},
+			},
+		}, nil)
+	cloud.On("GetLoadBalancerMetrics", ctx, []string{"lbArn1"}, 5*time.Minute).
+		Return(map[string]map[string]float64{
+			"lbArn1": {aws.MetricConsumedLCUs: 1.5, aws.MetricRequestCount: 300},
+		}, nil)
+
+	poller := &lbMetricsPoller{
+		cloud:           cloud,
+		mc:              metric.DummyCollector{},
+		clusterTagKey:   "kubernetes.io/cluster/cluster",
+		clusterTagValue: "owned",
+		interval:        5*time.Minute + 30*time.Second,
+	}
+	metrics, err := poller.pullLoadBalancerMetrics(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]float64{
+		"namespace/ingress": {aws.MetricConsumedLCUs: 1.5, aws.MetricRequestCount: 300},
+	}, metrics)
+	cloud.AssertExpectations(t)
+}
+
+func TestMetricsPeriod(t *testing.T) {
+	assert.Equal(t, time.Minute, metricsPeriod(10*time.Second))
+	assert.Equal(t, 2*time.Minute, metricsPeriod(150*time.Second))
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ingressPartitions tracks the names of partitions reconciled for ingresses, so LoadBalancers of partitions an ingress is no longer
+// split into are only looked up when its partitions change, instead of on every reconcile.
+type ingressPartitions struct {
+	// mutex protects names, the names of partitions of ingresses by their keys
+	mutex sync.Mutex
+	names map[types.NamespacedName]sets.String
+}
+
+func newIngressPartitions() *ingressPartitions {
+	return &ingressPartitions{names: make(map[types.NamespacedName]sets.String)}
+}
+
+// changed tests whether the partitions of ingress differ from the ones recorded, which they do for ingresses not recorded since the controller started.
+func (p *ingressPartitions) changed(ingressKey types.NamespacedName, names sets.String) bool {
+	if p == nil {
+		return true
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	recorded, ok := p.names[ingressKey]
+	return !ok || !recorded.Equal(names)
+}
+
+// record records the partitions of ingress once the LoadBalancers of its other partitions are deleted.
+func (p *ingressPartitions) record(ingressKey types.NamespacedName, names sets.String) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.names[ingressKey] = sets.NewString(names.List()...)
+}
+
+// forget forgets the partitions of ingress once its resources are deleted.
+func (p *ingressPartitions) forget(ingressKey types.NamespacedName) {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.names, ingressKey)
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestIngressPartitions(t *testing.T) {
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	partitions := newIngressPartitions()
+	assert.True(t, partitions.changed(ingressKey, sets.NewString("ingress")))
+
+	partitions.record(ingressKey, sets.NewString("ingress_1", "ingress_2"))
+	assert.False(t, partitions.changed(ingressKey, sets.NewString("ingress_2", "ingress_1")))
+	assert.True(t, partitions.changed(ingressKey, sets.NewString("ingress_1")))
+	assert.True(t, partitions.changed(types.NamespacedName{Namespace: "namespace", Name: "other"}, sets.NewString("ingress_1", "ingress_2")))
+
+	partitions.forget(ingressKey)
+	assert.True(t, partitions.changed(ingressKey, sets.NewString("ingress_1", "ingress_2")))
+
+	var untracked *ingressPartitions
+	assert.True(t, untracked.changed(ingressKey, sets.NewString("ingress")))
+}
@@ -2,22 +2,46 @@ package controller
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
+	"github.com/golang/glog"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/lb"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tags"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/policy"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	// CanonicalHostedZoneIDAnnotation is set by the controller on ingresses to the canonical hosted zone IDs of their LoadBalancers.
+	CanonicalHostedZoneIDAnnotation = "alb.ingress.kubernetes.io/canonical-hosted-zone-id"
+
+	// DualstackDNSNameAnnotation is set by the controller on ingresses to the dualstack DNS names of their LoadBalancers.
+	DualstackDNSNameAnnotation = "alb.ingress.kubernetes.io/dualstack-dns-name"
+)
+
 // Reconciler reconciles an single ingress object
 type Reconciler struct {
 	client   client.Client
@@ -28,79 +52,389 @@ type Reconciler struct {
 	store store.Storer
 
 	lbController lb.Controller
+	cloud        aws.CloudAPI
+
+	// policies constrain the annotations of ingresses
+	policies []*policy.Policy
 
 	metricCollector metric.Collector
+
+	// failureNotifier is nil unless failure notifications are enabled
+	failureNotifier *failureNotifier
+
+	backoff *reconcileBackoff
+
+	// roles tracks the roles assumed for AWS calls of ingresses with the role-arn annotation
+	roles *ingressRoles
+
+	// drainer tracks in-flight reconciles for graceful shutdown
+	drainer *Drainer
+
+	// targetsRegistry records the targetGroups reconciled, so the targets of services can be reconciled alone when their endpoints change
+	targetsRegistry *tg.TargetsRegistry
+
+	// stateCache is nil unless the state of ingresses is persisted across restarts
+	stateCache *stateCache
+
+	// partitions tracks the partitions reconciled for ingresses, so LoadBalancers of removed partitions are only looked up when they change
+	partitions *ingressPartitions
 }
 
 // Reconcile will reconcile the aws resources with k8s state of ingress.
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	if !r.store.GetConfig().IsWatchedNamespace(request.Namespace) || !r.store.GetConfig().IsShardedIngress(request.Namespace, request.Name) {
+		// ingresses in other namespaces or shards, including their deletion, are reconciled by other controllers.
+		return reconcile.Result{}, nil
+	}
+	if !r.drainer.begin() {
+		// the controller is shutting down, ingresses are reconciled again by the next leader when it starts.
+		return reconcile.Result{}, nil
+	}
+	defer r.drainer.end()
 	ctx := context.Background()
 	ingress := &extensions.Ingress{}
 	if err := r.cache.Get(ctx, request.NamespacedName, ingress); err != nil {
 		if !errors.IsNotFound(err) {
-			r.metricCollector.IncReconcileErrorCount(request.NamespacedName.String())
-			return reconcile.Result{}, err
+			return r.recordFailure(ctx, request.NamespacedName, nil, err), nil
 		}
 
 		if err := r.deleteIngress(ctx, request.NamespacedName); err != nil {
-			r.metricCollector.IncReconcileErrorCount(request.NamespacedName.String())
-			return reconcile.Result{}, err
+			return r.recordFailure(ctx, request.NamespacedName, nil, err), nil
 		}
+		r.stateCache.forget(request.NamespacedName)
+		r.roles.forget(request.NamespacedName)
+		r.partitions.forget(request.NamespacedName)
+		r.metricCollector.RemoveMetrics(request.NamespacedName.String())
 
-		r.metricCollector.IncReconcileCount()
+		r.recordSuccess(ctx, request.NamespacedName.String())
 		return reconcile.Result{}, nil
 	}
 
+	if ingress.DeletionTimestamp != nil {
+		// ingresses with our finalizer are finalized even if they're no longer selected by labels, otherwise their deletion would wait forever.
+		if !k8s.HasFinalizer(ingress, k8s.IngressFinalizer) {
+			// the ingress is removed once other finalizers are done, its resources are deleted then.
+			return reconcile.Result{}, nil
+		}
+		if err := r.finalizeIngress(ctx, request.NamespacedName, ingress); err != nil {
+			return r.recordFailure(ctx, request.NamespacedName, ingress, err), nil
+		}
+		r.stateCache.forget(request.NamespacedName)
+		r.roles.forget(request.NamespacedName)
+		r.partitions.forget(request.NamespacedName)
+		r.recordSuccess(ctx, request.NamespacedName.String())
+		return reconcile.Result{}, nil
+	}
+	if !r.store.GetConfig().IsWatchedIngress(ingress) {
+		// ingresses not selected by labels are reconciled by other controllers.
+		return reconcile.Result{}, nil
+	}
+	if r.restoreState(ctx, request.NamespacedName, ingress) {
+		return reconcile.Result{}, nil
+	}
 	if err := r.reconcileIngress(ctx, request.NamespacedName, ingress); err != nil {
-		r.metricCollector.IncReconcileErrorCount(request.NamespacedName.String())
-		return reconcile.Result{}, err
+		return r.recordFailure(ctx, request.NamespacedName, ingress, err), nil
 	}
 
-	r.metricCollector.IncReconcileCount()
+	r.recordSuccess(ctx, request.NamespacedName.String())
 	return reconcile.Result{}, nil
 }
 
+// recordFailure records an reconcile failure of ingress, and returns the result requeuing it after its backoff.
+// The error isn't returned to the work queue, so ingresses failing persistently are backed off on their own instead of by the queue's rate limiter.
+// ingress is nil when it couldn't be retrieved.
+func (r *Reconciler) recordFailure(ctx context.Context, ingressKey types.NamespacedName, ingress *extensions.Ingress, err error) reconcile.Result {
+	if aws.IsCredentialsError(err) {
+		// credentials briefly failing to be vended aren't an failure of the ingress, it's retried within seconds instead.
+		r.metricCollector.IncReconcileCredentialsErrorCount()
+		delay := r.backoff.RecordCredentialsFailure(ingressKey.String())
+		nextRetry := time.Now().Add(delay).UTC().Format(time.RFC3339)
+		glog.Warningf("failed to reconcile ingress %v as AWS credentials couldn't be retrieved due to %v, retrying at %v", ingressKey, err, nextRetry)
+		r.recorder.Eventf(eventObject(ingressKey, ingress), corev1.EventTypeWarning, "CREDENTIALS",
+			"Failed to retrieve AWS credentials: %v, next retry at %v", err, nextRetry)
+		return reconcile.Result{RequeueAfter: delay}
+	}
+	r.metricCollector.IncReconcileErrorCount(ingressKey.String())
+	if r.failureNotifier != nil {
+		r.failureNotifier.RecordFailure(ctx, ingressKey.String(), err)
+	}
+	failures, delay := r.backoff.RecordFailure(ingressKey.String())
+	nextRetry := time.Now().Add(delay).UTC().Format(time.RFC3339)
+	glog.Errorf("failed to reconcile ingress %v %d times due to %v, retrying at %v", ingressKey, failures, err, nextRetry)
+	// extensions/v1beta1 ingresses have no status conditions, so the backoff is recorded as an event.
+	r.recorder.Eventf(eventObject(ingressKey, ingress), corev1.EventTypeWarning, "BACKOFF",
+		"Failed to reconcile %d times, last error: %v, next retry at %v", failures, err, nextRetry)
+	return reconcile.Result{RequeueAfter: delay}
+}
+
+func (r *Reconciler) recordSuccess(ctx context.Context, ingressKey string) {
+	r.metricCollector.IncReconcileCount()
+	r.backoff.RecordSuccess(ingressKey)
+	if r.failureNotifier != nil {
+		r.failureNotifier.RecordSuccess(ctx, ingressKey)
+	}
+}
+
 func (r *Reconciler) reconcileIngress(ctx context.Context, ingressKey types.NamespacedName, ingress *extensions.Ingress) error {
 	ctx = r.buildReconcileContext(ctx, ingressKey, ingress)
-	lbInfo, err := r.lbController.Reconcile(ctx, ingress)
+	ingressAnnos, err := r.store.GetIngressAnnotations(ingressKey.String())
+	if err != nil {
+		return err
+	}
+	if err := r.enforcePolicies(ctx, ingressKey, ingressAnnos); err != nil {
+		return err
+	}
+	if roleARN := aws.StringValue(ingressAnnos.LoadBalancer.RoleARN); roleARN != "" && !r.roles.allows(roleARN) {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "ERROR", "role %v is not allowed by --allowed-role-arns", roleARN)
+		return fmt.Errorf("role %v of ingress %v is not allowed by --allowed-role-arns", roleARN, ingressKey)
+	}
+	partitions := buildPartitions(ingress, ingressAnnos)
+	ctx = tags.WithBatch(ctx, r.reconciledTargetGroupArns(partitions))
+	if r.store.GetConfig().DryRun || ingressAnnos.LoadBalancer.DryRun {
+		dryRunCtx, plan := aws.WithDryRun(ctx)
+		_, err := r.reconcilePartitions(dryRunCtx, ingressKey, ingress, partitions)
+		return r.reportDryRun(ctx, plan, err)
+	}
+	if err := r.ensureFinalizer(ctx, ingress); err != nil {
+		return err
+	}
+	lbInfos, err := r.reconcilePartitions(ctx, ingressKey, ingress, partitions)
 	if err != nil {
 		return err
 	}
-	if err := r.updateIngressStatus(ctx, ingress, lbInfo); err != nil {
+	if err := r.updateIngressStatus(ctx, ingress, lbInfos); err != nil {
 		return err
 	}
+	r.recordState(ctx, ingressKey, ingress, partitions)
 
 	return nil
 }
 
+// reconciledTargetGroupArns returns the ARNs of the targetGroups of partitions reconciled before, whose tags are described
+// in batches, together with the first tags described by the reconcile.
+func (r *Reconciler) reconciledTargetGroupArns(partitions []*extensions.Ingress) []string {
+	var arns []string
+	for _, partition := range partitions {
+		for _, targets := range r.targetsRegistry.IngressTargets(types.NamespacedName{Namespace: partition.Namespace, Name: partition.Name}) {
+			arns = append(arns, targets.TgArn)
+		}
+	}
+	return arns
+}
+
+// reconcilePartitions reconciles the LoadBalancers of partitions of ingress.
+func (r *Reconciler) reconcilePartitions(ctx context.Context, ingressKey types.NamespacedName, ingress *extensions.Ingress, partitions []*extensions.Ingress) ([]*lb.LoadBalancer, error) {
+	var lbInfos []*lb.LoadBalancer
+	partitionNames := sets.NewString()
+	for _, partition := range partitions {
+		lbInfo, err := r.lbController.Reconcile(ctx, partition)
+		if err != nil {
+			return lbInfos, err
+		}
+		lbInfos = append(lbInfos, lbInfo)
+		partitionNames.Insert(partition.Name)
+	}
+	if !r.partitions.changed(ingressKey, partitionNames) {
+		return lbInfos, nil
+	}
+	if !partitionNames.Has(ingress.Name) {
+		// the LoadBalancer of ingress before it's split is no longer needed.
+		if err := r.lbController.Delete(ctx, ingressKey); err != nil {
+			return lbInfos, err
+		}
+	}
+	if err := r.deletePartitions(ctx, ingressKey, partitionNames); err != nil {
+		return lbInfos, err
+	}
+	// nothing is deleted by dry runs, so the LoadBalancers are still looked up by the next reconcile.
+	if !aws.IsDryRun(ctx) {
+		r.partitions.record(ingressKey, partitionNames)
+	}
+	return lbInfos, nil
+}
+
 func (r *Reconciler) deleteIngress(ctx context.Context, ingressKey types.NamespacedName) error {
 	ctx = r.buildReconcileContext(ctx, ingressKey, nil)
+	if r.store.GetConfig().DryRun {
+		dryRunCtx, plan := aws.WithDryRun(ctx)
+		return r.reportDryRun(ctx, plan, r.deleteLoadBalancers(dryRunCtx, ingressKey))
+	}
+	return r.deleteLoadBalancers(ctx, ingressKey)
+}
+
+// ensureFinalizer adds the finalizer to ingress before any AWS resources are created for it, if finalizers are enabled.
+func (r *Reconciler) ensureFinalizer(ctx context.Context, ingress *extensions.Ingress) error {
+	if !r.store.GetConfig().IngressFinalizer || k8s.HasFinalizer(ingress, k8s.IngressFinalizer) {
+		return nil
+	}
+	k8s.AddFinalizer(ingress, k8s.IngressFinalizer)
+	if err := r.client.Update(ctx, ingress); err != nil {
+		return fmt.Errorf("failed to add finalizer due to %v", err)
+	}
+	return nil
+}
+
+// finalizeIngress deletes the AWS resources of ingress being deleted, and removes its finalizer once they're deleted, so it's removed from the cluster.
+func (r *Reconciler) finalizeIngress(ctx context.Context, ingressKey types.NamespacedName, ingress *extensions.Ingress) error {
+	if err := r.deleteIngress(ctx, ingressKey); err != nil {
+		return err
+	}
+	k8s.RemoveFinalizer(ingress, k8s.IngressFinalizer)
+	if err := r.client.Update(ctx, ingress); err != nil {
+		return fmt.Errorf("failed to remove finalizer due to %v", err)
+	}
+	return nil
+}
+
+func (r *Reconciler) deleteLoadBalancers(ctx context.Context, ingressKey types.NamespacedName) error {
+	if err := r.deletePartitions(ctx, ingressKey, sets.NewString()); err != nil {
+		return err
+	}
 	if err := r.lbController.Delete(ctx, ingressKey); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *Reconciler) updateIngressStatus(ctx context.Context, ingress *extensions.Ingress, lbInfo *lb.LoadBalancer) error {
-	if len(ingress.Status.LoadBalancer.Ingress) != 1 ||
-		ingress.Status.LoadBalancer.Ingress[0].IP != "" ||
-		ingress.Status.LoadBalancer.Ingress[0].Hostname != lbInfo.DNSName {
-		ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{
-			{
-				Hostname: lbInfo.DNSName,
-			},
+// deletePartitions deletes the LoadBalancers of partitions of ingress split by host, except the ones in keepNames.
+func (r *Reconciler) deletePartitions(ctx context.Context, ingressKey types.NamespacedName, keepNames sets.String) error {
+	partitionKeys, err := r.lbController.ListPartitions(ctx, ingressKey)
+	if err != nil {
+		return err
+	}
+	for _, partitionKey := range partitionKeys {
+		if keepNames.Has(partitionKey.Name) {
+			continue
+		}
+		albctx.GetLogger(ctx).Infof("deleting LoadBalancer of partition %v", partitionKey)
+		if err := r.lbController.Delete(ctx, partitionKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enforcePolicies emits the violations of policies by ingress as events, and returns an error if there're any.
+func (r *Reconciler) enforcePolicies(ctx context.Context, ingressKey types.NamespacedName, ingressAnnos *annotations.Ingress) error {
+	violations := policy.Evaluate(r.policies, ingressKey.Namespace, ingressAnnos)
+	if len(violations) == 0 {
+		return nil
+	}
+	for _, violation := range violations {
+		albctx.GetEventf(ctx)(corev1.EventTypeWarning, "POLICY", "%s", violation)
+	}
+	return fmt.Errorf("ingress %v violates policies: %v", ingressKey, violations)
+}
+
+// buildPartitions returns the partitions of ingress that get their own LoadBalancer, which is ingress itself unless it's split by host.
+func buildPartitions(ingress *extensions.Ingress, ingressAnnos *annotations.Ingress) []*extensions.Ingress {
+	if aws.StringValue(ingressAnnos.LoadBalancer.SplitBy) == loadbalancer.SplitByHost {
+		return k8s.PartitionIngressByHost(ingress)
+	}
+	return []*extensions.Ingress{ingress}
+}
+
+// reportDryRun logs and emits the number of changes planned in dry-run, err of reconcile is ignored if it may be caused by resources planned to be created.
+func (r *Reconciler) reportDryRun(ctx context.Context, plan *aws.DryRunPlan, err error) error {
+	if err != nil && !plan.Incomplete() {
+		return err
+	}
+	changes := plan.Changes()
+	msg := fmt.Sprintf("dry-run planned %d changes", len(changes))
+	if err != nil {
+		msg += fmt.Sprintf(", changes depending on resources to be created are not planned since %v", err)
+	}
+	albctx.GetLogger(ctx).Infof("%s", msg)
+	albctx.GetEventf(ctx)(corev1.EventTypeNormal, "DRYRUN", "%s", msg)
+	return nil
+}
+
+// updateIngressStatus updates the status of ingress with the DNS names of its LoadBalancers, which are multiple when it's split by host.
+// The canonical hosted zone IDs and dualstack DNS names of the LoadBalancers are set as annotations of ingress as well, comma separated in the same order,
+// since the status of ingresses can't hold them, so alias records can be created without looking the LoadBalancers up.
+func (r *Reconciler) updateIngressStatus(ctx context.Context, ingress *extensions.Ingress, lbInfos []*lb.LoadBalancer) error {
+	var lbIngresses []corev1.LoadBalancerIngress
+	var zoneIDs, dualstackDNSNames []string
+	for _, lbInfo := range lbInfos {
+		lbIngresses = append(lbIngresses, corev1.LoadBalancerIngress{
+			Hostname: lbInfo.DNSName,
+		})
+		zoneIDs = append(zoneIDs, lbInfo.CanonicalHostedZoneID)
+		dualstackDNSNames = append(dualstackDNSNames, lbInfo.DualstackDNSName)
+	}
+	if setStatusAnnotations(ingress, map[string]string{
+		CanonicalHostedZoneIDAnnotation: strings.Join(zoneIDs, ","),
+		DualstackDNSNameAnnotation:      strings.Join(dualstackDNSNames, ","),
+	}) {
+		if err := r.client.Update(ctx, ingress); err != nil {
+			return fmt.Errorf("failed to update status annotations due to %v", err)
 		}
+	}
+	if !reflect.DeepEqual(ingress.Status.LoadBalancer.Ingress, lbIngresses) {
+		ingress.Status.LoadBalancer.Ingress = lbIngresses
 		return r.client.Status().Update(ctx, ingress)
 	}
 	return nil
 }
 
+// setStatusAnnotations sets annotations on ingress, removing the ones with empty value, and returns whether ingress is changed.
+func setStatusAnnotations(ingress *extensions.Ingress, annotations map[string]string) bool {
+	changed := false
+	for key, value := range annotations {
+		current, ok := ingress.Annotations[key]
+		switch {
+		case value == "" && ok:
+			delete(ingress.Annotations, key)
+		case value != "" && current != value:
+			if ingress.Annotations == nil {
+				ingress.Annotations = make(map[string]string)
+			}
+			ingress.Annotations[key] = value
+		default:
+			continue
+		}
+		changed = true
+	}
+	return changed
+}
+
 func (r *Reconciler) buildReconcileContext(ctx context.Context, ingressKey types.NamespacedName, ingress *extensions.Ingress) context.Context {
-	ctx = albctx.SetLogger(ctx, log.New(ingressKey.String()))
+	logger := log.New(ingressKey.String()).
+		With("namespace", ingressKey.Namespace).
+		With("ingress", ingressKey.Name).
+		With("reconcileID", newReconcileID())
+	ctx = albctx.SetLogger(ctx, logger)
+	// calls with the role of ingress are attributed to it in CloudTrail by their session tags.
+	ctx = aws.WithRole(ctx, r.roles.roleARN(r.store, ingressKey), map[string]string{
+		tags.Namespace:   ingressKey.Namespace,
+		tags.IngressName: ingressKey.Name,
+	})
+	object := eventObject(ingressKey, ingress)
+	ctx = albctx.SetEventf(ctx, func(eventType string, reason string, messageFmt string, args ...interface{}) {
+		r.recorder.Eventf(object, eventType, reason, messageFmt, args...)
+	})
+	return ctx
+}
+
+// eventObject returns the object events of ingress are recorded against.
+func eventObject(ingressKey types.NamespacedName, ingress *extensions.Ingress) *extensions.Ingress {
 	if ingress != nil {
-		ctx = albctx.SetEventf(ctx, func(eventType string, reason string, messageFmt string, args ...interface{}) {
-			r.recorder.Eventf(ingress, eventType, reason, messageFmt, args...)
-		})
+		return ingress
 	}
-	return ctx
+	// the ingress is already deleted, events are recorded against its key so they can still be found in its namespace.
+	return &extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ingressKey.Namespace,
+			Name:      ingressKey.Name,
+		},
+	}
+}
+
+// newReconcileID generates an random ID to correlate the log lines of an reconcile.
+func newReconcileID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
 }
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSetStatusAnnotations(t *testing.T) {
+	ingress := &extensions.Ingress{}
+	statusAnnotations := map[string]string{
+		CanonicalHostedZoneIDAnnotation: "Z1H1FL5HABSF5,Z1H1FL5HABSF5",
+		DualstackDNSNameAnnotation:      "dualstack.web-1.us-west-2.elb.amazonaws.com,dualstack.web-2.us-west-2.elb.amazonaws.com",
+	}
+	assert.True(t, setStatusAnnotations(ingress, statusAnnotations))
+	assert.Equal(t, statusAnnotations, ingress.Annotations)
+	assert.False(t, setStatusAnnotations(ingress, statusAnnotations))
+
+	ingress = &extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		"kubernetes.io/ingress.class":   "alb",
+		DualstackDNSNameAnnotation:      "dualstack.web-1.us-west-2.elb.amazonaws.com",
+		CanonicalHostedZoneIDAnnotation: "Z1H1FL5HABSF5",
+	}}}
+	assert.True(t, setStatusAnnotations(ingress, map[string]string{
+		CanonicalHostedZoneIDAnnotation: "",
+		DualstackDNSNameAnnotation:      "",
+	}))
+	assert.Equal(t, map[string]string{"kubernetes.io/ingress.class": "alb"}, ingress.Annotations)
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ghodss/yaml"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/albctx"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// RenderPath is the path of the render endpoint, which renders the desired AWS model of the ingress manifest posted.
+	RenderPath = "/debug/render"
+
+	// maxRenderManifestSize is the maximum size of ingress manifests posted to the render endpoint.
+	maxRenderManifestSize = 1 << 20
+)
+
+// RenderedModel is the desired AWS model of an ingress manifest.
+type RenderedModel struct {
+	Ingress string `json:"ingress"`
+	// Desired is the configuration of ingress parsed from its annotations.
+	Desired *DesiredIngressConfig `json:"desired,omitempty"`
+	// Resources are the AWS calls planned to create, modify or delete resources for ingress, by kind of resource, e.g. TargetGroup.
+	Resources map[string][]aws.PlannedChange `json:"resources"`
+	// Incomplete is set if planning stopped at an resource depending on resources planned to be created.
+	Incomplete bool   `json:"incomplete,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// renderStore is an store.Storer with the annotations of an ingress manifest, which may not exist in cluster.
+type renderStore struct {
+	store.Storer
+
+	ingressKey   string
+	ingressAnnos *annotations.Ingress
+}
+
+func (s *renderStore) GetIngressAnnotations(key string) (*annotations.Ingress, error) {
+	if key == s.ingressKey {
+		return s.ingressAnnos, nil
+	}
+	return s.Storer.GetIngressAnnotations(key)
+}
+
+func (s *renderStore) ListIngressAnnotations() []*annotations.Ingress {
+	result := []*annotations.Ingress{s.ingressAnnos}
+	for _, ingressAnnos := range s.Storer.ListIngressAnnotations() {
+		if ingressAnnos.Namespace+"/"+ingressAnnos.Name != s.ingressKey {
+			result = append(result, ingressAnnos)
+		}
+	}
+	return result
+}
+
+// renderIngress renders the desired AWS model of ingress, by reconciling it in dry-run against the AWS resources of ingress with the same name.
+// The services ingress routes to are looked up in cluster.
+func (r *Reconciler) renderIngress(ctx context.Context, ingress *extensions.Ingress) *RenderedModel {
+	ingressKey := types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+	model := &RenderedModel{Ingress: ingressKey.String(), Resources: make(map[string][]aws.PlannedChange)}
+	ingressAnnos := annotations.NewIngressAnnotationExtractor(r.store).ExtractIngress(ingress)
+	if ingressAnnos.Error != nil {
+		model.Error = ingressAnnos.Error.Error()
+		return model
+	}
+	model.Desired = newDesiredIngressConfig(ingressAnnos)
+
+	renderer := *r
+	renderer.store = &renderStore{Storer: r.store, ingressKey: ingressKey.String(), ingressAnnos: ingressAnnos}
+	// metrics of rendered manifests are not collected.
+	renderer.lbController = newLBController(r.store.GetConfig(), renderer.store, r.cloud, metric.DummyCollector{}, nil)
+
+	ctx = renderer.buildReconcileContext(ctx, ingressKey, ingress)
+	ctx = albctx.SetEventf(ctx, func(string, string, string, ...interface{}) {})
+	dryRunCtx, plan := aws.WithDryRun(ctx)
+	_, err := renderer.reconcilePartitions(dryRunCtx, ingressKey, ingress, buildPartitions(ingress, ingressAnnos))
+	if err != nil {
+		model.Error = err.Error()
+	}
+	model.Incomplete = err != nil && plan.Incomplete()
+	for _, change := range plan.PlannedChanges() {
+		model.Resources[change.Resource()] = append(model.Resources[change.Resource()], change)
+	}
+	return model
+}
+
+// parseIngressManifest parses an ingress manifest in YAML or JSON, the namespace defaults to default.
+func parseIngressManifest(manifest []byte) (*extensions.Ingress, error) {
+	ingress := &extensions.Ingress{}
+	if err := yaml.Unmarshal(manifest, ingress); err != nil {
+		return nil, fmt.Errorf("failed to parse ingress manifest due to %v", err)
+	}
+	if ingress.Kind != "" && ingress.Kind != "Ingress" {
+		return nil, fmt.Errorf("manifest is an %v, not an Ingress", ingress.Kind)
+	}
+	if ingress.Name == "" {
+		return nil, fmt.Errorf("ingress manifest has no name")
+	}
+	if ingress.Namespace == "" {
+		ingress.Namespace = "default"
+	}
+	return ingress, nil
+}
+
+// newRenderHandler renders the desired AWS model of the ingress manifest posted to RenderPath, to requests authenticated by the bearer token.
+// The model is JSON unless format=yaml is queried. Sensitive values are redacted.
+func newRenderHandler(reconciler *Reconciler, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !isAuthorized(req, token) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if req.Method != http.MethodPost {
+			http.Error(w, "ingress manifest must be posted", http.StatusMethodNotAllowed)
+			return
+		}
+		manifest, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxRenderManifestSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ingress, err := parseIngressManifest(manifest)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		model := reconciler.renderIngress(req.Context(), ingress)
+		payload, err := json.MarshalIndent(log.Redact(model), "", "  ")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		contentType := "application/json"
+		if req.URL.Query().Get("format") == "yaml" {
+			if payload, err = yaml.JSONToYAML(payload); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			contentType = "application/yaml"
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(payload)
+	})
+}
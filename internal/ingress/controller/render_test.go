@@ -0,0 +1,123 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseIngressManifest(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		manifest          string
+		expectedNamespace string
+		expectedError     string
+	}{
+		{
+			name: "yaml",
+			manifest: `
+apiVersion: extensions/v1beta1
+kind: Ingress
+metadata:
+  name: ingress
+  namespace: namespace
+  annotations:
+    alb.ingress.kubernetes.io/scheme: internal
+`,
+			expectedNamespace: "namespace",
+		},
+		{
+			name:              "json without namespace",
+			manifest:          `{"apiVersion": "extensions/v1beta1", "kind": "Ingress", "metadata": {"name": "ingress"}}`,
+			expectedNamespace: "default",
+		},
+		{
+			name:          "not an ingress",
+			manifest:      `{"kind": "Service", "metadata": {"name": "service"}}`,
+			expectedError: "manifest is an Service, not an Ingress",
+		},
+		{
+			name:          "missing name",
+			manifest:      `{"kind": "Ingress"}`,
+			expectedError: "ingress manifest has no name",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ingress, err := parseIngressManifest([]byte(tc.manifest))
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "ingress", ingress.Name)
+			assert.Equal(t, tc.expectedNamespace, ingress.Namespace)
+		})
+	}
+}
+
+func TestRenderStore(t *testing.T) {
+	rendered := &annotations.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}}
+	existing := annotations.NewIngressDummy()
+	existing.ObjectMeta = metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"}
+	s := &renderStore{
+		Storer:       &store.Dummy{GetIngressAnnotationsResponse: existing},
+		ingressKey:   "namespace/ingress",
+		ingressAnnos: rendered,
+	}
+
+	ingressAnnos, err := s.GetIngressAnnotations("namespace/ingress")
+	assert.NoError(t, err)
+	assert.Equal(t, rendered, ingressAnnos)
+
+	ingressAnnos, err = s.GetIngressAnnotations("namespace/other")
+	assert.NoError(t, err)
+	assert.Equal(t, existing, ingressAnnos)
+
+	// the rendered ingress replaces the ingress of the same key in cluster.
+	assert.Equal(t, []*annotations.Ingress{rendered}, s.ListIngressAnnotations())
+}
+
+func TestRenderHandler_rejectsRequests(t *testing.T) {
+	handler := newRenderHandler(&Reconciler{}, "token")
+	for _, tc := range []struct {
+		name           string
+		method         string
+		body           string
+		authorization  string
+		expectedStatus int
+	}{
+		{
+			name:           "wrong token",
+			method:         http.MethodPost,
+			authorization:  "Bearer wrong",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "not posted",
+			method:         http.MethodGet,
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "malformed manifest",
+			method:         http.MethodPost,
+			body:           "kind: [",
+			authorization:  "Bearer token",
+			expectedStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, RenderPath, strings.NewReader(tc.body))
+			req.Header.Set("Authorization", tc.authorization)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
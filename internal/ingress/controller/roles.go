@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ingressRoles tracks the roles of ingresses assumed by their role-arn annotation, so the resources of an ingress
+// are still deleted with its role once it's removed from the cluster without an finalizer.
+// Only roles of allowedRoleARNs are assumed, no role is by default.
+type ingressRoles struct {
+	allowedRoleARNs sets.String
+
+	// mutex protects roles, the role ARNs of ingresses by their keys
+	mutex sync.Mutex
+	roles map[types.NamespacedName]string
+}
+
+func newIngressRoles(allowedRoleARNs []string) *ingressRoles {
+	return &ingressRoles{
+		allowedRoleARNs: sets.NewString(allowedRoleARNs...),
+		roles:           make(map[types.NamespacedName]string),
+	}
+}
+
+// allows tests whether roleARN can be assumed for ingresses.
+func (r *ingressRoles) allows(roleARN string) bool {
+	return r != nil && r.allowedRoleARNs.Has(roleARN)
+}
+
+// roleARN returns the role ARN of ingress, from its annotations in store if they can be parsed, or the one recorded before otherwise.
+// It's empty if the ingress's resources are managed with the controller's own credentials, including when its role isn't allowed.
+func (r *ingressRoles) roleARN(store store.Storer, ingressKey types.NamespacedName) string {
+	if r == nil {
+		return ""
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	ingressAnnos, err := store.GetIngressAnnotations(ingressKey.String())
+	if err != nil || ingressAnnos == nil || ingressAnnos.LoadBalancer == nil {
+		return r.roles[ingressKey]
+	}
+	roleARN := aws.StringValue(ingressAnnos.LoadBalancer.RoleARN)
+	if !r.allowedRoleARNs.Has(roleARN) {
+		roleARN = ""
+	}
+	if roleARN == "" {
+		delete(r.roles, ingressKey)
+	} else {
+		r.roles[ingressKey] = roleARN
+	}
+	return roleARN
+}
+
+// forget forgets the role of ingress once its resources are deleted.
+func (r *ingressRoles) forget(ingressKey types.NamespacedName) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.roles, ingressKey)
+}
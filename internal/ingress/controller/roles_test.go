@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type rolesStore struct {
+	store.Storer
+	roleARN *string
+	err     error
+}
+
+func (s *rolesStore) GetIngressAnnotations(key string) (*annotations.Ingress, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &annotations.Ingress{LoadBalancer: &loadbalancer.Config{RoleARN: s.roleARN}}, nil
+}
+
+func TestIngressRoles(t *testing.T) {
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	roles := newIngressRoles([]string{"arn:aws:iam::111111111111:role/alb"})
+	s := &rolesStore{roleARN: aws.String("arn:aws:iam::111111111111:role/alb")}
+	assert.Equal(t, "arn:aws:iam::111111111111:role/alb", roles.roleARN(s, ingressKey))
+
+	// the role is recorded for the ingress once it's removed from the store.
+	s.err = errors.New("annotations not found")
+	assert.Equal(t, "arn:aws:iam::111111111111:role/alb", roles.roleARN(s, ingressKey))
+	roles.forget(ingressKey)
+	assert.Equal(t, "", roles.roleARN(s, ingressKey))
+
+	s.err = nil
+	roles.roleARN(s, ingressKey)
+	s.roleARN = nil
+	assert.Equal(t, "", roles.roleARN(s, ingressKey))
+	s.err = errors.New("annotations not found")
+	assert.Equal(t, "", roles.roleARN(s, ingressKey))
+
+	var noRoles *ingressRoles
+	assert.Equal(t, "", noRoles.roleARN(s, ingressKey))
+	assert.False(t, noRoles.allows("arn:aws:iam::111111111111:role/alb"))
+}
+
+func TestIngressRoles_notAllowed(t *testing.T) {
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	s := &rolesStore{roleARN: aws.String("arn:aws:iam::222222222222:role/alb")}
+
+	// roles are denied by default.
+	roles := newIngressRoles(nil)
+	assert.False(t, roles.allows("arn:aws:iam::222222222222:role/alb"))
+	assert.Equal(t, "", roles.roleARN(s, ingressKey))
+
+	roles = newIngressRoles([]string{"arn:aws:iam::111111111111:role/alb"})
+	assert.True(t, roles.allows("arn:aws:iam::111111111111:role/alb"))
+	assert.False(t, roles.allows("arn:aws:iam::222222222222:role/alb"))
+	assert.Equal(t, "", roles.roleARN(s, ingressKey))
+	s.err = errors.New("annotations not found")
+	assert.Equal(t, "", roles.roleARN(s, ingressKey))
+}
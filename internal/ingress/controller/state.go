@@ -0,0 +1,327 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/log"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// stateFlushInterval is the interval at which changes to the state of ingresses are written to the state ConfigMap.
+const stateFlushInterval = 30 * time.Second
+
+// stateConfigMapDataKey is the key of the state of ingresses, encoded in JSON, in the data of the state ConfigMap.
+const stateConfigMapDataKey = "ingresses.json"
+
+var stateLogger = log.New("state-cache")
+
+// ingressState is the state of an ingress recorded after it's reconciled.
+type ingressState struct {
+	// Hash is the hash of the ingress and its services when it's reconciled, see hashIngressState
+	Hash string `json:"hash"`
+	// TargetGroups are the targetGroups reconciled by partition name, which is the ingress name unless it's split by host
+	TargetGroups map[string][]targetGroupState `json:"targetGroups"`
+}
+
+// targetGroupState is the targetGroup reconciled for an backend.
+type targetGroupState struct {
+	Backend    extensions.IngressBackend `json:"backend"`
+	TargetType string                    `json:"targetType"`
+	Arn        string                    `json:"arn"`
+}
+
+// stateCache persists the state of ingresses reconciled in an ConfigMap, so that ingresses unchanged since they were last reconciled
+// aren't reconciled in full again when the controller restarts, which would describe all their AWS resources at once in large clusters.
+// The methods of an nil stateCache are no-ops.
+type stateCache struct {
+	client       client.Client
+	configMapKey types.NamespacedName
+
+	mutex  sync.Mutex
+	states map[string]ingressState
+	// restorable are the states loaded on startup which are not yet restored or discarded
+	restorable map[string]ingressState
+	dirty      bool
+}
+
+func newStateCache(client client.Client, configMapKey types.NamespacedName) *stateCache {
+	return &stateCache{
+		client:       client,
+		configMapKey: configMapKey,
+		states:       make(map[string]ingressState),
+		restorable:   make(map[string]ingressState),
+	}
+}
+
+// load loads the states written by the previous controller, none are loaded if the ConfigMap doesn't exist.
+func (c *stateCache) load(ctx context.Context) error {
+	configMap := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, c.configMapKey, configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get state configMap %v due to %v", c.configMapKey, err)
+	}
+	states := make(map[string]ingressState)
+	if data, ok := configMap.Data[stateConfigMapDataKey]; ok {
+		if err := json.Unmarshal([]byte(data), &states); err != nil {
+			// the state is only an optimization, ingresses are reconciled in full without it.
+			stateLogger.Warnf("ignoring state configMap %v due to %v", c.configMapKey, err)
+			return nil
+		}
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key, state := range states {
+		c.states[key] = state
+		c.restorable[key] = state
+	}
+	return nil
+}
+
+// Start implements manager.Runnable, so that only the leader writes the state ConfigMap.
+func (c *stateCache) Start(stop <-chan struct{}) error {
+	wait.Until(c.flushWithLog, stateFlushInterval, stop)
+	// the changes since the last flush are written before the controller exits.
+	c.flushWithLog()
+	return nil
+}
+
+func (c *stateCache) flushWithLog() {
+	if err := c.flush(context.Background()); err != nil {
+		stateLogger.Errorf("failed to write state configMap %v due to %v", c.configMapKey, err)
+	}
+}
+
+// flush writes the states to the ConfigMap if they changed since the last flush.
+func (c *stateCache) flush(ctx context.Context) error {
+	c.mutex.Lock()
+	if !c.dirty {
+		c.mutex.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(c.states)
+	c.dirty = false
+	c.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := c.writeConfigMap(ctx, string(data)); err != nil {
+		c.mutex.Lock()
+		c.dirty = true
+		c.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (c *stateCache) writeConfigMap(ctx context.Context, data string) error {
+	configMap := &corev1.ConfigMap{}
+	if err := c.client.Get(ctx, c.configMapKey, configMap); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		return c.client.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: c.configMapKey.Namespace, Name: c.configMapKey.Name},
+			Data:       map[string]string{stateConfigMapDataKey: data},
+		})
+	}
+	configMap.Data = map[string]string{stateConfigMapDataKey: data}
+	return c.client.Update(ctx, configMap)
+}
+
+// record records the state of ingress after it's reconciled, replacing the state loaded on startup.
+func (c *stateCache) record(ingressKey types.NamespacedName, state ingressState) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.restorable, ingressKey.String())
+	if existing, ok := c.states[ingressKey.String()]; ok && reflect.DeepEqual(existing, state) {
+		return
+	}
+	c.states[ingressKey.String()] = state
+	c.dirty = true
+}
+
+// forget removes the state of ingress, e.g. when it's deleted.
+func (c *stateCache) forget(ingressKey types.NamespacedName) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.states[ingressKey.String()]; ok {
+		delete(c.states, ingressKey.String())
+		c.dirty = true
+	}
+	delete(c.restorable, ingressKey.String())
+}
+
+// restore returns the state of ingress loaded on startup if its hash is hash. The state loaded is only returned once,
+// later reconciles of ingress are always in full.
+func (c *stateCache) restore(ingressKey types.NamespacedName, hash string) (ingressState, bool) {
+	if c == nil {
+		return ingressState{}, false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	state, ok := c.restorable[ingressKey.String()]
+	delete(c.restorable, ingressKey.String())
+	return state, ok && state.Hash == hash
+}
+
+// hashIngressState returns the hash of the controller configuration cfg, the spec and annotations of ingress, and the annotations and ports of services,
+// which are the inputs of its AWS resources other than endpoints. The annotations of ingress include the annotation defaults it inherits,
+// so changes to the configuration or defaults, e.g. by an upgrade, make ingresses be reconciled in full on startup.
+func hashIngressState(cfg *config.Configuration, ingress *extensions.Ingress, services []*corev1.Service) (string, error) {
+	type serviceInput struct {
+		Name        string
+		Annotations map[string]string
+		Ports       []corev1.ServicePort
+	}
+	input := struct {
+		Config      *config.Configuration
+		Spec        extensions.IngressSpec
+		Annotations map[string]string
+		Services    []serviceInput
+	}{
+		Config:      cfg,
+		Spec:        ingress.Spec,
+		Annotations: ingress.Annotations,
+	}
+	for _, service := range services {
+		input.Services = append(input.Services, serviceInput{Name: service.Name, Annotations: service.Annotations, Ports: service.Spec.Ports})
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+	hasher := fnv.New64a()
+	_, _ = hasher.Write(data)
+	return strconv.FormatUint(hasher.Sum64(), 16), nil
+}
+
+// backendServiceNames returns the sorted names of the services of the backends of ingress.
+func backendServiceNames(ingress *extensions.Ingress) []string {
+	names := sets.NewString()
+	if ingress.Spec.Backend != nil {
+		names.Insert(ingress.Spec.Backend.ServiceName)
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			names.Insert(path.Backend.ServiceName)
+		}
+	}
+	return names.List()
+}
+
+// buildTargetGroupStates returns the states of the targetGroups recorded for an partition.
+func buildTargetGroupStates(targets []*tg.Targets) []targetGroupState {
+	states := make([]targetGroupState, 0, len(targets))
+	for _, t := range targets {
+		states = append(states, targetGroupState{Backend: *t.Backend, TargetType: t.TargetType, Arn: t.TgArn})
+	}
+	// targets are recorded from an map, they're sorted so that the state of an unchanged ingress stays the same.
+	sort.Slice(states, func(i, j int) bool { return states[i].Arn < states[j].Arn })
+	return states
+}
+
+// restoreState restores the targetGroups recorded for ingress when it was last reconciled by the previous controller,
+// and returns true if it's unchanged since, so that it's not reconciled in full on startup.
+// Its AWS resources are still reconciled when it or its services change, and by periodic resyncs.
+func (r *Reconciler) restoreState(ctx context.Context, ingressKey types.NamespacedName, ingress *extensions.Ingress) bool {
+	if r.stateCache == nil {
+		return false
+	}
+	config := r.store.GetConfig()
+	if config.DryRun || (config.IngressFinalizer && !k8s.HasFinalizer(ingress, k8s.IngressFinalizer)) {
+		return false
+	}
+	hash, err := r.hashIngressState(ctx, ingress)
+	if err != nil {
+		return false
+	}
+	state, ok := r.stateCache.restore(ingressKey, hash)
+	if !ok {
+		return false
+	}
+	ingressAnnos, err := r.store.GetIngressAnnotations(ingressKey.String())
+	if err != nil {
+		return false
+	}
+	partitions := buildPartitions(ingress, ingressAnnos)
+	for _, partition := range partitions {
+		if _, ok := state.TargetGroups[partition.Name]; !ok {
+			return false
+		}
+	}
+	for _, partition := range partitions {
+		tgByBackend := make(map[extensions.IngressBackend]tg.TargetGroup)
+		for _, tgState := range state.TargetGroups[partition.Name] {
+			tgByBackend[tgState.Backend] = tg.TargetGroup{Arn: tgState.Arn, TargetType: tgState.TargetType}
+		}
+		r.targetsRegistry.Record(partition, tgByBackend)
+	}
+	stateLogger.Infof("ingress %v is unchanged since it was last reconciled, its state is restored", ingressKey)
+	return true
+}
+
+// recordState records the targetGroups of ingress after it's reconciled.
+func (r *Reconciler) recordState(ctx context.Context, ingressKey types.NamespacedName, ingress *extensions.Ingress, partitions []*extensions.Ingress) {
+	if r.stateCache == nil {
+		return
+	}
+	hash, err := r.hashIngressState(ctx, ingress)
+	if err != nil {
+		// the ingress is reconciled in full on the next restart.
+		r.stateCache.forget(ingressKey)
+		return
+	}
+	state := ingressState{Hash: hash, TargetGroups: make(map[string][]targetGroupState)}
+	for _, partition := range partitions {
+		partitionKey := types.NamespacedName{Namespace: partition.Namespace, Name: partition.Name}
+		state.TargetGroups[partition.Name] = buildTargetGroupStates(r.targetsRegistry.IngressTargets(partitionKey))
+	}
+	r.stateCache.record(ingressKey, state)
+}
+
+// hashIngressState returns the hash of ingress with its annotation defaults and its services in cache, under the current controller configuration.
+func (r *Reconciler) hashIngressState(ctx context.Context, ingress *extensions.Ingress) (string, error) {
+	var services []*corev1.Service
+	for _, name := range backendServiceNames(ingress) {
+		service := &corev1.Service{}
+		if err := r.cache.Get(ctx, types.NamespacedName{Namespace: ingress.Namespace, Name: name}, service); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		services = append(services, service)
+	}
+	return hashIngressState(r.store.GetConfig(), annotations.WithAnnotationDefaults(r.store, ingress), services)
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestStateCache(t *testing.T) {
+	ingressKey := types.NamespacedName{Namespace: "namespace", Name: "ingress"}
+	state := ingressState{Hash: "hash", TargetGroups: map[string][]targetGroupState{"ingress": {{TargetType: "ip", Arn: "tgArn"}}}}
+	c := newStateCache(nil, types.NamespacedName{})
+	c.states[ingressKey.String()] = state
+	c.restorable[ingressKey.String()] = state
+
+	_, ok := c.restore(ingressKey, "other")
+	assert.False(t, ok)
+	_, ok = c.restore(ingressKey, "hash")
+	assert.False(t, ok, "state is only restored once")
+
+	c.restorable[ingressKey.String()] = state
+	restored, ok := c.restore(ingressKey, "hash")
+	assert.True(t, ok)
+	assert.Equal(t, state, restored)
+
+	c.record(ingressKey, state)
+	assert.False(t, c.dirty, "unchanged state isn't written again")
+	c.record(ingressKey, ingressState{Hash: "changed"})
+	assert.True(t, c.dirty)
+
+	c.dirty = false
+	c.forget(ingressKey)
+	assert.True(t, c.dirty)
+	assert.Empty(t, c.states)
+
+	var nilCache *stateCache
+	nilCache.record(ingressKey, state)
+	nilCache.forget(ingressKey)
+	_, ok = nilCache.restore(ingressKey, "hash")
+	assert.False(t, ok)
+}
+
+func TestHashIngressState(t *testing.T) {
+	ingress := &extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress", Annotations: map[string]string{"alb.ingress.kubernetes.io/scheme": "internal"}},
+		Spec:       extensions.IngressSpec{Backend: &extensions.IngressBackend{ServiceName: "service", ServicePort: intstr.FromInt(80)}},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "service"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80}}},
+	}
+	cfg := &config.Configuration{ClusterName: "cluster", DefaultTags: []string{"team=platform"}}
+	hash, err := hashIngressState(cfg, ingress, []*corev1.Service{service})
+	assert.NoError(t, err)
+
+	// status and metadata other than annotations don't change the hash.
+	updated := ingress.DeepCopy()
+	updated.ResourceVersion = "2"
+	updated.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.us-west-2.elb.amazonaws.com"}}
+	updatedHash, err := hashIngressState(cfg, updated, []*corev1.Service{service})
+	assert.NoError(t, err)
+	assert.Equal(t, hash, updatedHash)
+
+	updated.Annotations["alb.ingress.kubernetes.io/scheme"] = "internet-facing"
+	updatedHash, err = hashIngressState(cfg, updated, []*corev1.Service{service})
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, updatedHash)
+
+	updatedService := service.DeepCopy()
+	updatedService.Annotations = map[string]string{"alb.ingress.kubernetes.io/target-type": "ip"}
+	updatedHash, err = hashIngressState(cfg, ingress, []*corev1.Service{updatedService})
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, updatedHash)
+
+	// changes to the configuration of the controller, e.g. on upgrades, change the hash.
+	updatedCfg := &config.Configuration{ClusterName: "cluster", DefaultTags: []string{"team=platform", "env=prod"}}
+	updatedHash, err = hashIngressState(updatedCfg, ingress, []*corev1.Service{service})
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, updatedHash)
+
+	updatedCfg = &config.Configuration{ClusterName: "cluster", DefaultTags: []string{"team=platform"}, RequireWAF: true}
+	updatedHash, err = hashIngressState(updatedCfg, ingress, []*corev1.Service{service})
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, updatedHash)
+}
+
+func TestBackendServiceNames(t *testing.T) {
+	ingress := &extensions.Ingress{
+		Spec: extensions.IngressSpec{
+			Backend: &extensions.IngressBackend{ServiceName: "default"},
+			Rules: []extensions.IngressRule{
+				{IngressRuleValue: extensions.IngressRuleValue{HTTP: &extensions.HTTPIngressRuleValue{
+					Paths: []extensions.HTTPIngressPath{{Backend: extensions.IngressBackend{ServiceName: "web"}}, {Backend: extensions.IngressBackend{ServiceName: "default"}}},
+				}}},
+				{},
+			},
+		},
+	}
+	assert.Equal(t, []string{"default", "web"}, backendServiceNames(ingress))
+}
+
+func TestBuildTargetGroupStates(t *testing.T) {
+	backend1 := extensions.IngressBackend{ServiceName: "service1", ServicePort: intstr.FromInt(80)}
+	backend2 := extensions.IngressBackend{ServiceName: "service2", ServicePort: intstr.FromString("http")}
+	states := buildTargetGroupStates([]*tg.Targets{
+		{TgArn: "tgArn2", TargetType: "instance", Backend: &backend2},
+		{TgArn: "tgArn1", TargetType: "ip", Backend: &backend1},
+	})
+	assert.Equal(t, []targetGroupState{
+		{Backend: backend1, TargetType: "ip", Arn: "tgArn1"},
+		{Backend: backend2, TargetType: "instance", Arn: "tgArn2"},
+	}, states)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/dummy"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type Dummy struct {
@@ -26,6 +27,11 @@ func (d Dummy) GetConfigMap(key string) (*corev1.ConfigMap, error) {
 	return nil, nil
 }
 
+// GetSecret ...
+func (d Dummy) GetSecret(key string) (*corev1.Secret, error) {
+	return nil, NotExistsError(key)
+}
+
 // GetService ...
 func (d Dummy) GetService(key string) (*corev1.Service, error) {
 	return d.GetServiceFunc(key)
@@ -46,6 +52,11 @@ func (d Dummy) GetIngress(key string) (*extensions.Ingress, error) {
 	return nil, nil
 }
 
+// GetNamespace ...
+func (d Dummy) GetNamespace(name string) (*corev1.Namespace, error) {
+	return nil, NotExistsError(name)
+}
+
 // ListNodes ...
 func (d Dummy) ListNodes() []*corev1.Node {
 	return d.ListNodesFunc()
@@ -61,6 +72,24 @@ func (d Dummy) GetIngressAnnotations(key string) (*annotations.Ingress, error) {
 	return d.GetIngressAnnotationsResponse, nil
 }
 
+// ListIngressAnnotations ...
+func (d Dummy) ListIngressAnnotations() []*annotations.Ingress {
+	if d.GetIngressAnnotationsResponse == nil {
+		return nil
+	}
+	return []*annotations.Ingress{d.GetIngressAnnotationsResponse}
+}
+
+// ReloadIngressAnnotations ...
+func (d Dummy) ReloadIngressAnnotations(namespace string) []types.NamespacedName {
+	return nil
+}
+
+// ReloadIngressAnnotationsReferencing ...
+func (d Dummy) ReloadIngressAnnotationsReferencing(namespace string, references func(*annotations.Ingress) bool) []types.NamespacedName {
+	return nil
+}
+
 // Run ...
 func (d Dummy) Run(stopCh chan struct{}) {
 }
@@ -5,6 +5,7 @@ package store
 import annotations "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
 import config "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
 import mock "github.com/stretchr/testify/mock"
+import types "k8s.io/apimachinery/pkg/types"
 import v1 "k8s.io/api/core/v1"
 
 // MockStorer is an autogenerated mock type for the Storer type
@@ -51,6 +52,29 @@ func (_m *MockStorer) GetConfig() *config.Configuration {
 	return r0
 }
 
+// GetConfigMap provides a mock function with given fields: key
+func (_m *MockStorer) GetConfigMap(key string) (*v1.ConfigMap, error) {
+	ret := _m.Called(key)
+
+	var r0 *v1.ConfigMap
+	if rf, ok := ret.Get(0).(func(string) *v1.ConfigMap); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ConfigMap)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetIngressAnnotations provides a mock function with given fields: key
 func (_m *MockStorer) GetIngressAnnotations(key string) (*annotations.Ingress, error) {
 	ret := _m.Called(key)
@@ -95,6 +119,29 @@ func (_m *MockStorer) GetInstanceIDFromPodIP(_a0 string) (string, error) {
 	return r0, r1
 }
 
+// GetNamespace provides a mock function with given fields: name
+func (_m *MockStorer) GetNamespace(name string) (*v1.Namespace, error) {
+	ret := _m.Called(name)
+
+	var r0 *v1.Namespace
+	if rf, ok := ret.Get(0).(func(string) *v1.Namespace); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Namespace)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNodeInstanceID provides a mock function with given fields: node
 func (_m *MockStorer) GetNodeInstanceID(node *v1.Node) (string, error) {
 	ret := _m.Called(node)
@@ -116,6 +163,29 @@ func (_m *MockStorer) GetNodeInstanceID(node *v1.Node) (string, error) {
 	return r0, r1
 }
 
+// GetSecret provides a mock function with given fields: key
+func (_m *MockStorer) GetSecret(key string) (*v1.Secret, error) {
+	ret := _m.Called(key)
+
+	var r0 *v1.Secret
+	if rf, ok := ret.Get(0).(func(string) *v1.Secret); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Secret)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetService provides a mock function with given fields: key
 func (_m *MockStorer) GetService(key string) (*v1.Service, error) {
 	ret := _m.Called(key)
@@ -185,6 +255,22 @@ func (_m *MockStorer) GetServiceEndpoints(key string) (*v1.Endpoints, error) {
 	return r0, r1
 }
 
+// ListIngressAnnotations provides a mock function with given fields:
+func (_m *MockStorer) ListIngressAnnotations() []*annotations.Ingress {
+	ret := _m.Called()
+
+	var r0 []*annotations.Ingress
+	if rf, ok := ret.Get(0).(func() []*annotations.Ingress); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*annotations.Ingress)
+		}
+	}
+
+	return r0
+}
+
 // ListNodes provides a mock function with given fields:
 func (_m *MockStorer) ListNodes() []*v1.Node {
 	ret := _m.Called()
@@ -200,3 +286,35 @@ func (_m *MockStorer) ListNodes() []*v1.Node {
 
 	return r0
 }
+
+// ReloadIngressAnnotations provides a mock function with given fields: namespace
+func (_m *MockStorer) ReloadIngressAnnotations(namespace string) []types.NamespacedName {
+	ret := _m.Called(namespace)
+
+	var r0 []types.NamespacedName
+	if rf, ok := ret.Get(0).(func(string) []types.NamespacedName); ok {
+		r0 = rf(namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.NamespacedName)
+		}
+	}
+
+	return r0
+}
+
+// ReloadIngressAnnotationsReferencing provides a mock function with given fields: namespace, references
+func (_m *MockStorer) ReloadIngressAnnotationsReferencing(namespace string, references func(*annotations.Ingress) bool) []types.NamespacedName {
+	ret := _m.Called(namespace, references)
+
+	var r0 []types.NamespacedName
+	if rf, ok := ret.Get(0).(func(string, func(*annotations.Ingress) bool) []types.NamespacedName); ok {
+		r0 = rf(namespace, references)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.NamespacedName)
+		}
+	}
+
+	return r0
+}
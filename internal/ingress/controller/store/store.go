@@ -31,6 +31,7 @@ import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
 	corev1 "k8s.io/api/core/v1"
 	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -48,12 +49,31 @@ type Storer interface {
 	// GetServiceAnnotations returns the parsed annotations of an Service matching key. if ingress is non-nil, merges ingress annotations into the service.
 	GetServiceAnnotations(key string, ingress *annotations.Ingress) (*annotations.Service, error)
 
+	// GetNamespace returns the Namespace matching name.
+	GetNamespace(name string) (*corev1.Namespace, error)
+
+	// GetSecret returns the Secret matching key.
+	GetSecret(key string) (*corev1.Secret, error)
+
+	// GetConfigMap returns the ConfigMap matching key.
+	GetConfigMap(key string) (*corev1.ConfigMap, error)
+
 	// ListNodes returns a list of all Nodes in the store.
 	ListNodes() []*corev1.Node
 
 	// GetIngressAnnotations returns the parsed annotations of an Ingress matching key.
 	GetIngressAnnotations(key string) (*annotations.Ingress, error)
 
+	// ListIngressAnnotations returns the parsed annotations of all valid Ingresses satisfied by this controller.
+	ListIngressAnnotations() []*annotations.Ingress
+
+	// ReloadIngressAnnotations parses the annotations of all Ingresses in namespace satisfied by this controller again, and returns their keys. Ingresses in all namespaces are parsed if namespace is empty.
+	ReloadIngressAnnotations(namespace string) []types.NamespacedName
+
+	// ReloadIngressAnnotationsReferencing parses the annotations of the Ingresses in namespace satisfied by this controller that references tells reference an object,
+	// or that failed to parse, which may be due to an missing object, again, and returns their keys.
+	ReloadIngressAnnotationsReferencing(namespace string, references func(*annotations.Ingress) bool) []types.NamespacedName
+
 	// GetConfig returns the controller configuration
 	GetConfig() *config.Configuration
 
@@ -74,6 +94,13 @@ type Informer struct {
 	Endpoint cache.SharedIndexInformer
 	Node     cache.SharedIndexInformer
 	Pod      cache.SharedIndexInformer
+
+	// Secret and ConfigMap are read for the objects referenced by annotations, e.g. the client credentials of authenticate actions
+	Secret    cache.SharedIndexInformer
+	ConfigMap cache.SharedIndexInformer
+
+	// Namespace is only watched when namespaces are needed to render tag templates or for annotation defaults
+	Namespace cache.SharedIndexInformer
 }
 
 // Lister contains object listers (stores).
@@ -83,6 +110,9 @@ type Lister struct {
 	Endpoint          EndpointLister
 	Node              NodeLister
 	Pod               PodLister
+	Namespace         NamespaceLister
+	Secret            SecretLister
+	ConfigMap         ConfigMapLister
 	IngressAnnotation IngressAnnotationsLister
 	ServiceAnnotation ServiceAnnotationsLister
 }
@@ -106,19 +136,21 @@ type k8sStore struct {
 	ingannotations annotations.Extractor
 	svcannotations annotations.Extractor
 
-	// configuration
-	cfg *config.Configuration
+	// configuration, which is reloaded atomically
+	cfg *config.Holder
 
 	// mu protects against simultaneous invocations of syncSecret
 	mu *sync.Mutex
 }
 
 // New creates a new object store to be used in the ingress controller
-func New(mgr manager.Manager, cfg *config.Configuration) (Storer, error) {
+// Settings that aren't reloaded are read from the configuration of configHolder at the time.
+func New(mgr manager.Manager, configHolder *config.Holder) (Storer, error) {
+	cfg := configHolder.Get()
 	store := &k8sStore{
 		informers: &Informer{},
 		listers:   &Lister{},
-		cfg:       cfg,
+		cfg:       configHolder,
 		mu:        &sync.Mutex{},
 	}
 
@@ -160,10 +192,30 @@ func New(mgr manager.Manager, cfg *config.Configuration) (Storer, error) {
 	}
 	store.listers.Pod.Store = store.informers.Pod.GetStore()
 
+	store.informers.Secret, err = mgrCache.GetInformer(&corev1.Secret{})
+	if err != nil {
+		return nil, err
+	}
+	store.listers.Secret.Store = store.informers.Secret.GetStore()
+
+	store.informers.ConfigMap, err = mgrCache.GetInformer(&corev1.ConfigMap{})
+	if err != nil {
+		return nil, err
+	}
+	store.listers.ConfigMap.Store = store.informers.ConfigMap.GetStore()
+
+	if len(cfg.TagTemplates) != 0 || cfg.NamespaceAnnotationDefaults {
+		store.informers.Namespace, err = mgrCache.GetInformer(&corev1.Namespace{})
+		if err != nil {
+			return nil, err
+		}
+		store.listers.Namespace.Store = store.informers.Namespace.GetStore()
+	}
+
 	ingEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			ing := obj.(*extensions.Ingress)
-			if !class.IsValidIngress(cfg.IngressClass, ing) {
+			if !class.IsValidIngress(cfg.IngressClass, ing) || !cfg.IsWatchedIngress(ing) {
 				return
 			}
 			store.extractIngressAnnotations(ing)
@@ -183,14 +235,14 @@ func New(mgr manager.Manager, cfg *config.Configuration) (Storer, error) {
 					return
 				}
 			}
-			if !class.IsValidIngress(cfg.IngressClass, ing) {
+			if !class.IsValidIngress(cfg.IngressClass, ing) || !cfg.IsWatchedIngress(ing) {
 				return
 			}
 			_ = store.listers.IngressAnnotation.Delete(ing)
 		},
 		UpdateFunc: func(old, cur interface{}) {
 			curIng := cur.(*extensions.Ingress)
-			if !class.IsValidIngress(cfg.IngressClass, curIng) {
+			if !class.IsValidIngress(cfg.IngressClass, curIng) || !cfg.IsWatchedIngress(curIng) {
 				return
 			}
 			store.extractIngressAnnotations(curIng)
@@ -251,6 +303,24 @@ func (s k8sStore) GetService(key string) (*corev1.Service, error) {
 	return s.listers.Service.ByKey(key)
 }
 
+// GetNamespace returns the Namespace matching name.
+func (s k8sStore) GetNamespace(name string) (*corev1.Namespace, error) {
+	if s.listers.Namespace.Store == nil {
+		return nil, fmt.Errorf("namespaces are not watched")
+	}
+	return s.listers.Namespace.ByKey(name)
+}
+
+// GetSecret returns the Secret matching key.
+func (s k8sStore) GetSecret(key string) (*corev1.Secret, error) {
+	return s.listers.Secret.ByKey(key)
+}
+
+// GetConfigMap returns the ConfigMap matching key.
+func (s k8sStore) GetConfigMap(key string) (*corev1.ConfigMap, error) {
+	return s.listers.ConfigMap.ByKey(key)
+}
+
 // ListNodes returns the list of Nodes
 func (s k8sStore) ListNodes() []*corev1.Node {
 	var nodes []*corev1.Node
@@ -267,9 +337,9 @@ func (s k8sStore) ListNodes() []*corev1.Node {
 	return nodes
 }
 
-// GetConfig returns the controller configuration.
+// GetConfig returns an snapshot of the controller configuration, which mustn't be modified.
 func (s k8sStore) GetConfig() *config.Configuration {
-	return s.cfg
+	return s.cfg.Get()
 }
 
 // GetIngressAnnotations returns the parsed annotations of an Ingress matching key.
@@ -282,6 +352,55 @@ func (s k8sStore) GetIngressAnnotations(key string) (*annotations.Ingress, error
 	return ia, nil
 }
 
+// ListIngressAnnotations returns the parsed annotations of all valid Ingresses satisfied by this controller.
+func (s k8sStore) ListIngressAnnotations() []*annotations.Ingress {
+	var result []*annotations.Ingress
+	for _, item := range s.listers.IngressAnnotation.List() {
+		ia := item.(*annotations.Ingress)
+		if ia.Error != nil {
+			continue
+		}
+		result = append(result, ia)
+	}
+	return result
+}
+
+// ReloadIngressAnnotations parses the annotations of all Ingresses in namespace satisfied by this controller again, e.g. after annotation defaults changed, and returns their keys.
+func (s *k8sStore) ReloadIngressAnnotations(namespace string) []types.NamespacedName {
+	var keys []types.NamespacedName
+	for _, item := range s.listers.Ingress.List() {
+		ing := item.(*extensions.Ingress)
+		if namespace != "" && ing.Namespace != namespace {
+			continue
+		}
+		if !class.IsValidIngress(s.GetConfig().IngressClass, ing) || !s.GetConfig().IsWatchedIngress(ing) {
+			continue
+		}
+		s.extractIngressAnnotations(ing)
+		keys = append(keys, types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name})
+	}
+	return keys
+}
+
+// ReloadIngressAnnotationsReferencing parses the annotations of the Ingresses in namespace that references tells reference an object again, e.g. after the object changed, and returns their keys.
+// Ingresses whose annotations failed to parse are parsed again as well, as they may reference the object but their references are unknown.
+func (s *k8sStore) ReloadIngressAnnotationsReferencing(namespace string, references func(*annotations.Ingress) bool) []types.NamespacedName {
+	var keys []types.NamespacedName
+	for _, item := range s.listers.IngressAnnotation.List() {
+		ia := item.(*annotations.Ingress)
+		if ia.Namespace != namespace || (ia.Error == nil && !references(ia)) {
+			continue
+		}
+		ing, err := s.listers.Ingress.ByKey(k8s.MetaNamespaceKey(&ia.ObjectMeta))
+		if err != nil {
+			continue
+		}
+		s.extractIngressAnnotations(ing)
+		keys = append(keys, types.NamespacedName{Namespace: ing.Namespace, Name: ing.Name})
+	}
+	return keys
+}
+
 // GetServiceAnnotations returns the parsed annotations of an Service matching key.
 func (s k8sStore) GetServiceAnnotations(key string, ingress *annotations.Ingress) (*annotations.Service, error) {
 	sa, err := s.listers.ServiceAnnotation.ByKey(key)
@@ -290,7 +409,7 @@ func (s k8sStore) GetServiceAnnotations(key string, ingress *annotations.Ingress
 	}
 
 	if ingress != nil {
-		return sa.Merge(ingress, s.cfg), nil
+		return sa.Merge(ingress, s.GetConfig()), nil
 	}
 
 	return sa, nil
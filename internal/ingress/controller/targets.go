@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/alb/tg"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/class"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/k8s"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// targetsReconciler reconciles the targets of the targetGroups of an service alone when its endpoints change,
+// instead of reconciling the listeners, rules and attributes of its ingresses in full.
+// Ingresses referencing the service without recorded targetGroups for it, e.g. not yet reconciled since the controller started, are reconciled in full instead.
+type targetsReconciler struct {
+	reconciler        *Reconciler
+	registry          *tg.TargetsRegistry
+	targetsController tg.TargetsController
+
+	// ingressEvents enqueues ingresses to be reconciled in full by reconciler
+	ingressEvents chan<- event.GenericEvent
+}
+
+// Reconcile reconciles the targets of the service of request, which is the key of its endpoints.
+func (r *targetsReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	if !r.reconciler.drainer.begin() {
+		return reconcile.Result{}, nil
+	}
+	defer r.reconciler.drainer.end()
+	ctx := context.Background()
+	recorded := sets.NewString()
+	for _, t := range r.registry.ServiceTargets(request.NamespacedName) {
+		ingressKey := targetsIngressKey(t.Ingress)
+		recorded.Insert(ingressKey.String())
+		if t.TargetType != elbv2.TargetTypeEnumIp {
+			// instance targets are the nodes of the cluster, which don't depend on endpoints.
+			continue
+		}
+		// t is shared with the registry, so the targets are reconciled on an copy.
+		targets := *t
+		if err := r.targetsController.Reconcile(r.reconciler.buildReconcileContext(ctx, ingressKey, t.Ingress), &targets); err != nil {
+			return reconcile.Result{}, fmt.Errorf("failed to reconcile targets of service %v for ingress %v due to %v", request.NamespacedName, ingressKey, err)
+		}
+	}
+
+	ingressList := &extensions.IngressList{}
+	if err := r.reconciler.cache.List(ctx, client.InNamespace(request.Namespace), ingressList); err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to fetch impacted ingresses by endpoints due to %v", err)
+	}
+	config := r.reconciler.store.GetConfig()
+	for i := range ingressList.Items {
+		ingress := &ingressList.Items[i]
+		if !class.IsValidIngress(config.IngressClass, ingress) || !config.IsWatchedIngress(ingress) {
+			continue
+		}
+		if recorded.Has(types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}.String()) || !referencesService(ingress, request.Name) {
+			continue
+		}
+		r.ingressEvents <- event.GenericEvent{
+			Meta: &metav1.ObjectMeta{Namespace: ingress.Namespace, Name: ingress.Name},
+		}
+	}
+	return reconcile.Result{}, nil
+}
+
+// targetsIngressKey returns the key of the ingress targets are reconciled for, partitions of ingresses split by host map to the ingress.
+func targetsIngressKey(ingress *extensions.Ingress) types.NamespacedName {
+	if partitionOf, ok := ingress.Annotations[k8s.IngressPartitionOfAnnotation]; ok {
+		return types.NamespacedName{Namespace: ingress.Namespace, Name: partitionOf}
+	}
+	return types.NamespacedName{Namespace: ingress.Namespace, Name: ingress.Name}
+}
+
+// referencesService returns whether ingress has an backend of service.
+func referencesService(ingress *extensions.Ingress, service string) bool {
+	if ingress.Spec.Backend != nil && ingress.Spec.Backend.ServiceName == service {
+		return true
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.ServiceName == service {
+				return true
+			}
+		}
+	}
+	return false
+}
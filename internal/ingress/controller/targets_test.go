@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestTargetsIngressKey(t *testing.T) {
+	assert.Equal(t, types.NamespacedName{Namespace: "namespace", Name: "ingress"}, targetsIngressKey(&extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"},
+	}))
+	assert.Equal(t, types.NamespacedName{Namespace: "namespace", Name: "ingress"}, targetsIngressKey(&extensions.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "namespace",
+			Name:        "ingress-a-example-com",
+			Annotations: map[string]string{"alb.ingress.kubernetes.io/partition-of": "ingress"},
+		},
+	}))
+}
+
+func TestReferencesService(t *testing.T) {
+	ingress := &extensions.Ingress{
+		Spec: extensions.IngressSpec{
+			Backend: &extensions.IngressBackend{ServiceName: "default"},
+			Rules: []extensions.IngressRule{
+				{},
+				{
+					IngressRuleValue: extensions.IngressRuleValue{
+						HTTP: &extensions.HTTPIngressRuleValue{
+							Paths: []extensions.HTTPIngressPath{{Backend: extensions.IngressBackend{ServiceName: "service"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.True(t, referencesService(ingress, "default"))
+	assert.True(t, referencesService(ingress, "service"))
+	assert.False(t, referencesService(ingress, "other"))
+}
@@ -0,0 +1,260 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/glog"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/class"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/parser"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/policy"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/resolver"
+	admission "k8s.io/api/admission/v1beta1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+const (
+	// ValidatingWebhookPath is the path of the validating admission webhook of ingresses.
+	ValidatingWebhookPath = "/validate-ingress"
+	// MutatingWebhookPath is the path of the mutating admission webhook of ingresses, injecting default annotations.
+	MutatingWebhookPath = "/mutate-ingress"
+
+	// sslPolicyRefreshInterval is the interval at which the SSL policies available in AWS are refreshed.
+	sslPolicyRefreshInterval = time.Hour
+
+	maxAdmissionReviewSize = 3 << 20
+)
+
+// ingressValidator validates the annotations of ingresses at admission.
+type ingressValidator struct {
+	resolver resolver.Resolver
+	cloud    aws.CloudAPI
+	policies []*policy.Policy
+
+	mutex             sync.Mutex
+	sslPolicies       sets.String
+	sslPolicyDeadline time.Time
+}
+
+func newIngressValidator(resolver resolver.Resolver, cloud aws.CloudAPI, policies []*policy.Policy) *ingressValidator {
+	return &ingressValidator{resolver: resolver, cloud: cloud, policies: policies}
+}
+
+// Validate returns an error describing the malformed annotations of ingress, or the policies it violates. Ingresses not reconciled by this controller are valid.
+func (v *ingressValidator) Validate(ctx context.Context, ingress *extensions.Ingress) error {
+	controllerCfg := v.resolver.GetConfig()
+	if !class.IsValidIngress(controllerCfg.IngressClass, ingress) || !controllerCfg.IsWatchedIngress(ingress) {
+		return nil
+	}
+	ingressAnnos := annotations.NewIngressAnnotationExtractor(v.resolver).ExtractIngress(ingress)
+	if ingressAnnos.Error != nil {
+		return ingressAnnos.Error
+	}
+	if ingressAnnos.Listener != nil && ingressAnnos.Listener.SslPolicy != nil {
+		sslPolicy := aws.StringValue(ingressAnnos.Listener.SslPolicy)
+		known, err := v.isKnownSSLPolicy(ctx, sslPolicy)
+		if err != nil {
+			// ingresses are not rejected when AWS is unavailable, the policy is validated again when reconciling.
+			glog.Errorf("failed to validate ssl-policy %v of ingress %v/%v due to %v", sslPolicy, ingress.Namespace, ingress.Name, err)
+			return nil
+		}
+		if !known {
+			return fmt.Errorf("ssl-policy %v is not an SSL policy available in AWS", sslPolicy)
+		}
+	}
+	if violations := policy.Evaluate(v.policies, ingress.Namespace, ingressAnnos); len(violations) != 0 {
+		return fmt.Errorf("violates policies: %v", violations)
+	}
+	return nil
+}
+
+func (v *ingressValidator) isKnownSSLPolicy(ctx context.Context, sslPolicy string) (bool, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	if v.sslPolicies == nil || time.Now().After(v.sslPolicyDeadline) {
+		names, err := v.cloud.GetSSLPolicyNames(ctx)
+		if err != nil {
+			return false, err
+		}
+		v.sslPolicies = sets.NewString(names...)
+		v.sslPolicyDeadline = time.Now().Add(sslPolicyRefreshInterval)
+	}
+	return v.sslPolicies.Has(sslPolicy), nil
+}
+
+// newAdmissionHandler serves AdmissionReviews, whose responses are built by review.
+func newAdmissionHandler(review func(context.Context, *admission.AdmissionRequest) *admission.AdmissionResponse) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxAdmissionReviewSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		admissionReview := admission.AdmissionReview{}
+		if err := json.Unmarshal(body, &admissionReview); err != nil || admissionReview.Request == nil {
+			http.Error(w, fmt.Sprintf("failed to decode AdmissionReview due to %v", err), http.StatusBadRequest)
+			return
+		}
+		admissionReview.Response = review(req.Context(), admissionReview.Request)
+		payload, err := json.Marshal(admissionReview)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(payload)
+	})
+}
+
+// newValidatingWebhookHandler serves AdmissionReviews of ingresses at ValidatingWebhookPath, rejecting ingresses with malformed annotations or violating policies.
+func newValidatingWebhookHandler(validator *ingressValidator) http.Handler {
+	return newAdmissionHandler(func(ctx context.Context, req *admission.AdmissionRequest) *admission.AdmissionResponse {
+		return reviewIngress(ctx, validator, req)
+	})
+}
+
+func reviewIngress(ctx context.Context, validator *ingressValidator, req *admission.AdmissionRequest) *admission.AdmissionResponse {
+	resp := &admission.AdmissionResponse{UID: req.UID, Allowed: true}
+	if req.Kind.Kind != "Ingress" || req.Operation == admission.Delete {
+		return resp
+	}
+	ingress := &extensions.Ingress{}
+	if err := json.Unmarshal(req.Object.Raw, ingress); err != nil {
+		resp.Allowed = false
+		resp.Result = &metav1.Status{Message: fmt.Sprintf("failed to decode ingress due to %v", err)}
+		return resp
+	}
+	if ingress.Namespace == "" {
+		ingress.Namespace = req.Namespace
+	}
+	// updates by the controller itself, i.e. removing its finalizer and setting its status annotations, must be admitted even if the ingress
+	// became invalid since it was admitted, otherwise its deletion would hang and its status couldn't be updated.
+	if ingress.DeletionTimestamp != nil {
+		return resp
+	}
+	if req.Operation == admission.Update && len(req.OldObject.Raw) != 0 {
+		oldIngress := &extensions.Ingress{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldIngress); err == nil && !isUserChangedIngress(oldIngress, ingress) {
+			return resp
+		}
+	}
+	if err := validator.Validate(ctx, ingress); err != nil {
+		resp.Allowed = false
+		resp.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: fmt.Sprintf("ingress %v/%v is rejected: %v", ingress.Namespace, ingress.Name, err),
+			Code:    http.StatusUnprocessableEntity,
+		}
+	}
+	return resp
+}
+
+// controllerOwnedAnnotations are the annotations set on ingresses by the controller rather than their users.
+var controllerOwnedAnnotations = []string{CanonicalHostedZoneIDAnnotation, DualstackDNSNameAnnotation}
+
+// isUserChangedIngress tests whether ingress was changed from oldIngress by more than its finalizers, status or controller owned annotations.
+func isUserChangedIngress(oldIngress *extensions.Ingress, ingress *extensions.Ingress) bool {
+	return !reflect.DeepEqual(oldIngress.Spec, ingress.Spec) ||
+		!reflect.DeepEqual(oldIngress.Labels, ingress.Labels) ||
+		!reflect.DeepEqual(userAnnotations(oldIngress), userAnnotations(ingress))
+}
+
+// userAnnotations returns the annotations of ingress other than controller owned ones.
+func userAnnotations(ingress *extensions.Ingress) map[string]string {
+	annotations := make(map[string]string)
+	for key, value := range ingress.Annotations {
+		annotations[key] = value
+	}
+	for _, key := range controllerOwnedAnnotations {
+		delete(annotations, key)
+	}
+	return annotations
+}
+
+// jsonPatchOperation is an operation of an JSONPatch, as defined in RFC 6902.
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// loadAnnotationDefaults loads the default annotations from an YAML file mapping annotations without prefix to values.
+func loadAnnotationDefaults(file string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotation defaults due to %v", err)
+	}
+	defaults := make(map[string]string)
+	if err := yaml.Unmarshal(content, &defaults); err != nil {
+		return nil, fmt.Errorf("failed to parse annotation defaults in %v due to %v", file, err)
+	}
+	return defaults, nil
+}
+
+// newMutatingWebhookHandler serves AdmissionReviews of ingresses at MutatingWebhookPath, injecting defaults into ingresses of ingressClass without them.
+func newMutatingWebhookHandler(ingressClass string, defaults map[string]string) http.Handler {
+	return newAdmissionHandler(func(ctx context.Context, req *admission.AdmissionRequest) *admission.AdmissionResponse {
+		return mutateIngress(ingressClass, defaults, req)
+	})
+}
+
+func mutateIngress(ingressClass string, defaults map[string]string, req *admission.AdmissionRequest) *admission.AdmissionResponse {
+	resp := &admission.AdmissionResponse{UID: req.UID, Allowed: true}
+	if req.Kind.Kind != "Ingress" || req.Operation == admission.Delete {
+		return resp
+	}
+	ingress := &extensions.Ingress{}
+	if err := json.Unmarshal(req.Object.Raw, ingress); err != nil {
+		resp.Allowed = false
+		resp.Result = &metav1.Status{Message: fmt.Sprintf("failed to decode ingress due to %v", err)}
+		return resp
+	}
+	if !class.IsValidIngress(ingressClass, ingress) {
+		return resp
+	}
+	patch := buildAnnotationDefaultsPatch(ingress, defaults)
+	if len(patch) == 0 {
+		return resp
+	}
+	payload, err := json.Marshal(patch)
+	if err != nil {
+		resp.Allowed = false
+		resp.Result = &metav1.Status{Message: fmt.Sprintf("failed to encode patch due to %v", err)}
+		return resp
+	}
+	patchType := admission.PatchTypeJSONPatch
+	resp.Patch = payload
+	resp.PatchType = &patchType
+	return resp
+}
+
+// buildAnnotationDefaultsPatch builds the JSONPatch adding the defaults missing in the annotations of ingress, in order of annotations.
+func buildAnnotationDefaultsPatch(ingress *extensions.Ingress, defaults map[string]string) []jsonPatchOperation {
+	var patch []jsonPatchOperation
+	if ingress.Annotations == nil && len(defaults) != 0 {
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/metadata/annotations", Value: map[string]string{}})
+	}
+	for _, key := range sets.StringKeySet(defaults).List() {
+		annotation := parser.GetAnnotationWithPrefix(key)
+		if _, ok := ingress.Annotations[annotation]; ok {
+			continue
+		}
+		// "~" and "/" in annotations are escaped as JSON pointer.
+		escaped := strings.Replace(strings.Replace(annotation, "~", "~0", -1), "/", "~1", -1)
+		patch = append(patch, jsonPatchOperation{Op: "add", Path: "/metadata/annotations/" + escaped, Value: defaults[key]})
+	}
+	return patch
+}
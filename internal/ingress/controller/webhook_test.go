@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/store"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/mocks"
+	"github.com/stretchr/testify/assert"
+	admission "k8s.io/api/admission/v1beta1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestReviewIngress(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		annotations     map[string]string
+		expectedAllowed bool
+	}{
+		{
+			name:            "valid annotations",
+			annotations:     map[string]string{"alb.ingress.kubernetes.io/listen-ports": `[{"HTTP": 80}]`},
+			expectedAllowed: true,
+		},
+		{
+			name:            "invalid port",
+			annotations:     map[string]string{"alb.ingress.kubernetes.io/listen-ports": `[{"HTTP": 70000}]`},
+			expectedAllowed: false,
+		},
+		{
+			name:            "bad JSON action",
+			annotations:     map[string]string{"alb.ingress.kubernetes.io/actions.redirect": `{"Type": "redirect"`},
+			expectedAllowed: false,
+		},
+		{
+			name: "known ssl policy",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/certificate-arn": "certificate-arn",
+				"alb.ingress.kubernetes.io/ssl-policy":      "ELBSecurityPolicy-2016-08",
+			},
+			expectedAllowed: true,
+		},
+		{
+			name: "unknown ssl policy",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/certificate-arn": "certificate-arn",
+				"alb.ingress.kubernetes.io/ssl-policy":      "ELBSecurityPolicy-Unknown",
+			},
+			expectedAllowed: false,
+		},
+		{
+			name: "other ingress class",
+			annotations: map[string]string{
+				"kubernetes.io/ingress.class":            "nginx",
+				"alb.ingress.kubernetes.io/listen-ports": `[{"HTTP": 70000}]`,
+			},
+			expectedAllowed: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := store.NewDummy()
+			s.SetConfig(&config.Configuration{DefaultTargetType: "instance"})
+			cloud := &mocks.CloudAPI{}
+			cloud.On("GetSSLPolicyNames", context.Background()).Return([]string{"ELBSecurityPolicy-2016-08"}, nil)
+			raw, err := json.Marshal(&extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress", Annotations: tc.annotations},
+			})
+			assert.NoError(t, err)
+
+			resp := reviewIngress(context.Background(), newIngressValidator(s, cloud, nil), &admission.AdmissionRequest{
+				UID:       "uid",
+				Kind:      metav1.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"},
+				Namespace: "namespace",
+				Operation: admission.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			assert.Equal(t, "uid", string(resp.UID))
+			assert.Equal(t, tc.expectedAllowed, resp.Allowed)
+			if !tc.expectedAllowed {
+				assert.Contains(t, resp.Result.Message, "ingress namespace/ingress is rejected")
+			}
+		})
+	}
+}
+
+func TestReviewIngress_update(t *testing.T) {
+	invalid := map[string]string{"alb.ingress.kubernetes.io/listen-ports": `[{"HTTP": 70000}]`}
+	now := metav1.Now()
+	for _, tc := range []struct {
+		name            string
+		old             extensions.Ingress
+		new             extensions.Ingress
+		expectedAllowed bool
+	}{
+		{
+			name:            "finalizer removed from invalid ingress being deleted",
+			old:             extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: invalid, Finalizers: []string{"alb.ingress.kubernetes.io/finalizer"}, DeletionTimestamp: &now}},
+			new:             extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: invalid, DeletionTimestamp: &now}},
+			expectedAllowed: true,
+		},
+		{
+			name: "status annotations set on invalid ingress",
+			old:  extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: invalid}},
+			new: extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				"alb.ingress.kubernetes.io/listen-ports": `[{"HTTP": 70000}]`,
+				CanonicalHostedZoneIDAnnotation:          "Z1H1FL5HABSF5",
+				DualstackDNSNameAnnotation:               "dualstack.lb.elb.amazonaws.com",
+			}}},
+			expectedAllowed: true,
+		},
+		{
+			name:            "finalizer added to invalid ingress",
+			old:             extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: invalid}},
+			new:             extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: invalid, Finalizers: []string{"alb.ingress.kubernetes.io/finalizer"}}},
+			expectedAllowed: true,
+		},
+		{
+			name:            "annotations changed to invalid ones",
+			old:             extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"alb.ingress.kubernetes.io/listen-ports": `[{"HTTP": 80}]`}}},
+			new:             extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: invalid}},
+			expectedAllowed: false,
+		},
+		{
+			name:            "spec of invalid ingress changed",
+			old:             extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: invalid}},
+			new:             extensions.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: invalid}, Spec: extensions.IngressSpec{Rules: []extensions.IngressRule{{Host: "example.com"}}}},
+			expectedAllowed: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := store.NewDummy()
+			s.SetConfig(&config.Configuration{DefaultTargetType: "instance"})
+			tc.old.Namespace, tc.old.Name = "namespace", "ingress"
+			tc.new.Namespace, tc.new.Name = "namespace", "ingress"
+			oldRaw, err := json.Marshal(&tc.old)
+			assert.NoError(t, err)
+			raw, err := json.Marshal(&tc.new)
+			assert.NoError(t, err)
+
+			resp := reviewIngress(context.Background(), newIngressValidator(s, &mocks.CloudAPI{}, nil), &admission.AdmissionRequest{
+				UID:       "uid",
+				Kind:      metav1.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"},
+				Namespace: "namespace",
+				Operation: admission.Update,
+				Object:    runtime.RawExtension{Raw: raw},
+				OldObject: runtime.RawExtension{Raw: oldRaw},
+			})
+			assert.Equal(t, tc.expectedAllowed, resp.Allowed)
+		})
+	}
+}
+
+func TestMutateIngress(t *testing.T) {
+	defaults := map[string]string{
+		"scheme":      "internal",
+		"target-type": "ip",
+	}
+	for _, tc := range []struct {
+		name          string
+		annotations   map[string]string
+		expectedPatch string
+	}{
+		{
+			name:          "without annotations",
+			expectedPatch: `[{"op":"add","path":"/metadata/annotations","value":{}},{"op":"add","path":"/metadata/annotations/alb.ingress.kubernetes.io~1scheme","value":"internal"},{"op":"add","path":"/metadata/annotations/alb.ingress.kubernetes.io~1target-type","value":"ip"}]`,
+		},
+		{
+			name:          "with some annotations",
+			annotations:   map[string]string{"alb.ingress.kubernetes.io/scheme": "internet-facing"},
+			expectedPatch: `[{"op":"add","path":"/metadata/annotations/alb.ingress.kubernetes.io~1target-type","value":"ip"}]`,
+		},
+		{
+			name: "with all annotations",
+			annotations: map[string]string{
+				"alb.ingress.kubernetes.io/scheme":      "internet-facing",
+				"alb.ingress.kubernetes.io/target-type": "instance",
+			},
+		},
+		{
+			name:        "other ingress class",
+			annotations: map[string]string{"kubernetes.io/ingress.class": "nginx"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := json.Marshal(&extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress", Annotations: tc.annotations},
+			})
+			assert.NoError(t, err)
+
+			resp := mutateIngress("", defaults, &admission.AdmissionRequest{
+				UID:       "uid",
+				Kind:      metav1.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"},
+				Operation: admission.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			})
+			assert.True(t, resp.Allowed)
+			if tc.expectedPatch == "" {
+				assert.Nil(t, resp.Patch)
+				return
+			}
+			assert.JSONEq(t, tc.expectedPatch, string(resp.Patch))
+			assert.Equal(t, admission.PatchTypeJSONPatch, *resp.PatchType)
+		})
+	}
+}
@@ -24,9 +24,11 @@ import (
 type AWSAPIController struct {
 	prometheus.Collector
 
-	awsAPIRequest *prometheus.CounterVec
-	awsAPIError   *prometheus.CounterVec
-	awsAPIRetry   *prometheus.CounterVec
+	awsAPIRequest         *prometheus.CounterVec
+	awsAPIError           *prometheus.CounterVec
+	awsAPIRetry           *prometheus.CounterVec
+	awsAPIRequestDuration *prometheus.HistogramVec
+	awsAPIRateLimit       *prometheus.GaugeVec
 }
 
 // NewAWSAPIController creates a new prometheus collector for the
@@ -45,9 +47,9 @@ func NewAWSAPIController() *AWSAPIController {
 			prometheus.CounterOpts{
 				Namespace: PrometheusNamespace,
 				Name:      "aws_api_errors",
-				Help:      `Cumulative number of errors from the AWS API`,
+				Help:      `Cumulative number of errors from the AWS API, by error code such as Throttling or AccessDenied`,
 			},
-			[]string{"service", "operation"},
+			[]string{"service", "operation", "error_code"},
 		),
 		awsAPIRetry: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
@@ -57,6 +59,23 @@ func NewAWSAPIController() *AWSAPIController {
 			},
 			[]string{"service", "operation"},
 		),
+		awsAPIRequestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: PrometheusNamespace,
+				Name:      "aws_api_request_duration_seconds",
+				Help:      `Latency of requests made to the AWS API, including retries`,
+				Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+			},
+			[]string{"service", "operation"},
+		),
+		awsAPIRateLimit: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Name:      "aws_api_rate_limit",
+				Help:      `Requests per second the controller limits requests to the AWS API to after being throttled`,
+			},
+			[]string{"service", "operation"},
+		),
 	}
 }
 
@@ -75,11 +94,27 @@ func (a *AWSAPIController) IncAPIRetryCount(l prometheus.Labels) {
 	a.awsAPIRetry.With(l).Inc()
 }
 
+// ObserveAPIRequestDuration records the latency of an request to the AWS API in seconds
+func (a *AWSAPIController) ObserveAPIRequestDuration(l prometheus.Labels, seconds float64) {
+	a.awsAPIRequestDuration.With(l).Observe(seconds)
+}
+
+// SetAPIRateLimit sets the requests per second requests to an AWS API are limited to, it's removed if 0 as the API is no longer limited
+func (a *AWSAPIController) SetAPIRateLimit(l prometheus.Labels, limit float64) {
+	if limit == 0 {
+		a.awsAPIRateLimit.Delete(l)
+		return
+	}
+	a.awsAPIRateLimit.With(l).Set(limit)
+}
+
 // Describe implements prometheus.Collector
 func (a AWSAPIController) Describe(ch chan<- *prometheus.Desc) {
 	a.awsAPIRequest.Describe(ch)
 	a.awsAPIError.Describe(ch)
 	a.awsAPIRetry.Describe(ch)
+	a.awsAPIRequestDuration.Describe(ch)
+	a.awsAPIRateLimit.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface.
@@ -87,4 +122,6 @@ func (a AWSAPIController) Collect(ch chan<- prometheus.Metric) {
 	a.awsAPIRequest.Collect(ch)
 	a.awsAPIError.Collect(ch)
 	a.awsAPIRetry.Collect(ch)
+	a.awsAPIRequestDuration.Collect(ch)
+	a.awsAPIRateLimit.Collect(ch)
 }
@@ -0,0 +1,97 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestAWSAPIControllerErrors(t *testing.T) {
+	ac := NewAWSAPIController()
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(ac); err != nil {
+		t.Errorf("registering collector failed: %s", err)
+	}
+
+	ac.IncAPIErrorCount(prometheus.Labels{"service": "elasticloadbalancing", "operation": "DescribeTags", "error_code": "Throttling"})
+	ac.IncAPIErrorCount(prometheus.Labels{"service": "elasticloadbalancing", "operation": "DescribeTags", "error_code": "Throttling"})
+	ac.IncAPIErrorCount(prometheus.Labels{"service": "ec2", "operation": "CreateSecurityGroup", "error_code": "UnauthorizedOperation"})
+
+	want := `
+		# HELP aws_alb_ingress_controller_aws_api_errors Cumulative number of errors from the AWS API, by error code such as Throttling or AccessDenied
+		# TYPE aws_alb_ingress_controller_aws_api_errors counter
+		aws_alb_ingress_controller_aws_api_errors{error_code="Throttling",operation="DescribeTags",service="elasticloadbalancing"} 2
+		aws_alb_ingress_controller_aws_api_errors{error_code="UnauthorizedOperation",operation="CreateSecurityGroup",service="ec2"} 1
+	`
+	if err := GatherAndCompare(ac, want, []string{"aws_alb_ingress_controller_aws_api_errors"}, reg); err != nil {
+		t.Errorf("unexpected error collecting result:\n%s", err)
+	}
+}
+
+func TestAWSAPIControllerRequestDuration(t *testing.T) {
+	ac := NewAWSAPIController()
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(ac); err != nil {
+		t.Errorf("registering collector failed: %s", err)
+	}
+
+	ac.ObserveAPIRequestDuration(prometheus.Labels{"service": "elasticloadbalancing", "operation": "DescribeTags"}, 0.2)
+	ac.ObserveAPIRequestDuration(prometheus.Labels{"service": "elasticloadbalancing", "operation": "DescribeTags"}, 3)
+
+	want := `
+		# HELP aws_alb_ingress_controller_aws_api_request_duration_seconds Latency of requests made to the AWS API, including retries
+		# TYPE aws_alb_ingress_controller_aws_api_request_duration_seconds histogram
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="0.05"} 0
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="0.1"} 0
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="0.25"} 1
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="0.5"} 1
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="1"} 1
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="2.5"} 1
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="5"} 2
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="10"} 2
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="30"} 2
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_bucket{operation="DescribeTags",service="elasticloadbalancing",le="+Inf"} 2
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_sum{operation="DescribeTags",service="elasticloadbalancing"} 3.2
+		aws_alb_ingress_controller_aws_api_request_duration_seconds_count{operation="DescribeTags",service="elasticloadbalancing"} 2
+	`
+	if err := GatherAndCompare(ac, want, []string{"aws_alb_ingress_controller_aws_api_request_duration_seconds"}, reg); err != nil {
+		t.Errorf("unexpected error collecting result:\n%s", err)
+	}
+}
+
+func TestAWSAPIControllerRateLimit(t *testing.T) {
+	ac := NewAWSAPIController()
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(ac); err != nil {
+		t.Errorf("registering collector failed: %s", err)
+	}
+
+	ac.SetAPIRateLimit(prometheus.Labels{"service": "elasticloadbalancing", "operation": "DescribeTags"}, 5)
+	ac.SetAPIRateLimit(prometheus.Labels{"service": "ec2", "operation": "DescribeSecurityGroups"}, 10)
+	ac.SetAPIRateLimit(prometheus.Labels{"service": "ec2", "operation": "DescribeSecurityGroups"}, 0)
+
+	want := `
+		# HELP aws_alb_ingress_controller_aws_api_rate_limit Requests per second the controller limits requests to the AWS API to after being throttled
+		# TYPE aws_alb_ingress_controller_aws_api_rate_limit gauge
+		aws_alb_ingress_controller_aws_api_rate_limit{operation="DescribeTags",service="elasticloadbalancing"} 5
+	`
+	if err := GatherAndCompare(ac, want, []string{"aws_alb_ingress_controller_aws_api_rate_limit"}, reg); err != nil {
+		t.Errorf("unexpected error collecting result:\n%s", err)
+	}
+}
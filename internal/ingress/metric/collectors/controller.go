@@ -18,10 +18,12 @@ package collectors
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/golang/glog"
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
 )
 
 // Controller defines base metrics about the ingress controller
@@ -30,9 +32,18 @@ type Controller struct {
 
 	reconcileOperation       *prometheus.CounterVec
 	reconcileOperationErrors *prometheus.CounterVec
+	credentialsErrors        *prometheus.CounterVec
+	driftCorrections         *prometheus.CounterVec
+	queueDepth               *prometheus.GaugeVec
 	managedIngresses         *prometheus.GaugeVec
+	quotaUsage               *prometheus.GaugeVec
 
 	labels prometheus.Labels
+
+	// quotasMutex protects quotas, the names of quotas that have usage reported, so they can be removed with ingress.
+	// Usages are reported by concurrent reconciles.
+	quotasMutex sync.RWMutex
+	quotas      sets.String
 }
 
 // NewController creates a new prometheus collector for the
@@ -60,6 +71,30 @@ func NewController(class string) *Controller {
 			},
 			[]string{"class", "ingress"},
 		),
+		credentialsErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: PrometheusNamespace,
+				Name:      "credentials_errors",
+				Help:      `Cumulative number of reconcile operations failed as AWS credentials couldn't be retrieved`,
+			},
+			[]string{"class"},
+		),
+		driftCorrections: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: PrometheusNamespace,
+				Name:      "drift_corrections",
+				Help:      `Cumulative number of ingresses reconciled as their AWS resources were changed out-of-band`,
+			},
+			[]string{"class"},
+		),
+		queueDepth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Name:      "queue_depth",
+				Help:      `Number of items waiting in the work queues of the controller`,
+			},
+			[]string{"queue"},
+		),
 		managedIngresses: prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -68,6 +103,15 @@ func NewController(class string) *Controller {
 			},
 			[]string{"class", "namespace"},
 		),
+		quotaUsage: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Name:      "quota_usage_ratio",
+				Help:      `Ratio of AWS quota used by the resources of ingress`,
+			},
+			[]string{"class", "ingress", "quota"},
+		),
+		quotas: sets.NewString(),
 	}
 
 	return cm
@@ -87,6 +131,64 @@ func (cm *Controller) IncReconcileErrorCount(name string) {
 	cm.reconcileOperationErrors.With(l).Inc()
 }
 
+// IncReconcileCredentialsErrorCount increment the counter of reconciles failed as AWS credentials couldn't be retrieved
+func (cm *Controller) IncReconcileCredentialsErrorCount() {
+	cm.credentialsErrors.With(cm.labels).Inc()
+}
+
+// IncDriftCorrectionCount increment the counter of ingresses reconciled as their AWS resources drifted
+func (cm *Controller) IncDriftCorrectionCount() {
+	cm.driftCorrections.With(cm.labels).Inc()
+}
+
+// WorkqueueMetricsProvider returns the provider of the depth of work queues, other metrics of work queues aren't collected.
+func (cm *Controller) WorkqueueMetricsProvider() workqueue.MetricsProvider {
+	return &workqueueMetricsProvider{depth: cm.queueDepth}
+}
+
+type workqueueMetricsProvider struct {
+	depth *prometheus.GaugeVec
+}
+
+func (p *workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return p.depth.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewAddsMetric(string) workqueue.CounterMetric {
+	return noopMetric{}
+}
+
+func (p *workqueueMetricsProvider) NewLatencyMetric(string) workqueue.SummaryMetric {
+	return noopMetric{}
+}
+
+func (p *workqueueMetricsProvider) NewWorkDurationMetric(string) workqueue.SummaryMetric {
+	return noopMetric{}
+}
+
+func (p *workqueueMetricsProvider) NewRetriesMetric(string) workqueue.CounterMetric {
+	return noopMetric{}
+}
+
+type noopMetric struct{}
+
+func (noopMetric) Inc()            {}
+func (noopMetric) Dec()            {}
+func (noopMetric) Observe(float64) {}
+
+// SetQuotaUsage sets the ratio of quota used by ingress
+func (cm *Controller) SetQuotaUsage(name string, quota string, ratio float64) {
+	l := prometheus.Labels{
+		"class":   cm.labels["class"],
+		"ingress": name,
+		"quota":   quota,
+	}
+	cm.quotasMutex.Lock()
+	cm.quotas.Insert(quota)
+	cm.quotasMutex.Unlock()
+	cm.quotaUsage.With(l).Set(ratio)
+}
+
 // SetManagedIngresses sets the number of managed ingresses
 func (cm *Controller) SetManagedIngresses(nsmap map[string]int, registry prometheus.Gatherer) {
 	l := prometheus.Labels{
@@ -134,17 +236,25 @@ func (cm *Controller) SetManagedIngresses(nsmap map[string]int, registry prometh
 }
 
 // Describe implements prometheus.Collector
-func (cm Controller) Describe(ch chan<- *prometheus.Desc) {
+func (cm *Controller) Describe(ch chan<- *prometheus.Desc) {
 	cm.reconcileOperation.Describe(ch)
 	cm.reconcileOperationErrors.Describe(ch)
+	cm.credentialsErrors.Describe(ch)
+	cm.driftCorrections.Describe(ch)
+	cm.queueDepth.Describe(ch)
 	cm.managedIngresses.Describe(ch)
+	cm.quotaUsage.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface.
-func (cm Controller) Collect(ch chan<- prometheus.Metric) {
+func (cm *Controller) Collect(ch chan<- prometheus.Metric) {
 	cm.reconcileOperation.Collect(ch)
 	cm.reconcileOperationErrors.Collect(ch)
+	cm.credentialsErrors.Collect(ch)
+	cm.driftCorrections.Collect(ch)
+	cm.queueDepth.Collect(ch)
 	cm.managedIngresses.Collect(ch)
+	cm.quotaUsage.Collect(ch)
 }
 
 // RemoveMetrics removes metrics for ingresses that have been removed
@@ -154,4 +264,13 @@ func (cm *Controller) RemoveMetrics(name string) {
 	}
 	l["ingress"] = name
 	cm.reconcileOperationErrors.Delete(l)
+	cm.quotasMutex.RLock()
+	defer cm.quotasMutex.RUnlock()
+	for quota := range cm.quotas {
+		cm.quotaUsage.Delete(prometheus.Labels{
+			"class":   cm.labels["class"],
+			"ingress": name,
+			"quota":   quota,
+		})
+	}
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package collectors
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -64,6 +66,45 @@ func TestControllerCounters(t *testing.T) {
 			`,
 			metrics: []string{"aws_alb_ingress_controller_errors"},
 		},
+		{
+			name: "single increase in credentials error count should return 1",
+			test: func(cm *Controller) {
+				cm.IncReconcileCredentialsErrorCount()
+			},
+			want: `
+				# HELP aws_alb_ingress_controller_credentials_errors Cumulative number of reconcile operations failed as AWS credentials couldn't be retrieved
+				# TYPE aws_alb_ingress_controller_credentials_errors counter
+				aws_alb_ingress_controller_credentials_errors{class="alb"} 1
+			`,
+			metrics: []string{"aws_alb_ingress_controller_credentials_errors"},
+		},
+		{
+			name: "single increase in drift correction count should return 1",
+			test: func(cm *Controller) {
+				cm.IncDriftCorrectionCount()
+			},
+			want: `
+				# HELP aws_alb_ingress_controller_drift_corrections Cumulative number of ingresses reconciled as their AWS resources were changed out-of-band
+				# TYPE aws_alb_ingress_controller_drift_corrections counter
+				aws_alb_ingress_controller_drift_corrections{class="alb"} 1
+			`,
+			metrics: []string{"aws_alb_ingress_controller_drift_corrections"},
+		},
+		{
+			name: "depth of work queues should be reported by queue",
+			test: func(cm *Controller) {
+				depth := cm.WorkqueueMetricsProvider().NewDepthMetric("alb-ingress-controller")
+				depth.Inc()
+				depth.Inc()
+				depth.Dec()
+			},
+			want: `
+				# HELP aws_alb_ingress_controller_queue_depth Number of items waiting in the work queues of the controller
+				# TYPE aws_alb_ingress_controller_queue_depth gauge
+				aws_alb_ingress_controller_queue_depth{queue="alb-ingress-controller"} 1
+			`,
+			metrics: []string{"aws_alb_ingress_controller_queue_depth"},
+		},
 	}
 
 	for _, c := range cases {
@@ -84,3 +125,21 @@ func TestControllerCounters(t *testing.T) {
 		})
 	}
 }
+
+func TestControllerQuotaUsageConcurrently(t *testing.T) {
+	cm := NewController("alb")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		ingress, quota := fmt.Sprintf("default/ingress-%d", i), fmt.Sprintf("quota-%d", i)
+		go func() {
+			defer wg.Done()
+			cm.SetQuotaUsage(ingress, quota, 0.5)
+		}()
+		go func() {
+			defer wg.Done()
+			cm.RemoveMetrics(ingress)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LoadBalancerController defines metrics of LoadBalancers pulled from CloudWatch
+type LoadBalancerController struct {
+	prometheus.Collector
+
+	// gauges are keyed by CloudWatch metric name
+	gauges map[string]*prometheus.GaugeVec
+
+	labels prometheus.Labels
+}
+
+// NewLoadBalancerController creates a new prometheus collector for the
+// CloudWatch metrics of LoadBalancers
+func NewLoadBalancerController(class string) *LoadBalancerController {
+	newGauge := func(name string, help string) *prometheus.GaugeVec {
+		return prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Name:      name,
+				Help:      help,
+			},
+			[]string{"class", "ingress"},
+		)
+	}
+	return &LoadBalancerController{
+		labels: prometheus.Labels{
+			"class": class,
+		},
+		gauges: map[string]*prometheus.GaugeVec{
+			"ConsumedLCUs":              newGauge("load_balancer_consumed_lcus", `Number of load balancer capacity units (LCU) consumed by the ALB of ingress over the latest CloudWatch period`),
+			"RequestCount":              newGauge("load_balancer_requests", `Number of requests processed by the ALB of ingress over the latest CloudWatch period`),
+			"HTTPCode_ELB_5XX_Count":    newGauge("load_balancer_elb_5xx_responses", `Number of HTTP 5XX responses generated by the ALB of ingress over the latest CloudWatch period`),
+			"HTTPCode_Target_5XX_Count": newGauge("load_balancer_target_5xx_responses", `Number of HTTP 5XX responses generated by the targets of ingress over the latest CloudWatch period`),
+		},
+	}
+}
+
+// SetLoadBalancerMetrics replaces the metrics of all LoadBalancers, metrics are keyed by ingress and CloudWatch metric name
+func (lc *LoadBalancerController) SetLoadBalancerMetrics(metrics map[string]map[string]float64) {
+	for _, gauge := range lc.gauges {
+		gauge.Reset()
+	}
+	for ingress, values := range metrics {
+		l := prometheus.Labels{
+			"class":   lc.labels["class"],
+			"ingress": ingress,
+		}
+		for name, value := range values {
+			if gauge, ok := lc.gauges[name]; ok {
+				gauge.With(l).Set(value)
+			}
+		}
+	}
+}
+
+// Describe implements prometheus.Collector
+func (lc LoadBalancerController) Describe(ch chan<- *prometheus.Desc) {
+	for _, gauge := range lc.gauges {
+		gauge.Describe(ch)
+	}
+}
+
+// Collect implements the prometheus.Collector interface.
+func (lc LoadBalancerController) Collect(ch chan<- prometheus.Metric) {
+	for _, gauge := range lc.gauges {
+		gauge.Collect(ch)
+	}
+}
@@ -29,9 +29,21 @@ func (dc DummyCollector) IncReconcileCount() {}
 // IncReloadErrorCount ...
 func (dc DummyCollector) IncReconcileErrorCount(string) {}
 
+// IncReconcileCredentialsErrorCount ...
+func (dc DummyCollector) IncReconcileCredentialsErrorCount() {}
+
+// IncDriftCorrectionCount ...
+func (dc DummyCollector) IncDriftCorrectionCount() {}
+
 // SetManagedIngresses ...
 func (dc DummyCollector) SetManagedIngresses(map[string]int) {}
 
+// SetQuotaUsage ...
+func (dc DummyCollector) SetQuotaUsage(string, string, float64) {}
+
+// SetLoadBalancerMetrics ...
+func (dc DummyCollector) SetLoadBalancerMetrics(map[string]map[string]float64) {}
+
 // IncAPIRequestCount ...
 func (dc DummyCollector) IncAPIRequestCount(prometheus.Labels) {}
 
@@ -41,6 +53,15 @@ func (dc DummyCollector) IncAPIErrorCount(prometheus.Labels) {}
 // IncAPIRetryCount ...
 func (dc DummyCollector) IncAPIRetryCount(prometheus.Labels) {}
 
+// ObserveAPIRequestDuration ...
+func (dc DummyCollector) ObserveAPIRequestDuration(prometheus.Labels, float64) {}
+
+// SetAPIRateLimit ...
+func (dc DummyCollector) SetAPIRateLimit(prometheus.Labels, float64) {}
+
+// GetHealthMetrics ...
+func (dc DummyCollector) GetHealthMetrics() (map[string]float64, error) { return nil, nil }
+
 // Start ...
 func (dc DummyCollector) Start() {}
 
@@ -18,6 +18,7 @@ package metric
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
 
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/metric/collectors"
 )
@@ -26,11 +27,20 @@ import (
 type Collector interface {
 	IncReconcileCount()
 	IncReconcileErrorCount(string)
+	IncReconcileCredentialsErrorCount()
+	IncDriftCorrectionCount()
 	SetManagedIngresses(map[string]int)
+	SetQuotaUsage(string, string, float64)
+	SetLoadBalancerMetrics(map[string]map[string]float64)
 
 	IncAPIRequestCount(prometheus.Labels)
 	IncAPIErrorCount(prometheus.Labels)
 	IncAPIRetryCount(prometheus.Labels)
+	ObserveAPIRequestDuration(prometheus.Labels, float64)
+	SetAPIRateLimit(prometheus.Labels, float64)
+
+	// GetHealthMetrics returns the values of HealthMetrics, counters are cumulative.
+	GetHealthMetrics() (map[string]float64, error)
 
 	RemoveMetrics(string)
 
@@ -38,9 +48,28 @@ type Collector interface {
 	Stop()
 }
 
+// Health metrics of the controller, summed from its Prometheus metrics
+const (
+	HealthMetricReconciles        = "Reconciles"
+	HealthMetricReconcileErrors   = "ReconcileErrors"
+	HealthMetricCredentialsErrors = "CredentialsErrors"
+	HealthMetricDriftCorrections  = "DriftCorrections"
+	HealthMetricQueueDepth        = "QueueDepth"
+)
+
+// HealthMetrics are the Prometheus metrics health metrics are summed from, by health metric name.
+var HealthMetrics = map[string]string{
+	HealthMetricReconciles:        collectors.PrometheusNamespace + "_success",
+	HealthMetricReconcileErrors:   collectors.PrometheusNamespace + "_errors",
+	HealthMetricCredentialsErrors: collectors.PrometheusNamespace + "_credentials_errors",
+	HealthMetricDriftCorrections:  collectors.PrometheusNamespace + "_drift_corrections",
+	HealthMetricQueueDepth:        collectors.PrometheusNamespace + "_queue_depth",
+}
+
 type collector struct {
 	ingressController *collectors.Controller
 	awsAPIController  *collectors.AWSAPIController
+	lbController      *collectors.LoadBalancerController
 
 	registry *prometheus.Registry
 }
@@ -49,10 +78,14 @@ type collector struct {
 func NewCollector(registry *prometheus.Registry, ingressClass string) (Collector, error) {
 	ic := collectors.NewController(ingressClass)
 	ac := collectors.NewAWSAPIController()
+	lc := collectors.NewLoadBalancerController(ingressClass)
+	// work queues of controllers created from now on report their depth.
+	workqueue.SetProvider(ic.WorkqueueMetricsProvider())
 
 	return Collector(&collector{
 		ingressController: ic,
 		awsAPIController:  ac,
+		lbController:      lc,
 		registry:          registry,
 	}), nil
 }
@@ -65,10 +98,26 @@ func (c *collector) IncReconcileErrorCount(s string) {
 	c.ingressController.IncReconcileErrorCount(s)
 }
 
+func (c *collector) IncReconcileCredentialsErrorCount() {
+	c.ingressController.IncReconcileCredentialsErrorCount()
+}
+
+func (c *collector) IncDriftCorrectionCount() {
+	c.ingressController.IncDriftCorrectionCount()
+}
+
 func (c *collector) SetManagedIngresses(i map[string]int) {
 	c.ingressController.SetManagedIngresses(i, c.registry)
 }
 
+func (c *collector) SetQuotaUsage(ingressName string, quota string, ratio float64) {
+	c.ingressController.SetQuotaUsage(ingressName, quota, ratio)
+}
+
+func (c *collector) SetLoadBalancerMetrics(metrics map[string]map[string]float64) {
+	c.lbController.SetLoadBalancerMetrics(metrics)
+}
+
 func (c *collector) IncAPIRequestCount(l prometheus.Labels) {
 	c.awsAPIController.IncAPIRequestCount(l)
 }
@@ -81,6 +130,32 @@ func (c *collector) IncAPIRetryCount(l prometheus.Labels) {
 	c.awsAPIController.IncAPIRetryCount(l)
 }
 
+func (c *collector) ObserveAPIRequestDuration(l prometheus.Labels, seconds float64) {
+	c.awsAPIController.ObserveAPIRequestDuration(l, seconds)
+}
+
+func (c *collector) SetAPIRateLimit(l prometheus.Labels, limit float64) {
+	c.awsAPIController.SetAPIRateLimit(l, limit)
+}
+
+func (c *collector) GetHealthMetrics() (map[string]float64, error) {
+	mfs, err := c.registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+	sums := make(map[string]float64, len(mfs))
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			sums[mf.GetName()] += m.GetCounter().GetValue() + m.GetGauge().GetValue()
+		}
+	}
+	values := make(map[string]float64, len(HealthMetrics))
+	for name, metricName := range HealthMetrics {
+		values[name] = sums[metricName]
+	}
+	return values, nil
+}
+
 func (c *collector) RemoveMetrics(ingressName string) {
 	c.ingressController.RemoveMetrics(ingressName)
 }
@@ -88,9 +163,11 @@ func (c *collector) RemoveMetrics(ingressName string) {
 func (c *collector) Start() {
 	c.registry.MustRegister(c.ingressController)
 	c.registry.MustRegister(c.awsAPIController)
+	c.registry.MustRegister(c.lbController)
 }
 
 func (c *collector) Stop() {
 	c.registry.Unregister(c.ingressController)
 	c.registry.Unregister(c.awsAPIController)
+	c.registry.Unregister(c.lbController)
 }
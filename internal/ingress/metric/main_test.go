@@ -1 +1,30 @@
 package metric
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectorGetHealthMetrics(t *testing.T) {
+	c, err := NewCollector(prometheus.NewRegistry(), "alb")
+	assert.NoError(t, err)
+	c.Start()
+	defer c.Stop()
+
+	c.IncReconcileCount()
+	c.IncReconcileErrorCount("namespace/ingress")
+	c.IncReconcileErrorCount("namespace/other")
+	c.IncReconcileCredentialsErrorCount()
+	c.IncDriftCorrectionCount()
+	values, err := c.GetHealthMetrics()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{
+		HealthMetricReconciles:        1,
+		HealthMetricReconcileErrors:   2,
+		HealthMetricCredentialsErrors: 1,
+		HealthMetricDriftCorrections:  1,
+		HealthMetricQueueDepth:        0,
+	}, values)
+}
@@ -0,0 +1,128 @@
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+
+	"github.com/ghodss/yaml"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// Policy constrains the annotations of ingresses in Namespaces, empty constraints allow any value.
+type Policy struct {
+	Name string `json:"name"`
+	// Namespaces are the namespaces policy applies to, it applies to all namespaces if empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Schemes are the allowed schemes of LoadBalancers, e.g. internal.
+	Schemes []string `json:"schemes,omitempty"`
+	// InboundCIDRs are the CIDRs that inbound CIDRs of LoadBalancers must be within, e.g. 10.0.0.0/8.
+	InboundCIDRs []string `json:"inboundCIDRs,omitempty"`
+	// TargetTypes are the allowed target types of targetGroups, e.g. ip.
+	TargetTypes []string `json:"targetTypes,omitempty"`
+	// SSLPolicies are the allowed SSL policies of HTTPS listeners.
+	SSLPolicies []string `json:"sslPolicies,omitempty"`
+	// RoleARNs are the allowed roles assumed for ingresses by the role-arn annotation, ingresses without it are allowed.
+	RoleARNs []string `json:"roleARNs,omitempty"`
+
+	inboundNets []*net.IPNet
+}
+
+// Violation is an violation of policy by an ingress.
+type Violation struct {
+	Policy  string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("policy %v: %v", v.Policy, v.Message)
+}
+
+// Load loads the policies in an YAML file with an list of policies.
+func Load(file string) ([]*Policy, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies due to %v", err)
+	}
+	var policies []*Policy
+	if err := yaml.Unmarshal(content, &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse policies in %v due to %v", file, err)
+	}
+	for _, policy := range policies {
+		if policy.Name == "" {
+			return nil, fmt.Errorf("policies in %v must have names", file)
+		}
+		for _, cidr := range policy.InboundCIDRs {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("policy %v has invalid inboundCIDR %v due to %v", policy.Name, cidr, err)
+			}
+			policy.inboundNets = append(policy.inboundNets, ipNet)
+		}
+	}
+	return policies, nil
+}
+
+// Evaluate returns the violations of policies by the annotations of an ingress in namespace.
+func Evaluate(policies []*Policy, namespace string, ingressAnnos *annotations.Ingress) []Violation {
+	var violations []Violation
+	for _, policy := range policies {
+		if len(policy.Namespaces) != 0 && !sets.NewString(policy.Namespaces...).Has(namespace) {
+			continue
+		}
+		for _, message := range policy.evaluate(ingressAnnos) {
+			violations = append(violations, Violation{Policy: policy.Name, Message: message})
+		}
+	}
+	return violations
+}
+
+func (p *Policy) evaluate(ingressAnnos *annotations.Ingress) []string {
+	var messages []string
+	if lbConfig := ingressAnnos.LoadBalancer; lbConfig != nil {
+		if len(p.Schemes) != 0 && !sets.NewString(p.Schemes...).Has(aws.StringValue(lbConfig.Scheme)) {
+			messages = append(messages, fmt.Sprintf("scheme %v is not one of %v", aws.StringValue(lbConfig.Scheme), p.Schemes))
+		}
+		// inbound CIDRs are not used with securityGroups specified by user.
+		if len(p.inboundNets) != 0 && len(lbConfig.SecurityGroups) == 0 {
+			for _, cidr := range lbConfig.InboundCidrs {
+				if !p.containsCIDR(cidr) {
+					messages = append(messages, fmt.Sprintf("inbound CIDR %v is not within %v", cidr, p.InboundCIDRs))
+				}
+			}
+		}
+		if len(p.RoleARNs) != 0 && lbConfig.RoleARN != nil && !sets.NewString(p.RoleARNs...).Has(aws.StringValue(lbConfig.RoleARN)) {
+			messages = append(messages, fmt.Sprintf("role %v is not one of %v", aws.StringValue(lbConfig.RoleARN), p.RoleARNs))
+		}
+	}
+	if tgConfig := ingressAnnos.TargetGroup; tgConfig != nil && tgConfig.TargetType != nil {
+		if len(p.TargetTypes) != 0 && !sets.NewString(p.TargetTypes...).Has(aws.StringValue(tgConfig.TargetType)) {
+			messages = append(messages, fmt.Sprintf("target type %v is not one of %v", aws.StringValue(tgConfig.TargetType), p.TargetTypes))
+		}
+	}
+	if lsConfig := ingressAnnos.Listener; lsConfig != nil && lsConfig.SslPolicy != nil {
+		if len(p.SSLPolicies) != 0 && !sets.NewString(p.SSLPolicies...).Has(aws.StringValue(lsConfig.SslPolicy)) {
+			messages = append(messages, fmt.Sprintf("ssl policy %v is not one of %v", aws.StringValue(lsConfig.SslPolicy), p.SSLPolicies))
+		}
+	}
+	return messages
+}
+
+// containsCIDR tests whether cidr is within any of the inbound CIDRs of policy.
+func (p *Policy) containsCIDR(cidr string) bool {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	ones, _ := ipNet.Mask.Size()
+	for _, allowed := range p.inboundNets {
+		allowedOnes, _ := allowed.Mask.Size()
+		if allowed.Contains(ipNet.IP) && allowedOnes <= ones {
+			return true
+		}
+	}
+	return false
+}
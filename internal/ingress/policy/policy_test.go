@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/loadbalancer"
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/annotations/targetgroup"
+	"github.com/stretchr/testify/assert"
+)
+
+const policiesYAML = `
+- name: internal-only
+  namespaces: [team-a]
+  schemes: [internal]
+- name: private-cidrs
+  inboundCIDRs: [10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16]
+- name: ip-targets
+  targetTypes: [ip]
+- name: team-a-account
+  namespaces: [team-a]
+  roleARNs: [arn:aws:iam::111111111111:role/alb]
+`
+
+func loadPolicies(t *testing.T, content string) ([]*Policy, error) {
+	f, err := ioutil.TempFile("", "policies")
+	assert.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(content)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	return Load(f.Name())
+}
+
+func TestLoad(t *testing.T) {
+	_, err := loadPolicies(t, "- name: invalid\n  inboundCIDRs: [10.0.0.0]\n")
+	assert.Error(t, err)
+	_, err = loadPolicies(t, "- schemes: [internal]\n")
+	assert.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	policies, err := loadPolicies(t, policiesYAML)
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		name               string
+		namespace          string
+		scheme             string
+		inboundCIDRs       []string
+		securityGroups     []string
+		targetType         string
+		roleARN            *string
+		expectedViolations []Violation
+	}{
+		{
+			name:         "compliant",
+			namespace:    "team-a",
+			scheme:       "internal",
+			inboundCIDRs: []string{"10.1.0.0/16", "192.168.1.1/32"},
+			targetType:   "ip",
+			roleARN:      aws.String("arn:aws:iam::111111111111:role/alb"),
+		},
+		{
+			name:         "internet-facing in other namespace",
+			namespace:    "team-b",
+			scheme:       "internet-facing",
+			inboundCIDRs: []string{"10.0.0.0/8"},
+			targetType:   "ip",
+		},
+		{
+			name:         "violations",
+			namespace:    "team-a",
+			scheme:       "internet-facing",
+			inboundCIDRs: []string{"0.0.0.0/0", "10.0.0.0/7"},
+			targetType:   "instance",
+			roleARN:      aws.String("arn:aws:iam::222222222222:role/alb"),
+			expectedViolations: []Violation{
+				{Policy: "internal-only", Message: "scheme internet-facing is not one of [internal]"},
+				{Policy: "private-cidrs", Message: "inbound CIDR 0.0.0.0/0 is not within [10.0.0.0/8 172.16.0.0/12 192.168.0.0/16]"},
+				{Policy: "private-cidrs", Message: "inbound CIDR 10.0.0.0/7 is not within [10.0.0.0/8 172.16.0.0/12 192.168.0.0/16]"},
+				{Policy: "ip-targets", Message: "target type instance is not one of [ip]"},
+				{Policy: "team-a-account", Message: "role arn:aws:iam::222222222222:role/alb is not one of [arn:aws:iam::111111111111:role/alb]"},
+			},
+		},
+		{
+			name:           "inbound CIDRs unused with securityGroups",
+			namespace:      "team-b",
+			scheme:         "internal",
+			inboundCIDRs:   []string{"0.0.0.0/0"},
+			securityGroups: []string{"sg-abc"},
+			targetType:     "ip",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ingressAnnos := &annotations.Ingress{
+				LoadBalancer: &loadbalancer.Config{
+					Scheme:         aws.String(tc.scheme),
+					InboundCidrs:   tc.inboundCIDRs,
+					SecurityGroups: tc.securityGroups,
+					RoleARN:        tc.roleARN,
+				},
+				TargetGroup: &targetgroup.Config{TargetType: aws.String(tc.targetType)},
+			}
+			assert.Equal(t, tc.expectedViolations, Evaluate(policies, tc.namespace, ingressAnnos))
+		})
+	}
+}
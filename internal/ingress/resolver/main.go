@@ -18,6 +18,7 @@ package resolver
 
 import (
 	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // Resolver is an interface that knows how to extract information from a controller
@@ -25,4 +26,10 @@ type Resolver interface {
 	// GetConfig returns the controller configuration
 	GetConfig() *config.Configuration
 	GetInstanceIDFromPodIP(string) (string, error)
+	// GetNamespace returns the Namespace matching name
+	GetNamespace(name string) (*corev1.Namespace, error)
+	// GetSecret returns the Secret matching key
+	GetSecret(key string) (*corev1.Secret, error)
+	// GetConfigMap returns the ConfigMap matching key
+	GetConfigMap(key string) (*corev1.ConfigMap, error)
 }
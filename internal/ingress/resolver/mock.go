@@ -16,7 +16,10 @@ limitations under the License.
 
 package resolver
 
-import "github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+import (
+	"github.com/kubernetes-sigs/aws-alb-ingress-controller/internal/ingress/controller/config"
+	corev1 "k8s.io/api/core/v1"
+)
 
 // Mock implements the Resolver interface
 type Mock struct {
@@ -29,3 +32,15 @@ func (m Mock) GetConfig() *config.Configuration {
 func (m Mock) GetInstanceIDFromPodIP(s string) (string, error) {
 	return "", nil
 }
+
+func (m Mock) GetNamespace(name string) (*corev1.Namespace, error) {
+	return &corev1.Namespace{}, nil
+}
+
+func (m Mock) GetSecret(key string) (*corev1.Secret, error) {
+	return &corev1.Secret{}, nil
+}
+
+func (m Mock) GetConfigMap(key string) (*corev1.ConfigMap, error) {
+	return &corev1.ConfigMap{}, nil
+}
@@ -0,0 +1,37 @@
+package k8s
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IngressFinalizer is added to ingresses with AWS resources managed by the controller,
+// so that they're only removed from the cluster once their resources are deleted.
+const IngressFinalizer = "alb.ingress.kubernetes.io/finalizer"
+
+// HasFinalizer tests whether obj has finalizer.
+func HasFinalizer(obj metav1.Object, finalizer string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFinalizer adds finalizer to obj if it doesn't have it.
+func AddFinalizer(obj metav1.Object, finalizer string) {
+	if !HasFinalizer(obj, finalizer) {
+		obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+	}
+}
+
+// RemoveFinalizer removes finalizer from obj.
+func RemoveFinalizer(obj metav1.Object, finalizer string) {
+	var finalizers []string
+	for _, f := range obj.GetFinalizers() {
+		if f != finalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	obj.SetFinalizers(finalizers)
+}
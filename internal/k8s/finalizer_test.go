@@ -0,0 +1,22 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFinalizers(t *testing.T) {
+	obj := &metav1.ObjectMeta{Finalizers: []string{"other"}}
+	assert.False(t, HasFinalizer(obj, IngressFinalizer))
+
+	AddFinalizer(obj, IngressFinalizer)
+	AddFinalizer(obj, IngressFinalizer)
+	assert.True(t, HasFinalizer(obj, IngressFinalizer))
+	assert.Equal(t, []string{"other", IngressFinalizer}, obj.Finalizers)
+
+	RemoveFinalizer(obj, IngressFinalizer)
+	assert.False(t, HasFinalizer(obj, IngressFinalizer))
+	assert.Equal(t, []string{"other"}, obj.Finalizers)
+}
@@ -0,0 +1,225 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// LeaderElectionLockConfigMaps and LeaderElectionLockLeases are the kinds of objects leader election locks are stored in
+	LeaderElectionLockConfigMaps = resourcelock.ConfigMapsResourceLock
+	LeaderElectionLockLeases     = "leases"
+
+	// leaseDuration, renewDeadline and retryPeriod are the timings of leader election, as of the manager of controller-runtime
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+
+	// inClusterNamespacePath is the file holding the namespace of the pod of the controller
+	inClusterNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// coordinationGroupVersion is the API group of Leases, whose types are newer than the Kubernetes client of the controller.
+var coordinationGroupVersion = schema.GroupVersion{Group: "coordination.k8s.io", Version: "v1"}
+
+// lease is an coordination.k8s.io/v1 Lease, only the fields used by leader election are declared.
+type lease struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec leaseSpec `json:"spec"`
+}
+
+type leaseSpec struct {
+	HolderIdentity       *string           `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds *int32            `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          *metav1.MicroTime `json:"acquireTime,omitempty"`
+	RenewTime            *metav1.MicroTime `json:"renewTime,omitempty"`
+	LeaseTransitions     *int32            `json:"leaseTransitions,omitempty"`
+}
+
+var _ resourcelock.Interface = (*LeaseLock)(nil)
+
+// LeaseLock is an leader election lock stored in an Lease, which unlike ConfigMaps aren't watched by other controllers,
+// so renewing it every few seconds doesn't wake them up.
+type LeaseLock struct {
+	client    rest.Interface
+	namespace string
+	name      string
+	identity  string
+
+	// lease is the Lease last read or written, it's updated with its resourceVersion so concurrent updates conflict.
+	lease *lease
+}
+
+// NewLeaseLock creates an lock stored in the Lease name in namespace, held by identity. It's stored in the namespace of
+// the pod of the controller if namespace is empty.
+func NewLeaseLock(restCfg *rest.Config, namespace string, name string, identity string) (*LeaseLock, error) {
+	if namespace == "" {
+		raw, err := ioutil.ReadFile(inClusterNamespacePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find namespace of leader election lease due to %v", err)
+		}
+		namespace = strings.TrimSpace(string(raw))
+	}
+	cfg := rest.CopyConfig(restCfg)
+	cfg.GroupVersion = &coordinationGroupVersion
+	cfg.APIPath = "/apis"
+	cfg.ContentType = runtime.ContentTypeJSON
+	cfg.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: scheme.Codecs}
+	client, err := rest.RESTClientFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &LeaseLock{client: client, namespace: namespace, name: name, identity: identity}, nil
+}
+
+// Get returns the election record of the Lease.
+func (l *LeaseLock) Get() (*resourcelock.LeaderElectionRecord, error) {
+	raw, err := l.client.Get().Namespace(l.namespace).Resource("leases").Name(l.name).Do().Raw()
+	if err != nil {
+		return nil, err
+	}
+	if err := l.decode(raw); err != nil {
+		return nil, err
+	}
+	return leaseToRecord(l.lease.Spec), nil
+}
+
+// Create creates the Lease with election record ler.
+func (l *LeaseLock) Create(ler resourcelock.LeaderElectionRecord) error {
+	body, err := json.Marshal(&lease{
+		TypeMeta:   metav1.TypeMeta{APIVersion: coordinationGroupVersion.String(), Kind: "Lease"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: l.namespace, Name: l.name},
+		Spec:       recordToLease(ler),
+	})
+	if err != nil {
+		return err
+	}
+	raw, err := l.client.Post().Namespace(l.namespace).Resource("leases").Body(body).Do().Raw()
+	if err != nil {
+		return err
+	}
+	return l.decode(raw)
+}
+
+// Update updates the Lease with election record ler, it fails if the Lease was modified since it was last read.
+func (l *LeaseLock) Update(ler resourcelock.LeaderElectionRecord) error {
+	if l.lease == nil {
+		return fmt.Errorf("lease %v/%v not initialized, call Get or Create first", l.namespace, l.name)
+	}
+	updated := *l.lease
+	updated.Spec = recordToLease(ler)
+	body, err := json.Marshal(&updated)
+	if err != nil {
+		return err
+	}
+	raw, err := l.client.Put().Namespace(l.namespace).Resource("leases").Name(l.name).Body(body).Do().Raw()
+	if err != nil {
+		return err
+	}
+	return l.decode(raw)
+}
+
+// RecordEvent logs changes of leadership, Leases can't be referenced by events since their types are unknown to the client.
+func (l *LeaseLock) RecordEvent(event string) {
+	glog.Infof("%v %v", l.identity, event)
+}
+
+// Identity returns the identity of the lock holder.
+func (l *LeaseLock) Identity() string {
+	return l.identity
+}
+
+// Describe describes the lock.
+func (l *LeaseLock) Describe() string {
+	return fmt.Sprintf("%v/%v", l.namespace, l.name)
+}
+
+func (l *LeaseLock) decode(raw []byte) error {
+	var decoded lease
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return fmt.Errorf("failed to decode lease %v/%v due to %v", l.namespace, l.name, err)
+	}
+	l.lease = &decoded
+	return nil
+}
+
+func leaseToRecord(spec leaseSpec) *resourcelock.LeaderElectionRecord {
+	var ler resourcelock.LeaderElectionRecord
+	if spec.HolderIdentity != nil {
+		ler.HolderIdentity = *spec.HolderIdentity
+	}
+	if spec.LeaseDurationSeconds != nil {
+		ler.LeaseDurationSeconds = int(*spec.LeaseDurationSeconds)
+	}
+	if spec.LeaseTransitions != nil {
+		ler.LeaderTransitions = int(*spec.LeaseTransitions)
+	}
+	if spec.AcquireTime != nil {
+		ler.AcquireTime = metav1.NewTime(spec.AcquireTime.Time)
+	}
+	if spec.RenewTime != nil {
+		ler.RenewTime = metav1.NewTime(spec.RenewTime.Time)
+	}
+	return &ler
+}
+
+func recordToLease(ler resourcelock.LeaderElectionRecord) leaseSpec {
+	holderIdentity := ler.HolderIdentity
+	leaseDurationSeconds := int32(ler.LeaseDurationSeconds)
+	leaseTransitions := int32(ler.LeaderTransitions)
+	acquireTime := metav1.NewMicroTime(ler.AcquireTime.Time)
+	renewTime := metav1.NewMicroTime(ler.RenewTime.Time)
+	return leaseSpec{
+		HolderIdentity:       &holderIdentity,
+		LeaseDurationSeconds: &leaseDurationSeconds,
+		AcquireTime:          &acquireTime,
+		RenewTime:            &renewTime,
+		LeaseTransitions:     &leaseTransitions,
+	}
+}
+
+// RunLeaderElected runs run once lock is acquired, and exits the controller once it's lost. It returns without running run
+// if stopCh is closed before lock is acquired, so replicas that aren't leading shut down straight away.
+func RunLeaderElected(lock resourcelock.Interface, stopCh <-chan struct{}, run func() error) error {
+	elected := make(chan struct{})
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(<-chan struct{}) {
+				close(elected)
+			},
+			OnStoppedLeading: func() {
+				glog.Fatalf("leader election lost")
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	go elector.Run()
+	select {
+	case <-stopCh:
+		return nil
+	case <-elected:
+	}
+	glog.Infof("acquired leader election lease %v", lock.Describe())
+	return run()
+}
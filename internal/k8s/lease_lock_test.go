@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// fakeLeases serves a single Lease, rejecting updates of stale resourceVersions like the API server.
+type fakeLeases struct {
+	mutex sync.Mutex
+	lease *lease
+}
+
+func (f *fakeLeases) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	resource := schema.GroupResource{Group: coordinationGroupVersion.Group, Resource: "leases"}
+	var status *apierrors.StatusError
+	switch r.Method {
+	case http.MethodGet:
+		if f.lease == nil {
+			status = apierrors.NewNotFound(resource, "lock")
+		}
+	case http.MethodPost, http.MethodPut:
+		raw, _ := ioutil.ReadAll(r.Body)
+		var received lease
+		_ = json.Unmarshal(raw, &received)
+		switch {
+		case r.Method == http.MethodPost && f.lease != nil:
+			status = apierrors.NewAlreadyExists(resource, "lock")
+		case r.Method == http.MethodPut && (f.lease == nil || received.ObjectMeta.ResourceVersion != f.lease.ObjectMeta.ResourceVersion):
+			status = apierrors.NewConflict(resource, "lock", nil)
+		default:
+			version, _ := strconv.Atoi(received.ObjectMeta.ResourceVersion)
+			received.ObjectMeta.ResourceVersion = strconv.Itoa(version + 1)
+			f.lease = &received
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if status != nil {
+		status.ErrStatus.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Status"}
+		w.WriteHeader(int(status.ErrStatus.Code))
+		_ = json.NewEncoder(w).Encode(&status.ErrStatus)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(f.lease)
+}
+
+func TestLeaseLock(t *testing.T) {
+	server := httptest.NewServer(&fakeLeases{})
+	defer server.Close()
+	lock, err := NewLeaseLock(&rest.Config{Host: server.URL}, "kube-system", "lock", "replica-1")
+	assert.NoError(t, err)
+	other, err := NewLeaseLock(&rest.Config{Host: server.URL}, "kube-system", "lock", "replica-2")
+	assert.NoError(t, err)
+
+	_, err = lock.Get()
+	assert.True(t, apierrors.IsNotFound(err))
+
+	now := metav1.NewTime(time.Now().Truncate(time.Microsecond))
+	ler := resourcelock.LeaderElectionRecord{HolderIdentity: "replica-1", LeaseDurationSeconds: 15, AcquireTime: now, RenewTime: now}
+	assert.NoError(t, lock.Create(ler))
+	assert.True(t, apierrors.IsAlreadyExists(other.Create(ler)))
+
+	got, err := other.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "replica-1", got.HolderIdentity)
+	assert.Equal(t, 15, got.LeaseDurationSeconds)
+	assert.True(t, now.Equal(&got.RenewTime))
+
+	// the lease was renewed since other read it, so its update conflicts.
+	ler.RenewTime = metav1.NewTime(now.Add(2 * time.Second))
+	assert.NoError(t, lock.Update(ler))
+	assert.True(t, apierrors.IsConflict(other.Update(resourcelock.LeaderElectionRecord{HolderIdentity: "replica-2", LeaderTransitions: 1})))
+
+	got, err = other.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "replica-1", got.HolderIdentity)
+	assert.Equal(t, "kube-system/lock", lock.Describe())
+	assert.Equal(t, "replica-1", lock.Identity())
+}
@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	extensions "k8s.io/api/extensions/v1beta1"
+)
+
+// IngressPartitionOfAnnotation is set on partitions of an ingress split by host, to the name of the ingress they are split from.
+// Partitions only exist in memory, so they share the parsed annotations of that ingress.
+const IngressPartitionOfAnnotation = "alb.ingress.kubernetes.io/partition-of"
+
+// ingressPartitionSeparator separates the name of ingress from the hash of host in the names of its partitions.
+// It isn't allowed in names of Kubernetes objects, so partitions never take the name of another ingress.
+const ingressPartitionSeparator = "_"
+
+// PartitionIngressByHost splits ingress into one partition for each distinct host of its rules, so each host can be served by its own ALB.
+// Rules without host and the default backend match requests of all hosts, so they are kept in every partition.
+// ingress is returned as is if none of its rules has host.
+func PartitionIngressByHost(ingress *extensions.Ingress) []*extensions.Ingress {
+	var hosts []string
+	hostRules := make(map[string][]extensions.IngressRule)
+	var sharedRules []extensions.IngressRule
+	for _, rule := range ingress.Spec.Rules {
+		if rule.Host == "" {
+			sharedRules = append(sharedRules, rule)
+			continue
+		}
+		if _, ok := hostRules[rule.Host]; !ok {
+			hosts = append(hosts, rule.Host)
+		}
+		hostRules[rule.Host] = append(hostRules[rule.Host], rule)
+	}
+	if len(hosts) == 0 {
+		return []*extensions.Ingress{ingress}
+	}
+
+	var partitions []*extensions.Ingress
+	for _, host := range hosts {
+		partition := ingress.DeepCopy()
+		partition.Name = IngressPartitionName(ingress.Name, host)
+		if partition.Annotations == nil {
+			partition.Annotations = make(map[string]string)
+		}
+		partition.Annotations[IngressPartitionOfAnnotation] = ingress.Name
+		partition.Spec.Rules = append(append([]extensions.IngressRule{}, hostRules[host]...), sharedRules...)
+
+		var tls []extensions.IngressTLS
+		for _, t := range ingress.Spec.TLS {
+			for _, tlsHost := range t.Hosts {
+				if tlsHost == host {
+					tls = append(tls, extensions.IngressTLS{Hosts: []string{host}, SecretName: t.SecretName})
+					break
+				}
+			}
+		}
+		partition.Spec.TLS = tls
+		partitions = append(partitions, partition)
+	}
+	return partitions
+}
+
+// IngressPartitionName generates the name of the partition of ingress for host.
+func IngressPartitionName(ingressName string, host string) string {
+	hash := sha256.Sum256([]byte(host))
+	return fmt.Sprintf("%s%s%s", ingressName, ingressPartitionSeparator, hex.EncodeToString(hash[:])[:8])
+}
+
+// IngressParentName returns the name of the ingress that ingress is split from, which is the name of ingress itself if it's not an partition.
+func IngressParentName(ingress *extensions.Ingress) string {
+	if parent, ok := ingress.Annotations[IngressPartitionOfAnnotation]; ok {
+		return parent
+	}
+	return ingress.Name
+}
+
+// IngressAnnotationsKey returns the key to lookup parsed annotations of ingress,
+// which is the key of the ingress it's split from for partitions.
+func IngressAnnotationsKey(ingress *extensions.Ingress) string {
+	return ingress.Namespace + "/" + IngressParentName(ingress)
+}
@@ -0,0 +1,122 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestPartitionIngressByHost(t *testing.T) {
+	rule := func(host string, serviceName string) extensions.IngressRule {
+		return extensions.IngressRule{
+			Host: host,
+			IngressRuleValue: extensions.IngressRuleValue{
+				HTTP: &extensions.HTTPIngressRuleValue{
+					Paths: []extensions.HTTPIngressPath{
+						{Backend: extensions.IngressBackend{ServiceName: serviceName, ServicePort: intstr.FromInt(80)}},
+					},
+				},
+			},
+		}
+	}
+	defaultBackend := &extensions.IngressBackend{ServiceName: "default", ServicePort: intstr.FromInt(80)}
+	for _, tc := range []struct {
+		Name               string
+		Ingress            *extensions.Ingress
+		ExpectedPartitions []*extensions.Ingress
+	}{
+		{
+			Name: "ingress without host is kept as is",
+			Ingress: &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"},
+				Spec: extensions.IngressSpec{
+					Rules: []extensions.IngressRule{rule("", "service")},
+				},
+			},
+			ExpectedPartitions: []*extensions.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"},
+					Spec: extensions.IngressSpec{
+						Rules: []extensions.IngressRule{rule("", "service")},
+					},
+				},
+			},
+		},
+		{
+			Name: "each host gets its own partition, with rules without host and default backend",
+			Ingress: &extensions.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "ingress"},
+				Spec: extensions.IngressSpec{
+					Backend: defaultBackend,
+					TLS: []extensions.IngressTLS{
+						{Hosts: []string{"a.example.com", "b.example.com"}, SecretName: "secret"},
+					},
+					Rules: []extensions.IngressRule{
+						rule("a.example.com", "service-a"),
+						rule("", "service"),
+						rule("b.example.com", "service-b"),
+						rule("a.example.com", "service-a2"),
+					},
+				},
+			},
+			ExpectedPartitions: []*extensions.Ingress{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:   "namespace",
+						Name:        IngressPartitionName("ingress", "a.example.com"),
+						Annotations: map[string]string{IngressPartitionOfAnnotation: "ingress"},
+					},
+					Spec: extensions.IngressSpec{
+						Backend: defaultBackend,
+						TLS: []extensions.IngressTLS{
+							{Hosts: []string{"a.example.com"}, SecretName: "secret"},
+						},
+						Rules: []extensions.IngressRule{
+							rule("a.example.com", "service-a"),
+							rule("a.example.com", "service-a2"),
+							rule("", "service"),
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:   "namespace",
+						Name:        IngressPartitionName("ingress", "b.example.com"),
+						Annotations: map[string]string{IngressPartitionOfAnnotation: "ingress"},
+					},
+					Spec: extensions.IngressSpec{
+						Backend: defaultBackend,
+						TLS: []extensions.IngressTLS{
+							{Hosts: []string{"b.example.com"}, SecretName: "secret"},
+						},
+						Rules: []extensions.IngressRule{
+							rule("b.example.com", "service-b"),
+							rule("", "service"),
+						},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			partitions := PartitionIngressByHost(tc.Ingress)
+			assert.Equal(t, tc.ExpectedPartitions, partitions)
+			for _, partition := range partitions {
+				assert.Equal(t, "namespace/ingress", IngressAnnotationsKey(partition))
+			}
+		})
+	}
+}
+
+func TestIngressPartitionName(t *testing.T) {
+	name := IngressPartitionName("ingress", "a.example.com")
+	assert.True(t, strings.HasPrefix(name, "ingress_"))
+	// partitions can't take the name of an ingress, which is an DNS subdomain
+	assert.NotEmpty(t, validation.IsDNS1123Subdomain(name))
+	assert.NotEqual(t, name, IngressPartitionName("ingress", "b.example.com"))
+}
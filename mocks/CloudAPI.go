@@ -2,12 +2,16 @@
 
 package mocks
 
+import cloudwatch "github.com/aws/aws-sdk-go/service/cloudwatch"
 import context "context"
 import ec2 "github.com/aws/aws-sdk-go/service/ec2"
 import ec2metadata "github.com/aws/aws-sdk-go/aws/ec2metadata"
 import elbv2 "github.com/aws/aws-sdk-go/service/elbv2"
 import mock "github.com/stretchr/testify/mock"
 import resourcegroupstaggingapi "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+import route53 "github.com/aws/aws-sdk-go/service/route53"
+import s3 "github.com/aws/aws-sdk-go/service/s3"
+import time "time"
 import types "github.com/kubernetes-sigs/aws-alb-ingress-controller/pkg/util/types"
 import waf "github.com/aws/aws-sdk-go/service/waf"
 import wafregional "github.com/aws/aws-sdk-go/service/wafregional"
@@ -63,6 +67,64 @@ func (_m *CloudAPI) AuthorizeSecurityGroupIngressWithContext(_a0 context.Context
 	return r0, r1
 }
 
+// BucketExists provides a mock function with given fields: ctx, bucket
+func (_m *CloudAPI) BucketExists(ctx context.Context, bucket string) (bool, error) {
+	ret := _m.Called(ctx, bucket)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, bucket)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, bucket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChangeResourceRecordSets provides a mock function with given fields: ctx, zoneID, changes
+func (_m *CloudAPI) ChangeResourceRecordSets(ctx context.Context, zoneID string, changes []*route53.Change) error {
+	ret := _m.Called(ctx, zoneID, changes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*route53.Change) error); ok {
+		r0 = rf(ctx, zoneID, changes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateBucketWithContext provides a mock function with given fields: _a0, _a1
+func (_m *CloudAPI) CreateBucketWithContext(_a0 context.Context, _a1 *s3.CreateBucketInput) (*s3.CreateBucketOutput, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *s3.CreateBucketOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *s3.CreateBucketInput) *s3.CreateBucketOutput); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*s3.CreateBucketOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *s3.CreateBucketInput) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateListenerWithContext provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) CreateListenerWithContext(_a0 context.Context, _a1 *elbv2.CreateListenerInput) (*elbv2.CreateListenerOutput, error) {
 	ret := _m.Called(_a0, _a1)
@@ -109,6 +171,29 @@ func (_m *CloudAPI) CreateLoadBalancerWithContext(_a0 context.Context, _a1 *elbv
 	return r0, r1
 }
 
+// CreateLoadBalancerWithCustomerOwnedIpv4PoolWithContext provides a mock function with given fields: _a0, _a1, _a2
+func (_m *CloudAPI) CreateLoadBalancerWithCustomerOwnedIpv4PoolWithContext(_a0 context.Context, _a1 *elbv2.CreateLoadBalancerInput, _a2 string) (*elbv2.CreateLoadBalancerOutput, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *elbv2.CreateLoadBalancerOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *elbv2.CreateLoadBalancerInput, string) *elbv2.CreateLoadBalancerOutput); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*elbv2.CreateLoadBalancerOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *elbv2.CreateLoadBalancerInput, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateRuleWithContext provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) CreateRuleWithContext(_a0 context.Context, _a1 *elbv2.CreateRuleInput) (*elbv2.CreateRuleOutput, error) {
 	ret := _m.Called(_a0, _a1)
@@ -229,6 +314,20 @@ func (_m *CloudAPI) DeleteLoadBalancerByArn(_a0 context.Context, _a1 string) err
 	return r0
 }
 
+// DeleteMetricAlarms provides a mock function with given fields: ctx, names
+func (_m *CloudAPI) DeleteMetricAlarms(ctx context.Context, names []string) error {
+	ret := _m.Called(ctx, names)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, names)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteRuleWithContext provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) DeleteRuleWithContext(_a0 context.Context, _a1 *elbv2.DeleteRuleInput) (*elbv2.DeleteRuleOutput, error) {
 	ret := _m.Called(_a0, _a1)
@@ -418,6 +517,75 @@ func (_m *CloudAPI) DisassociateWAF(ctx context.Context, resourceArn *string) (*
 	return r0, r1
 }
 
+// GetAccountLimits provides a mock function with given fields: _a0
+func (_m *CloudAPI) GetAccountLimits(_a0 context.Context) (map[string]int64, error) {
+	ret := _m.Called(_a0)
+
+	var r0 map[string]int64
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]int64); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBranchNetworkInterfacesByPrivateIPs provides a mock function with given fields: _a0, _a1
+func (_m *CloudAPI) GetBranchNetworkInterfacesByPrivateIPs(_a0 context.Context, _a1 []string) ([]*ec2.NetworkInterface, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*ec2.NetworkInterface
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*ec2.NetworkInterface); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*ec2.NetworkInterface)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBucketPolicy provides a mock function with given fields: ctx, bucket
+func (_m *CloudAPI) GetBucketPolicy(ctx context.Context, bucket string) (*string, error) {
+	ret := _m.Called(ctx, bucket)
+
+	var r0 *string
+	if rf, ok := ret.Get(0).(func(context.Context, string) *string); ok {
+		r0 = rf(ctx, bucket)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, bucket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetClusterSubnets provides a mock function with given fields:
 func (_m *CloudAPI) GetClusterSubnets() (map[string]types.EC2Tags, error) {
 	ret := _m.Called()
@@ -441,6 +609,27 @@ func (_m *CloudAPI) GetClusterSubnets() (map[string]types.EC2Tags, error) {
 	return r0, r1
 }
 
+// GetHostedZoneName provides a mock function with given fields: ctx, zoneID
+func (_m *CloudAPI) GetHostedZoneName(ctx context.Context, zoneID string) (string, error) {
+	ret := _m.Called(ctx, zoneID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, zoneID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, zoneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetInstanceIdentityDocument provides a mock function with given fields:
 func (_m *CloudAPI) GetInstanceIdentityDocument() (ec2metadata.EC2InstanceIdentityDocument, error) {
 	ret := _m.Called()
@@ -531,8 +720,54 @@ func (_m *CloudAPI) GetLoadBalancerByName(_a0 context.Context, _a1 string) (*elb
 	return r0, r1
 }
 
-// GetResourcesByFilters provides a mock function with given fields: tagFilters, resourceTypeFilters
-func (_m *CloudAPI) GetResourcesByFilters(tagFilters map[string][]string, resourceTypeFilters ...string) ([]string, error) {
+// GetLoadBalancerMetrics provides a mock function with given fields: ctx, lbArns, period
+func (_m *CloudAPI) GetLoadBalancerMetrics(ctx context.Context, lbArns []string, period time.Duration) (map[string]map[string]float64, error) {
+	ret := _m.Called(ctx, lbArns, period)
+
+	var r0 map[string]map[string]float64
+	if rf, ok := ret.Get(0).(func(context.Context, []string, time.Duration) map[string]map[string]float64); ok {
+		r0 = rf(ctx, lbArns, period)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]map[string]float64)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string, time.Duration) error); ok {
+		r1 = rf(ctx, lbArns, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMetricAlarmsByPrefix provides a mock function with given fields: ctx, prefix
+func (_m *CloudAPI) GetMetricAlarmsByPrefix(ctx context.Context, prefix string) ([]*cloudwatch.MetricAlarm, error) {
+	ret := _m.Called(ctx, prefix)
+
+	var r0 []*cloudwatch.MetricAlarm
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*cloudwatch.MetricAlarm); ok {
+		r0 = rf(ctx, prefix)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*cloudwatch.MetricAlarm)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, prefix)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetResourceTagsByFilters provides a mock function with given fields: tagFilters, resourceTypeFilters
+func (_m *CloudAPI) GetResourceTagsByFilters(tagFilters map[string][]string, resourceTypeFilters ...string) (map[string]map[string]string, error) {
 	_va := make([]interface{}, len(resourceTypeFilters))
 	for _i := range resourceTypeFilters {
 		_va[_i] = resourceTypeFilters[_i]
@@ -542,12 +777,12 @@ func (_m *CloudAPI) GetResourcesByFilters(tagFilters map[string][]string, resour
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(map[string][]string, ...string) []string); ok {
+	var r0 map[string]map[string]string
+	if rf, ok := ret.Get(0).(func(map[string][]string, ...string) map[string]map[string]string); ok {
 		r0 = rf(tagFilters, resourceTypeFilters...)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).(map[string]map[string]string)
 		}
 	}
 
@@ -561,6 +796,36 @@ func (_m *CloudAPI) GetResourcesByFilters(tagFilters map[string][]string, resour
 	return r0, r1
 }
 
+// GetResourcesByFilters provides a mock function with given fields: ctx, tagFilters, resourceTypeFilters
+func (_m *CloudAPI) GetResourcesByFilters(ctx context.Context, tagFilters map[string][]string, resourceTypeFilters ...string) ([]string, error) {
+	_va := make([]interface{}, len(resourceTypeFilters))
+	for _i := range resourceTypeFilters {
+		_va[_i] = resourceTypeFilters[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, tagFilters)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, map[string][]string, ...string) []string); ok {
+		r0 = rf(ctx, tagFilters, resourceTypeFilters...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, map[string][]string, ...string) error); ok {
+		r1 = rf(ctx, tagFilters, resourceTypeFilters...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRules provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) GetRules(_a0 context.Context, _a1 string) ([]*elbv2.Rule, error) {
 	ret := _m.Called(_a0, _a1)
@@ -584,6 +849,50 @@ func (_m *CloudAPI) GetRules(_a0 context.Context, _a1 string) ([]*elbv2.Rule, er
 	return r0, r1
 }
 
+// GetSSLPolicyNames provides a mock function with given fields: _a0
+func (_m *CloudAPI) GetSSLPolicyNames(_a0 context.Context) ([]string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSecretString provides a mock function with given fields: ctx, secretArn
+func (_m *CloudAPI) GetSecretString(ctx context.Context, secretArn string) (string, error) {
+	ret := _m.Called(ctx, secretArn)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, secretArn)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, secretArn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSecurityGroupByID provides a mock function with given fields: _a0
 func (_m *CloudAPI) GetSecurityGroupByID(_a0 string) (*ec2.SecurityGroup, error) {
 	ret := _m.Called(_a0)
@@ -653,6 +962,29 @@ func (_m *CloudAPI) GetSecurityGroupsByName(_a0 context.Context, _a1 []string) (
 	return r0, r1
 }
 
+// GetSecurityGroupsReferencingGroups provides a mock function with given fields: _a0, _a1
+func (_m *CloudAPI) GetSecurityGroupsReferencingGroups(_a0 context.Context, _a1 []string) ([]*ec2.SecurityGroup, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*ec2.SecurityGroup
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*ec2.SecurityGroup); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*ec2.SecurityGroup)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSubnetsByNameOrID provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) GetSubnetsByNameOrID(_a0 context.Context, _a1 []string) ([]*ec2.Subnet, error) {
 	ret := _m.Called(_a0, _a1)
@@ -676,6 +1008,29 @@ func (_m *CloudAPI) GetSubnetsByNameOrID(_a0 context.Context, _a1 []string) ([]*
 	return r0, r1
 }
 
+// GetSubnetsByTagFilters provides a mock function with given fields: _a0, _a1
+func (_m *CloudAPI) GetSubnetsByTagFilters(_a0 context.Context, _a1 map[string][]string) ([]*ec2.Subnet, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*ec2.Subnet
+	if rf, ok := ret.Get(0).(func(context.Context, map[string][]string) []*ec2.Subnet); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*ec2.Subnet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, map[string][]string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTargetGroupByArn provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) GetTargetGroupByArn(_a0 context.Context, _a1 string) (*elbv2.TargetGroup, error) {
 	ret := _m.Called(_a0, _a1)
@@ -835,6 +1190,29 @@ func (_m *CloudAPI) ListListenersByLoadBalancer(_a0 context.Context, _a1 string)
 	return r0, r1
 }
 
+// ListResourceRecordSets provides a mock function with given fields: ctx, zoneID
+func (_m *CloudAPI) ListResourceRecordSets(ctx context.Context, zoneID string) ([]*route53.ResourceRecordSet, error) {
+	ret := _m.Called(ctx, zoneID)
+
+	var r0 []*route53.ResourceRecordSet
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*route53.ResourceRecordSet); ok {
+		r0 = rf(ctx, zoneID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*route53.ResourceRecordSet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, zoneID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ModifyListenerWithContext provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) ModifyListenerWithContext(_a0 context.Context, _a1 *elbv2.ModifyListenerInput) (*elbv2.ModifyListenerOutput, error) {
 	ret := _m.Called(_a0, _a1)
@@ -973,6 +1351,94 @@ func (_m *CloudAPI) ModifyTargetGroupWithContext(_a0 context.Context, _a1 *elbv2
 	return r0, r1
 }
 
+// PublishNotification provides a mock function with given fields: ctx, topicArn, subject, message
+func (_m *CloudAPI) PublishNotification(ctx context.Context, topicArn string, subject string, message string) error {
+	ret := _m.Called(ctx, topicArn, subject, message)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, topicArn, subject, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PutBucketLifecycleConfigurationWithContext provides a mock function with given fields: _a0, _a1
+func (_m *CloudAPI) PutBucketLifecycleConfigurationWithContext(_a0 context.Context, _a1 *s3.PutBucketLifecycleConfigurationInput) (*s3.PutBucketLifecycleConfigurationOutput, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *s3.PutBucketLifecycleConfigurationOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *s3.PutBucketLifecycleConfigurationInput) *s3.PutBucketLifecycleConfigurationOutput); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*s3.PutBucketLifecycleConfigurationOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *s3.PutBucketLifecycleConfigurationInput) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PutBucketPolicyWithContext provides a mock function with given fields: _a0, _a1
+func (_m *CloudAPI) PutBucketPolicyWithContext(_a0 context.Context, _a1 *s3.PutBucketPolicyInput) (*s3.PutBucketPolicyOutput, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *s3.PutBucketPolicyOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *s3.PutBucketPolicyInput) *s3.PutBucketPolicyOutput); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*s3.PutBucketPolicyOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *s3.PutBucketPolicyInput) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PutCountMetrics provides a mock function with given fields: ctx, namespace, dimensions, values
+func (_m *CloudAPI) PutCountMetrics(ctx context.Context, namespace string, dimensions map[string]string, values map[string]float64) error {
+	ret := _m.Called(ctx, namespace, dimensions, values)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]string, map[string]float64) error); ok {
+		r0 = rf(ctx, namespace, dimensions, values)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PutMetricAlarm provides a mock function with given fields: ctx, input, tags
+func (_m *CloudAPI) PutMetricAlarm(ctx context.Context, input *cloudwatch.PutMetricAlarmInput, tags map[string]string) error {
+	ret := _m.Called(ctx, input, tags)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *cloudwatch.PutMetricAlarmInput, map[string]string) error); ok {
+		r0 = rf(ctx, input, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RegisterTargetsWithContext provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) RegisterTargetsWithContext(_a0 context.Context, _a1 *elbv2.RegisterTargetsInput) (*elbv2.RegisterTargetsOutput, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1152,6 +1618,22 @@ func (_m *CloudAPI) StatusIAM() func() error {
 	return r0
 }
 
+// StatusVPCEndpoints provides a mock function with given fields: services
+func (_m *CloudAPI) StatusVPCEndpoints(services []string) func() error {
+	ret := _m.Called(services)
+
+	var r0 func() error
+	if rf, ok := ret.Get(0).(func([]string) func() error); ok {
+		r0 = rf(services)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func() error)
+		}
+	}
+
+	return r0
+}
+
 // TagResourcesWithContext provides a mock function with given fields: _a0, _a1
 func (_m *CloudAPI) TagResourcesWithContext(_a0 context.Context, _a1 *resourcegroupstaggingapi.TagResourcesInput) (*resourcegroupstaggingapi.TagResourcesOutput, error) {
 	ret := _m.Called(_a0, _a1)
@@ -1198,6 +1680,29 @@ func (_m *CloudAPI) UntagResourcesWithContext(_a0 context.Context, _a1 *resource
 	return r0, r1
 }
 
+// UpdateSecurityGroupRuleDescriptionsIngressWithContext provides a mock function with given fields: _a0, _a1
+func (_m *CloudAPI) UpdateSecurityGroupRuleDescriptionsIngressWithContext(_a0 context.Context, _a1 *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput) (*ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput
+	if rf, ok := ret.Get(0).(func(context.Context, *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput) *ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ec2.UpdateSecurityGroupRuleDescriptionsIngressOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *ec2.UpdateSecurityGroupRuleDescriptionsIngressInput) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WebACLExists provides a mock function with given fields: ctx, webACLId
 func (_m *CloudAPI) WebACLExists(ctx context.Context, webACLId *string) (bool, error) {
 	ret := _m.Called(ctx, webACLId)
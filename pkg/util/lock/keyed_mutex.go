@@ -0,0 +1,41 @@
+package lock
+
+import "sync"
+
+// KeyedMutex is mutual exclusion per key, locks are released once no one holds or waits for them.
+type KeyedMutex struct {
+	mutex sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	refs int
+}
+
+func NewKeyedMutex() *KeyedMutex {
+	return &KeyedMutex{locks: make(map[string]*keyedLock)}
+}
+
+// Lock locks key, and returns the function unlocking it.
+func (m *KeyedMutex) Lock(key string) func() {
+	m.mutex.Lock()
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		m.locks[key] = lock
+	}
+	lock.refs++
+	m.mutex.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		m.mutex.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(m.locks, key)
+		}
+		m.mutex.Unlock()
+	}
+}
@@ -0,0 +1,35 @@
+package lock
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	m := NewKeyedMutex()
+	// counts guards the map itself, read-modify-writes of an key are only serialized by m.
+	var countsMutex sync.Mutex
+	counts := map[string]int{}
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		for _, key := range []string{"lb-a", "lb-b"} {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				unlock := m.Lock(key)
+				defer unlock()
+				countsMutex.Lock()
+				count := counts[key]
+				countsMutex.Unlock()
+				countsMutex.Lock()
+				counts[key] = count + 1
+				countsMutex.Unlock()
+			}(key)
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, map[string]int{"lb-a": 100, "lb-b": 100}, counts)
+	assert.Empty(t, m.locks)
+}
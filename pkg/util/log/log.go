@@ -2,16 +2,45 @@
 package log
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"reflect"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awsutil"
 	"github.com/golang/glog"
 )
 
+// Formats of log lines
+const (
+	// FormatText prints log lines with glog, prefixed by the name of logger
+	FormatText = "text"
+	// FormatJSON prints each log line as an JSON object, with the name and fields of logger as keys
+	FormatJSON = "json"
+)
+
+var (
+	logFormat            = FormatText
+	jsonOutput io.Writer = os.Stderr
+	jsonMu     sync.Mutex
+)
+
+// SetFormat sets the format of log lines, either FormatText or FormatJSON.
+func SetFormat(f string) error {
+	if f != FormatText && f != FormatJSON {
+		return fmt.Errorf("unsupported log format %s, must be %s or %s", f, FormatText, FormatJSON)
+	}
+	logFormat = f
+	return nil
+}
+
 type Logger struct {
-	name string
+	name   string
+	fields map[string]string
 }
 
 // New creates a new Logger.
@@ -20,45 +49,69 @@ func New(name string) *Logger {
 	return &Logger{name: name}
 }
 
+// With returns a copy of Logger with an additional field, which is only printed in JSON format,
+// e.g. With("reconcileID", id) allows filtering log lines of an reconcile.
+func (l *Logger) With(key string, value string) *Logger {
+	fields := make(map[string]string, len(l.fields)+1)
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	return &Logger{name: l.name, fields: fields}
+}
+
+// Name returns the name of Logger, e.g. namespace/name of the ingress being reconciled.
+func (l *Logger) Name() string {
+	return l.name
+}
+
+// Field returns the value of field with key, or empty if it's not set.
+func (l *Logger) Field(key string) string {
+	return l.fields[key]
+}
+
 // Debugf will print debug messages if debug logging is enabled
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	debugf(format, l.name, 2, args...)
+	l.debugf(format, 2, args...)
 }
 
 // DebugLevelf will print debug messages if debug logging is enabled
 func (l *Logger) DebugLevelf(level int, format string, args ...interface{}) {
-	debugf(format, l.name, level, args...)
+	l.debugf(format, level, args...)
 }
 
 // Infof will print info level messages
 func (l *Logger) Infof(format string, args ...interface{}) {
-	infof(format, l.name, args...)
+	l.infof(format, args...)
 }
 
 // Warnf will print warning level messages
 func (l *Logger) Warnf(format string, args ...interface{}) {
-	warnf(format, l.name, args...)
+	l.warnf(format, args...)
 }
 
 // Errorf will print error level messages
 func (l *Logger) Errorf(format string, args ...interface{}) {
-	errorf(format, l.name, args...)
+	l.errorf(format, args...)
 }
 
 // Fatalf will print error level messages
 func (l *Logger) Fatalf(format string, args ...interface{}) {
-	fatalf(format, l.name, args...)
+	l.fatalf(format, args...)
 }
 
 // Exitf will print error level messages and exit
 func (l *Logger) Exitf(format string, args ...interface{}) {
-	exitf(format, l.name, args...)
+	l.exitf(format, args...)
 }
 
 // debugf will print debug messages if debug logging is enabled
-func debugf(format, ingressName string, level int, args ...interface{}) {
+func (l *Logger) debugf(format string, level int, args ...interface{}) {
 	if glog.V(2) {
-		prefix := fmt.Sprintf("%s: ", ingressName)
+		if l.printJSON("debug", format, args...) {
+			return
+		}
+		prefix := fmt.Sprintf("%s: ", l.name)
 		for _, line := range strings.Split(fmt.Sprintf(format, args...), "\n") {
 			glog.InfoDepth(level, prefix, line)
 		}
@@ -66,44 +119,83 @@ func debugf(format, ingressName string, level int, args ...interface{}) {
 }
 
 // infof will print info level messages
-func infof(format, ingressName string, args ...interface{}) {
-	prefix := fmt.Sprintf("%s: ", ingressName)
+func (l *Logger) infof(format string, args ...interface{}) {
+	if l.printJSON("info", format, args...) {
+		return
+	}
+	prefix := fmt.Sprintf("%s: ", l.name)
 	for _, line := range strings.Split(fmt.Sprintf(format, args...), "\n") {
 		glog.InfoDepth(2, prefix, line)
 	}
 }
 
 // warnf will print warning level messages
-func warnf(format, ingressName string, args ...interface{}) {
-	prefix := fmt.Sprintf("%s: ", ingressName)
+func (l *Logger) warnf(format string, args ...interface{}) {
+	if l.printJSON("warning", format, args...) {
+		return
+	}
+	prefix := fmt.Sprintf("%s: ", l.name)
 	for _, line := range strings.Split(fmt.Sprintf(format, args...), "\n") {
 		glog.WarningDepth(2, prefix, line)
 	}
 }
 
 // errorf will print error level messages
-func errorf(format, ingressName string, args ...interface{}) {
-	prefix := fmt.Sprintf("%s: ", ingressName)
+func (l *Logger) errorf(format string, args ...interface{}) {
+	if l.printJSON("error", format, args...) {
+		return
+	}
+	prefix := fmt.Sprintf("%s: ", l.name)
 	for _, line := range strings.Split(fmt.Sprintf(format, args...), "\n") {
 		glog.ErrorDepth(2, prefix, line)
 	}
 }
 
 // fatalf will print error level messages
-func fatalf(format, ingressName string, args ...interface{}) {
-	prefix := fmt.Sprintf("%s: ", ingressName)
+func (l *Logger) fatalf(format string, args ...interface{}) {
+	if l.printJSON("fatal", format, args...) {
+		os.Exit(255)
+	}
+	prefix := fmt.Sprintf("%s: ", l.name)
 	glog.FatalDepth(2, fmt.Sprintf(prefix+format, args...))
 }
 
 // Exitf will print error level messages and exit
-func exitf(format, ingressName string, args ...interface{}) {
-	prefix := fmt.Sprintf("%s: ", ingressName)
+func (l *Logger) exitf(format string, args ...interface{}) {
+	if l.printJSON("fatal", format, args...) {
+		os.Exit(1)
+	}
+	prefix := fmt.Sprintf("%s: ", l.name)
 	glog.ExitDepth(2, fmt.Sprintf(prefix+format, args...))
 }
 
+// printJSON prints the message as an JSON object if JSON format is used, it returns whether the message is printed.
+func (l *Logger) printJSON(level string, format string, args ...interface{}) bool {
+	if logFormat != FormatJSON {
+		return false
+	}
+	entry := make(map[string]string, len(l.fields)+4)
+	for k, v := range l.fields {
+		entry[k] = v
+	}
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level
+	entry["logger"] = l.name
+	entry["msg"] = fmt.Sprintf(format, args...)
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return false
+	}
+	jsonMu.Lock()
+	defer jsonMu.Unlock()
+	_, _ = jsonOutput.Write(append(line, '\n'))
+	return true
+}
+
 // Prettify uses awsutil.Prettify to print structs, but also removes '\n' for better logging.
+// Sensitive values are redacted, see Redact.
 func Prettify(i interface{}) string {
-	return strings.Replace(awsutil.Prettify(i), "\n", "", -1)
+	return strings.Replace(awsutil.Prettify(Redact(i)), "\n", "", -1)
 }
 
 type stringInt interface {
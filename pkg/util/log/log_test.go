@@ -1 +1,101 @@
 package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFormat(t *testing.T) {
+	defer func() { logFormat = FormatText }()
+	assert.NoError(t, SetFormat(FormatJSON))
+	assert.Equal(t, FormatJSON, logFormat)
+	assert.Error(t, SetFormat("xml"))
+}
+
+func TestLogger_With(t *testing.T) {
+	logger := New("namespace/ingress")
+	withID := logger.With("reconcileID", "1234")
+	withRequest := withID.With("awsRequestID", "abcd")
+
+	assert.Nil(t, logger.fields)
+	assert.Equal(t, map[string]string{"reconcileID": "1234"}, withID.fields)
+	assert.Equal(t, map[string]string{"reconcileID": "1234", "awsRequestID": "abcd"}, withRequest.fields)
+}
+
+func TestLogger_JSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	jsonOutput = &buf
+	logFormat = FormatJSON
+	defer func() {
+		logFormat = FormatText
+		jsonOutput = os.Stderr
+	}()
+
+	New("namespace/ingress").With("reconcileID", "1234").Infof("creating %s", "LoadBalancer")
+
+	var entry map[string]string
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.NotEmpty(t, entry["time"])
+	delete(entry, "time")
+	assert.Equal(t, map[string]string{
+		"level":       "info",
+		"logger":      "namespace/ingress",
+		"msg":         "creating LoadBalancer",
+		"reconcileID": "1234",
+	}, entry)
+}
+
+func TestRedact(t *testing.T) {
+	SetSensitiveTagKeys([]string{"token"})
+	defer SetSensitiveTagKeys(nil)
+
+	type authConfig struct {
+		ClientId     *string
+		ClientSecret *string
+	}
+	type tag struct {
+		Key   *string
+		Value *string
+	}
+	clientID, clientSecret := "client", "secret"
+	tokenKey, tokenValue, envKey, envValue := "token", "abcd", "env", "prod"
+	config := &authConfig{ClientId: &clientID, ClientSecret: &clientSecret}
+	tags := []*tag{{Key: &tokenKey, Value: &tokenValue}, {Key: &envKey, Value: &envValue}}
+
+	redactedConfig := Redact(config).(*authConfig)
+	assert.Equal(t, "client", *redactedConfig.ClientId)
+	assert.Equal(t, Redacted, *redactedConfig.ClientSecret)
+	assert.Equal(t, "secret", *config.ClientSecret)
+
+	redactedTags := Redact(tags).([]*tag)
+	assert.Equal(t, Redacted, *redactedTags[0].Value)
+	assert.Equal(t, "prod", *redactedTags[1].Value)
+	assert.Equal(t, "abcd", *tags[0].Value)
+
+	assert.Equal(t, map[string]string{"token": Redacted, "env": "prod"}, Redact(map[string]string{"token": "abcd", "env": "prod"}))
+	assert.Equal(t, "{  ClientId: \"client\",  ClientSecret: \""+Redacted+"\"}", Prettify(config))
+}
+
+func TestRedact_secretValue(t *testing.T) {
+	output := &secretsmanager.GetSecretValueOutput{
+		ARN:          aws.String("arn:aws:secretsmanager:us-west-2:123456789012:secret:oidc"),
+		SecretString: aws.String("secret"),
+		SecretBinary: []byte("binary secret"),
+		VersionId:    aws.String("v1"),
+	}
+
+	redacted := Redact(output).(*secretsmanager.GetSecretValueOutput)
+	assert.Equal(t, Redacted, aws.StringValue(redacted.SecretString))
+	assert.Equal(t, []byte(Redacted), redacted.SecretBinary)
+	assert.Equal(t, "v1", aws.StringValue(redacted.VersionId))
+	assert.Equal(t, "secret", aws.StringValue(output.SecretString))
+	assert.Equal(t, []byte("binary secret"), output.SecretBinary)
+	assert.NotContains(t, Prettify(output), "secret\"")
+	assert.NotContains(t, Prettify(output), "binary secret")
+}
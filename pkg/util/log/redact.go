@@ -0,0 +1,152 @@
+package log
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Redacted replaces sensitive values in log lines and events.
+const Redacted = "*** redacted ***"
+
+var (
+	// sensitiveFieldNames are names of struct fields whose values are always redacted, e.g. the client secret of OIDC authentication,
+	// and the values of Secrets Manager secrets it's read from.
+	sensitiveFieldNames = map[string]bool{
+		"ClientSecret": true,
+		"SecretString": true,
+		"SecretBinary": true,
+	}
+
+	sensitiveTagKeysMu sync.RWMutex
+	sensitiveTagKeys   = map[string]bool{}
+)
+
+// SetSensitiveTagKeys sets the keys of tags whose values are redacted.
+func SetSensitiveTagKeys(keys []string) {
+	sensitiveTagKeysMu.Lock()
+	defer sensitiveTagKeysMu.Unlock()
+	sensitiveTagKeys = make(map[string]bool, len(keys))
+	for _, key := range keys {
+		sensitiveTagKeys[key] = true
+	}
+}
+
+// IsSensitiveTagKey tests whether the value of tag with key must be redacted.
+func IsSensitiveTagKey(key string) bool {
+	sensitiveTagKeysMu.RLock()
+	defer sensitiveTagKeysMu.RUnlock()
+	return sensitiveTagKeys[key]
+}
+
+// Redact returns a copy of i with sensitive values replaced by Redacted, i is not modified.
+// Sensitive values are fields in sensitiveFieldNames, values of tags with sensitive keys in maps keyed by tag key,
+// and the Value of Key/Value structs such as AWS tags.
+func Redact(i interface{}) interface{} {
+	if i == nil {
+		return nil
+	}
+	return redactValue(reflect.ValueOf(i)).Interface()
+}
+
+func redactValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Elem().Type())
+		copied.Elem().Set(redactValue(v.Elem()))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(redactValue(v.Elem()))
+		return copied
+	case reflect.Struct:
+		return redactStruct(v)
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			value := redactValue(v.MapIndex(key))
+			if key.Kind() == reflect.String && IsSensitiveTagKey(key.String()) {
+				value = redactedString(value)
+			}
+			copied.SetMapIndex(key, value)
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+func redactStruct(v reflect.Value) reflect.Value {
+	copied := reflect.New(v.Type()).Elem()
+	copied.Set(v)
+	sensitiveTag := false
+	if key := v.FieldByName("Key"); key.IsValid() {
+		sensitiveTag = IsSensitiveTagKey(stringValue(key))
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" {
+			// unexported fields are kept as is, e.g. the internals of time.Time.
+			continue
+		}
+		value := redactValue(v.Field(i))
+		if sensitiveFieldNames[field.Name] || (sensitiveTag && field.Name == "Value") {
+			value = redactedString(value)
+		}
+		copied.Field(i).Set(value)
+	}
+	return copied
+}
+
+// redactedString returns Redacted in the type of v if v is an non-empty string, pointer to string or byte slice, otherwise v is returned.
+func redactedString(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		if v.Len() == 0 {
+			return v
+		}
+		return reflect.ValueOf([]byte(Redacted)).Convert(v.Type())
+	}
+	if stringValue(v) == "" {
+		return v
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(Redacted).Convert(v.Type())
+	case reflect.Ptr:
+		redacted := reflect.New(v.Type().Elem())
+		redacted.Elem().Set(reflect.ValueOf(Redacted).Convert(v.Type().Elem()))
+		return redacted
+	}
+	return v
+}
+
+// stringValue returns the value of an string or pointer to string, or empty for other values.
+func stringValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.String {
+		return ""
+	}
+	return v.String()
+}